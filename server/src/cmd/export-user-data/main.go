@@ -0,0 +1,66 @@
+// Command export-user-data writes all personal data the server stores about a user as ZIP file (GDPR Art. 20), e.g.
+// for a request that reaches the admins by mail. The database of the server is configured like the server, by the
+// TIMEASY_ environment variables or the config file in TIMEASY_CONFIG.
+//
+// Usage: export-user-data -user <user id> -out data.zip
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+	"timeasy-server/pkg/configuration"
+	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/notification"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gofrs/uuid"
+)
+
+var databaseService database.DatabaseService
+
+func main() {
+	userParam := flag.String("user", "", "id of the user whose data is exported")
+	out := flag.String("out", "", "the ZIP file to write")
+	flag.Parse() // also initializes the glog flags
+
+	userId, err := uuid.FromString(*userParam)
+	if err != nil || *out == "" {
+		fmt.Fprintln(os.Stderr, "usage: export-user-data -user <user id> -out data.zip")
+		os.Exit(2)
+	}
+
+	configuration, err := configuration.ParseConfiguration(nil)
+	if err != nil {
+		panic(err)
+	}
+	err = databaseService.Init(configuration.DbHost, configuration.DbName, configuration.DbUser,
+		configuration.DbPassword, configuration.DbPort)
+	if err != nil {
+		panic(err)
+	}
+	notificationRepository := database.NewGormNotificationRepository(databaseService.Database)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepository, map[string]notification.Channel{
+		model.NotificationChannelInbox: notification.NewInboxChannel(notificationRepository),
+	})
+	dataExportUsecase := usecase.NewDataExportUsecase(database.NewGormDataExportRepository(databaseService.Database),
+		notificationUsecase, configuration.DataExportRetention)
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = dataExportUsecase.WritePersonalData(userId, file, time.Now().UTC())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Remove(*out)
+		os.Exit(1)
+	}
+	fmt.Printf("exported the data of user %v to %v\n", userId, *out)
+}
@@ -0,0 +1,97 @@
+// Command import-app-database imports the SQLite database of the tracking app (timeasy.db) into the server for a
+// user, e.g. for users who tracked their time offline before the sync existed. The ids of the app are kept, so a
+// later sync of the app does not duplicate anything. The database of the server is configured like the server, by
+// the TIMEASY_ environment variables or the config file in TIMEASY_CONFIG.
+//
+// Usage: import-app-database -user <user id> -file timeasy.db [-dry-run]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+	"timeasy-server/pkg/configuration"
+	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/importer"
+	"timeasy-server/pkg/notification"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gofrs/uuid"
+)
+
+var databaseService database.DatabaseService
+
+func main() {
+	userParam := flag.String("user", "", "id of the user the projects and time entries are imported for")
+	file := flag.String("file", "", "the database file of the app (timeasy.db)")
+	dryRun := flag.Bool("dry-run", false, "only validate the entries without importing anything")
+	flag.Parse() // also initializes the glog flags
+
+	userId, err := uuid.FromString(*userParam)
+	if err != nil || *file == "" {
+		fmt.Fprintln(os.Stderr, "usage: import-app-database -user <user id> -file timeasy.db [-dry-run]")
+		os.Exit(2)
+	}
+	appDatabase, recordErrors, err := importer.ReadAppDatabase(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	configuration, err := configuration.ParseConfiguration(nil)
+	if err != nil {
+		panic(err)
+	}
+	err = databaseService.Init(configuration.DbHost, configuration.DbName, configuration.DbUser,
+		configuration.DbPassword, configuration.DbPort)
+	if err != nil {
+		panic(err)
+	}
+	importUsecase := newImportUsecase(configuration.WebhookAllowPrivateNetworks)
+
+	result, err := importUsecase.ImportAppDatabase(userId, appDatabase, recordErrors, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, recordError := range result.Errors {
+		fmt.Fprintln(os.Stderr, recordError.Error())
+	}
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(os.Stderr, "nothing imported, %d errors\n", len(result.Errors))
+		os.Exit(1)
+	}
+	verb := "imported"
+	if result.DryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%v %d time entries, %d skipped because they exist already\n", verb, result.Imported, result.Skipped)
+	for _, project := range result.NewProjects {
+		fmt.Printf("new project: %v\n", project)
+	}
+}
+
+// newImportUsecase wires the usecases the import depends on like the server does. Notifications are only stored in
+// the inbox.
+func newImportUsecase(webhookAllowPrivateNetworks bool) usecase.ImportUsecase {
+	notificationRepository := database.NewGormNotificationRepository(databaseService.Database)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepository, map[string]notification.Channel{
+		model.NotificationChannelInbox:   notification.NewInboxChannel(notificationRepository),
+		model.NotificationChannelWebhook: notification.NewWebhookChannel(10*time.Second, webhookAllowPrivateNetworks),
+	})
+	teamRepository := database.NewGormTeamRepository(databaseService.Database)
+	teamUsecase := usecase.NewTeamUsecase(teamRepository, notificationUsecase)
+	projectUsecase := usecase.NewProjectUsecase(database.NewGormProjectRepository(databaseService.Database, teamRepository),
+		teamUsecase)
+	timesheetUsecase := usecase.NewTimesheetUsecase(database.NewGormTimesheetRepository(databaseService.Database),
+		teamUsecase, notificationUsecase)
+	periodLockUsecase := usecase.NewPeriodLockUsecase(database.NewGormPeriodLockRepository(databaseService.Database),
+		teamUsecase)
+	timeEntryUsecase := usecase.NewTimeEntryUsecase(database.NewGormTimeEntryRepository(databaseService.Database),
+		projectUsecase, timesheetUsecase, periodLockUsecase)
+	syncUsecase := usecase.NewSyncUsecase(database.NewGormSyncRepository(databaseService.Database), timeEntryUsecase)
+	return usecase.NewImportUsecase(database.NewGormImportedTimeEntryRepository(databaseService.Database),
+		timeEntryUsecase, projectUsecase, teamUsecase, syncUsecase)
+}
@@ -1,15 +1,34 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"timeasy-server/pkg/configuration"
 	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/event"
+	"timeasy-server/pkg/job"
+	"timeasy-server/pkg/notification"
 	"timeasy-server/pkg/transport/rest"
 	"timeasy-server/pkg/usecase"
+	"timeasy-server/pkg/webhook"
+
+	"github.com/golang/glog"
 )
 
 var databaseService database.DatabaseService
 
+const shutdownTimeout = 30 * time.Second
+
+// notificationQueueSize limits the emails and webhook notifications that wait to be sent.
+const notificationQueueSize = 1000
+
 func main() {
 	configuration, err := configuration.GetConfiguration()
 	if err != nil {
@@ -27,19 +46,180 @@ func main() {
 	tokenVerifier := rest.NewKeycloakTokenVerifier(configuration.KeycloakHost, configuration.KeycloakRealm)
 	authMiddleware := rest.NewJwtAuthMiddleware(tokenVerifier)
 
+	notificationRepository := database.NewGormNotificationRepository(databaseService.Database)
+	// Emails and webhooks are sent in the background, the inbox is written right away:
+	webhookChannel := notification.NewAsyncChannel(model.NotificationChannelWebhook, notification.NewWebhookChannel(10*time.Second,
+		configuration.WebhookAllowPrivateNetworks), notificationQueueSize)
+	asyncChannels := []notification.AsyncChannel{webhookChannel}
+	notificationChannels := map[string]notification.Channel{
+		model.NotificationChannelInbox:   notification.NewInboxChannel(notificationRepository),
+		model.NotificationChannelWebhook: webhookChannel,
+	}
+	if configuration.SmtpHost != "" {
+		emailChannel := notification.NewAsyncChannel(model.NotificationChannelEmail, notification.NewSmtpChannel(configuration.SmtpHost,
+			configuration.SmtpPort, configuration.SmtpUser, configuration.SmtpPassword, configuration.SmtpFrom), notificationQueueSize)
+		asyncChannels = append(asyncChannels, emailChannel)
+		notificationChannels[model.NotificationChannelEmail] = emailChannel
+	}
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepository, notificationChannels)
+	notificationHandler := rest.NewNotificationHandler(tokenVerifier, notificationUsecase)
+
 	teamRepository := database.NewGormTeamRepository(databaseService.Database)
-	teamUsecase := usecase.NewTeamUsecase(teamRepository)
+	teamUsecase := usecase.NewTeamUsecase(teamRepository, notificationUsecase)
 	teamHandler := rest.NewTeamHandler(tokenVerifier, teamUsecase)
 
 	projectUsecase := usecase.NewProjectUsecase(database.NewGormProjectRepository(databaseService.Database, teamRepository), teamUsecase)
 	projectHandler := rest.NewProjectHandler(tokenVerifier, projectUsecase, teamUsecase)
 
-	timeEntryUsecase := usecase.NewTimeEntryUsecase(database.NewGormTimeEntryRepository(databaseService.Database), projectUsecase)
-	timeEntryHandler := rest.NewTimeEntryHandler(tokenVerifier, timeEntryUsecase)
+	timesheetUsecase := usecase.NewTimesheetUsecase(database.NewGormTimesheetRepository(databaseService.Database), teamUsecase,
+		notificationUsecase)
+	timesheetHandler := rest.NewTimesheetHandler(tokenVerifier, timesheetUsecase, teamUsecase)
+
+	periodLockUsecase := usecase.NewPeriodLockUsecase(database.NewGormPeriodLockRepository(databaseService.Database), teamUsecase)
+	periodLockHandler := rest.NewPeriodLockHandler(tokenVerifier, periodLockUsecase, teamUsecase)
+
+	timeEntryUsecase := usecase.NewTimeEntryUsecase(database.NewGormTimeEntryRepository(databaseService.Database), projectUsecase,
+		timesheetUsecase, periodLockUsecase)
+	complianceUsecase := usecase.NewComplianceUsecase(timeEntryUsecase, teamUsecase)
+	complianceHandler := rest.NewComplianceHandler(tokenVerifier, complianceUsecase, teamUsecase)
+	timeEntryHandler := rest.NewTimeEntryHandler(tokenVerifier, timeEntryUsecase, complianceUsecase)
+
+	syncUsecase := usecase.NewSyncUsecase(database.NewGormSyncRepository(databaseService.Database), timeEntryUsecase)
+	erasureUsecase := usecase.NewErasureUsecase(database.NewGormErasureRepository(databaseService.Database),
+		notificationUsecase, configuration.ErasureGracePeriod)
+	erasureHandler := rest.NewErasureHandler(tokenVerifier, erasureUsecase)
+	syncHandler := rest.NewSyncHandler(tokenVerifier, syncUsecase, erasureUsecase)
+
+	absenceUsecase := usecase.NewAbsenceUsecase(database.NewGormAbsenceRepository(databaseService.Database), teamUsecase,
+		notificationUsecase)
+	absenceHandler := rest.NewAbsenceHandler(tokenVerifier, absenceUsecase, teamUsecase)
+
+	reportUsecase := usecase.NewReportUsecase(timeEntryUsecase, teamUsecase, absenceUsecase)
+	reportHandler := rest.NewReportHandler(tokenVerifier, reportUsecase, teamUsecase)
+
+	holidayUsecase := usecase.NewHolidayUsecase(database.NewGormHolidayCalendarRepository(databaseService.Database), teamUsecase)
+	holidayHandler := rest.NewHolidayHandler(tokenVerifier, holidayUsecase, teamUsecase)
+
+	workingTimeRepository := database.NewGormWorkingTimeModelRepository(databaseService.Database)
+	workingTimeUsecase := usecase.NewWorkingTimeUsecase(workingTimeRepository, teamUsecase, absenceUsecase, holidayUsecase)
+	workingTimeHandler := rest.NewWorkingTimeHandler(tokenVerifier, workingTimeUsecase, teamUsecase)
 
-	syncUsecase := usecase.NewSyncUsecase(database.NewGormSyncRepository(databaseService.Database))
-	syncHandler := rest.NewSyncHandler(tokenVerifier, syncUsecase)
+	overtimeUsecase := usecase.NewOvertimeUsecase(database.NewGormOvertimeRepository(databaseService.Database), timeEntryUsecase,
+		workingTimeUsecase)
+	overtimeHandler := rest.NewOvertimeHandler(tokenVerifier, overtimeUsecase, teamUsecase)
 
-	router := rest.SetupRouter(authMiddleware, teamHandler, projectHandler, timeEntryHandler, syncHandler)
-	router.Run()
+	missingTimeUsecase := usecase.NewMissingTimeUsecase(timeEntryUsecase, workingTimeUsecase, teamUsecase)
+	missingTimeHandler := rest.NewMissingTimeHandler(tokenVerifier, missingTimeUsecase, teamUsecase)
+
+	runningTimerUsecase := usecase.NewRunningTimerUsecase(database.NewGormRunningTimerRepository(databaseService.Database),
+		timeEntryUsecase, teamUsecase, workingTimeUsecase, notificationUsecase, configuration.RunningTimerThreshold)
+	runningTimerHandler := rest.NewRunningTimerHandler(tokenVerifier, runningTimerUsecase, teamUsecase)
+
+	webhookUsecase := usecase.NewWebhookUsecase(database.NewGormWebhookRepository(databaseService.Database), projectUsecase,
+		webhook.NewHttpSender(10*time.Second, configuration.WebhookAllowPrivateNetworks))
+	webhookHandler := rest.NewWebhookHandler(tokenVerifier, webhookUsecase, teamUsecase)
+
+	exportUsecase := usecase.NewExportUsecase(timeEntryUsecase, projectUsecase, teamUsecase)
+	exportHandler := rest.NewExportHandler(tokenVerifier, exportUsecase, teamUsecase)
+	pdfReportUsecase := usecase.NewPdfReportUsecase(database.NewGormReportTemplateRepository(databaseService.Database),
+		exportUsecase, projectUsecase, teamUsecase)
+	pdfReportHandler := rest.NewPdfReportHandler(tokenVerifier, pdfReportUsecase, teamUsecase)
+
+	calendarFeedUsecase := usecase.NewCalendarFeedUsecase(database.NewGormCalendarFeedRepository(databaseService.Database),
+		exportUsecase)
+	calendarFeedHandler := rest.NewCalendarFeedHandler(tokenVerifier, calendarFeedUsecase)
+	calendarFeedAuthMiddleware := rest.NewCalendarFeedAuthMiddleware(calendarFeedUsecase)
+
+	importUsecase := usecase.NewImportUsecase(database.NewGormImportedTimeEntryRepository(databaseService.Database),
+		timeEntryUsecase, projectUsecase, teamUsecase, syncUsecase)
+	importHandler := rest.NewImportHandler(tokenVerifier, importUsecase, teamUsecase)
+
+	dataExportUsecase := usecase.NewDataExportUsecase(database.NewGormDataExportRepository(databaseService.Database),
+		notificationUsecase, configuration.DataExportRetention)
+	dataExportHandler := rest.NewDataExportHandler(tokenVerifier, dataExportUsecase)
+
+	invoiceUsecase := usecase.NewInvoiceUsecase(database.NewGormInvoiceRepository(databaseService.Database), projectUsecase,
+		teamUsecase)
+	invoiceHandler := rest.NewInvoiceHandler(tokenVerifier, invoiceUsecase, teamUsecase)
+
+	eventBus := event.NewEventBus(database.NewGormOutboxRepository(databaseService.Database))
+	err = eventBus.Subscribe("webhooks", func(ctx context.Context, metadata event.Metadata, domainEvent model.DomainEvent) error {
+		return webhookUsecase.EnqueueEvent(metadata.EventID, metadata.OccurredAt, domainEvent)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	scheduler := job.NewScheduler(database.NewGormJobRunRepository(databaseService.Database))
+	err = scheduler.Register(job.RunningTimerJobName, fmt.Sprintf("@every %v", configuration.RunningTimerCheckInterval),
+		job.NewRunningTimerJob(runningTimerUsecase))
+	if err != nil {
+		panic(err)
+	}
+	err = scheduler.Register(job.WebhookDeliveryJobName, "@every 1m", job.NewWebhookDeliveryJob(webhookUsecase))
+	if err != nil {
+		panic(err)
+	}
+	err = scheduler.Register(job.DataExportJobName, "@every 1m", job.NewDataExportJob(dataExportUsecase))
+	if err != nil {
+		panic(err)
+	}
+	err = scheduler.Register(job.ErasureJobName, "@hourly", job.NewErasureJob(erasureUsecase))
+	if err != nil {
+		panic(err)
+	}
+	err = scheduler.Register(job.OutboxCleanupJobName, "@daily", job.NewOutboxCleanupJob(eventBus, configuration.EventRetention))
+	if err != nil {
+		panic(err)
+	}
+	jobHandler := rest.NewJobHandler(tokenVerifier, scheduler)
+
+	router := rest.SetupRouter(authMiddleware, calendarFeedAuthMiddleware, teamHandler, projectHandler, timeEntryHandler, syncHandler,
+		reportHandler, workingTimeHandler, absenceHandler, holidayHandler, timesheetHandler,
+		periodLockHandler, complianceHandler, overtimeHandler, missingTimeHandler, runningTimerHandler, notificationHandler,
+		jobHandler, webhookHandler, exportHandler, pdfReportHandler, calendarFeedHandler, importHandler,
+		dataExportHandler, erasureHandler, invoiceHandler)
+
+	server := &http.Server{
+		Addr:    getListenAddress(),
+		Handler: router,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			glog.Fatalf("the server failed: %v", err)
+		}
+	}()
+	scheduler.Start()
+	eventBus.Start(configuration.EventPublishInterval)
+
+	// Wait for the signal to shut down, then finish the running requests and jobs:
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	glog.Info("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		glog.Errorf("shutting down the server failed: %v", err)
+	}
+	if err := scheduler.Stop(ctx); err != nil {
+		glog.Errorf("stopping the background jobs failed: %v", err)
+	}
+	if err := eventBus.Stop(ctx); err != nil {
+		glog.Errorf("stopping the event bus failed: %v", err)
+	}
+	for _, channel := range asyncChannels {
+		if err := channel.Stop(ctx); err != nil {
+			glog.Errorf("sending the queued notifications failed: %v", err)
+		}
+	}
+	glog.Flush()
+}
+
+// getListenAddress uses the port from the environment variable PORT like gin does, 8080 by default.
+func getListenAddress() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return ":8080"
 }
@@ -0,0 +1,161 @@
+package compliance
+
+import (
+	"sort"
+	"time"
+	"timeasy-server/pkg/domain/model"
+)
+
+// Limits of the German working time law (Arbeitszeitgesetz).
+const (
+	// MaxWorkingTimePerDay is the longest allowed working time of a day (§3)
+	MaxWorkingTimePerDay = 10 * time.Hour
+	// BreakRequiredAfter is the working time after which a break of ShortBreak is required (§4)
+	BreakRequiredAfter = 6 * time.Hour
+	ShortBreak         = 30 * time.Minute
+	// LongBreakRequiredAfter is the working time after which a break of LongBreak is required (§4)
+	LongBreakRequiredAfter = 9 * time.Hour
+	LongBreak              = 45 * time.Minute
+	// MinBreakDuration is the shortest interruption that counts as a break (§4)
+	MinBreakDuration = 15 * time.Minute
+	// MinRestPeriod is the shortest allowed rest between two working days (§5)
+	MinRestPeriod = 11 * time.Hour
+)
+
+type workingPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+type workingDay struct {
+	day     time.Time
+	periods []workingPeriod
+}
+
+// CheckTimeEntries checks the time entries of a single user against the German working time law and returns the
+// violations sorted by day. Overlapping entries are merged, running entries are ignored. An entry belongs to the day
+// (UTC) it starts on, the rest period is checked between the last entry of a day and the first entry of the next day
+// with entries.
+func CheckTimeEntries(timeEntries []model.TimeEntry) []model.ComplianceViolation {
+	var violations []model.ComplianceViolation
+	var previousDay *workingDay
+	workingDays := groupByDay(mergeTimeEntries(timeEntries))
+	for i := range workingDays {
+		currentDay := &workingDays[i]
+		userId := timeEntries[0].UserId
+		workingTime, breaks := currentDay.workingTimeAndBreaks()
+		if workingTime > MaxWorkingTimePerDay {
+			violations = append(violations, model.ComplianceViolation{
+				UserID:   userId,
+				Day:      currentDay.day,
+				Type:     model.ViolationMaxWorkingTimeExceeded,
+				Actual:   workingTime,
+				Required: MaxWorkingTimePerDay,
+			})
+		}
+		if requiredBreak := RequiredBreak(workingTime); breaks < requiredBreak {
+			violations = append(violations, model.ComplianceViolation{
+				UserID:   userId,
+				Day:      currentDay.day,
+				Type:     model.ViolationBreakTooShort,
+				Actual:   breaks,
+				Required: requiredBreak,
+			})
+		}
+		if previousDay != nil {
+			rest := currentDay.periods[0].start.Sub(previousDay.periods[len(previousDay.periods)-1].end)
+			if rest < MinRestPeriod {
+				violations = append(violations, model.ComplianceViolation{
+					UserID:   userId,
+					Day:      currentDay.day,
+					Type:     model.ViolationRestPeriodTooShort,
+					Actual:   rest,
+					Required: MinRestPeriod,
+				})
+			}
+		}
+		previousDay = currentDay
+	}
+	return violations
+}
+
+// RequiredBreak returns the total break that is required for the given working time of a day.
+func RequiredBreak(workingTime time.Duration) time.Duration {
+	switch {
+	case workingTime > LongBreakRequiredAfter:
+		return LongBreak
+	case workingTime > BreakRequiredAfter:
+		return ShortBreak
+	default:
+		return 0
+	}
+}
+
+// MissingBreaks returns for each day (UTC) of the time entries of a single user the part of the required break that
+// was not taken. Days on which enough break was taken are omitted.
+func MissingBreaks(timeEntries []model.TimeEntry) map[time.Time]time.Duration {
+	missingBreaks := make(map[time.Time]time.Duration)
+	for _, workingDay := range groupByDay(mergeTimeEntries(timeEntries)) {
+		workingTime, breaks := workingDay.workingTimeAndBreaks()
+		if requiredBreak := RequiredBreak(workingTime); breaks < requiredBreak {
+			missingBreaks[workingDay.day] = requiredBreak - breaks
+		}
+	}
+	return missingBreaks
+}
+
+// workingTimeAndBreaks sums up the working periods of the day and the interruptions between them that are long
+// enough to count as a break.
+func (workingDay *workingDay) workingTimeAndBreaks() (time.Duration, time.Duration) {
+	var workingTime, breaks time.Duration
+	for i, period := range workingDay.periods {
+		workingTime += period.end.Sub(period.start)
+		if i > 0 {
+			interruption := period.start.Sub(workingDay.periods[i-1].end)
+			if interruption >= MinBreakDuration {
+				breaks += interruption
+			}
+		}
+	}
+	return workingTime, breaks
+}
+
+// mergeTimeEntries returns the sorted working periods of the finished time entries. Overlapping or adjacent entries
+// are merged into one period.
+func mergeTimeEntries(timeEntries []model.TimeEntry) []workingPeriod {
+	var periods []workingPeriod
+	for _, timeEntry := range timeEntries {
+		if timeEntry.Duration() > 0 {
+			periods = append(periods, workingPeriod{start: timeEntry.StartTime.UTC(), end: timeEntry.EndTime.UTC()})
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].start.Before(periods[j].start)
+	})
+	var merged []workingPeriod
+	for _, period := range periods {
+		last := len(merged) - 1
+		if last >= 0 && !period.start.After(merged[last].end) {
+			if period.end.After(merged[last].end) {
+				merged[last].end = period.end
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+	return merged
+}
+
+func groupByDay(periods []workingPeriod) []workingDay {
+	var workingDays []workingDay
+	for _, period := range periods {
+		day := time.Date(period.start.Year(), period.start.Month(), period.start.Day(), 0, 0, 0, 0, time.UTC)
+		last := len(workingDays) - 1
+		if last >= 0 && workingDays[last].day.Equal(day) {
+			workingDays[last].periods = append(workingDays[last].periods, period)
+			continue
+		}
+		workingDays = append(workingDays, workingDay{day: day, periods: []workingPeriod{period}})
+	}
+	return workingDays
+}
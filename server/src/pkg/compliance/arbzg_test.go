@@ -0,0 +1,100 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckTimeEntriesWithoutViolations(t *testing.T) {
+	violations := CheckTimeEntries([]model.TimeEntry{
+		entry(2023, 9, 4, 8, 0, 4*time.Hour),
+		entry(2023, 9, 4, 12, 30, 4*time.Hour),
+		entry(2023, 9, 5, 8, 0, 6*time.Hour),
+	})
+	assert.Equal(t, 0, len(violations))
+}
+
+func Test_CheckTimeEntriesBreakTooShort(t *testing.T) {
+	violations := CheckTimeEntries([]model.TimeEntry{
+		entry(2023, 9, 4, 8, 0, 4*time.Hour),
+		// interruptions of 10 minutes do not count as break:
+		entry(2023, 9, 4, 12, 10, 2*time.Hour+30*time.Minute),
+		entry(2023, 9, 4, 14, 50, 10*time.Minute),
+	})
+	assert.Equal(t, 1, len(violations))
+	assert.Equal(t, model.ViolationBreakTooShort, violations[0].Type)
+	assert.Equal(t, time.Duration(0), violations[0].Actual)
+	assert.Equal(t, ShortBreak, violations[0].Required)
+}
+
+func Test_CheckTimeEntriesLongBreakAfterNineHours(t *testing.T) {
+	violations := CheckTimeEntries([]model.TimeEntry{
+		entry(2023, 9, 4, 7, 0, 5*time.Hour),
+		entry(2023, 9, 4, 12, 30, 4*time.Hour+30*time.Minute),
+	})
+	assert.Equal(t, 1, len(violations))
+	assert.Equal(t, model.ViolationBreakTooShort, violations[0].Type)
+	assert.Equal(t, 30*time.Minute, violations[0].Actual)
+	assert.Equal(t, LongBreak, violations[0].Required)
+}
+
+func Test_CheckTimeEntriesMaxWorkingTimeExceeded(t *testing.T) {
+	violations := CheckTimeEntries([]model.TimeEntry{
+		entry(2023, 9, 4, 6, 0, 6*time.Hour),
+		entry(2023, 9, 4, 13, 0, 5*time.Hour),
+		// overlapping entries are only counted once:
+		entry(2023, 9, 4, 14, 0, time.Hour),
+	})
+	assert.Equal(t, 1, len(violations))
+	assert.Equal(t, model.ViolationMaxWorkingTimeExceeded, violations[0].Type)
+	assert.Equal(t, 11*time.Hour, violations[0].Actual)
+	assert.Equal(t, date(2023, 9, 4), violations[0].Day)
+}
+
+func Test_CheckTimeEntriesRestPeriodTooShort(t *testing.T) {
+	violations := CheckTimeEntries([]model.TimeEntry{
+		entry(2023, 9, 4, 14, 0, 6*time.Hour),
+		entry(2023, 9, 5, 6, 0, 4*time.Hour),
+		// running entries are ignored:
+		{StartTime: time.Date(2023, 9, 6, 0, 30, 0, 0, time.UTC)},
+	})
+	assert.Equal(t, 1, len(violations))
+	assert.Equal(t, model.ViolationRestPeriodTooShort, violations[0].Type)
+	assert.Equal(t, 10*time.Hour, violations[0].Actual)
+	assert.Equal(t, date(2023, 9, 5), violations[0].Day)
+}
+
+func Test_RequiredBreak(t *testing.T) {
+	assert.Equal(t, time.Duration(0), RequiredBreak(6*time.Hour))
+	assert.Equal(t, ShortBreak, RequiredBreak(6*time.Hour+time.Minute))
+	assert.Equal(t, ShortBreak, RequiredBreak(9*time.Hour))
+	assert.Equal(t, LongBreak, RequiredBreak(9*time.Hour+time.Minute))
+}
+
+func Test_MissingBreaks(t *testing.T) {
+	missingBreaks := MissingBreaks([]model.TimeEntry{
+		entry(2023, 9, 4, 8, 0, 4*time.Hour),
+		entry(2023, 9, 4, 12, 20, 3*time.Hour),
+		entry(2023, 9, 5, 7, 0, 10*time.Hour),
+		entry(2023, 9, 6, 8, 0, 5*time.Hour),
+	})
+	assert.Equal(t, 2, len(missingBreaks))
+	assert.Equal(t, 10*time.Minute, missingBreaks[date(2023, 9, 4)])
+	assert.Equal(t, LongBreak, missingBreaks[date(2023, 9, 5)])
+}
+
+func entry(year int, month time.Month, day int, hour int, minute int, duration time.Duration) model.TimeEntry {
+	startTime := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	return model.TimeEntry{
+		StartTime: startTime,
+		EndTime:   startTime.Add(duration),
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
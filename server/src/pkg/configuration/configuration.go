@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/peterbourgon/ff"
 )
@@ -17,22 +18,64 @@ type Configuration struct {
 	DbPassword    string
 	KeycloakHost  string
 	KeycloakRealm string
+	// RunningTimerThreshold is the time after which a running time entry is considered as forgotten
+	RunningTimerThreshold time.Duration
+	// RunningTimerCheckInterval defines how often the server looks for forgotten running time entries
+	RunningTimerCheckInterval time.Duration
+	// SmtpHost is the mail server for notifications, without host no emails are sent
+	SmtpHost     string
+	SmtpPort     int
+	SmtpUser     string
+	SmtpPassword string
+	SmtpFrom     string
+	// EventPublishInterval defines how often the domain events of the outbox are published
+	EventPublishInterval time.Duration
+	// EventRetention is the time after which published domain events are removed from the outbox
+	EventRetention time.Duration
+	// DataExportRetention is the time after which the files of personal data exports expire and are removed
+	DataExportRetention time.Duration
+	// ErasureGracePeriod is the time between the request of a data erasure and the erasure, within it the request can
+	// be cancelled
+	ErasureGracePeriod time.Duration
+	// WebhookAllowPrivateNetworks allows webhooks to loopback, link-local and private addresses, e.g. if the server
+	// and the receivers of its webhooks run in the same private network. Any user could reach internal services then.
+	WebhookAllowPrivateNetworks bool
 }
 
+// GetConfiguration reads the configuration of the server from the command line, the environment (prefix TIMEASY_)
+// and the optional config file.
 func GetConfiguration() (Configuration, error) {
+	return ParseConfiguration(os.Args[1:])
+}
+
+// ParseConfiguration reads the configuration from the arguments, the environment and the optional config file. Tools
+// with their own command line pass no arguments, they are configured by the environment.
+func ParseConfiguration(args []string) (Configuration, error) {
 	fs := flag.NewFlagSet("timeasy", flag.ContinueOnError)
 	var (
-		dbHost        = fs.String("database-host", "localhost", "database host")
-		dbPort        = fs.String("database-port", "5432", "database port")
-		dbName        = fs.String("database-name", "timeasy", "database name")
-		dbUser        = fs.String("database-user", "dbuser", "database user")
-		dbPassword    = fs.String("database-password", "dbpassword", "database password")
-		keycloakHost  = fs.String("keycloak-host", "http://localhost:8180", "keycloak host")
-		keycloakRealm = fs.String("keycloak-realm", "timeasy", "keycloak realm")
-		_             = fs.String("config", "", "config file (optional)")
+		dbHost          = fs.String("database-host", "localhost", "database host")
+		dbPort          = fs.String("database-port", "5432", "database port")
+		dbName          = fs.String("database-name", "timeasy", "database name")
+		dbUser          = fs.String("database-user", "dbuser", "database user")
+		dbPassword      = fs.String("database-password", "dbpassword", "database password")
+		keycloakHost    = fs.String("keycloak-host", "http://localhost:8180", "keycloak host")
+		keycloakRealm   = fs.String("keycloak-realm", "timeasy", "keycloak realm")
+		timerThreshold  = fs.String("running-timer-threshold", "12h", "time after which a running time entry is stopped or flagged")
+		timerInterval   = fs.String("running-timer-check-interval", "15m", "interval of the check for forgotten running time entries")
+		smtpHost        = fs.String("smtp-host", "", "mail server for notifications (optional)")
+		smtpPort        = fs.String("smtp-port", "25", "mail server port")
+		smtpUser        = fs.String("smtp-user", "", "mail server user (optional)")
+		smtpPassword    = fs.String("smtp-password", "", "mail server password")
+		smtpFrom        = fs.String("smtp-from", "timeasy@localhost", "sender address of notification emails")
+		eventInterval   = fs.String("event-publish-interval", "2s", "interval in which the domain events are published")
+		eventRetention  = fs.String("event-retention", "168h", "time after which published domain events are removed")
+		exportRetention = fs.String("data-export-retention", "168h", "time after which personal data exports expire")
+		erasureGrace    = fs.String("erasure-grace-period", "336h", "time after which requested data erasures are done")
+		webhookPrivate  = fs.String("webhook-allow-private-networks", "false", "allow webhooks to private network addresses")
+		_               = fs.String("config", "", "config file (optional)")
 	)
 
-	ff.Parse(fs, os.Args[1:],
+	ff.Parse(fs, args,
 		ff.WithEnvVarPrefix("TIMEASY"),
 		ff.WithConfigFileFlag("config"),
 		ff.WithConfigFileParser(ff.PlainParser),
@@ -50,5 +93,41 @@ func GetConfiguration() (Configuration, error) {
 	configuration.DbPort = port
 	configuration.KeycloakHost = *keycloakHost
 	configuration.KeycloakRealm = *keycloakRealm
+	configuration.RunningTimerThreshold, err = time.ParseDuration(*timerThreshold)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified running timer threshold is invalid: %w", err)
+	}
+	configuration.RunningTimerCheckInterval, err = time.ParseDuration(*timerInterval)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified running timer check interval is invalid: %w", err)
+	}
+	configuration.SmtpHost = *smtpHost
+	configuration.SmtpPort, err = strconv.Atoi(*smtpPort)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified smtp port is invalid: %w", err)
+	}
+	configuration.SmtpUser = *smtpUser
+	configuration.SmtpPassword = *smtpPassword
+	configuration.SmtpFrom = *smtpFrom
+	configuration.EventPublishInterval, err = time.ParseDuration(*eventInterval)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified event publish interval is invalid: %w", err)
+	}
+	configuration.EventRetention, err = time.ParseDuration(*eventRetention)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified event retention is invalid: %w", err)
+	}
+	configuration.DataExportRetention, err = time.ParseDuration(*exportRetention)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified data export retention is invalid: %w", err)
+	}
+	configuration.ErasureGracePeriod, err = time.ParseDuration(*erasureGrace)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified erasure grace period is invalid: %w", err)
+	}
+	configuration.WebhookAllowPrivateNetworks, err = strconv.ParseBool(*webhookPrivate)
+	if err != nil {
+		return configuration, fmt.Errorf("the specified webhook-allow-private-networks is invalid: %w", err)
+	}
 	return configuration, nil
 }
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	"gorm.io/gorm"
+)
+
+// tryAdvisoryLock uses a postgres advisory lock. Advisory locks belong to a database session, so a dedicated
+// connection is taken from the pool and kept until the lock is released.
+func tryAdvisoryLock(db *gorm.DB, lockKey string) (func(), bool, error) {
+	sqlDb, err := db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	ctx := context.Background()
+	connection, err := sqlDb.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	var locked bool
+	err = connection.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockKey).Scan(&locked)
+	if err != nil || !locked {
+		connection.Close()
+		return nil, false, err
+	}
+	unlock := func() {
+		_, err := connection.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockKey)
+		if err != nil {
+			glog.Errorf("releasing the lock %v failed: %v", lockKey, err)
+		}
+		connection.Close()
+	}
+	return unlock, true, nil
+}
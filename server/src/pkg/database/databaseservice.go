@@ -22,6 +22,29 @@ func (databaseService *DatabaseService) Init(host string, databaseName string, u
 	database.AutoMigrate(&model.TimeEntry{})
 	database.AutoMigrate(&model.Team{})
 	database.AutoMigrate(&model.UserTeamAssignment{})
+	database.AutoMigrate(&model.WorkingTimeModel{})
+	database.AutoMigrate(&model.Absence{})
+	database.AutoMigrate(&model.HolidayCalendar{})
+	database.AutoMigrate(&model.CustomHoliday{})
+	database.AutoMigrate(&model.Timesheet{})
+	database.AutoMigrate(&model.PeriodLockChange{})
+	database.AutoMigrate(&model.OvertimeAccount{})
+	database.AutoMigrate(&model.OvertimeCorrection{})
+	database.AutoMigrate(&model.RunningTimerNotice{})
+	database.AutoMigrate(&model.Notification{})
+	database.AutoMigrate(&model.NotificationSettings{})
+	database.AutoMigrate(&model.NotificationTemplate{})
+	database.AutoMigrate(&model.JobRun{})
+	database.AutoMigrate(&model.OutboxEvent{})
+	database.AutoMigrate(&model.WebhookSubscription{})
+	database.AutoMigrate(&model.WebhookDelivery{})
+	database.AutoMigrate(&model.ReportTemplate{})
+	database.AutoMigrate(&model.CalendarFeedToken{})
+	database.AutoMigrate(&model.ImportedTimeEntry{})
+	database.AutoMigrate(&model.DataExport{})
+	database.AutoMigrate(&model.ErasureRequest{})
+	database.AutoMigrate(&model.Invoice{})
+	database.AutoMigrate(&model.InvoiceLine{})
 
 	databaseService.Database = database
 	return nil
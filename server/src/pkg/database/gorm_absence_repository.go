@@ -0,0 +1,79 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormAbsenceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAbsenceRepository(database *gorm.DB) repository.AbsenceRepository {
+	return &gormAbsenceRepository{
+		db: database,
+	}
+}
+
+func (repo *gormAbsenceRepository) AddAbsence(absence *model.Absence) error {
+	if err := repo.db.Create(absence).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormAbsenceRepository) UpdateAbsence(absence *model.Absence) error {
+	if err := repo.db.Save(absence).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormAbsenceRepository) DeleteAbsence(absence *model.Absence) error {
+	if err := repo.db.Delete(absence).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormAbsenceRepository) GetAbsenceById(id uuid.UUID) (*model.Absence, error) {
+	var absence model.Absence
+	if err := repo.db.First(&absence, id).Error; err != nil {
+		return nil, err
+	}
+	return &absence, nil
+}
+
+// GetAbsencesOfUser returns the absences of the user that overlap the interval [from, to). A zero time leaves the
+// respective side of the interval open.
+func (repo *gormAbsenceRepository) GetAbsencesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error) {
+	var absences []model.Absence
+	query := repo.whereOverlapping(repo.db.Order("start_date").Where("user_id=?", userId), from, to)
+	if err := query.Find(&absences).Error; err != nil {
+		return nil, err
+	}
+	return absences, nil
+}
+
+func (repo *gormAbsenceRepository) GetAbsencesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error) {
+	var absences []model.Absence
+	query := repo.whereOverlapping(repo.db.Order("start_date").Where("team_id=?", teamId), from, to)
+	if err := query.Find(&absences).Error; err != nil {
+		return nil, err
+	}
+	return absences, nil
+}
+
+func (repo *gormAbsenceRepository) whereOverlapping(query *gorm.DB, from time.Time, to time.Time) *gorm.DB {
+	if !from.IsZero() {
+		query = query.Where("end_date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("start_date < ?", to)
+	}
+	return query
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormCalendarFeedRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCalendarFeedRepository(database *gorm.DB) repository.CalendarFeedRepository {
+	return &gormCalendarFeedRepository{
+		db: database,
+	}
+}
+
+func (repo *gormCalendarFeedRepository) GetCalendarFeedTokenOfUser(userId uuid.UUID) (*model.CalendarFeedToken, error) {
+	var token model.CalendarFeedToken
+	if err := repo.db.First(&token, "user_id=?", userId).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (repo *gormCalendarFeedRepository) GetCalendarFeedTokenByHash(tokenHash string) (*model.CalendarFeedToken, error) {
+	var token model.CalendarFeedToken
+	if err := repo.db.First(&token, "token_hash=?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (repo *gormCalendarFeedRepository) SaveCalendarFeedToken(token *model.CalendarFeedToken) error {
+	if err := repo.db.Save(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteCalendarFeedToken removes the token permanently, so that the user can get a new one later.
+func (repo *gormCalendarFeedRepository) DeleteCalendarFeedToken(token *model.CalendarFeedToken) error {
+	if err := repo.db.Unscoped().Delete(token).Error; err != nil {
+		return err
+	}
+	return nil
+}
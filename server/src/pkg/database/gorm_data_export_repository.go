@@ -0,0 +1,123 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// dataExportColumns are the columns of a data export without the file
+var dataExportColumns = []string{"id", "created_at", "updated_at", "deleted_at", "user_id", "requested_by", "status", "size",
+	"completed_at", "expires_at", "last_error"}
+
+type gormDataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDataExportRepository(database *gorm.DB) repository.DataExportRepository {
+	return &gormDataExportRepository{
+		db: database,
+	}
+}
+
+// GetPersonalData loads all data of the user. Deleted rows are included, because they are still stored.
+func (repo *gormDataExportRepository) GetPersonalData(userId uuid.UUID) (*model.PersonalData, error) {
+	data := model.PersonalData{UserID: userId}
+	db := repo.db.Unscoped()
+	queries := []*gorm.DB{
+		db.Order("start_time").Find(&data.TimeEntries, "user_id=?", userId),
+		db.Order("name").Find(&data.Projects,
+			"user_id=? OR id IN (SELECT project_id FROM time_entries WHERE user_id=?)", userId, userId),
+		db.Preload("Team", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).Order("created_at").
+			Find(&data.TeamMemberships, "user_id=?", userId),
+		db.Order("start_date").Find(&data.Absences, "user_id=?", userId),
+		db.Order("valid_from").Find(&data.WorkingTimeModels, "user_id=?", userId),
+		db.Order("start_date").Find(&data.Timesheets, "user_id=?", userId),
+		db.Order("start_date").Find(&data.OvertimeAccounts, "user_id=?", userId),
+		db.Order("date").Find(&data.OvertimeCorrections, "user_id=?", userId),
+		db.Order("created_at").Find(&data.Notifications, "user_id=?", userId),
+		db.Find(&data.NotificationSettings, "user_id=?", userId),
+		db.Order("created_at").Find(&data.WebhookSubscriptions, "user_id=?", userId),
+		db.Find(&data.CalendarFeedTokens, "user_id=?", userId),
+		db.Find(&data.ImportedTimeEntries, "user_id=?", userId),
+		db.Order("created_at").Find(&data.RunningTimerNotices, "user_id=?", userId),
+		db.Order("created_at").Find(&data.PeriodLockChanges, "changed_by=?", userId),
+		db.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped().Order("position") }).Order("created_at").
+			Find(&data.Invoices, "issuer_id=? AND team_id IS NULL", userId),
+	}
+	for _, query := range queries {
+		if query.Error != nil {
+			return nil, query.Error
+		}
+	}
+	return &data, nil
+}
+
+func (repo *gormDataExportRepository) CountTimeEntriesOfUser(userId uuid.UUID) (int64, error) {
+	var count int64
+	if err := repo.db.Unscoped().Model(&model.TimeEntry{}).Where("user_id=?", userId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (repo *gormDataExportRepository) AddDataExport(dataExport *model.DataExport) error {
+	if err := repo.db.Create(dataExport).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormDataExportRepository) GetDataExportById(id uuid.UUID) (*model.DataExport, error) {
+	var dataExport model.DataExport
+	if err := repo.db.Select(dataExportColumns).First(&dataExport, "id=?", id).Error; err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+func (repo *gormDataExportRepository) GetDataExportContent(id uuid.UUID) ([]byte, error) {
+	var dataExport model.DataExport
+	if err := repo.db.Select("id", "content").First(&dataExport, "id=?", id).Error; err != nil {
+		return nil, err
+	}
+	return dataExport.Content, nil
+}
+
+func (repo *gormDataExportRepository) GetDataExportsOfUser(userId uuid.UUID) ([]model.DataExport, error) {
+	var dataExports []model.DataExport
+	if err := repo.db.Select(dataExportColumns).Order("created_at desc").
+		Find(&dataExports, "user_id=?", userId).Error; err != nil {
+		return nil, err
+	}
+	return dataExports, nil
+}
+
+func (repo *gormDataExportRepository) GetPendingDataExports(limit int) ([]model.DataExport, error) {
+	var dataExports []model.DataExport
+	if err := repo.db.Select(dataExportColumns).Order("created_at").Where("status=?", model.DataExportStatusPending).
+		Limit(limit).Find(&dataExports).Error; err != nil {
+		return nil, err
+	}
+	return dataExports, nil
+}
+
+// UpdateDataExport stores the export including its file.
+func (repo *gormDataExportRepository) UpdateDataExport(dataExport *model.DataExport) error {
+	if err := repo.db.Save(dataExport).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredDataExports removes the expired exports permanently, so that their files are not kept.
+func (repo *gormDataExportRepository) DeleteExpiredDataExports(now time.Time) (int64, error) {
+	result := repo.db.Unscoped().Where("expires_at <= ?", now).Delete(&model.DataExport{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
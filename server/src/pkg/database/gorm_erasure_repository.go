@@ -0,0 +1,146 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormErasureRepository struct {
+	db *gorm.DB
+}
+
+func NewGormErasureRepository(database *gorm.DB) repository.ErasureRepository {
+	return &gormErasureRepository{
+		db: database,
+	}
+}
+
+func (repo *gormErasureRepository) AddErasureRequest(request *model.ErasureRequest) error {
+	if err := repo.db.Create(request).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormErasureRepository) GetErasureRequestById(id uuid.UUID) (*model.ErasureRequest, error) {
+	var request model.ErasureRequest
+	if err := repo.db.First(&request, "id=?", id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (repo *gormErasureRepository) GetErasureRequestsOfUser(userId uuid.UUID) ([]model.ErasureRequest, error) {
+	var requests []model.ErasureRequest
+	if err := repo.db.Order("created_at desc").Find(&requests, "user_id=?", userId).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (repo *gormErasureRepository) GetErasureRequestsWithStatus(statuses []string) ([]model.ErasureRequest, error) {
+	var requests []model.ErasureRequest
+	if err := repo.db.Order("due_at").Find(&requests, "status IN ?", statuses).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (repo *gormErasureRepository) GetDueErasureRequests(now time.Time) ([]model.ErasureRequest, error) {
+	var requests []model.ErasureRequest
+	if err := repo.db.Order("due_at").Find(&requests, "status=? AND due_at <= ?", model.ErasureStatusConfirmed, now).
+		Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (repo *gormErasureRepository) UpdateErasureRequest(request *model.ErasureRequest) error {
+	if err := repo.db.Save(request).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// EraseUserData deletes the data of the user permanently, including soft deleted rows. Team projects, team webhooks
+// and team invoices the user created are kept for the other members, they and the reviews of the user only lose the
+// reference to the user. The domain events in the outbox and the webhook deliveries containing the user are deleted as well.
+// The completed request with the receipt is saved in the same transaction.
+func (repo *gormErasureRepository) EraseUserData(request *model.ErasureRequest, completedAt time.Time) error {
+	deleted := make(map[string]int64)
+	anonymised := make(map[string]int64)
+	userId := request.UserID
+	userPattern := "%" + userId.String() + "%"
+	return repo.db.Transaction(func(requestTx *gorm.DB) error {
+		tx := requestTx.Unscoped().Session(&gorm.Session{SkipHooks: true})
+		deletions := []struct {
+			table string
+			value interface{}
+			query string
+			args  []interface{}
+		}{
+			{"running_timer_notices", &model.RunningTimerNotice{}, "user_id=?", []interface{}{userId}},
+			{"imported_time_entries", &model.ImportedTimeEntry{}, "user_id=?", []interface{}{userId}},
+			{"time_entries", &model.TimeEntry{}, "user_id=?", []interface{}{userId}},
+			{"projects", &model.Project{}, "user_id=? AND team_id IS NULL", []interface{}{userId}},
+			{"user_team_assignments", &model.UserTeamAssignment{}, "user_id=?", []interface{}{userId}},
+			{"absences", &model.Absence{}, "user_id=?", []interface{}{userId}},
+			{"timesheets", &model.Timesheet{}, "user_id=?", []interface{}{userId}},
+			{"working_time_models", &model.WorkingTimeModel{}, "user_id=?", []interface{}{userId}},
+			{"overtime_accounts", &model.OvertimeAccount{}, "user_id=?", []interface{}{userId}},
+			{"overtime_corrections", &model.OvertimeCorrection{}, "user_id=?", []interface{}{userId}},
+			{"notifications", &model.Notification{}, "user_id=?", []interface{}{userId}},
+			{"notification_settings", &model.NotificationSettings{}, "user_id=?", []interface{}{userId}},
+			{"calendar_feed_tokens", &model.CalendarFeedToken{}, "user_id=?", []interface{}{userId}},
+			{"data_exports", &model.DataExport{}, "user_id=?", []interface{}{userId}},
+			{"webhook_deliveries", &model.WebhookDelivery{},
+				"subscription_id IN (SELECT id FROM webhook_subscriptions WHERE user_id=? AND team_id IS NULL) OR payload LIKE ?",
+				[]interface{}{userId, userPattern}},
+			{"webhook_subscriptions", &model.WebhookSubscription{}, "user_id=? AND team_id IS NULL", []interface{}{userId}},
+			{"outbox_events", &model.OutboxEvent{}, "payload LIKE ?", []interface{}{userPattern}},
+			{"invoice_lines", &model.InvoiceLine{}, "invoice_id IN (SELECT id FROM invoices WHERE issuer_id=?)",
+				[]interface{}{userId}},
+			{"invoices", &model.Invoice{}, "issuer_id=?", []interface{}{userId}},
+		}
+		for _, deletion := range deletions {
+			result := tx.Where(deletion.query, deletion.args...).Delete(deletion.value)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted[deletion.table] += result.RowsAffected
+		}
+
+		anonymisations := []struct {
+			table  string
+			value  interface{}
+			column string
+			query  string
+			to     interface{}
+		}{
+			{"projects", &model.Project{}, "user_id", "user_id=?", uuid.Nil},
+			{"webhook_subscriptions", &model.WebhookSubscription{}, "user_id", "user_id=?", uuid.Nil},
+			{"absences", &model.Absence{}, "reviewed_by", "reviewed_by=?", nil},
+			{"timesheets", &model.Timesheet{}, "reviewed_by", "reviewed_by=?", nil},
+			{"overtime_corrections", &model.OvertimeCorrection{}, "created_by", "created_by=?", uuid.Nil},
+			{"period_lock_changes", &model.PeriodLockChange{}, "changed_by", "changed_by=?", uuid.Nil},
+			{"invoices", &model.Invoice{}, "created_by", "created_by=?", uuid.Nil},
+		}
+		for _, anonymisation := range anonymisations {
+			result := tx.Model(anonymisation.value).Where(anonymisation.query, userId).
+				Update(anonymisation.column, anonymisation.to)
+			if result.Error != nil {
+				return result.Error
+			}
+			anonymised[anonymisation.table] += result.RowsAffected
+		}
+
+		if err := request.Complete(completedAt, deleted, anonymised); err != nil {
+			return err
+		}
+		return requestTx.Save(request).Error
+	})
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormHolidayCalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewGormHolidayCalendarRepository(database *gorm.DB) repository.HolidayCalendarRepository {
+	return &gormHolidayCalendarRepository{
+		db: database,
+	}
+}
+
+func (repo *gormHolidayCalendarRepository) AddHolidayCalendar(calendar *model.HolidayCalendar) error {
+	if err := repo.db.Create(calendar).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormHolidayCalendarRepository) UpdateHolidayCalendar(calendar *model.HolidayCalendar) error {
+	if err := repo.db.Save(calendar).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteHolidayCalendar deletes the calendar with its custom holidays and removes it from all teams and members it is
+// assigned to.
+func (repo *gormHolidayCalendarRepository) DeleteHolidayCalendar(calendar *model.HolidayCalendar) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Team{}).Where("holiday_calendar_id=?", calendar.ID).Update("holiday_calendar_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.UserTeamAssignment{}).Where("holiday_calendar_id=?", calendar.ID).Update("holiday_calendar_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("holiday_calendar_id=?", calendar.ID).Delete(&model.CustomHoliday{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(calendar).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (repo *gormHolidayCalendarRepository) GetHolidayCalendarById(id uuid.UUID) (*model.HolidayCalendar, error) {
+	var calendar model.HolidayCalendar
+	if err := repo.db.First(&calendar, id).Error; err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (repo *gormHolidayCalendarRepository) GetHolidayCalendarsOfTeam(teamId uuid.UUID) ([]model.HolidayCalendar, error) {
+	var calendars []model.HolidayCalendar
+	if err := repo.db.Order("name").Where("team_id=?", teamId).Find(&calendars).Error; err != nil {
+		return nil, err
+	}
+	return calendars, nil
+}
+
+func (repo *gormHolidayCalendarRepository) AddCustomHoliday(customHoliday *model.CustomHoliday) error {
+	if err := repo.db.Create(customHoliday).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormHolidayCalendarRepository) DeleteCustomHoliday(customHoliday *model.CustomHoliday) error {
+	if err := repo.db.Delete(customHoliday).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormHolidayCalendarRepository) GetCustomHolidayById(id uuid.UUID) (*model.CustomHoliday, error) {
+	var customHoliday model.CustomHoliday
+	if err := repo.db.First(&customHoliday, id).Error; err != nil {
+		return nil, err
+	}
+	return &customHoliday, nil
+}
+
+// GetCustomHolidaysOfCalendar returns the custom holidays of the calendar in the interval [from, to). A zero time leaves
+// the respective side of the interval open.
+func (repo *gormHolidayCalendarRepository) GetCustomHolidaysOfCalendar(calendarId uuid.UUID, from time.Time, to time.Time) ([]model.CustomHoliday, error) {
+	var customHolidays []model.CustomHoliday
+	query := repo.db.Order("date").Where("holiday_calendar_id=?", calendarId)
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", to)
+	}
+	if err := query.Find(&customHolidays).Error; err != nil {
+		return nil, err
+	}
+	return customHolidays, nil
+}
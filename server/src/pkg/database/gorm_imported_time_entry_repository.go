@@ -0,0 +1,47 @@
+package database
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormImportedTimeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormImportedTimeEntryRepository(database *gorm.DB) repository.ImportedTimeEntryRepository {
+	return &gormImportedTimeEntryRepository{
+		db: database,
+	}
+}
+
+func (repo *gormImportedTimeEntryRepository) GetImportedTimeEntriesOfUser(userId uuid.UUID, source string) ([]model.ImportedTimeEntry, error) {
+	var importedTimeEntries []model.ImportedTimeEntry
+	if err := repo.db.Where("user_id=? AND source=?", userId, source).Find(&importedTimeEntries).Error; err != nil {
+		return nil, err
+	}
+	return importedTimeEntries, nil
+}
+
+func (repo *gormImportedTimeEntryRepository) ImportTimeEntries(projects []model.Project, timeEntries []model.TimeEntry,
+	importedTimeEntries []model.ImportedTimeEntry) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, project := range projects {
+			if err := tx.Create(&project).Error; err != nil {
+				return err
+			}
+		}
+		for _, timeEntry := range timeEntries {
+			if err := tx.Omit("InvoiceID").Create(&timeEntry).Error; err != nil {
+				return err
+			}
+		}
+		if len(importedTimeEntries) == 0 {
+			return nil
+		}
+		return tx.Create(&importedTimeEntries).Error
+	})
+}
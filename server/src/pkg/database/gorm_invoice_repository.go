@@ -0,0 +1,151 @@
+package database
+
+import (
+	"fmt"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/invoice"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormInvoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormInvoiceRepository(database *gorm.DB) repository.InvoiceRepository {
+	return &gormInvoiceRepository{
+		db: database,
+	}
+}
+
+func (repo *gormInvoiceRepository) GetBillableTimeEntries(filter model.InvoiceFilter) ([]model.BillableTimeEntry, error) {
+	query := repo.db.Preload("Project", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).Joins("JOIN projects ON projects.id = time_entries.project_id").
+		Where("time_entries.invoice_id IS NULL AND time_entries.end_time > time_entries.start_time").
+		Where("time_entries.start_time >= ? AND time_entries.start_time < ?", filter.From, filter.To).
+		// The flag of an imported entry overrides the flag of its project
+		Where("COALESCE((SELECT imported_time_entries.billable FROM imported_time_entries WHERE " +
+			"imported_time_entries.time_entry_id = time_entries.id AND imported_time_entries.deleted_at IS NULL), " +
+			"projects.billable)")
+	if filter.TeamID != nil {
+		query = query.Where("projects.team_id = ?", *filter.TeamID)
+	} else {
+		query = query.Where("time_entries.user_id = ? AND projects.team_id IS NULL", filter.UserID)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("projects.id = ?", *filter.ProjectID)
+	}
+	if filter.Client != "" {
+		query = query.Where("projects.client = ?", filter.Client)
+	}
+	var timeEntries []model.TimeEntry
+	if err := query.Order("time_entries.start_time").Find(&timeEntries).Error; err != nil {
+		return nil, err
+	}
+	if len(timeEntries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(timeEntries))
+	for i, timeEntry := range timeEntries {
+		ids[i] = timeEntry.ID
+	}
+	var importedTimeEntries []model.ImportedTimeEntry
+	if err := repo.db.Select("time_entry_id", "task").Where("time_entry_id IN ?", ids).
+		Find(&importedTimeEntries).Error; err != nil {
+		return nil, err
+	}
+	tasks := make(map[uuid.UUID]string)
+	for _, importedTimeEntry := range importedTimeEntries {
+		tasks[importedTimeEntry.TimeEntryID] = importedTimeEntry.Task
+	}
+	entries := make([]model.BillableTimeEntry, len(timeEntries))
+	for i, timeEntry := range timeEntries {
+		entries[i] = model.BillableTimeEntry{TimeEntry: timeEntry, Task: tasks[timeEntry.ID]}
+	}
+	return entries, nil
+}
+
+// AddInvoice numbers the invoices of an issuer per year of their creation. The transaction holds a lock of the
+// issuer, so concurrent invoices get consecutive numbers.
+func (repo *gormInvoiceRepository) AddInvoice(newInvoice *model.Invoice, timeEntryIds []uuid.UUID) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "invoice-"+newInvoice.IssuerID.String()).
+			Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Unscoped().Model(&model.Invoice{}).
+			Where("issuer_id = ? AND number LIKE ?", newInvoice.IssuerID, fmt.Sprintf("%d-%%", newInvoice.CreatedAt.Year())).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		newInvoice.Number = invoice.FormatNumber(newInvoice.CreatedAt.Year(), int(count)+1)
+		if err := tx.Create(newInvoice).Error; err != nil {
+			return err
+		}
+		var timeEntries []model.TimeEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ? AND invoice_id IS NULL", timeEntryIds).
+			Find(&timeEntries).Error; err != nil {
+			return err
+		}
+		if len(timeEntries) != len(timeEntryIds) {
+			return fmt.Errorf("some of the time entries were invoiced meanwhile, please try again")
+		}
+		return setInvoiceOfTimeEntries(tx, timeEntries, &newInvoice.ID)
+	})
+}
+
+func (repo *gormInvoiceRepository) GetInvoiceById(id uuid.UUID) (*model.Invoice, error) {
+	var invoice model.Invoice
+	if err := repo.db.Preload("Lines", func(tx *gorm.DB) *gorm.DB { return tx.Order("position") }).
+		First(&invoice, "id=?", id).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (repo *gormInvoiceRepository) GetInvoicesOfIssuer(issuerId uuid.UUID) ([]model.Invoice, error) {
+	var invoices []model.Invoice
+	if err := repo.db.Order("created_at desc").Find(&invoices, "issuer_id=?", issuerId).Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (repo *gormInvoiceRepository) GetTimeEntryIdsOfInvoice(id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := repo.db.Model(&model.TimeEntry{}).Where("invoice_id=?", id).Order("start_time").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (repo *gormInvoiceRepository) CancelInvoice(invoice *model.Invoice) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		invoice.Status = model.InvoiceStatusCancelled
+		if err := tx.Model(invoice).Update("status", invoice.Status).Error; err != nil {
+			return err
+		}
+		var timeEntries []model.TimeEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("invoice_id=?", invoice.ID).
+			Find(&timeEntries).Error; err != nil {
+			return err
+		}
+		return setInvoiceOfTimeEntries(tx, timeEntries, nil)
+	})
+}
+
+// setInvoiceOfTimeEntries marks the entries as invoiced or releases them. The entries are updated one by one, so
+// their update time changes and a TimeEntryUpdated event tells the clients and webhooks that they are (un)locked.
+func setInvoiceOfTimeEntries(tx *gorm.DB, timeEntries []model.TimeEntry, invoiceId *uuid.UUID) error {
+	for i := range timeEntries {
+		if err := tx.Model(&timeEntries[i]).Update("invoice_id", invoiceId).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
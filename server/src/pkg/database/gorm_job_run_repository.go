@@ -0,0 +1,58 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type gormJobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewGormJobRunRepository(database *gorm.DB) repository.JobRunRepository {
+	return &gormJobRunRepository{
+		db: database,
+	}
+}
+
+func (repo *gormJobRunRepository) TryLock(name string) (func(), bool, error) {
+	return tryAdvisoryLock(repo.db, "timeasy-job-"+name)
+}
+
+func (repo *gormJobRunRepository) AddJobRun(jobRun *model.JobRun) error {
+	if err := repo.db.Create(jobRun).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormJobRunRepository) UpdateJobRun(jobRun *model.JobRun) error {
+	if err := repo.db.Save(jobRun).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormJobRunRepository) GetJobRun(name string, scheduledAt time.Time) (*model.JobRun, error) {
+	var jobRun model.JobRun
+	if err := repo.db.First(&jobRun, "name=? AND scheduled_at=?", name, scheduledAt).Error; err != nil {
+		return nil, err
+	}
+	return &jobRun, nil
+}
+
+// GetJobRuns returns the latest runs of the job first. Without name the runs of all jobs are returned.
+func (repo *gormJobRunRepository) GetJobRuns(name string, limit int) ([]model.JobRun, error) {
+	var jobRuns []model.JobRun
+	query := repo.db.Order("scheduled_at desc").Limit(limit)
+	if name != "" {
+		query = query.Where("name=?", name)
+	}
+	if err := query.Find(&jobRuns).Error; err != nil {
+		return nil, err
+	}
+	return jobRuns, nil
+}
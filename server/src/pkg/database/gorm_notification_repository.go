@@ -0,0 +1,100 @@
+package database
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormNotificationRepository(database *gorm.DB) repository.NotificationRepository {
+	return &gormNotificationRepository{
+		db: database,
+	}
+}
+
+func (repo *gormNotificationRepository) AddNotification(notification *model.Notification) error {
+	if err := repo.db.Create(notification).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormNotificationRepository) UpdateNotification(notification *model.Notification) error {
+	if err := repo.db.Save(notification).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormNotificationRepository) GetNotificationById(id uuid.UUID) (*model.Notification, error) {
+	var notification model.Notification
+	if err := repo.db.First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// GetNotificationsOfUser returns the inbox of the user, the newest notification first.
+func (repo *gormNotificationRepository) GetNotificationsOfUser(userId uuid.UUID, onlyUnread bool) ([]model.Notification, error) {
+	var notifications []model.Notification
+	query := repo.db.Order("created_at desc").Where("user_id=?", userId)
+	if onlyUnread {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (repo *gormNotificationRepository) GetNotificationSettingsOfUser(userId uuid.UUID) (*model.NotificationSettings, error) {
+	var settings model.NotificationSettings
+	if err := repo.db.First(&settings, "user_id=?", userId).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (repo *gormNotificationRepository) SaveNotificationSettings(settings *model.NotificationSettings) error {
+	if err := repo.db.Save(settings).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormNotificationRepository) GetNotificationTemplate(notificationType string) (*model.NotificationTemplate, error) {
+	var template model.NotificationTemplate
+	if err := repo.db.First(&template, "type=?", notificationType).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (repo *gormNotificationRepository) GetAllNotificationTemplates() ([]model.NotificationTemplate, error) {
+	var templates []model.NotificationTemplate
+	if err := repo.db.Order("type").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (repo *gormNotificationRepository) SaveNotificationTemplate(template *model.NotificationTemplate) error {
+	if err := repo.db.Save(template).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteNotificationTemplate removes the template permanently, so that the type can get a new one later.
+func (repo *gormNotificationRepository) DeleteNotificationTemplate(template *model.NotificationTemplate) error {
+	if err := repo.db.Unscoped().Delete(template).Error; err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type gormOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOutboxRepository(database *gorm.DB) repository.OutboxRepository {
+	return &gormOutboxRepository{
+		db: database,
+	}
+}
+
+func (repo *gormOutboxRepository) TryLock() (func(), bool, error) {
+	return tryAdvisoryLock(repo.db, "timeasy-outbox")
+}
+
+func (repo *gormOutboxRepository) GetPendingOutboxEvents(now time.Time, maxAttempts int, limit int) ([]model.OutboxEvent, error) {
+	var outboxEvents []model.OutboxEvent
+	if err := repo.db.Where("published_at IS NULL AND attempts < ? AND next_attempt_at <= ?", maxAttempts, now).
+		Order("occurred_at").Order("created_at").Limit(limit).Find(&outboxEvents).Error; err != nil {
+		return nil, err
+	}
+	return outboxEvents, nil
+}
+
+func (repo *gormOutboxRepository) UpdateOutboxEvent(outboxEvent *model.OutboxEvent) error {
+	if err := repo.db.Save(outboxEvent).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormOutboxRepository) DeletePublishedOutboxEvents(publishedBefore time.Time) (int64, error) {
+	result := repo.db.Unscoped().Where("published_at < ?", publishedBefore).Delete(&model.OutboxEvent{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
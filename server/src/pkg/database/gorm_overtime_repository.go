@@ -0,0 +1,81 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormOvertimeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOvertimeRepository(database *gorm.DB) repository.OvertimeRepository {
+	return &gormOvertimeRepository{
+		db: database,
+	}
+}
+
+func (repo *gormOvertimeRepository) GetOvertimeAccountOfUser(userId uuid.UUID) (*model.OvertimeAccount, error) {
+	var account model.OvertimeAccount
+	if err := repo.db.First(&account, "user_id=?", userId).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (repo *gormOvertimeRepository) AddOvertimeAccount(account *model.OvertimeAccount) error {
+	if err := repo.db.Create(account).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormOvertimeRepository) UpdateOvertimeAccount(account *model.OvertimeAccount) error {
+	if err := repo.db.Save(account).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormOvertimeRepository) AddOvertimeCorrection(correction *model.OvertimeCorrection) error {
+	if err := repo.db.Create(correction).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormOvertimeRepository) DeleteOvertimeCorrection(correction *model.OvertimeCorrection) error {
+	if err := repo.db.Delete(correction).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormOvertimeRepository) GetOvertimeCorrectionById(id uuid.UUID) (*model.OvertimeCorrection, error) {
+	var correction model.OvertimeCorrection
+	if err := repo.db.First(&correction, id).Error; err != nil {
+		return nil, err
+	}
+	return &correction, nil
+}
+
+// GetOvertimeCorrectionsOfUser returns the corrections of the user on the days in the interval [from, to), sorted by
+// date. A zero time leaves the respective side of the interval open.
+func (repo *gormOvertimeRepository) GetOvertimeCorrectionsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.OvertimeCorrection, error) {
+	var corrections []model.OvertimeCorrection
+	query := repo.db.Order("date").Order("created_at").Where("user_id=?", userId)
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", to)
+	}
+	if err := query.Find(&corrections).Error; err != nil {
+		return nil, err
+	}
+	return corrections, nil
+}
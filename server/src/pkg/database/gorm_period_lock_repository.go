@@ -0,0 +1,40 @@
+package database
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormPeriodLockRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPeriodLockRepository(database *gorm.DB) repository.PeriodLockRepository {
+	return &gormPeriodLockRepository{
+		db: database,
+	}
+}
+
+// SetLockDate stores the lock date of the team and the record of the change in one transaction.
+func (repo *gormPeriodLockRepository) SetLockDate(team *model.Team, change *model.PeriodLockChange) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(team).Update("lock_date", team.LockDate).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (repo *gormPeriodLockRepository) GetPeriodLockChangesOfTeam(teamId uuid.UUID) ([]model.PeriodLockChange, error) {
+	var changes []model.PeriodLockChange
+	if err := repo.db.Order("created_at desc").Where("team_id=?", teamId).Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormReportTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewGormReportTemplateRepository(database *gorm.DB) repository.ReportTemplateRepository {
+	return &gormReportTemplateRepository{
+		db: database,
+	}
+}
+
+func (repo *gormReportTemplateRepository) GetReportTemplateOfTeam(teamId uuid.UUID) (*model.ReportTemplate, error) {
+	var template model.ReportTemplate
+	if err := repo.db.First(&template, "team_id=?", teamId).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (repo *gormReportTemplateRepository) SaveReportTemplate(template *model.ReportTemplate) error {
+	if err := repo.db.Save(template).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteReportTemplate removes the template permanently, so that the team can get a new one later.
+func (repo *gormReportTemplateRepository) DeleteReportTemplate(template *model.ReportTemplate) error {
+	if err := repo.db.Unscoped().Delete(template).Error; err != nil {
+		return err
+	}
+	return nil
+}
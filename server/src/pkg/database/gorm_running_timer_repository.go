@@ -0,0 +1,68 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormRunningTimerRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRunningTimerRepository(database *gorm.DB) repository.RunningTimerRepository {
+	return &gormRunningTimerRepository{
+		db: database,
+	}
+}
+
+// GetRunningTimeEntriesStartedBefore returns all running entries (without end time) of all users that were started
+// before the given time.
+func (repo *gormRunningTimerRepository) GetRunningTimeEntriesStartedBefore(t time.Time) ([]model.TimeEntry, error) {
+	var timeEntries []model.TimeEntry
+	if err := repo.db.Order("start_time").Find(&timeEntries, "(end_time IS NULL OR end_time < start_time) AND start_time < ?",
+		t).Error; err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
+// StopTimeEntry stores the new end time of the entry together with the notice for the user.
+func (repo *gormRunningTimerRepository) StopTimeEntry(timeEntry *model.TimeEntry, notice *model.RunningTimerNotice) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveTimeEntry(tx, timeEntry); err != nil {
+			return err
+		}
+		if err := tx.Create(notice).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (repo *gormRunningTimerRepository) AddRunningTimerNotice(notice *model.RunningTimerNotice) error {
+	if err := repo.db.Create(notice).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormRunningTimerRepository) GetRunningTimerNoticesOfTimeEntry(timeEntryId uuid.UUID) ([]model.RunningTimerNotice, error) {
+	var notices []model.RunningTimerNotice
+	if err := repo.db.Order("created_at").Find(&notices, "time_entry_id=?", timeEntryId).Error; err != nil {
+		return nil, err
+	}
+	return notices, nil
+}
+
+func (repo *gormRunningTimerRepository) UpdateRunningTimerPolicyOfTeam(teamId uuid.UUID, policy string) error {
+	return repo.db.Model(&model.Team{}).Where("id=?", teamId).Update("running_timer_policy", policy).Error
+}
+
+func (repo *gormRunningTimerRepository) UpdateRunningTimerPolicyOfUser(userId uuid.UUID, teamId uuid.UUID, policy string) error {
+	return repo.db.Model(&model.UserTeamAssignment{}).Where("user_id=? AND team_id=?", userId, teamId).
+		Update("running_timer_policy", policy).Error
+}
@@ -49,12 +49,12 @@ func (repo *gormSyncRepository) updateAndDeleteProjects(tx *gorm.DB, data model.
 
 func (repo *gormSyncRepository) updateAndDeleteTimeEntries(tx *gorm.DB, data model.SyncData) error {
 	for _, timeEntry := range data.TimeEntriesToBeUpdated {
-		if err := tx.Save(&timeEntry).Error; err != nil {
+		if err := saveTimeEntry(tx, &timeEntry); err != nil {
 			return err
 		}
 	}
 	for _, timeEntry := range data.TimeEntriesToBeDeleted {
-		if err := tx.Delete(&timeEntry).Error; err != nil {
+		if err := deleteTimeEntry(tx, &timeEntry); err != nil {
 			return err
 		}
 	}
@@ -76,3 +76,11 @@ func (repo *gormSyncRepository) GetUpdatedProjectsOfUser(userId uuid.UUID, since
 	}
 	return updatedProjects, nil
 }
+
+func (repo *gormSyncRepository) GetRunningTimerNoticesOfUser(userId uuid.UUID, sinceWhen time.Time) ([]model.RunningTimerNotice, error) {
+	var notices []model.RunningTimerNotice
+	if err := repo.db.Order("created_at").Find(&notices, "user_id=? AND created_at >= ?", userId, sinceWhen).Error; err != nil {
+		return nil, err
+	}
+	return notices, nil
+}
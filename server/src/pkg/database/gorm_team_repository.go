@@ -40,11 +40,64 @@ func (repo *gormTeamRepository) UpdateTeam(team *model.Team) error {
 	return nil
 }
 
+// DeleteTeam deletes the team together with all user assignments, working time models, holiday calendars, timesheets,
+// absences, the report template, the webhook subscriptions with their deliveries and the history of its lock date. The projects of the team are detached and belong to their owners again. Because
+// their update timestamp changes they are delivered with the next sync.
+// The assignments and projects are changed one by one, so that their domain events are recorded.
 func (repo *gormTeamRepository) DeleteTeam(team *model.Team) error {
-	if err := repo.db.Delete(team).Error; err != nil {
-		return err
-	}
-	return nil
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var assignments []model.UserTeamAssignment
+		if err := tx.Find(&assignments, "team_id=?", team.ID).Error; err != nil {
+			return err
+		}
+		for i := range assignments {
+			if err := tx.Delete(&assignments[i]).Error; err != nil {
+				return err
+			}
+		}
+		var projects []model.Project
+		if err := tx.Find(&projects, "team_id=?", team.ID).Error; err != nil {
+			return err
+		}
+		for i := range projects {
+			if err := tx.Model(&projects[i]).Update("team_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("team_id=?", team.ID).Delete(&model.WorkingTimeModel{}).Error; err != nil {
+			return err
+		}
+		calendarsOfTeam := tx.Model(&model.HolidayCalendar{}).Select("id").Where("team_id=?", team.ID)
+		if err := tx.Where("holiday_calendar_id IN (?)", calendarsOfTeam).Delete(&model.CustomHoliday{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id=?", team.ID).Delete(&model.HolidayCalendar{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id=?", team.ID).Delete(&model.Timesheet{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id=?", team.ID).Delete(&model.PeriodLockChange{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id=?", team.ID).Delete(&model.Absence{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("team_id=?", team.ID).Delete(&model.ReportTemplate{}).Error; err != nil {
+			return err
+		}
+		subscriptionsOfTeam := tx.Model(&model.WebhookSubscription{}).Select("id").Where("team_id=?", team.ID)
+		if err := tx.Unscoped().Where("subscription_id IN (?)", subscriptionsOfTeam).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id=?", team.ID).Delete(&model.WebhookSubscription{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(team).Error; err != nil {
+			return err
+		}
+		return nil
+	})
 }
 
 func (repo *gormTeamRepository) GetAllTeams() ([]model.Team, error) {
@@ -70,6 +123,14 @@ func (repo *gormTeamRepository) GetTeamsOfUser(userId uuid.UUID) ([]model.UserTe
 	return assignments, nil
 }
 
+func (repo *gormTeamRepository) GetUsersOfTeam(teamId uuid.UUID) ([]model.UserTeamAssignment, error) {
+	var assignments []model.UserTeamAssignment
+	if err := repo.db.Order("created_at").Find(&assignments, "team_id=?", teamId).Error; err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
 func (repo *gormTeamRepository) GetUserTeamAssignment(userId uuid.UUID, teamId uuid.UUID) (*model.UserTeamAssignment, error) {
 	var teamAssignment model.UserTeamAssignment
 	if err := repo.db.First(&teamAssignment, "user_id=? AND team_id=?", userId, teamId).Error; err != nil {
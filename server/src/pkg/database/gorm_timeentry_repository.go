@@ -1,11 +1,13 @@
 package database
 
 import (
+	"time"
 	"timeasy-server/pkg/domain/model"
 	"timeasy-server/pkg/domain/repository"
 
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type gormTimeEntryRepository struct {
@@ -19,7 +21,7 @@ func NewGormTimeEntryRepository(database *gorm.DB) repository.TimeEntryRepositor
 }
 
 func (repo *gormTimeEntryRepository) AddTimeEntry(timeEntry *model.TimeEntry) error {
-	if err := repo.db.Create(timeEntry).Error; err != nil {
+	if err := repo.db.Omit("InvoiceID").Create(timeEntry).Error; err != nil {
 		return err
 	}
 	return nil
@@ -28,7 +30,7 @@ func (repo *gormTimeEntryRepository) AddTimeEntry(timeEntry *model.TimeEntry) er
 func (repo *gormTimeEntryRepository) AddTimeEntryList(timeEntryList []model.TimeEntry) error {
 	return repo.db.Transaction(func(tx *gorm.DB) error {
 		for _, timeEntry := range timeEntryList {
-			if err := tx.Create(&timeEntry).Error; err != nil {
+			if err := tx.Omit("InvoiceID").Create(&timeEntry).Error; err != nil {
 				return err
 			}
 		}
@@ -45,16 +47,15 @@ func (repo *gormTimeEntryRepository) GetTimeEntryById(id uuid.UUID) (*model.Time
 }
 
 func (repo *gormTimeEntryRepository) UpdateTimeEntry(timeEntry *model.TimeEntry) error {
-	if err := repo.db.Save(timeEntry).Error; err != nil {
-		return err
-	}
-	return nil
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return saveTimeEntry(tx, timeEntry)
+	})
 }
 
 func (repo *gormTimeEntryRepository) UpdateTimeEntryList(timeEntryList []model.TimeEntry) error {
 	return repo.db.Transaction(func(tx *gorm.DB) error {
 		for _, timeEntry := range timeEntryList {
-			if err := tx.Save(&timeEntry).Error; err != nil {
+			if err := saveTimeEntry(tx, &timeEntry); err != nil {
 				return err
 			}
 		}
@@ -63,10 +64,9 @@ func (repo *gormTimeEntryRepository) UpdateTimeEntryList(timeEntryList []model.T
 }
 
 func (repo *gormTimeEntryRepository) DeleteTimeEntry(timeEntry *model.TimeEntry) error {
-	if err := repo.db.Delete(timeEntry).Error; err != nil {
-		return err
-	}
-	return nil
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return deleteTimeEntry(tx, timeEntry)
+	})
 }
 
 func (repo *gormTimeEntryRepository) GetAllTimeEntries() ([]model.TimeEntry, error) {
@@ -85,6 +85,18 @@ func (repo *gormTimeEntryRepository) GetAllTimeEntriesOfUser(userId uuid.UUID) (
 	return timeEntries, nil
 }
 
+// GetTimeEntriesOfUserInRange returns the entries of the user that start in the interval [from, to), sorted by their
+// start time. A zero time leaves the respective side of the interval open.
+func (repo *gormTimeEntryRepository) GetTimeEntriesOfUserInRange(userId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error) {
+	var timeEntries []model.TimeEntry
+	query := repo.db.Order("start_time").Order("end_time").Where("user_id=?", userId)
+	query = repo.whereStartTimeInRange(query, from, to)
+	if err := query.Find(&timeEntries).Error; err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
 func (repo *gormTimeEntryRepository) GetAllTimeEntriesOfUserAndProject(userId uuid.UUID, projectId uuid.UUID) ([]model.TimeEntry, error) {
 	var timeEntries []model.TimeEntry
 	if err := repo.db.Order("start_time desc").Order("end_time desc").Find(&timeEntries, "user_id=? AND project_id=?",
@@ -93,3 +105,57 @@ func (repo *gormTimeEntryRepository) GetAllTimeEntriesOfUserAndProject(userId uu
 	}
 	return timeEntries, nil
 }
+
+// GetAllTimeEntriesOfTeam returns the entries of all users on the projects of the given team. The entries must start
+// in the interval [from, to). A zero time leaves the respective side of the interval open.
+func (repo *gormTimeEntryRepository) GetAllTimeEntriesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error) {
+	var timeEntries []model.TimeEntry
+	teamProjects := repo.db.Model(&model.Project{}).Select("id").Where("team_id=?", teamId)
+	query := repo.db.Preload("Project").Order("start_time desc").Order("end_time desc").Where("project_id IN (?)", teamProjects)
+	query = repo.whereStartTimeInRange(query, from, to)
+	if err := query.Find(&timeEntries).Error; err != nil {
+		return nil, err
+	}
+	return timeEntries, nil
+}
+
+func (repo *gormTimeEntryRepository) whereStartTimeInRange(query *gorm.DB, from time.Time, to time.Time) *gorm.DB {
+	if !from.IsZero() {
+		query = query.Where("start_time >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("start_time < ?", to)
+	}
+	return query
+}
+
+// lockTimeEntry locks the row of the entry until the end of the transaction and fails with a TimeEntryInvoicedError if
+// the entry is invoiced. The invoices mark their entries in a transaction as well, so no entry can be invoiced between
+// this check and the change of the entry.
+func lockTimeEntry(tx *gorm.DB, id uuid.UUID) error {
+	var storedEntry model.TimeEntry
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "invoice_id").
+		Where("id = ?", id).Limit(1).Find(&storedEntry).Error; err != nil {
+		return err
+	}
+	if storedEntry.InvoiceID != nil {
+		return &model.TimeEntryInvoicedError{TimeEntryID: id}
+	}
+	return nil
+}
+
+// saveTimeEntry updates (or inserts) the entry. The invoice id is never written here, only the invoices set and
+// release it.
+func saveTimeEntry(tx *gorm.DB, timeEntry *model.TimeEntry) error {
+	if err := lockTimeEntry(tx, timeEntry.ID); err != nil {
+		return err
+	}
+	return tx.Omit("InvoiceID").Save(timeEntry).Error
+}
+
+func deleteTimeEntry(tx *gorm.DB, timeEntry *model.TimeEntry) error {
+	if err := lockTimeEntry(tx, timeEntry.ID); err != nil {
+		return err
+	}
+	return tx.Delete(timeEntry).Error
+}
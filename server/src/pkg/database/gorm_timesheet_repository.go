@@ -0,0 +1,79 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormTimesheetRepository struct {
+	db *gorm.DB
+}
+
+func NewGormTimesheetRepository(database *gorm.DB) repository.TimesheetRepository {
+	return &gormTimesheetRepository{
+		db: database,
+	}
+}
+
+func (repo *gormTimesheetRepository) AddTimesheet(timesheet *model.Timesheet) error {
+	if err := repo.db.Create(timesheet).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormTimesheetRepository) UpdateTimesheet(timesheet *model.Timesheet) error {
+	if err := repo.db.Save(timesheet).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormTimesheetRepository) DeleteTimesheet(timesheet *model.Timesheet) error {
+	if err := repo.db.Delete(timesheet).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormTimesheetRepository) GetTimesheetById(id uuid.UUID) (*model.Timesheet, error) {
+	var timesheet model.Timesheet
+	if err := repo.db.First(&timesheet, id).Error; err != nil {
+		return nil, err
+	}
+	return &timesheet, nil
+}
+
+// GetTimesheetsOfUser returns the timesheets of the user whose period overlaps the interval [from, to). A zero time
+// leaves the respective side of the interval open.
+func (repo *gormTimesheetRepository) GetTimesheetsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error) {
+	var timesheets []model.Timesheet
+	query := repo.whereOverlapping(repo.db.Order("start_date").Where("user_id=?", userId), from, to)
+	if err := query.Find(&timesheets).Error; err != nil {
+		return nil, err
+	}
+	return timesheets, nil
+}
+
+func (repo *gormTimesheetRepository) GetTimesheetsOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error) {
+	var timesheets []model.Timesheet
+	query := repo.whereOverlapping(repo.db.Order("start_date").Order("user_id").Where("team_id=?", teamId), from, to)
+	if err := query.Find(&timesheets).Error; err != nil {
+		return nil, err
+	}
+	return timesheets, nil
+}
+
+func (repo *gormTimesheetRepository) whereOverlapping(query *gorm.DB, from time.Time, to time.Time) *gorm.DB {
+	if !from.IsZero() {
+		query = query.Where("end_date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("start_date < ?", to)
+	}
+	return query
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWebhookRepository(database *gorm.DB) repository.WebhookRepository {
+	return &gormWebhookRepository{
+		db: database,
+	}
+}
+
+func (repo *gormWebhookRepository) AddWebhookSubscription(subscription *model.WebhookSubscription) error {
+	if err := repo.db.Create(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWebhookRepository) UpdateWebhookSubscription(subscription *model.WebhookSubscription) error {
+	if err := repo.db.Save(subscription).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWebhookRepository) DeleteWebhookSubscription(subscription *model.WebhookSubscription) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("subscription_id=?", subscription.ID).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(subscription).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (repo *gormWebhookRepository) GetWebhookSubscriptionById(id uuid.UUID) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	if err := repo.db.Where("id=?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (repo *gormWebhookRepository) GetWebhookSubscriptionsOfUser(userId uuid.UUID) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	if err := repo.db.Order("created_at").Where("user_id=? AND team_id IS NULL", userId).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (repo *gormWebhookRepository) GetWebhookSubscriptionsOfTeam(teamId uuid.UUID) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	if err := repo.db.Order("created_at").Where("team_id=?", teamId).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (repo *gormWebhookRepository) GetActiveWebhookSubscriptions(userId *uuid.UUID, teamId *uuid.UUID) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	if userId == nil && teamId == nil {
+		return subscriptions, nil
+	}
+	query := repo.db.Where("active")
+	switch {
+	case userId != nil && teamId != nil:
+		query = query.Where(repo.db.Where("user_id=? AND team_id IS NULL", *userId).Or("team_id=?", *teamId))
+	case userId != nil:
+		query = query.Where("user_id=? AND team_id IS NULL", *userId)
+	default:
+		query = query.Where("team_id=?", *teamId)
+	}
+	if err := query.Order("created_at").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (repo *gormWebhookRepository) AddWebhookDelivery(delivery *model.WebhookDelivery) error {
+	if err := repo.db.Create(delivery).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWebhookRepository) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	if err := repo.db.Save(delivery).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWebhookRepository) GetWebhookDeliveryById(id uuid.UUID) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := repo.db.Where("id=?", id).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (repo *gormWebhookRepository) GetWebhookDeliveryOfEvent(subscriptionId uuid.UUID, eventId uuid.UUID) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := repo.db.Where("subscription_id=? AND event_id=?", subscriptionId, eventId).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (repo *gormWebhookRepository) GetWebhookDeliveries(subscriptionId uuid.UUID, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	if err := repo.db.Order("created_at desc").Where("subscription_id=?", subscriptionId).Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (repo *gormWebhookRepository) GetDueWebhookDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	if err := repo.db.Order("next_attempt_at").Where("status=? AND next_attempt_at <= ?", model.WebhookDeliveryStatusPending, now).
+		Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
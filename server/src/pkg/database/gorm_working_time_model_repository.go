@@ -0,0 +1,64 @@
+package database
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type gormWorkingTimeModelRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWorkingTimeModelRepository(database *gorm.DB) repository.WorkingTimeModelRepository {
+	return &gormWorkingTimeModelRepository{
+		db: database,
+	}
+}
+
+func (repo *gormWorkingTimeModelRepository) AddWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error {
+	if err := repo.db.Create(workingTimeModel).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWorkingTimeModelRepository) UpdateWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error {
+	if err := repo.db.Save(workingTimeModel).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWorkingTimeModelRepository) DeleteWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error {
+	if err := repo.db.Delete(workingTimeModel).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repo *gormWorkingTimeModelRepository) GetWorkingTimeModelById(id uuid.UUID) (*model.WorkingTimeModel, error) {
+	var workingTimeModel model.WorkingTimeModel
+	if err := repo.db.First(&workingTimeModel, id).Error; err != nil {
+		return nil, err
+	}
+	return &workingTimeModel, nil
+}
+
+func (repo *gormWorkingTimeModelRepository) GetWorkingTimeModelsOfUser(userId uuid.UUID) ([]model.WorkingTimeModel, error) {
+	var workingTimeModels []model.WorkingTimeModel
+	if err := repo.db.Order("valid_from").Find(&workingTimeModels, "user_id=?", userId).Error; err != nil {
+		return nil, err
+	}
+	return workingTimeModels, nil
+}
+
+func (repo *gormWorkingTimeModelRepository) GetWorkingTimeModelsOfUserInTeam(userId uuid.UUID, teamId uuid.UUID) ([]model.WorkingTimeModel, error) {
+	var workingTimeModels []model.WorkingTimeModel
+	if err := repo.db.Order("valid_from").Find(&workingTimeModels, "user_id=? AND team_id=?", userId, teamId).Error; err != nil {
+		return nil, err
+	}
+	return workingTimeModels, nil
+}
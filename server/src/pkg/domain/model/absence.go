@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const AbsenceTypeVacation = "VACATION"
+const AbsenceTypeSickLeave = "SICK_LEAVE"
+const AbsenceTypeOther = "OTHER"
+
+const AbsenceStatusRequested = "REQUESTED"
+const AbsenceStatusApproved = "APPROVED"
+const AbsenceStatusRejected = "REJECTED"
+
+// Absence is a range of days a user does not work. The start and end date are both included. If HalfDayStart or
+// HalfDayEnd is set the user is only absent for half of the first or last day.
+type Absence struct {
+	gorm.Model
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID       uuid.UUID `gorm:"type:uuid;"`
+	TeamID       uuid.UUID `gorm:"type:uuid;"`
+	Type         string
+	StartDate    time.Time `gorm:"type:date;"`
+	EndDate      time.Time `gorm:"type:date;"`
+	HalfDayStart bool
+	HalfDayEnd   bool
+	Status       string
+	Comment      string
+	ReviewedBy   *uuid.UUID `gorm:"type:uuid;"`
+}
+
+func (absence *Absence) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	absence.ID = id
+	if absence.Status == "" {
+		absence.Status = AbsenceStatusRequested
+	}
+	return nil
+}
+
+// FractionOfDay returns which part of the given day (at midnight UTC) is covered by the absence: 0, 0.5 or 1.
+func (absence *Absence) FractionOfDay(day time.Time) float64 {
+	if day.Before(absence.StartDate) || day.After(absence.EndDate) {
+		return 0
+	}
+	if (absence.HalfDayStart && day.Equal(absence.StartDate)) || (absence.HalfDayEnd && day.Equal(absence.EndDate)) {
+		return 0.5
+	}
+	return 1
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedToken grants access to the iCalendar feed of the time entries of a user. Only the SHA-256 hash of the
+// secret token is stored, the token itself is shown to the user once when it is generated.
+type CalendarFeedToken struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID    uuid.UUID `gorm:"type:uuid;uniqueIndex;"`
+	TokenHash string    `gorm:"uniqueIndex;"`
+}
+
+func (token *CalendarFeedToken) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	token.ID = id
+	return nil
+}
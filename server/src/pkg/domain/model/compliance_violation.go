@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+const ViolationBreakTooShort = "BREAK_TOO_SHORT"
+const ViolationMaxWorkingTimeExceeded = "MAX_WORKING_TIME_EXCEEDED"
+const ViolationRestPeriodTooShort = "REST_PERIOD_TOO_SHORT"
+
+// ComplianceViolation is a breach of the working time law on a single day (at midnight UTC). Actual is the working
+// time, break or rest period found in the time entries and Required the limit it violates.
+type ComplianceViolation struct {
+	UserID   uuid.UUID
+	Day      time.Time
+	Type     string
+	Actual   time.Duration
+	Required time.Duration
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const DataExportStatusPending = "PENDING"
+const DataExportStatusReady = "READY"
+const DataExportStatusFailed = "FAILED"
+
+// DataExport is the export of all personal data of a user as ZIP file (GDPR Art. 20). Large exports are generated in
+// the background. The file can be downloaded by the user and the requester until it expires and is removed.
+type DataExport struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID uuid.UUID `gorm:"type:uuid;index;"`
+	// RequestedBy is the user or the admin who requested the export
+	RequestedBy uuid.UUID `gorm:"type:uuid;"`
+	Status      string
+	// Content is the ZIP file, it is only loaded for the download
+	Content     []byte
+	Size        int
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index;"`
+	LastError   string
+}
+
+func (dataExport *DataExport) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	dataExport.ID = id
+	if dataExport.Status == "" {
+		dataExport.Status = DataExportStatusPending
+	}
+	return nil
+}
+
+// IsExpired checks if the file of the export cannot be downloaded anymore.
+func (dataExport *DataExport) IsExpired(now time.Time) bool {
+	return dataExport.ExpiresAt != nil && !now.Before(*dataExport.ExpiresAt)
+}
+
+// PersonalData is everything the server stores about a user, including deleted data that is not removed yet. The
+// profile of a user (name, email address) is managed by Keycloak, the server only stores the user id.
+type PersonalData struct {
+	UserID      uuid.UUID
+	TimeEntries []TimeEntry
+	// Projects are the projects of the user and the team projects the user tracked time for
+	Projects []Project
+	// TeamMemberships are the current and former memberships with their teams loaded
+	TeamMemberships      []UserTeamAssignment
+	Absences             []Absence
+	WorkingTimeModels    []WorkingTimeModel
+	Timesheets           []Timesheet
+	OvertimeAccounts     []OvertimeAccount
+	OvertimeCorrections  []OvertimeCorrection
+	Notifications        []Notification
+	NotificationSettings []NotificationSettings
+	WebhookSubscriptions []WebhookSubscription
+	CalendarFeedTokens   []CalendarFeedToken
+	ImportedTimeEntries  []ImportedTimeEntry
+	RunningTimerNotices  []RunningTimerNotice
+	// PeriodLockChanges are the changes of lock dates the user made as team admin
+	PeriodLockChanges []PeriodLockChange
+	// Invoices are the personal invoices of the user with their lines, team invoices belong to the team
+	Invoices []Invoice
+}
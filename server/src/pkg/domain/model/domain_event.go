@@ -0,0 +1,176 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const EventTimeEntryCreated = "TimeEntryCreated"
+const EventTimeEntryUpdated = "TimeEntryUpdated"
+const EventTimeEntryDeleted = "TimeEntryDeleted"
+const EventProjectCreated = "ProjectCreated"
+const EventProjectUpdated = "ProjectUpdated"
+const EventProjectDeleted = "ProjectDeleted"
+const EventTeamCreated = "TeamCreated"
+const EventTeamDeleted = "TeamDeleted"
+const EventUserAddedToTeam = "UserAddedToTeam"
+const EventUserRemovedFromTeam = "UserRemovedFromTeam"
+
+// DomainEvent describes a change of an entity. The events are written to the outbox in the transaction of the change
+// and are published after the commit.
+type DomainEvent interface {
+	EventType() string
+	// AggregateID is the id of the changed entity
+	AggregateID() uuid.UUID
+}
+
+type TimeEntryEvent struct {
+	TimeEntryID uuid.UUID  `json:"timeEntryId"`
+	UserID      uuid.UUID  `json:"userId"`
+	ProjectID   uuid.UUID  `json:"projectId"`
+	StartTime   time.Time  `json:"startTime"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+	Description string     `json:"description"`
+	// InvoiceID is set while the entry is invoiced and cannot be changed
+	InvoiceID *uuid.UUID `json:"invoiceId,omitempty"`
+}
+
+func (event TimeEntryEvent) AggregateID() uuid.UUID {
+	return event.TimeEntryID
+}
+
+type TimeEntryCreated struct{ TimeEntryEvent }
+type TimeEntryUpdated struct{ TimeEntryEvent }
+type TimeEntryDeleted struct{ TimeEntryEvent }
+
+func (TimeEntryCreated) EventType() string { return EventTimeEntryCreated }
+func (TimeEntryUpdated) EventType() string { return EventTimeEntryUpdated }
+func (TimeEntryDeleted) EventType() string { return EventTimeEntryDeleted }
+
+type ProjectEvent struct {
+	ProjectID uuid.UUID  `json:"projectId"`
+	Name      string     `json:"name"`
+	UserID    uuid.UUID  `json:"userId"`
+	TeamID    *uuid.UUID `json:"teamId,omitempty"`
+}
+
+func (event ProjectEvent) AggregateID() uuid.UUID {
+	return event.ProjectID
+}
+
+type ProjectCreated struct{ ProjectEvent }
+type ProjectUpdated struct{ ProjectEvent }
+type ProjectDeleted struct{ ProjectEvent }
+
+func (ProjectCreated) EventType() string { return EventProjectCreated }
+func (ProjectUpdated) EventType() string { return EventProjectUpdated }
+func (ProjectDeleted) EventType() string { return EventProjectDeleted }
+
+type TeamEvent struct {
+	TeamID uuid.UUID `json:"teamId"`
+	Name   string    `json:"name"`
+}
+
+func (event TeamEvent) AggregateID() uuid.UUID {
+	return event.TeamID
+}
+
+type TeamCreated struct{ TeamEvent }
+
+// TeamDeleted follows the UserRemovedFromTeam events of the members and the ProjectUpdated events of the detached
+// projects of the team.
+type TeamDeleted struct{ TeamEvent }
+
+func (TeamCreated) EventType() string { return EventTeamCreated }
+func (TeamDeleted) EventType() string { return EventTeamDeleted }
+
+type TeamMembershipEvent struct {
+	TeamID uuid.UUID `json:"teamId"`
+	UserID uuid.UUID `json:"userId"`
+	Roles  RoleList  `json:"roles"`
+}
+
+func (event TeamMembershipEvent) AggregateID() uuid.UUID {
+	return event.TeamID
+}
+
+type UserAddedToTeam struct{ TeamMembershipEvent }
+type UserRemovedFromTeam struct{ TeamMembershipEvent }
+
+func (UserAddedToTeam) EventType() string     { return EventUserAddedToTeam }
+func (UserRemovedFromTeam) EventType() string { return EventUserRemovedFromTeam }
+
+var domainEventFactories = map[string]func() DomainEvent{
+	EventTimeEntryCreated:    func() DomainEvent { return &TimeEntryCreated{} },
+	EventTimeEntryUpdated:    func() DomainEvent { return &TimeEntryUpdated{} },
+	EventTimeEntryDeleted:    func() DomainEvent { return &TimeEntryDeleted{} },
+	EventProjectCreated:      func() DomainEvent { return &ProjectCreated{} },
+	EventProjectUpdated:      func() DomainEvent { return &ProjectUpdated{} },
+	EventProjectDeleted:      func() DomainEvent { return &ProjectDeleted{} },
+	EventTeamCreated:         func() DomainEvent { return &TeamCreated{} },
+	EventTeamDeleted:         func() DomainEvent { return &TeamDeleted{} },
+	EventUserAddedToTeam:     func() DomainEvent { return &UserAddedToTeam{} },
+	EventUserRemovedFromTeam: func() DomainEvent { return &UserRemovedFromTeam{} },
+}
+
+// GetDomainEventTypes returns the names of all domain events.
+func GetDomainEventTypes() []string {
+	var eventTypes []string
+	for eventType := range domainEventFactories {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+func IsKnownDomainEventType(eventType string) bool {
+	_, known := domainEventFactories[eventType]
+	return known
+}
+
+// NewOutboxEvent serializes the domain event for the outbox.
+func NewOutboxEvent(event DomainEvent) (*OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		Type:        event.EventType(),
+		AggregateID: event.AggregateID(),
+		Payload:     string(payload),
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+// DecodeDomainEvent restores the typed domain event of an outbox entry.
+func DecodeDomainEvent(outboxEvent *OutboxEvent) (DomainEvent, error) {
+	factory, known := domainEventFactories[outboxEvent.Type]
+	if !known {
+		return nil, fmt.Errorf("unknown domain event %v", outboxEvent.Type)
+	}
+	event := factory()
+	if err := json.Unmarshal([]byte(outboxEvent.Payload), event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// recordDomainEvent writes the event to the outbox. It is called by the hooks of the entities, which gorm runs in the
+// transaction of the change, so the event is stored if and only if the change is committed.
+func recordDomainEvent(db *gorm.DB, event DomainEvent) error {
+	if event.AggregateID() == uuid.Nil {
+		// Bulk updates and deletes without a loaded entity cannot be described, the event would be lost
+		return fmt.Errorf("the %v event of a bulk change cannot be recorded, the entities must be changed one by one",
+			event.EventType())
+	}
+	outboxEvent, err := NewOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+	return db.Session(&gorm.Session{NewDB: true}).Create(outboxEvent).Error
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const ErasureStatusRequested = "REQUESTED"
+const ErasureStatusConfirmed = "CONFIRMED"
+const ErasureStatusCompleted = "COMPLETED"
+const ErasureStatusCancelled = "CANCELLED"
+
+// ErasureRequest is the request to erase the personal data of a user (GDPR Art. 17). An admin has to confirm it, and
+// the data is erased when the grace period has passed, until then the request can be cancelled. The request is kept
+// after the erasure as receipt.
+type ErasureRequest struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID uuid.UUID `gorm:"type:uuid;index;"`
+	// RequestedBy is the user or the admin who requested the erasure
+	RequestedBy uuid.UUID `gorm:"type:uuid;"`
+	Status      string
+	// DueAt is the end of the grace period, the data of confirmed requests is erased afterwards
+	DueAt       time.Time  `gorm:"index;"`
+	ConfirmedBy *uuid.UUID `gorm:"type:uuid;"`
+	ConfirmedAt *time.Time
+	CompletedAt *time.Time
+	// Receipt is the JSON encoded ErasureReceipt of a completed erasure
+	Receipt string
+}
+
+func (request *ErasureRequest) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	request.ID = id
+	if request.Status == "" {
+		request.Status = ErasureStatusRequested
+	}
+	return nil
+}
+
+// IsOpen checks if the erasure can still be confirmed or cancelled.
+func (request *ErasureRequest) IsOpen() bool {
+	return request.Status == ErasureStatusRequested || request.Status == ErasureStatusConfirmed
+}
+
+// Complete marks the confirmed request as completed and stores the receipt with the numbers of deleted and
+// anonymised records per table.
+func (request *ErasureRequest) Complete(completedAt time.Time, deleted map[string]int64, anonymised map[string]int64) error {
+	receipt := ErasureReceipt{
+		RequestID:   request.ID,
+		UserID:      request.UserID,
+		RequestedBy: request.RequestedBy,
+		RequestedAt: request.CreatedAt.UTC(),
+		ConfirmedBy: *request.ConfirmedBy,
+		ConfirmedAt: request.ConfirmedAt.UTC(),
+		CompletedAt: completedAt.UTC(),
+		Deleted:     deleted,
+		Anonymised:  anonymised,
+	}
+	receiptJson, err := json.Marshal(receipt)
+	if err != nil {
+		return err
+	}
+	request.Status = ErasureStatusCompleted
+	request.CompletedAt = &completedAt
+	request.Receipt = string(receiptJson)
+	return nil
+}
+
+// ErasureReceipt documents what was erased. It only contains the ids of the user and the admins, no erased data.
+type ErasureReceipt struct {
+	RequestID   uuid.UUID `json:"requestId"`
+	UserID      uuid.UUID `json:"userId"`
+	RequestedBy uuid.UUID `json:"requestedBy"`
+	RequestedAt time.Time `json:"requestedAt"`
+	ConfirmedBy uuid.UUID `json:"confirmedBy"`
+	ConfirmedAt time.Time `json:"confirmedAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	// Deleted is the number of permanently deleted records per table
+	Deleted map[string]int64 `json:"deleted"`
+	// Anonymised is the number of records per table which are kept without reference to the user, e.g. team projects
+	Anonymised map[string]int64 `json:"anonymised"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// HolidayCalendar combines the public holidays of a region with custom days off of the company. Calendars belong to a
+// team and can be assigned to the whole team or to single members.
+type HolidayCalendar struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	TeamID uuid.UUID `gorm:"type:uuid;"`
+	Name   string
+	Region string // German federal state (e.g. "BW") or "DE" for the nationwide holidays only
+}
+
+func (calendar *HolidayCalendar) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	calendar.ID = id
+	return nil
+}
+
+type CustomHoliday struct {
+	gorm.Model
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	HolidayCalendarID uuid.UUID `gorm:"type:uuid;"`
+	Date              time.Time `gorm:"type:date;"`
+	Name              string
+}
+
+func (customHoliday *CustomHoliday) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	customHoliday.ID = id
+	return nil
+}
+
+// Holiday is a single non-working day (at midnight UTC), either a public holiday or a custom one.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Sources of imported time entries
+const (
+	ImportSourceCsv      = "csv"
+	ImportSourceToggl    = "toggl"
+	ImportSourceClockify = "clockify"
+)
+
+// ImportedTimeEntry keeps the attributes of an imported time entry which a TimeEntry does not have, and the id of the
+// entry in the source to recognize it when the same file is imported again.
+type ImportedTimeEntry struct {
+	gorm.Model
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	TimeEntryID uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	UserID      uuid.UUID `gorm:"type:uuid;index:idx_imported_time_entries_source"`
+	Source      string    `gorm:"index:idx_imported_time_entries_source"`
+	// ExternalID is the id of the entry in the source, it is empty if the source file has no ids
+	ExternalID string `gorm:"index:idx_imported_time_entries_source"`
+	Task       string
+	Tags       StringList
+	// Billable is the flag of the source, it overrides the flag of the project. It is nil if the source has no flag.
+	Billable *bool
+}
+
+// ImportSourceHasBillableFlag returns whether the entries of the source are marked as billable or not billable.
+func ImportSourceHasBillableFlag(source string) bool {
+	return source == ImportSourceToggl || source == ImportSourceClockify
+}
+
+func (importedTimeEntry *ImportedTimeEntry) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	importedTimeEntry.ID = id
+	return nil
+}
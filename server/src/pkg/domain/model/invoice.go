@@ -0,0 +1,103 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const InvoiceStatusDraft = "DRAFT"
+const InvoiceStatusCancelled = "CANCELLED"
+
+// Rounding modes of the billed durations
+const (
+	RoundingModeNearest = "NEAREST"
+	RoundingModeUp      = "UP"
+	RoundingModeDown    = "DOWN"
+)
+
+// Invoice is a numbered invoice of the billable time entries of a period. Its time entries are marked as invoiced, so
+// they cannot be changed or invoiced again until the invoice is cancelled.
+type Invoice struct {
+	gorm.Model
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	// IssuerID is the team of a team invoice or the user of a personal invoice, the numbers are counted per issuer
+	IssuerID  uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_invoice_number;"`
+	Number    string     `gorm:"uniqueIndex:idx_invoice_number;"`
+	TeamID    *uuid.UUID `gorm:"type:uuid;"`
+	CreatedBy uuid.UUID  `gorm:"type:uuid;"`
+	Status    string
+	// Client and ProjectID are the selection of the time entries, one of them is set
+	Client    string
+	ProjectID *uuid.UUID `gorm:"type:uuid;"`
+	// From and To define the invoiced period [From, To)
+	From     time.Time `gorm:"type:date;"`
+	To       time.Time `gorm:"type:date;"`
+	Currency string
+	// HourlyRate is the default rate in cents, the lines of projects with their own rate have other rates
+	HourlyRate      int64
+	RoundingMinutes int
+	RoundingMode    string
+	// TotalMinutes is the sum of the billed minutes of the lines, TotalAmount the sum of their amounts in cents
+	TotalMinutes int64
+	TotalAmount  int64
+	Lines        []InvoiceLine `gorm:"constraint:OnDelete:CASCADE;"`
+}
+
+func (invoice *Invoice) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	invoice.ID = id
+	if invoice.Status == "" {
+		invoice.Status = InvoiceStatusDraft
+	}
+	return nil
+}
+
+// InvoiceLine is the time of a project and task on a day. The tracked time is rounded to the billed time.
+type InvoiceLine struct {
+	gorm.Model
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	InvoiceID      uuid.UUID `gorm:"type:uuid;index;"`
+	Position       int
+	ProjectID      uuid.UUID `gorm:"type:uuid;"`
+	ProjectName    string
+	Task           string
+	Day            time.Time `gorm:"type:date;"`
+	TrackedMinutes int64
+	BilledMinutes  int64
+	// HourlyRate and Amount are in cents
+	HourlyRate int64
+	Amount     int64
+	// TimeEntryCount is the number of time entries of the line
+	TimeEntryCount int
+}
+
+func (line *InvoiceLine) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	line.ID = id
+	return nil
+}
+
+// InvoiceFilter selects the billable time entries of an invoice. Entries of a team invoice belong to the projects of
+// the team, the entries of a personal invoice to the user and projects without team.
+type InvoiceFilter struct {
+	TeamID    *uuid.UUID
+	UserID    uuid.UUID
+	Client    string
+	ProjectID *uuid.UUID
+	From      time.Time
+	To        time.Time
+}
+
+// BillableTimeEntry is a time entry that can be invoiced, with the task of an imported entry.
+type BillableTimeEntry struct {
+	TimeEntry TimeEntry
+	Task      string
+}
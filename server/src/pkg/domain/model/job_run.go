@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const JobRunStatusRunning = "RUNNING"
+const JobRunStatusSucceeded = "SUCCEEDED"
+const JobRunStatusFailed = "FAILED"
+
+// JobRun is an entry in the history of a background job. Every scheduled point in time of a job is run only once,
+// even if several server instances are running.
+type JobRun struct {
+	gorm.Model
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	Name        string    `gorm:"uniqueIndex:idx_job_run_schedule;"`
+	ScheduledAt time.Time `gorm:"uniqueIndex:idx_job_run_schedule;"`
+	// Instance identifies the server that ran the job
+	Instance   string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Status     string
+	Error      string
+}
+
+func (jobRun *JobRun) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	jobRun.ID = id
+	return nil
+}
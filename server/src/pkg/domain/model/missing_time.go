@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+const MissingTimeNoTimeRecorded = "NO_TIME_RECORDED"
+const MissingTimeBelowTarget = "BELOW_TARGET"
+const MissingTimeRunningEntry = "RUNNING_ENTRY"
+
+// MissingTime is a working day (at midnight UTC) of a user where time is missing: less time than the target was
+// recorded or an entry started on this day is still running. TimeEntryID references the running entry.
+type MissingTime struct {
+	UserID      uuid.UUID
+	Day         time.Time
+	Reason      string
+	Target      time.Duration
+	Recorded    time.Duration
+	TimeEntryID *uuid.UUID
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const NotificationChannelEmail = "EMAIL"
+const NotificationChannelWebhook = "WEBHOOK"
+const NotificationChannelInbox = "INBOX"
+
+const NotificationTimesheetSubmitted = "TIMESHEET_SUBMITTED"
+const NotificationTimesheetApproved = "TIMESHEET_APPROVED"
+const NotificationTimesheetRejected = "TIMESHEET_REJECTED"
+const NotificationAbsenceRequested = "ABSENCE_REQUESTED"
+const NotificationAbsenceApproved = "ABSENCE_APPROVED"
+const NotificationAbsenceRejected = "ABSENCE_REJECTED"
+const NotificationAddedToTeam = "ADDED_TO_TEAM"
+const NotificationRunningTimerStopped = "RUNNING_TIMER_STOPPED"
+const NotificationRunningTimerFlagged = "RUNNING_TIMER_FLAGGED"
+const NotificationDataExportReady = "DATA_EXPORT_READY"
+const NotificationErasureScheduled = "ERASURE_SCHEDULED"
+
+// Notification is a message in the in-app inbox of a user.
+type Notification struct {
+	gorm.Model
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID  uuid.UUID `gorm:"type:uuid;index;"`
+	Type    string
+	Subject string
+	Body    string
+	ReadAt  *time.Time
+}
+
+func (notification *Notification) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	notification.ID = id
+	return nil
+}
+
+// NotificationSettings define how a user wants to be notified. Without stored settings only the inbox is used.
+type NotificationSettings struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID uuid.UUID `gorm:"type:uuid;uniqueIndex;"`
+	// Channels are the enabled channels of the user
+	Channels StringList `gorm:"type:VARCHAR(255)"`
+	// MutedTypes are the notification types the user does not want to receive at all
+	MutedTypes StringList `gorm:"type:VARCHAR(1024)"`
+	Email      string
+	WebhookURL string
+}
+
+func (settings *NotificationSettings) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	settings.ID = id
+	return nil
+}
+
+// NotificationTemplate replaces the built-in subject and body of a notification type. Both are go text templates.
+type NotificationTemplate struct {
+	gorm.Model
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	Type    string    `gorm:"uniqueIndex;"`
+	Subject string
+	Body    string
+}
+
+func (template *NotificationTemplate) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	template.ID = id
+	return nil
+}
+
+func IsValidNotificationChannel(channel string) bool {
+	return channel == NotificationChannelEmail || channel == NotificationChannelWebhook || channel == NotificationChannelInbox
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event waiting to be published (transactional outbox). Events that could not be handled are
+// retried later, so every subscriber receives an event at least once.
+type OutboxEvent struct {
+	gorm.Model
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	Type        string
+	AggregateID uuid.UUID `gorm:"type:uuid;"`
+	// Payload is the JSON encoded domain event
+	Payload       string
+	OccurredAt    time.Time `gorm:"index;"`
+	PublishedAt   *time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+func (outboxEvent *OutboxEvent) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	outboxEvent.ID = id
+	return nil
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const OvertimeCorrectionPayout = "PAYOUT"
+const OvertimeCorrectionCompensation = "COMPENSATION"
+const OvertimeCorrectionOther = "OTHER"
+
+// OvertimeAccount defines the day from which on the overtime balance of a user is calculated and the balance that is
+// carried over from the time before.
+type OvertimeAccount struct {
+	gorm.Model
+	ID                    uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID                uuid.UUID `gorm:"type:uuid;"`
+	StartDate             time.Time `gorm:"type:date;"`
+	OpeningBalanceMinutes int
+}
+
+func (account *OvertimeAccount) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	account.ID = id
+	return nil
+}
+
+// OvertimeCorrection is a manual booking on the overtime account of a user, e.g. a payout. Negative minutes reduce
+// the balance, payouts and compensations are always negative.
+type OvertimeCorrection struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID    uuid.UUID `gorm:"type:uuid;"`
+	Date      time.Time `gorm:"type:date;"`
+	Minutes   int
+	Type      string
+	Comment   string
+	CreatedBy uuid.UUID `gorm:"type:uuid;"`
+}
+
+func (correction *OvertimeCorrection) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	correction.ID = id
+	return nil
+}
+
+// OvertimeBalance is the overtime of a user from the start date of the account up to (but excluding) EndDate. The
+// balance includes the opening balance and all corrections. Days contains the running balance of the requested days.
+type OvertimeBalance struct {
+	UserID         uuid.UUID
+	StartDate      time.Time
+	EndDate        time.Time
+	OpeningBalance time.Duration
+	Worked         time.Duration
+	Target         time.Duration
+	Corrections    time.Duration
+	Balance        time.Duration
+	Days           []DailyOvertime
+}
+
+// DailyOvertime contains the worked and target time of a single day (at midnight UTC) and the balance at its end.
+type DailyOvertime struct {
+	Day        time.Time
+	Worked     time.Duration
+	Target     time.Duration
+	Correction time.Duration
+	Balance    time.Duration
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// PeriodLockChange records who moved the lock date of a team and when. A nil lock date means that nothing was
+// locked.
+type PeriodLockChange struct {
+	gorm.Model
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;"`
+	TeamID      uuid.UUID  `gorm:"type:uuid;"`
+	ChangedBy   uuid.UUID  `gorm:"type:uuid;"`
+	OldLockDate *time.Time `gorm:"type:date;"`
+	NewLockDate *time.Time `gorm:"type:date;"`
+	Comment     string
+}
+
+func (change *PeriodLockChange) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	change.ID = id
+	return nil
+}
@@ -12,13 +12,48 @@ type Project struct {
 	UserId uuid.UUID  `gorm:"type:uuid;"`
 	TeamID *uuid.UUID `gorm:"type:uuid;"` // Team is optional
 	Team   Team
+	// Client is the customer the project is done for, e.g. from an import of Toggl or Clockify
+	Client   string
+	Billable bool
 }
 
 func (project *Project) BeforeCreate(db *gorm.DB) error {
-	id, err := uuid.NewV4()
-	if err != nil {
-		return err
+	// Projects of imports get their ids in advance
+	if project.ID == uuid.Nil {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		project.ID = id
 	}
-	project.ID = id
 	return nil
 }
+
+func (project *Project) toEvent() ProjectEvent {
+	return ProjectEvent{
+		ProjectID: project.ID,
+		Name:      project.Name,
+		UserID:    project.UserId,
+		TeamID:    project.TeamID,
+	}
+}
+
+func (project *Project) AfterCreate(db *gorm.DB) error {
+	if db.RowsAffected == 0 {
+		// The entity already existed, gorm saves associations with ON CONFLICT DO NOTHING
+		return nil
+	}
+	return recordDomainEvent(db, ProjectCreated{project.toEvent()})
+}
+
+func (project *Project) AfterUpdate(db *gorm.DB) error {
+	if db.RowsAffected == 0 {
+		// Save inserts the project afterwards (e.g. new projects of the sync), without running the create hooks
+		return recordDomainEvent(db, ProjectCreated{project.toEvent()})
+	}
+	return recordDomainEvent(db, ProjectUpdated{project.toEvent()})
+}
+
+func (project *Project) AfterDelete(db *gorm.DB) error {
+	return recordDomainEvent(db, ProjectDeleted{project.toEvent()})
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// Layouts of the table of a timesheet report
+const (
+	// ReportLayoutEntries lists every time entry
+	ReportLayoutEntries = "entries"
+	// ReportLayoutDailyTotals lists the total working time of each day
+	ReportLayoutDailyTotals = "dailytotals"
+)
+
+func IsValidReportLayout(layout string) bool {
+	return layout == ReportLayoutEntries || layout == ReportLayoutDailyTotals
+}
+
+// ReportTemplate customizes the PDF timesheets of a team. Without stored template the defaults of
+// NewDefaultReportTemplate are used.
+type ReportTemplate struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	TeamID uuid.UUID `gorm:"type:uuid;uniqueIndex;"`
+	// Title replaces the default title of the language
+	Title string
+	// Client is printed in the header, e.g. the customer who signs the timesheet
+	Client string
+	Layout string
+	// Language of the labels, "en" or "de"
+	Language string
+	// HeaderText is printed above the table, FooterText at the bottom of each page
+	HeaderText string
+	FooterText string
+	// SignatureLabels get a signature line each, e.g. "Employee" and "Client"
+	SignatureLabels StringList `gorm:"type:VARCHAR(1024)"`
+}
+
+func NewDefaultReportTemplate(teamId uuid.UUID) *ReportTemplate {
+	return &ReportTemplate{
+		TeamID:   teamId,
+		Layout:   ReportLayoutEntries,
+		Language: "en",
+	}
+}
+
+func (template *ReportTemplate) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	template.ID = id
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 type RoleList []string
 
 const RoleAdmin = "ADMIN"
+const RoleManager = "MANAGER"
 const RoleUser = "USER"
 
 func (roleList *RoleList) Scan(src any) error {
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// RunningTimerPolicyFlag only marks forgotten running time entries, the user has to stop them.
+const RunningTimerPolicyFlag = "FLAG"
+
+// RunningTimerPolicyStopAtThreshold stops forgotten running time entries when they exceed the threshold.
+const RunningTimerPolicyStopAtThreshold = "STOP_AT_THRESHOLD"
+
+// RunningTimerPolicyStopAtTargetTime stops forgotten running time entries after the target time of the day.
+const RunningTimerPolicyStopAtTargetTime = "STOP_AT_TARGET_TIME"
+
+const RunningTimerActionStopped = "STOPPED"
+const RunningTimerActionFlagged = "FLAGGED"
+
+// RunningTimerNotice records that a forgotten running time entry was stopped or flagged by the server. The user is
+// informed about it on the next sync.
+type RunningTimerNotice struct {
+	gorm.Model
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	TimeEntryID uuid.UUID `gorm:"type:uuid;"`
+	UserID      uuid.UUID `gorm:"type:uuid;"`
+	Action      string
+	// EndTime is the end time the server has set if the entry was stopped
+	EndTime *time.Time `gorm:"type:timestamp;"`
+}
+
+func (notice *RunningTimerNotice) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	notice.ID = id
+	return nil
+}
+
+func IsValidRunningTimerPolicy(policy string) bool {
+	switch policy {
+	case RunningTimerPolicyFlag, RunningTimerPolicyStopAtThreshold, RunningTimerPolicyStopAtTargetTime:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// StringList stores a list of strings comma separated in a single column.
+type StringList []string
+
+func (stringList *StringList) Scan(src any) error {
+	if src == nil {
+		*stringList = StringList{}
+		return nil
+	}
+	listString, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("src value %v cannot cast to string", src)
+	}
+	if listString == "" {
+		*stringList = StringList{}
+		return nil
+	}
+	*stringList = strings.Split(listString, ",")
+	return nil
+}
+
+func (stringList StringList) Value() (driver.Value, error) {
+	return strings.Join(stringList, ","), nil
+}
+
+func (stringList StringList) Contains(value string) bool {
+	for _, entry := range stringList {
+		if entry == value {
+			return true
+		}
+	}
+	return false
+}
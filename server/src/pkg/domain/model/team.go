@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
 )
@@ -11,6 +13,12 @@ type Team struct {
 	Name1 string
 	Name2 string
 	Name3 string
+	// HolidayCalendarID references the holiday calendar that applies to all members of the team
+	HolidayCalendarID *uuid.UUID `gorm:"type:uuid;"`
+	// LockDate closes the books of the team: time entries of its projects on or before this day cannot be changed
+	LockDate *time.Time `gorm:"type:date;"`
+	// RunningTimerPolicy defines what happens to forgotten running time entries of the members (see RunningTimerPolicyFlag)
+	RunningTimerPolicy string
 }
 
 func (team *Team) BeforeCreate(db *gorm.DB) error {
@@ -21,3 +29,15 @@ func (team *Team) BeforeCreate(db *gorm.DB) error {
 	team.ID = id
 	return nil
 }
+
+func (team *Team) AfterCreate(db *gorm.DB) error {
+	if db.RowsAffected == 0 {
+		// The team already existed, gorm saves associations with ON CONFLICT DO NOTHING
+		return nil
+	}
+	return recordDomainEvent(db, TeamCreated{TeamEvent{TeamID: team.ID, Name: team.Name1}})
+}
+
+func (team *Team) AfterDelete(db *gorm.DB) error {
+	return recordDomainEvent(db, TeamDeleted{TeamEvent{TeamID: team.ID, Name: team.Name1}})
+}
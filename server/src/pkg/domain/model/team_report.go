@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type TeamReport struct {
+	TeamID       uuid.UUID
+	From         time.Time
+	To           time.Time
+	Total        time.Duration
+	ByUser       map[uuid.UUID]time.Duration
+	ByProject    map[uuid.UUID]time.Duration
+	ByDay        map[time.Time]time.Duration // the keys are the days at midnight (UTC)
+	ProjectNames map[uuid.UUID]string
+	Absences     []Absence
+}
+
+func NewTeamReport(teamId uuid.UUID, from time.Time, to time.Time) *TeamReport {
+	return &TeamReport{
+		TeamID:       teamId,
+		From:         from,
+		To:           to,
+		ByUser:       make(map[uuid.UUID]time.Duration),
+		ByProject:    make(map[uuid.UUID]time.Duration),
+		ByDay:        make(map[time.Time]time.Duration),
+		ProjectNames: make(map[uuid.UUID]string),
+	}
+}
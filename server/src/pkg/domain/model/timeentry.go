@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -16,16 +17,74 @@ type TimeEntry struct {
 	StartTime   time.Time `gorm:"type:timestamp;"` // db: timestamp without time zone
 	EndTime     time.Time `gorm:"type:timestamp;"` // db: timestamp without time zone
 	Description string
+	// InvoiceID is set while the entry belongs to an invoice, invoiced entries cannot be changed
+	InvoiceID *uuid.UUID `gorm:"type:uuid;index;"`
 }
 
 func (timeEntry *TimeEntry) BeforeCreate(db *gorm.DB) error {
-	id, err := uuid.NewV4()
-	if err != nil {
-		return err
+	// Imported entries get their ids in advance
+	if timeEntry.ID == uuid.Nil {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		timeEntry.ID = id
 	}
-	timeEntry.ID = id
 	if timeEntry.StartTime.IsZero() {
 		timeEntry.StartTime = time.Now().UTC()
 	}
 	return nil
 }
+
+// Duration returns the tracked time of the entry. Running entries (without end time) have no duration yet.
+func (timeEntry *TimeEntry) Duration() time.Duration {
+	if timeEntry.EndTime.IsZero() || timeEntry.EndTime.Before(timeEntry.StartTime) {
+		return 0
+	}
+	return timeEntry.EndTime.Sub(timeEntry.StartTime)
+}
+
+func (timeEntry *TimeEntry) toEvent() TimeEntryEvent {
+	event := TimeEntryEvent{
+		TimeEntryID: timeEntry.ID,
+		UserID:      timeEntry.UserId,
+		ProjectID:   timeEntry.ProjectId,
+		StartTime:   timeEntry.StartTime,
+		Description: timeEntry.Description,
+		InvoiceID:   timeEntry.InvoiceID,
+	}
+	if !timeEntry.EndTime.IsZero() {
+		endTime := timeEntry.EndTime
+		event.EndTime = &endTime
+	}
+	return event
+}
+
+func (timeEntry *TimeEntry) AfterCreate(db *gorm.DB) error {
+	if db.RowsAffected == 0 {
+		// The entity already existed, gorm saves associations with ON CONFLICT DO NOTHING
+		return nil
+	}
+	return recordDomainEvent(db, TimeEntryCreated{timeEntry.toEvent()})
+}
+
+func (timeEntry *TimeEntry) AfterUpdate(db *gorm.DB) error {
+	if db.RowsAffected == 0 {
+		// Save inserts the entry afterwards (e.g. new entries of the sync), without running the create hooks
+		return recordDomainEvent(db, TimeEntryCreated{timeEntry.toEvent()})
+	}
+	return recordDomainEvent(db, TimeEntryUpdated{timeEntry.toEvent()})
+}
+
+func (timeEntry *TimeEntry) AfterDelete(db *gorm.DB) error {
+	return recordDomainEvent(db, TimeEntryDeleted{timeEntry.toEvent()})
+}
+
+// TimeEntryInvoicedError is returned by the repositories if an entry was invoiced before it could be changed.
+type TimeEntryInvoicedError struct {
+	TimeEntryID uuid.UUID
+}
+
+func (e *TimeEntryInvoicedError) Error() string {
+	return fmt.Sprintf("time entry %v is invoiced and cannot be changed", e.TimeEntryID)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const TimesheetPeriodWeek = "WEEK"
+const TimesheetPeriodMonth = "MONTH"
+
+const TimesheetStatusSubmitted = "SUBMITTED"
+const TimesheetStatusApproved = "APPROVED"
+const TimesheetStatusRejected = "REJECTED"
+
+// Timesheet is a week (monday to sunday) or a month of time entries a user submits to a team for approval. The start
+// and end date are both included. While a timesheet is approved the time entries of the period are locked.
+type Timesheet struct {
+	gorm.Model
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID      uuid.UUID `gorm:"type:uuid;"`
+	TeamID      uuid.UUID `gorm:"type:uuid;"`
+	PeriodType  string
+	StartDate   time.Time `gorm:"type:date;"`
+	EndDate     time.Time `gorm:"type:date;"`
+	Status      string
+	Comment     string
+	SubmittedAt time.Time
+	ReviewedBy  *uuid.UUID `gorm:"type:uuid;"`
+	ReviewedAt  *time.Time
+}
+
+func (timesheet *Timesheet) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	timesheet.ID = id
+	if timesheet.Status == "" {
+		timesheet.Status = TimesheetStatusSubmitted
+	}
+	return nil
+}
+
+// Contains checks if the given point in time lies within the period of the timesheet.
+func (timesheet *Timesheet) Contains(t time.Time) bool {
+	return !t.Before(timesheet.StartDate) && t.Before(timesheet.EndDate.AddDate(0, 0, 1))
+}
@@ -11,4 +11,24 @@ type UserTeamAssignment struct {
 	TeamID uuid.UUID
 	Team   Team
 	Roles  RoleList `gorm:"type:VARCHAR(255)"` //store the team roles in a string field
+	// HolidayCalendarID overrides the holiday calendar of the team for this member (e.g. if they work in another state)
+	HolidayCalendarID *uuid.UUID `gorm:"type:uuid;"`
+	// RunningTimerPolicy overrides the running timer policy of the team for this member
+	RunningTimerPolicy string
+}
+
+func (teamAssignment *UserTeamAssignment) AfterCreate(db *gorm.DB) error {
+	return recordDomainEvent(db, UserAddedToTeam{TeamMembershipEvent{
+		TeamID: teamAssignment.TeamID,
+		UserID: teamAssignment.UserID,
+		Roles:  teamAssignment.Roles,
+	}})
+}
+
+func (teamAssignment *UserTeamAssignment) AfterDelete(db *gorm.DB) error {
+	return recordDomainEvent(db, UserRemovedFromTeam{TeamMembershipEvent{
+		TeamID: teamAssignment.TeamID,
+		UserID: teamAssignment.UserID,
+		Roles:  teamAssignment.Roles,
+	}})
 }
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const WebhookDeliveryStatusPending = "PENDING"
+const WebhookDeliveryStatusSucceeded = "SUCCEEDED"
+const WebhookDeliveryStatusFailed = "FAILED"
+
+// WebhookSubscription posts domain events to an url. Subscriptions without team belong to the user and receive the
+// events of their own time entries, projects and team memberships. Team subscriptions are managed by the team admins
+// and receive the events of the team and its projects.
+type WebhookSubscription struct {
+	gorm.Model
+	ID     uuid.UUID  `gorm:"type:uuid;primaryKey;"`
+	UserID uuid.UUID  `gorm:"type:uuid;"`
+	TeamID *uuid.UUID `gorm:"type:uuid;"`
+	URL    string
+	// EventTypes are the subscribed domain events, all events if empty
+	EventTypes StringList
+	// Secret is the key of the HMAC-SHA256 signature of the payloads
+	Secret string
+	Active bool
+}
+
+func (subscription *WebhookSubscription) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	subscription.ID = id
+	return nil
+}
+
+// IsSubscribedTo returns whether the subscription wants the events of the given type.
+func (subscription *WebhookSubscription) IsSubscribedTo(eventType string) bool {
+	return len(subscription.EventTypes) == 0 || subscription.EventTypes.Contains(eventType)
+}
+
+// WebhookDelivery is an entry in the delivery log of a subscription. Every event is delivered once per subscription,
+// failed attempts are retried with increasing delays.
+type WebhookDelivery struct {
+	gorm.Model
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_webhook_delivery_event;"`
+	EventID        uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_webhook_delivery_event;"`
+	EventType      string
+	// Payload is the JSON body that is posted
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index;"`
+	LastAttemptAt  *time.Time
+	ResponseStatus int
+	LastError      string
+	DeliveredAt    *time.Time
+}
+
+func (delivery *WebhookDelivery) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	delivery.ID = id
+	return nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// WorkingTimeModel describes the contractual working time of a user in a team. It is valid from the given day until
+// the next model of the same user and team starts.
+type WorkingTimeModel struct {
+	gorm.Model
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey;"`
+	UserID             uuid.UUID `gorm:"type:uuid;"`
+	TeamID             uuid.UUID `gorm:"type:uuid;"`
+	ValidFrom          time.Time `gorm:"type:date;"`
+	PartTimePercentage int       // 100 means full time
+	MondayMinutes      int
+	TuesdayMinutes     int
+	WednesdayMinutes   int
+	ThursdayMinutes    int
+	FridayMinutes      int
+	SaturdayMinutes    int
+	SundayMinutes      int
+}
+
+func (workingTimeModel *WorkingTimeModel) BeforeCreate(db *gorm.DB) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	workingTimeModel.ID = id
+	return nil
+}
+
+// TargetTimeOfWeekday returns the working time the user has to work on the given weekday with the part time
+// percentage already applied.
+func (workingTimeModel *WorkingTimeModel) TargetTimeOfWeekday(weekday time.Weekday) time.Duration {
+	minutes := 0
+	switch weekday {
+	case time.Monday:
+		minutes = workingTimeModel.MondayMinutes
+	case time.Tuesday:
+		minutes = workingTimeModel.TuesdayMinutes
+	case time.Wednesday:
+		minutes = workingTimeModel.WednesdayMinutes
+	case time.Thursday:
+		minutes = workingTimeModel.ThursdayMinutes
+	case time.Friday:
+		minutes = workingTimeModel.FridayMinutes
+	case time.Saturday:
+		minutes = workingTimeModel.SaturdayMinutes
+	case time.Sunday:
+		minutes = workingTimeModel.SundayMinutes
+	}
+	return time.Duration(minutes*workingTimeModel.PartTimePercentage) * time.Minute / 100
+}
+
+// DailyTargetTime is the time a user has to work on a specific day (at midnight UTC). The parts of the working time
+// covered by holidays and absences are already subtracted from the target and stored separately.
+type DailyTargetTime struct {
+	Day     time.Time
+	Target  time.Duration
+	Holiday time.Duration
+	Absence time.Duration
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type AbsenceRepository interface {
+	AddAbsence(absence *model.Absence) error
+	UpdateAbsence(absence *model.Absence) error
+	DeleteAbsence(absence *model.Absence) error
+	GetAbsenceById(id uuid.UUID) (*model.Absence, error)
+	GetAbsencesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error)
+	GetAbsencesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type CalendarFeedRepository interface {
+	GetCalendarFeedTokenOfUser(userId uuid.UUID) (*model.CalendarFeedToken, error)
+	GetCalendarFeedTokenByHash(tokenHash string) (*model.CalendarFeedToken, error)
+	SaveCalendarFeedToken(token *model.CalendarFeedToken) error
+	DeleteCalendarFeedToken(token *model.CalendarFeedToken) error
+}
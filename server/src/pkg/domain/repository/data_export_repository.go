@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type DataExportRepository interface {
+	GetPersonalData(userId uuid.UUID) (*model.PersonalData, error)
+	CountTimeEntriesOfUser(userId uuid.UUID) (int64, error)
+	AddDataExport(dataExport *model.DataExport) error
+	GetDataExportById(id uuid.UUID) (*model.DataExport, error)
+	GetDataExportContent(id uuid.UUID) ([]byte, error)
+	GetDataExportsOfUser(userId uuid.UUID) ([]model.DataExport, error)
+	GetPendingDataExports(limit int) ([]model.DataExport, error)
+	UpdateDataExport(dataExport *model.DataExport) error
+	DeleteExpiredDataExports(now time.Time) (int64, error)
+}
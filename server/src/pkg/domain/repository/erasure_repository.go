@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type ErasureRepository interface {
+	AddErasureRequest(request *model.ErasureRequest) error
+	GetErasureRequestById(id uuid.UUID) (*model.ErasureRequest, error)
+	GetErasureRequestsOfUser(userId uuid.UUID) ([]model.ErasureRequest, error)
+	GetErasureRequestsWithStatus(statuses []string) ([]model.ErasureRequest, error)
+	GetDueErasureRequests(now time.Time) ([]model.ErasureRequest, error)
+	UpdateErasureRequest(request *model.ErasureRequest) error
+	// EraseUserData deletes or anonymises all data of the user of the request and completes the request with the
+	// receipt in one transaction, so that an erasure is never left without receipt.
+	EraseUserData(request *model.ErasureRequest, completedAt time.Time) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type HolidayCalendarRepository interface {
+	AddHolidayCalendar(calendar *model.HolidayCalendar) error
+	UpdateHolidayCalendar(calendar *model.HolidayCalendar) error
+	DeleteHolidayCalendar(calendar *model.HolidayCalendar) error
+	GetHolidayCalendarById(id uuid.UUID) (*model.HolidayCalendar, error)
+	GetHolidayCalendarsOfTeam(teamId uuid.UUID) ([]model.HolidayCalendar, error)
+	AddCustomHoliday(customHoliday *model.CustomHoliday) error
+	DeleteCustomHoliday(customHoliday *model.CustomHoliday) error
+	GetCustomHolidayById(id uuid.UUID) (*model.CustomHoliday, error)
+	GetCustomHolidaysOfCalendar(calendarId uuid.UUID, from time.Time, to time.Time) ([]model.CustomHoliday, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type ImportedTimeEntryRepository interface {
+	GetImportedTimeEntriesOfUser(userId uuid.UUID, source string) ([]model.ImportedTimeEntry, error)
+	// ImportTimeEntries adds the new projects, the time entries and their attributes of the source in one transaction
+	ImportTimeEntries(projects []model.Project, timeEntries []model.TimeEntry,
+		importedTimeEntries []model.ImportedTimeEntry) error
+}
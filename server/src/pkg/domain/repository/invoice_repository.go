@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type InvoiceRepository interface {
+	// GetBillableTimeEntries returns the finished, not yet invoiced time entries of the filter with their projects,
+	// which are billable because of their project. The billable flag of an imported entry overrides the flag of its project.
+	GetBillableTimeEntries(filter model.InvoiceFilter) ([]model.BillableTimeEntry, error)
+	// AddInvoice numbers the invoice, stores it with its lines and marks the time entries as invoiced. It fails if one
+	// of the time entries was invoiced meanwhile.
+	AddInvoice(invoice *model.Invoice, timeEntryIds []uuid.UUID) error
+	GetInvoiceById(id uuid.UUID) (*model.Invoice, error)
+	GetInvoicesOfIssuer(issuerId uuid.UUID) ([]model.Invoice, error)
+	GetTimeEntryIdsOfInvoice(id uuid.UUID) ([]uuid.UUID, error)
+	// CancelInvoice marks the invoice as cancelled and releases its time entries.
+	CancelInvoice(invoice *model.Invoice) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+)
+
+type JobRunRepository interface {
+	// TryLock acquires a lock for the job that is shared by all server instances. If the lock is held by another
+	// instance false is returned, otherwise the returned function releases the lock.
+	TryLock(name string) (func(), bool, error)
+	AddJobRun(jobRun *model.JobRun) error
+	UpdateJobRun(jobRun *model.JobRun) error
+	GetJobRun(name string, scheduledAt time.Time) (*model.JobRun, error)
+	GetJobRuns(name string, limit int) ([]model.JobRun, error)
+}
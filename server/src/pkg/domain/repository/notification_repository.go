@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type NotificationRepository interface {
+	AddNotification(notification *model.Notification) error
+	UpdateNotification(notification *model.Notification) error
+	GetNotificationById(id uuid.UUID) (*model.Notification, error)
+	GetNotificationsOfUser(userId uuid.UUID, onlyUnread bool) ([]model.Notification, error)
+	GetNotificationSettingsOfUser(userId uuid.UUID) (*model.NotificationSettings, error)
+	SaveNotificationSettings(settings *model.NotificationSettings) error
+	GetNotificationTemplate(notificationType string) (*model.NotificationTemplate, error)
+	GetAllNotificationTemplates() ([]model.NotificationTemplate, error)
+	SaveNotificationTemplate(template *model.NotificationTemplate) error
+	DeleteNotificationTemplate(template *model.NotificationTemplate) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+)
+
+type OutboxRepository interface {
+	// TryLock acquires the lock for publishing the outbox that is shared by all server instances. If the lock is held
+	// by another instance false is returned, otherwise the returned function releases the lock.
+	TryLock() (func(), bool, error)
+	// GetPendingOutboxEvents returns the oldest unpublished events that are due for an attempt at the given time.
+	GetPendingOutboxEvents(now time.Time, maxAttempts int, limit int) ([]model.OutboxEvent, error)
+	UpdateOutboxEvent(outboxEvent *model.OutboxEvent) error
+	DeletePublishedOutboxEvents(publishedBefore time.Time) (int64, error)
+}
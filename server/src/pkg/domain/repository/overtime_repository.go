@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type OvertimeRepository interface {
+	GetOvertimeAccountOfUser(userId uuid.UUID) (*model.OvertimeAccount, error)
+	AddOvertimeAccount(account *model.OvertimeAccount) error
+	UpdateOvertimeAccount(account *model.OvertimeAccount) error
+	AddOvertimeCorrection(correction *model.OvertimeCorrection) error
+	DeleteOvertimeCorrection(correction *model.OvertimeCorrection) error
+	GetOvertimeCorrectionById(id uuid.UUID) (*model.OvertimeCorrection, error)
+	GetOvertimeCorrectionsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.OvertimeCorrection, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type PeriodLockRepository interface {
+	SetLockDate(team *model.Team, change *model.PeriodLockChange) error
+	GetPeriodLockChangesOfTeam(teamId uuid.UUID) ([]model.PeriodLockChange, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type ReportTemplateRepository interface {
+	GetReportTemplateOfTeam(teamId uuid.UUID) (*model.ReportTemplate, error)
+	SaveReportTemplate(template *model.ReportTemplate) error
+	DeleteReportTemplate(template *model.ReportTemplate) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type RunningTimerRepository interface {
+	GetRunningTimeEntriesStartedBefore(t time.Time) ([]model.TimeEntry, error)
+	StopTimeEntry(timeEntry *model.TimeEntry, notice *model.RunningTimerNotice) error
+	AddRunningTimerNotice(notice *model.RunningTimerNotice) error
+	GetRunningTimerNoticesOfTimeEntry(timeEntryId uuid.UUID) ([]model.RunningTimerNotice, error)
+	UpdateRunningTimerPolicyOfTeam(teamId uuid.UUID, policy string) error
+	UpdateRunningTimerPolicyOfUser(userId uuid.UUID, teamId uuid.UUID, policy string) error
+}
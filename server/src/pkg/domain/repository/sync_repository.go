@@ -11,4 +11,5 @@ type SyncRepository interface {
 	UpdateAndDeleteData(data model.SyncData) error
 	GetUpdatedTimeEntriesOfUser(userId uuid.UUID, sinceWhen time.Time) ([]model.TimeEntry, error)
 	GetUpdatedProjectsOfUser(userId uuid.UUID, sinceWhen time.Time) ([]model.Project, error)
+	GetRunningTimerNoticesOfUser(userId uuid.UUID, sinceWhen time.Time) ([]model.RunningTimerNotice, error)
 }
@@ -14,6 +14,7 @@ type TeamRepository interface {
 	GetAllTeams() ([]model.Team, error)
 	AddUserTeamAssignment(teamAssignment *model.UserTeamAssignment) error
 	GetTeamsOfUser(userId uuid.UUID) ([]model.UserTeamAssignment, error)
+	GetUsersOfTeam(teamId uuid.UUID) ([]model.UserTeamAssignment, error)
 	GetUserTeamAssignment(userId uuid.UUID, teamId uuid.UUID) (*model.UserTeamAssignment, error)
 	DeleteUserTeamAssignment(teamAssignment *model.UserTeamAssignment) error
 	UpdateUserTeamAssignment(teamAssignment *model.UserTeamAssignment) error
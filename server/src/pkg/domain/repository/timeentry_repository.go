@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"time"
 	"timeasy-server/pkg/domain/model"
 
 	"github.com/gofrs/uuid"
@@ -14,5 +15,7 @@ type TimeEntryRepository interface {
 	DeleteTimeEntry(project *model.TimeEntry) error
 	GetTimeEntryById(id uuid.UUID) (*model.TimeEntry, error)
 	GetAllTimeEntriesOfUser(userId uuid.UUID) ([]model.TimeEntry, error)
+	GetTimeEntriesOfUserInRange(userId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error)
 	GetAllTimeEntriesOfUserAndProject(userId uuid.UUID, projectId uuid.UUID) ([]model.TimeEntry, error)
+	GetAllTimeEntriesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error)
 }
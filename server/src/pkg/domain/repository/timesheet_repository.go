@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type TimesheetRepository interface {
+	AddTimesheet(timesheet *model.Timesheet) error
+	UpdateTimesheet(timesheet *model.Timesheet) error
+	DeleteTimesheet(timesheet *model.Timesheet) error
+	GetTimesheetById(id uuid.UUID) (*model.Timesheet, error)
+	GetTimesheetsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error)
+	GetTimesheetsOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error)
+}
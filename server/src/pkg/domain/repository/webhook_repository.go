@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type WebhookRepository interface {
+	AddWebhookSubscription(subscription *model.WebhookSubscription) error
+	UpdateWebhookSubscription(subscription *model.WebhookSubscription) error
+	// DeleteWebhookSubscription deletes the subscription together with its delivery log
+	DeleteWebhookSubscription(subscription *model.WebhookSubscription) error
+	GetWebhookSubscriptionById(id uuid.UUID) (*model.WebhookSubscription, error)
+	// GetWebhookSubscriptionsOfUser returns the personal subscriptions of the user (without team)
+	GetWebhookSubscriptionsOfUser(userId uuid.UUID) ([]model.WebhookSubscription, error)
+	GetWebhookSubscriptionsOfTeam(teamId uuid.UUID) ([]model.WebhookSubscription, error)
+	// GetActiveWebhookSubscriptions returns the active personal subscriptions of the user and the active
+	// subscriptions of the team. Both ids are optional.
+	GetActiveWebhookSubscriptions(userId *uuid.UUID, teamId *uuid.UUID) ([]model.WebhookSubscription, error)
+
+	AddWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	GetWebhookDeliveryById(id uuid.UUID) (*model.WebhookDelivery, error)
+	GetWebhookDeliveryOfEvent(subscriptionId uuid.UUID, eventId uuid.UUID) (*model.WebhookDelivery, error)
+	// GetWebhookDeliveries returns the latest deliveries of the subscription first
+	GetWebhookDeliveries(subscriptionId uuid.UUID, limit int) ([]model.WebhookDelivery, error)
+	// GetDueWebhookDeliveries returns the pending deliveries whose next attempt is due at the given time
+	GetDueWebhookDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error)
+}
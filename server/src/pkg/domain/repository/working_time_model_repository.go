@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type WorkingTimeModelRepository interface {
+	AddWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error
+	UpdateWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error
+	DeleteWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error
+	GetWorkingTimeModelById(id uuid.UUID) (*model.WorkingTimeModel, error)
+	GetWorkingTimeModelsOfUser(userId uuid.UUID) ([]model.WorkingTimeModel, error)
+	GetWorkingTimeModelsOfUserInTeam(userId uuid.UUID, teamId uuid.UUID) ([]model.WorkingTimeModel, error)
+}
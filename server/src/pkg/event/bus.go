@@ -0,0 +1,248 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/glog"
+)
+
+const publishBatchSize = 100
+
+// MaxPublishAttempts limits the retries of an event. Events that still fail stay in the outbox with their last error.
+const MaxPublishAttempts = 20
+
+const firstRetryDelay = 5 * time.Second
+const maxRetryDelay = time.Hour
+
+// Metadata identifies a published event. It stays the same if the event is delivered again.
+type Metadata struct {
+	EventID    uuid.UUID
+	OccurredAt time.Time
+}
+
+// Handler reacts to a domain event, e.g. *model.TimeEntryCreated. Events are delivered at least once, so handlers
+// must be idempotent.
+type Handler func(ctx context.Context, metadata Metadata, event model.DomainEvent) error
+
+// EventBus publishes the domain events of the outbox to the subscribers. If several server instances share the
+// database only one of them publishes at a time.
+type EventBus interface {
+	// Subscribe registers a handler for the given event types, for all events if no type is given. Subscribers must
+	// be registered before the bus is started.
+	Subscribe(name string, handler Handler, eventTypes ...string) error
+	// PublishPending publishes the events that are due at the given time and returns how many were published.
+	PublishPending(now time.Time) (int, error)
+	// DeletePublishedEvents removes the published events from the outbox.
+	DeletePublishedEvents(publishedBefore time.Time) (int64, error)
+	// Start publishes the pending events in the given interval.
+	Start(interval time.Duration)
+	// Stop waits until the running publication is finished or the context expires.
+	Stop(ctx context.Context) error
+}
+
+type subscriber struct {
+	name       string
+	handler    Handler
+	eventTypes []string
+}
+
+func (subscriber *subscriber) isInterestedIn(eventType string) bool {
+	if len(subscriber.eventTypes) == 0 {
+		return true
+	}
+	for _, subscribedType := range subscriber.eventTypes {
+		if subscribedType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type eventBus struct {
+	repo        repository.OutboxRepository
+	subscribers []*subscriber
+	mutex       sync.Mutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	waitGroup   sync.WaitGroup
+	started     bool
+}
+
+func NewEventBus(repo repository.OutboxRepository) EventBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &eventBus{
+		repo:   repo,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (bus *eventBus) Subscribe(name string, handler Handler, eventTypes ...string) error {
+	for _, eventType := range eventTypes {
+		if !model.IsKnownDomainEventType(eventType) {
+			return fmt.Errorf("unknown domain event %v", eventType)
+		}
+	}
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.started {
+		return fmt.Errorf("subscriber %v cannot be registered after the event bus was started", name)
+	}
+	bus.subscribers = append(bus.subscribers, &subscriber{
+		name:       name,
+		handler:    handler,
+		eventTypes: eventTypes,
+	})
+	return nil
+}
+
+func (bus *eventBus) PublishPending(now time.Time) (int, error) {
+	unlock, locked, err := bus.repo.TryLock()
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		glog.V(1).Info("the outbox is published by another instance")
+		return 0, nil
+	}
+	defer unlock()
+
+	published := 0
+	for {
+		outboxEvents, err := bus.repo.GetPendingOutboxEvents(now, MaxPublishAttempts, publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for i := range outboxEvents {
+			ok, err := bus.publish(&outboxEvents[i], now)
+			if err != nil {
+				return published, err
+			}
+			if ok {
+				published++
+			}
+		}
+		if len(outboxEvents) < publishBatchSize || bus.ctx.Err() != nil {
+			return published, nil
+		}
+	}
+}
+
+// publish hands the event to all interested subscribers. If one of them fails the event is retried later with an
+// increasing delay. The returned error is only set if the outbox could not be updated.
+func (bus *eventBus) publish(outboxEvent *model.OutboxEvent, now time.Time) (bool, error) {
+	err := bus.handle(outboxEvent)
+	outboxEvent.Attempts++
+	if err == nil {
+		publishedAt := time.Now().UTC()
+		outboxEvent.PublishedAt = &publishedAt
+		outboxEvent.LastError = ""
+	} else {
+		outboxEvent.LastError = err.Error()
+		outboxEvent.NextAttemptAt = now.Add(getRetryDelay(outboxEvent.Attempts))
+		if outboxEvent.Attempts >= MaxPublishAttempts {
+			glog.Errorf("giving up publishing event %v (%v): %v", outboxEvent.ID, outboxEvent.Type, err)
+		} else {
+			glog.Warningf("publishing event %v (%v) failed: %v", outboxEvent.ID, outboxEvent.Type, err)
+		}
+	}
+	if err := bus.repo.UpdateOutboxEvent(outboxEvent); err != nil {
+		return false, err
+	}
+	return outboxEvent.PublishedAt != nil, nil
+}
+
+func (bus *eventBus) handle(outboxEvent *model.OutboxEvent) error {
+	event, err := model.DecodeDomainEvent(outboxEvent)
+	if err != nil {
+		return err
+	}
+	metadata := Metadata{
+		EventID:    outboxEvent.ID,
+		OccurredAt: outboxEvent.OccurredAt,
+	}
+	var firstErr error
+	for _, subscriber := range bus.subscribers {
+		if !subscriber.isInterestedIn(outboxEvent.Type) {
+			continue
+		}
+		if err := bus.execute(subscriber, metadata, event); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("subscriber %v failed: %v", subscriber.name, err)
+		}
+	}
+	return firstErr
+}
+
+// execute runs the handler and turns a panic into an error, so that a broken subscriber does not stop the server.
+func (bus *eventBus) execute(subscriber *subscriber, metadata Metadata, event model.DomainEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panicked: %v", recovered)
+		}
+	}()
+	return subscriber.handler(bus.ctx, metadata, event)
+}
+
+// getRetryDelay doubles the delay with every failed attempt.
+func getRetryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+func (bus *eventBus) DeletePublishedEvents(publishedBefore time.Time) (int64, error) {
+	return bus.repo.DeletePublishedOutboxEvents(publishedBefore)
+}
+
+func (bus *eventBus) Start(interval time.Duration) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	if bus.started {
+		return
+	}
+	bus.started = true
+	bus.waitGroup.Add(1)
+	go bus.loop(interval)
+}
+
+func (bus *eventBus) Stop(ctx context.Context) error {
+	bus.cancel()
+	done := make(chan bool)
+	go func() {
+		bus.waitGroup.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (bus *eventBus) loop(interval time.Duration) {
+	defer bus.waitGroup.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-bus.ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := bus.PublishPending(time.Now().UTC()); err != nil {
+				glog.Errorf("publishing the domain events failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/test"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	log.Println("Testmain")
+
+	pool, resource := test.SetupDatabase()
+	code := m.Run()
+	test.TeardownDatabase(pool, resource)
+
+	os.Exit(code)
+}
+
+func newTestEventBus(t *testing.T) *eventBus {
+	return NewEventBus(database.NewGormOutboxRepository(test.DB)).(*eventBus)
+}
+
+func addProject(t *testing.T, name string) *model.Project {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	project := model.Project{Name: name, UserId: userId}
+	err = database.NewGormProjectRepository(test.DB, database.NewGormTeamRepository(test.DB)).AddProject(&project)
+	assert.Nil(t, err)
+	return &project
+}
+
+func Test_eventBus_PublishesTypedEventsOfCommittedChanges(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	bus := newTestEventBus(t)
+	var received []model.DomainEvent
+	err := bus.Subscribe("test", func(ctx context.Context, metadata Metadata, event model.DomainEvent) error {
+		received = append(received, event)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	project := addProject(t, "project")
+	timeEntryRepository := database.NewGormTimeEntryRepository(test.DB)
+	timeEntry := model.TimeEntry{
+		UserId:    project.UserId,
+		ProjectId: project.ID,
+		StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC),
+	}
+	err = timeEntryRepository.AddTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	timeEntry.EndTime = time.Date(2023, 9, 4, 12, 0, 0, 0, time.UTC)
+	err = timeEntryRepository.UpdateTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	err = timeEntryRepository.DeleteTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+
+	published, err := bus.PublishPending(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 4, published)
+	assert.Equal(t, 4, len(received))
+
+	projectCreated, ok := received[0].(*model.ProjectCreated)
+	assert.True(t, ok)
+	assert.Equal(t, project.ID, projectCreated.ProjectID)
+	assert.Equal(t, "project", projectCreated.Name)
+
+	timeEntryCreated, ok := received[1].(*model.TimeEntryCreated)
+	assert.True(t, ok)
+	assert.Equal(t, timeEntry.ID, timeEntryCreated.TimeEntryID)
+	assert.Nil(t, timeEntryCreated.EndTime)
+
+	timeEntryUpdated, ok := received[2].(*model.TimeEntryUpdated)
+	assert.True(t, ok)
+	assert.True(t, timeEntry.EndTime.Equal(*timeEntryUpdated.EndTime))
+
+	_, ok = received[3].(*model.TimeEntryDeleted)
+	assert.True(t, ok)
+
+	// Published events are not delivered again
+	published, err = bus.PublishPending(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, 4, len(received))
+}
+
+func Test_eventBus_NoEventsOfRolledBackChanges(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	err = test.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.Project{Name: "project", UserId: userId}).Error; err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	assert.NotNil(t, err)
+
+	var count int64
+	err = test.DB.Model(&model.OutboxEvent{}).Count(&count).Error
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func Test_eventBus_OnlySubscribedEventTypesAreDelivered(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	bus := newTestEventBus(t)
+	var received []model.DomainEvent
+	err := bus.Subscribe("test", func(ctx context.Context, metadata Metadata, event model.DomainEvent) error {
+		received = append(received, event)
+		return nil
+	}, model.EventUserAddedToTeam)
+	assert.Nil(t, err)
+
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := model.Team{Name1: "team"}
+	teamRepository := database.NewGormTeamRepository(test.DB)
+	err = teamRepository.AddTeam(&team)
+	assert.Nil(t, err)
+	err = teamRepository.AddUserTeamAssignment(&model.UserTeamAssignment{
+		UserID: userId,
+		TeamID: team.ID,
+		Roles:  model.RoleList{model.RoleUser},
+	})
+	assert.Nil(t, err)
+
+	published, err := bus.PublishPending(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, 1, len(received))
+	userAdded, ok := received[0].(*model.UserAddedToTeam)
+	assert.True(t, ok)
+	assert.Equal(t, team.ID, userAdded.TeamID)
+	assert.Equal(t, userId, userAdded.UserID)
+	assert.Equal(t, model.RoleList{model.RoleUser}, userAdded.Roles)
+}
+
+func Test_eventBus_FailedEventsAreRetriedLater(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	bus := newTestEventBus(t)
+	calls := 0
+	err := bus.Subscribe("flaky", func(ctx context.Context, metadata Metadata, event model.DomainEvent) error {
+		calls++
+		if calls == 1 {
+			return errors.New("not reachable")
+		}
+		if calls == 2 {
+			panic("broken")
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	addProject(t, "project")
+
+	now := time.Now().UTC()
+	published, err := bus.PublishPending(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+
+	var outboxEvent model.OutboxEvent
+	err = test.DB.First(&outboxEvent).Error
+	assert.Nil(t, err)
+	assert.Equal(t, 1, outboxEvent.Attempts)
+	assert.Nil(t, outboxEvent.PublishedAt)
+	assert.Contains(t, outboxEvent.LastError, "not reachable")
+
+	// Not due yet
+	published, err = bus.PublishPending(now.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, 1, calls)
+
+	published, err = bus.PublishPending(now.Add(firstRetryDelay))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+	err = test.DB.First(&outboxEvent).Error
+	assert.Nil(t, err)
+	assert.Contains(t, outboxEvent.LastError, "panicked")
+
+	published, err = bus.PublishPending(now.Add(firstRetryDelay + 2*firstRetryDelay))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, published)
+	err = test.DB.First(&outboxEvent).Error
+	assert.Nil(t, err)
+	assert.Equal(t, 3, outboxEvent.Attempts)
+	assert.NotNil(t, outboxEvent.PublishedAt)
+	assert.Equal(t, "", outboxEvent.LastError)
+}
+
+func Test_eventBus_PublishIsSkippedWhileLockedByAnotherInstance(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	repo := database.NewGormOutboxRepository(test.DB)
+	unlock, locked, err := repo.TryLock()
+	assert.Nil(t, err)
+	assert.True(t, locked)
+	addProject(t, "project")
+
+	bus := newTestEventBus(t)
+	published, err := bus.PublishPending(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+
+	unlock()
+	published, err = bus.PublishPending(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, published)
+}
+
+func Test_eventBus_DeletePublishedEvents(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	bus := newTestEventBus(t)
+	addProject(t, "project")
+	_, err := bus.PublishPending(time.Now().UTC())
+	assert.Nil(t, err)
+	addProject(t, "pending")
+
+	deleted, err := bus.DeletePublishedEvents(time.Now().UTC().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	var outboxEvents []model.OutboxEvent
+	err = test.DB.Find(&outboxEvents).Error
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(outboxEvents))
+	assert.Nil(t, outboxEvents[0].PublishedAt)
+}
+
+func Test_eventBus_SubscribeFailsForUnknownEventType(t *testing.T) {
+	bus := newTestEventBus(t)
+	err := bus.Subscribe("test", func(ctx context.Context, metadata Metadata, event model.DomainEvent) error {
+		return nil
+	}, "SomethingHappened")
+	assert.NotNil(t, err)
+
+	bus.Start(time.Hour)
+	err = bus.Subscribe("test", func(ctx context.Context, metadata Metadata, event model.DomainEvent) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+	err = bus.Stop(context.Background())
+	assert.Nil(t, err)
+}
+
+func Test_getRetryDelay(t *testing.T) {
+	assert.Equal(t, firstRetryDelay, getRetryDelay(1))
+	assert.Equal(t, 4*firstRetryDelay, getRetryDelay(3))
+	assert.Equal(t, maxRetryDelay, getRetryDelay(MaxPublishAttempts))
+}
@@ -0,0 +1,123 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Columns of the CSV export
+const (
+	ColumnDate        = "date"
+	ColumnStart       = "start"
+	ColumnEnd         = "end"
+	ColumnDuration    = "duration"
+	ColumnNetDuration = "netduration"
+	ColumnProject     = "project"
+	ColumnDescription = "description"
+	ColumnUser        = "user"
+)
+
+// DefaultColumns are exported if no columns are configured.
+var DefaultColumns = []string{ColumnDate, ColumnStart, ColumnEnd, ColumnDuration, ColumnNetDuration, ColumnProject,
+	ColumnDescription, ColumnUser}
+
+type CsvOptions struct {
+	Columns []string
+	// Delimiter separates the fields, a comma is used if it is not set
+	Delimiter rune
+	// DecimalComma writes the durations with a decimal comma instead of a point (e.g. for German Excel)
+	DecimalComma bool
+	// Location is the time zone of dates and times, UTC is used if it is not set
+	Location *time.Location
+}
+
+// ParseColumns parses a comma separated list of columns. An empty list results in the default columns.
+func ParseColumns(columnList string) ([]string, error) {
+	if strings.TrimSpace(columnList) == "" {
+		return DefaultColumns, nil
+	}
+	var columns []string
+	for _, column := range strings.Split(columnList, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !isKnownColumn(column) {
+			return nil, fmt.Errorf("unknown column %v, allowed are %v", column, strings.Join(DefaultColumns, ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// WriteCsv writes a header line with the column names and one line per row.
+// Durations are written in decimal hours.
+func WriteCsv(writer io.Writer, rows []Row, options CsvOptions) error {
+	if len(options.Columns) == 0 {
+		options.Columns = DefaultColumns
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	csvWriter := csv.NewWriter(writer)
+	if options.Delimiter != 0 {
+		csvWriter.Comma = options.Delimiter
+	}
+	if err := csvWriter.Write(options.Columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(options.Columns))
+		for i, column := range options.Columns {
+			record[i] = formatColumn(row, column, options)
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatColumn(row Row, column string, options CsvOptions) string {
+	timeEntry := row.TimeEntry
+	switch column {
+	case ColumnDate:
+		return timeEntry.StartTime.In(options.Location).Format(dateFormat)
+	case ColumnStart:
+		return formatTime(timeEntry.StartTime, options.Location)
+	case ColumnEnd:
+		return formatTime(timeEntry.EndTime, options.Location)
+	case ColumnDuration:
+		return formatHours(timeEntry.Duration(), options.DecimalComma)
+	case ColumnNetDuration:
+		return formatHours(row.NetDuration, options.DecimalComma)
+	case ColumnProject:
+		return escapeFormula(timeEntry.Project.Name)
+	case ColumnDescription:
+		return escapeFormula(timeEntry.Description)
+	case ColumnUser:
+		return timeEntry.UserId.String()
+	default:
+		return ""
+	}
+}
+
+// escapeFormula prefixes texts that spreadsheets would run as formula with an apostrophe, so that a description
+// like "=HYPERLINK(...)" is shown as text when the export is opened in Excel. Leading tabs and carriage returns
+// start formulas as well.
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+	return value
+}
+
+func isKnownColumn(column string) bool {
+	for _, knownColumn := range DefaultColumns {
+		if column == knownColumn {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteCsv(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	timeEntry := entry(userId, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 90*time.Minute)
+	timeEntry.Description = "meeting, planning"
+	timeEntry.Project = model.Project{Name: "project"}
+	running := model.TimeEntry{UserId: userId, StartTime: time.Date(2023, 9, 5, 7, 0, 0, 0, time.UTC)}
+
+	var buffer bytes.Buffer
+	err := WriteCsv(&buffer, NewRows([]model.TimeEntry{running, timeEntry}), CsvOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "date,start,end,duration,netduration,project,description,user\n"+
+		"2023-09-04,08:00,09:30,1.50,1.50,project,\"meeting, planning\","+userId.String()+"\n"+
+		"2023-09-05,07:00,,0.00,0.00,,,"+userId.String()+"\n", buffer.String())
+}
+
+func Test_WriteCsvForGermanExcel(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	assert.Nil(t, err)
+	timeEntry := entry(uuid.Must(uuid.NewV4()), time.Date(2023, 9, 4, 22, 30, 0, 0, time.UTC), 45*time.Minute)
+
+	var buffer bytes.Buffer
+	err = WriteCsv(&buffer, NewRows([]model.TimeEntry{timeEntry}), CsvOptions{
+		Columns:      []string{ColumnDate, ColumnStart, ColumnEnd, ColumnDuration},
+		Delimiter:    ';',
+		DecimalComma: true,
+		Location:     location,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "date;start;end;duration\n2023-09-05;00:30;01:15;0,75\n", buffer.String())
+}
+
+func Test_WriteCsvEscapesFormulas(t *testing.T) {
+	var timeEntries []model.TimeEntry
+	for _, description := range []string{"=1+2", "+1", "-1", "@SUM(A1)", "\t=1", "\r=1", "a=b"} {
+		timeEntry := entry(uuid.Must(uuid.NewV4()), time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+		timeEntry.Description = description
+		timeEntry.Project = model.Project{Name: "=project"}
+		timeEntries = append(timeEntries, timeEntry)
+	}
+
+	var buffer bytes.Buffer
+	err := WriteCsv(&buffer, NewRows(timeEntries), CsvOptions{Columns: []string{ColumnProject, ColumnDescription}})
+	assert.Nil(t, err)
+	assert.Equal(t, "project,description\n'=project,'=1+2\n'=project,'+1\n'=project,'-1\n'=project,'@SUM(A1)\n"+
+		"'=project,'\t=1\n'=project,\"'\r=1\"\n'=project,a=b\n", buffer.String())
+}
+
+func Test_ParseColumns(t *testing.T) {
+	columns, err := ParseColumns("")
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultColumns, columns)
+
+	columns, err = ParseColumns("Date, duration,project")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{ColumnDate, ColumnDuration, ColumnProject}, columns)
+
+	_, err = ParseColumns("date,client")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"timeasy-server/pkg/compliance"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	dateFormat = "2006-01-02"
+	timeFormat = "15:04"
+)
+
+// Row is a time entry prepared for an export. The project of the time entry has to be loaded.
+type Row struct {
+	TimeEntry model.TimeEntry
+	// NetDuration is the duration of the time entry without the part of the statutory break that was not taken
+	NetDuration time.Duration
+}
+
+// NewRows sorts the time entries by start time and calculates their net durations. If the breaks between the
+// entries of a user on a day are shorter than required by the working time law, the missing break is deducted
+// from the last entries of the day.
+func NewRows(timeEntries []model.TimeEntry) []Row {
+	rows := make([]Row, len(timeEntries))
+	for i, timeEntry := range timeEntries {
+		rows[i] = Row{TimeEntry: timeEntry, NetDuration: timeEntry.Duration()}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].TimeEntry.StartTime.Before(rows[j].TimeEntry.StartTime)
+	})
+
+	entriesByUser := make(map[uuid.UUID][]model.TimeEntry)
+	for _, timeEntry := range timeEntries {
+		entriesByUser[timeEntry.UserId] = append(entriesByUser[timeEntry.UserId], timeEntry)
+	}
+	for userId, entriesOfUser := range entriesByUser {
+		for day, missingBreak := range compliance.MissingBreaks(entriesOfUser) {
+			for i := len(rows) - 1; i >= 0 && missingBreak > 0; i-- {
+				row := &rows[i]
+				if row.TimeEntry.UserId != userId || !startOfDay(row.TimeEntry.StartTime).Equal(day) {
+					continue
+				}
+				deduction := missingBreak
+				if deduction > row.NetDuration {
+					deduction = row.NetDuration
+				}
+				row.NetDuration -= deduction
+				missingBreak -= deduction
+			}
+		}
+	}
+	return rows
+}
+
+// startOfDay returns the beginning of the day (UTC) of the given time like the compliance check does.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// formatTime formats the time of day in the location, a zero time results in an empty string.
+func formatTime(t time.Time, location *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(location).Format(timeFormat)
+}
+
+// formatHours formats the duration as decimal hours with two decimal places.
+func formatHours(duration time.Duration, decimalComma bool) string {
+	hours := strconv.FormatFloat(duration.Hours(), 'f', 2, 64)
+	if decimalComma {
+		return strings.Replace(hours, ".", ",", 1)
+	}
+	return hours
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewRowsDeductsMissingBreakFromLastEntries(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	otherUserId := uuid.Must(uuid.NewV4())
+	rows := NewRows([]model.TimeEntry{
+		entry(userId, time.Date(2023, 9, 4, 12, 10, 0, 0, time.UTC), 20*time.Minute),
+		entry(userId, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 4*time.Hour),
+		entry(userId, time.Date(2023, 9, 4, 12, 30, 0, 0, time.UTC), 2*time.Hour),
+		entry(otherUserId, time.Date(2023, 9, 4, 9, 0, 0, 0, time.UTC), 5*time.Hour),
+	})
+	assert.Equal(t, 4, len(rows))
+	// sorted by start time:
+	assert.Equal(t, 4*time.Hour, rows[0].NetDuration)
+	assert.Equal(t, otherUserId, rows[1].TimeEntry.UserId)
+	assert.Equal(t, 5*time.Hour, rows[1].NetDuration)
+	// 6h20m without a break, the missing 30 minutes are deducted from the last entries:
+	assert.Equal(t, 20*time.Minute, rows[2].NetDuration)
+	assert.Equal(t, 20*time.Minute, rows[2].TimeEntry.Duration())
+	assert.Equal(t, 90*time.Minute, rows[3].NetDuration)
+}
+
+func Test_NewRowsDeductsMissingBreakAcrossEntries(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	rows := NewRows([]model.TimeEntry{
+		entry(userId, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 6*time.Hour),
+		entry(userId, time.Date(2023, 9, 4, 14, 0, 0, 0, time.UTC), 10*time.Minute),
+	})
+	assert.Equal(t, 6*time.Hour-20*time.Minute, rows[0].NetDuration)
+	assert.Equal(t, time.Duration(0), rows[1].NetDuration)
+}
+
+func Test_formatHours(t *testing.T) {
+	assert.Equal(t, "7.50", formatHours(7*time.Hour+30*time.Minute, false))
+	assert.Equal(t, "7,50", formatHours(7*time.Hour+30*time.Minute, true))
+	assert.Equal(t, "0.33", formatHours(20*time.Minute, false))
+}
+
+func entry(userId uuid.UUID, startTime time.Time, duration time.Duration) model.TimeEntry {
+	return model.TimeEntry{
+		UserId:    userId,
+		StartTime: startTime,
+		EndTime:   startTime.Add(duration),
+	}
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalTimeFormat = "20060102T150405Z"
+	// icalMaxLineLength is the maximum length of a content line in octets, longer lines are folded (RFC 5545 3.1)
+	icalMaxLineLength = 75
+)
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// WriteICalendar writes the rows as iCalendar (RFC 5545) with one event per time entry. The project name is the
+// summary and the description of the time entry the description of the event. Running entries are left out because
+// they have no end yet. The time stamp of the events is the given time.
+func WriteICalendar(writer io.Writer, rows []Row, calendarName string, now time.Time) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//timeasy//timeasy-server//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + icalTextEscaper.Replace(calendarName),
+	}
+	for _, line := range lines {
+		writeICalendarLine(bufferedWriter, line)
+	}
+	for _, row := range rows {
+		timeEntry := row.TimeEntry
+		if timeEntry.Duration() == 0 {
+			continue
+		}
+		lines = []string{
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%v@timeasy", timeEntry.ID),
+			"DTSTAMP:" + now.UTC().Format(icalTimeFormat),
+			"DTSTART:" + timeEntry.StartTime.UTC().Format(icalTimeFormat),
+			"DTEND:" + timeEntry.EndTime.UTC().Format(icalTimeFormat),
+			"SUMMARY:" + icalTextEscaper.Replace(timeEntry.Project.Name),
+		}
+		if timeEntry.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+icalTextEscaper.Replace(timeEntry.Description))
+		}
+		lines = append(lines, "TRANSP:TRANSPARENT", "END:VEVENT")
+		for _, line := range lines {
+			writeICalendarLine(bufferedWriter, line)
+		}
+	}
+	writeICalendarLine(bufferedWriter, "END:VCALENDAR")
+	return bufferedWriter.Flush()
+}
+
+// writeICalendarLine writes the content line terminated by CRLF. Lines longer than 75 octets are folded without
+// splitting UTF-8 characters, the continuation lines start with a space. Errors are reported by the final Flush.
+func writeICalendarLine(writer *bufio.Writer, line string) {
+	maxLength := icalMaxLineLength
+	for len(line) > maxLength {
+		cut := maxLength
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		_, _ = writer.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the leading space of the continuation line counts as well
+		maxLength = icalMaxLineLength - 1
+	}
+	_, _ = writer.WriteString(line + "\r\n")
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteICalendar(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	timeEntry := entry(userId, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 90*time.Minute)
+	timeEntry.ID = uuid.Must(uuid.NewV4())
+	timeEntry.Project = model.Project{Name: "Website, Relaunch"}
+	timeEntry.Description = "Planning; review\nfollow-up"
+	running := model.TimeEntry{UserId: userId, StartTime: time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC)}
+
+	var buffer bytes.Buffer
+	err := WriteICalendar(&buffer, NewRows([]model.TimeEntry{timeEntry, running}), "Timeasy",
+		time.Date(2023, 9, 6, 12, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//timeasy//timeasy-server//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"METHOD:PUBLISH\r\n"+
+		"X-WR-CALNAME:Timeasy\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:"+timeEntry.ID.String()+"@timeasy\r\n"+
+		"DTSTAMP:20230906T120000Z\r\n"+
+		"DTSTART:20230904T080000Z\r\n"+
+		"DTEND:20230904T093000Z\r\n"+
+		"SUMMARY:Website\\, Relaunch\r\n"+
+		"DESCRIPTION:Planning\\; review\\nfollow-up\r\n"+
+		"TRANSP:TRANSPARENT\r\n"+
+		"END:VEVENT\r\n"+
+		"END:VCALENDAR\r\n", buffer.String())
+}
+
+func Test_WriteICalendarFoldsLongLines(t *testing.T) {
+	timeEntry := entry(uuid.Must(uuid.NewV4()), time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+	timeEntry.Description = strings.Repeat("Ä", 100)
+
+	var buffer bytes.Buffer
+	err := WriteICalendar(&buffer, NewRows([]model.TimeEntry{timeEntry}), "Timeasy", time.Now())
+	assert.Nil(t, err)
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), icalMaxLineLength)
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+		} else {
+			unfolded.WriteString("\n" + line)
+		}
+	}
+	assert.Contains(t, unfolded.String(), "\nDESCRIPTION:"+strings.Repeat("Ä", 100)+"\n")
+}
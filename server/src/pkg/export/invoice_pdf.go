@@ -0,0 +1,122 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// WriteInvoicePdf writes the invoice as A4 PDF: a header with number, date, issuer, client and period, and the table
+// of the lines with the totals. German invoices use the decimal comma.
+func WriteInvoicePdf(writer io.Writer, invoice model.Invoice, issuerName string, language string) error {
+	if !IsKnownLanguage(language) {
+		return fmt.Errorf("unknown language %v", language)
+	}
+	if language == "" {
+		language = "en"
+	}
+	labels := labelsByLanguage[language]
+	decimalComma := language == "de"
+	title := fmt.Sprintf("%v %v", labels["invoice"], invoice.Number)
+	switch invoice.Status {
+	case model.InvoiceStatusDraft:
+		title += fmt.Sprintf(" (%v)", labels["draft"])
+	case model.InvoiceStatusCancelled:
+		title += fmt.Sprintf(" (%v)", labels["cancelled"])
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin+pdfLineHeight)
+	pdf.SetTitle(title, true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin - pdfLineHeight)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, pdfLineHeight, translate(fmt.Sprintf("%v %d/{nb}", labels["page"], pdf.PageNo())), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, translate(title), "", 1, "L", false, 0, "")
+	headerLines := [][2]string{
+		{labels["number"], invoice.Number},
+		{labels["invoiceDate"], invoice.CreatedAt.UTC().Format(dateFormat)},
+		{labels["team"], issuerName},
+	}
+	if invoice.TeamID == nil {
+		headerLines[2][0] = labels["user"]
+	}
+	if invoice.Client != "" {
+		headerLines = append(headerLines, [2]string{labels["client"], invoice.Client})
+	}
+	headerLines = append(headerLines, [2]string{labels["period"], formatPeriod(invoice.From, invoice.To)})
+	for _, headerLine := range headerLines {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(30, pdfLineHeight, translate(headerLine[0]+":"), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, pdfLineHeight, translate(headerLine[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(pdfLineHeight)
+
+	columns := []pdfColumn{{labels["date"], 22, "L"}, {labels["project"], 35, "L"}, {labels["task"], 55, "L"},
+		{labels["hours"], 18, "R"}, {labels["rate"], 25, "R"}, {labels["amount"], 25, "R"}}
+	writePdfTableHeader(pdf, columns, translate)
+	_, pageHeight := pdf.GetPageSize()
+	for _, line := range invoice.Lines {
+		if pdf.GetY()+pdfLineHeight > pageHeight-pdfMargin-pdfLineHeight {
+			pdf.AddPage()
+			writePdfTableHeader(pdf, columns, translate)
+		}
+		texts := []string{
+			line.Day.Format(dateFormat),
+			line.ProjectName,
+			line.Task,
+			formatHours(time.Duration(line.BilledMinutes)*time.Minute, decimalComma),
+			formatAmount(line.HourlyRate, invoice.Currency, decimalComma),
+			formatAmount(line.Amount, invoice.Currency, decimalComma),
+		}
+		pdf.SetFont("Helvetica", "", 9)
+		for i, column := range columns {
+			text := fitText(pdf, translate(texts[i]), column.width-2)
+			pdf.CellFormat(column.width, pdfLineHeight, text, "B", 0, column.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(columns[0].width+columns[1].width+columns[2].width, pdfLineHeight, translate(labels["total"]), "", 0, "L", false, 0, "")
+	pdf.CellFormat(columns[3].width, pdfLineHeight,
+		formatHours(time.Duration(invoice.TotalMinutes)*time.Minute, decimalComma), "", 0, "R", false, 0, "")
+	pdf.CellFormat(columns[4].width, pdfLineHeight, "", "", 0, "R", false, 0, "")
+	pdf.CellFormat(columns[5].width, pdfLineHeight,
+		translate(formatAmount(invoice.TotalAmount, invoice.Currency, decimalComma)), "", 1, "R", false, 0, "")
+	return pdf.Output(writer)
+}
+
+// formatAmount formats the amount in cents with thousands separators and the currency, e.g. 1,234.50 EUR or
+// 1.234,50 EUR with decimal comma.
+func formatAmount(cents int64, currency string, decimalComma bool) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	units := strconv.FormatInt(cents/100, 10)
+	thousandsSeparator, decimalSeparator := ",", "."
+	if decimalComma {
+		thousandsSeparator, decimalSeparator = ".", ","
+	}
+	var groups []string
+	for len(units) > 3 {
+		groups = append([]string{units[len(units)-3:]}, groups...)
+		units = units[:len(units)-3]
+	}
+	groups = append([]string{units}, groups...)
+	return fmt.Sprintf("%v%v%v%02d %v", sign, strings.Join(groups, thousandsSeparator), decimalSeparator, cents%100, currency)
+}
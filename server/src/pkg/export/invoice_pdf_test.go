@@ -0,0 +1,53 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteInvoicePdf(t *testing.T) {
+	teamId := uuid.Must(uuid.NewV4())
+	invoice := model.Invoice{
+		Number:       "2023-0001",
+		TeamID:       &teamId,
+		Status:       model.InvoiceStatusDraft,
+		Client:       "ACME",
+		From:         time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:           time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		Currency:     "EUR",
+		TotalMinutes: 615,
+		TotalAmount:  123450,
+		Lines: []model.InvoiceLine{
+			{Day: time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), ProjectName: "Website", Task: "Gestaltung für Müller",
+				BilledMinutes: 615, HourlyRate: 12000, Amount: 123450},
+		},
+	}
+	invoice.CreatedAt = time.Date(2023, 10, 2, 9, 0, 0, 0, time.UTC)
+
+	var buffer bytes.Buffer
+	err := WriteInvoicePdf(&buffer, invoice, "Team A", "de")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(buffer.String(), "%PDF-"))
+	text := readPdfText(t, buffer.Bytes())
+	for _, expected := range []string{`Rechnung 2023-0001 \(Entwurf\)`, "2023-10-02", "Team A", "ACME",
+		"2023-09-01 - 2023-09-30", "Gestaltung f\xfcr M\xfcller", "10,25", "120,00 EUR", "1.234,50 EUR", "Summe",
+		"Seite 1/1"} {
+		assert.Contains(t, text, expected)
+	}
+
+	err = WriteInvoicePdf(&buffer, invoice, "Team A", "fr")
+	assert.NotNil(t, err)
+}
+
+func Test_formatAmount(t *testing.T) {
+	assert.Equal(t, "0.05 EUR", formatAmount(5, "EUR", false))
+	assert.Equal(t, "1,234,567.89 USD", formatAmount(123456789, "USD", false))
+	assert.Equal(t, "1.234,50 EUR", formatAmount(123450, "EUR", true))
+	assert.Equal(t, "-999,00 EUR", formatAmount(-99900, "EUR", true))
+}
@@ -0,0 +1,69 @@
+package export
+
+// labelsByLanguage are the texts of the exports by language
+var labelsByLanguage = map[string]map[string]string{
+	"en": {
+		"sheet":       "Time entries",
+		"timesheet":   "Timesheet",
+		"date":        "Date",
+		"start":       "Start",
+		"end":         "End",
+		"pause":       "Break",
+		"project":     "Project",
+		"description": "Description",
+		"user":        "User",
+		"employee":    "Employee",
+		"team":        "Team",
+		"client":      "Client",
+		"period":      "Period",
+		"duration":    "Duration",
+		"dayTotal":    "Day total",
+		"total":       "Total",
+		"signature":   "Date, signature",
+		"page":        "Page",
+		"invoice":     "Invoice",
+		"draft":       "Draft",
+		"cancelled":   "Cancelled",
+		"number":      "Number",
+		"invoiceDate": "Date",
+		"task":        "Task",
+		"hours":       "Hours",
+		"rate":        "Rate",
+		"amount":      "Amount",
+	},
+	"de": {
+		"sheet":       "Zeiteinträge",
+		"timesheet":   "Stundenzettel",
+		"date":        "Datum",
+		"start":       "Start",
+		"end":         "Ende",
+		"pause":       "Pause",
+		"project":     "Projekt",
+		"description": "Beschreibung",
+		"user":        "Benutzer",
+		"employee":    "Mitarbeiter",
+		"team":        "Team",
+		"client":      "Kunde",
+		"period":      "Zeitraum",
+		"duration":    "Dauer",
+		"dayTotal":    "Tagessumme",
+		"total":       "Summe",
+		"signature":   "Datum, Unterschrift",
+		"page":        "Seite",
+		"invoice":     "Rechnung",
+		"draft":       "Entwurf",
+		"cancelled":   "Storniert",
+		"number":      "Nummer",
+		"invoiceDate": "Datum",
+		"task":        "Tätigkeit",
+		"hours":       "Stunden",
+		"rate":        "Satz",
+		"amount":      "Betrag",
+	},
+}
+
+// IsKnownLanguage returns true if the labels are available in the language, an empty language selects English.
+func IsKnownLanguage(language string) bool {
+	_, ok := labelsByLanguage[language]
+	return language == "" || ok
+}
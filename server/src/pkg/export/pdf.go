@@ -0,0 +1,238 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Timesheet is the content of a PDF timesheet of either a user or a project of a team.
+type Timesheet struct {
+	Template model.ReportTemplate
+	TeamName string
+	// UserID is set for the timesheet of a user, ProjectName for the timesheet of a project
+	UserID      *uuid.UUID
+	ProjectName string
+	// From and To define the period [From, To)
+	From time.Time
+	To   time.Time
+	Rows []Row
+}
+
+// Page layout in millimeters
+const (
+	pdfMargin          = 15
+	pdfLineHeight      = 6
+	pdfSignatureWidth  = 75
+	pdfSignatureHeight = 25
+)
+
+type pdfColumn struct {
+	title string
+	width float64
+	align string
+}
+
+// WriteTimesheetPdf writes the timesheet as A4 PDF: a header with team, client and period, the table in the layout of
+// the template with the totals, and a signature line for each signature label of the template.
+// Dates and times are shown in the given location.
+func WriteTimesheetPdf(writer io.Writer, timesheet Timesheet, location *time.Location) error {
+	template := timesheet.Template
+	if !IsKnownLanguage(template.Language) {
+		return fmt.Errorf("unknown language %v", template.Language)
+	}
+	if template.Language == "" {
+		template.Language = "en"
+	}
+	if template.Layout == "" {
+		template.Layout = model.ReportLayoutEntries
+	}
+	if !model.IsValidReportLayout(template.Layout) {
+		return fmt.Errorf("unknown layout %v", template.Layout)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	labels := labelsByLanguage[template.Language]
+	title := template.Title
+	if title == "" {
+		title = labels["timesheet"]
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	// The core fonts use cp1252, which covers the German umlauts
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin+pdfLineHeight)
+	pdf.SetTitle(title, true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin - pdfLineHeight)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, pdfLineHeight, translate(template.FooterText), "", 0, "L", false, 0, "")
+		pdf.SetX(pdfMargin)
+		pdf.CellFormat(0, pdfLineHeight, translate(fmt.Sprintf("%v %d/{nb}", labels["page"], pdf.PageNo())), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, translate(title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	headerLines := [][2]string{{labels["team"], timesheet.TeamName}}
+	if template.Client != "" {
+		headerLines = append(headerLines, [2]string{labels["client"], template.Client})
+	}
+	headerLines = append(headerLines, [2]string{labels["period"], formatPeriod(timesheet.From, timesheet.To)})
+	if timesheet.UserID != nil {
+		headerLines = append(headerLines, [2]string{labels["employee"], timesheet.UserID.String()})
+	} else {
+		headerLines = append(headerLines, [2]string{labels["project"], timesheet.ProjectName})
+	}
+	for _, headerLine := range headerLines {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(30, pdfLineHeight, translate(headerLine[0]+":"), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, pdfLineHeight, translate(headerLine[1]), "", 1, "L", false, 0, "")
+	}
+	if template.HeaderText != "" {
+		pdf.Ln(pdfLineHeight / 2)
+		pdf.MultiCell(0, 5, translate(template.HeaderText), "", "L", false)
+	}
+	pdf.Ln(pdfLineHeight)
+
+	columns, lines, total := createTimesheetTable(timesheet, template.Layout, labels, location)
+	writePdfTableHeader(pdf, columns, translate)
+	_, pageHeight := pdf.GetPageSize()
+	for _, line := range lines {
+		if pdf.GetY()+pdfLineHeight > pageHeight-pdfMargin-pdfLineHeight {
+			pdf.AddPage()
+			writePdfTableHeader(pdf, columns, translate)
+		}
+		pdf.SetFont("Helvetica", "", 9)
+		for i, column := range columns {
+			text := fitText(pdf, translate(line[i]), column.width-2)
+			pdf.CellFormat(column.width, pdfLineHeight, text, "B", 0, column.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	var totalWidth float64
+	for _, column := range columns[:len(columns)-1] {
+		totalWidth += column.width
+	}
+	pdf.CellFormat(totalWidth, pdfLineHeight, translate(labels["total"]), "", 0, "L", false, 0, "")
+	pdf.CellFormat(columns[len(columns)-1].width, pdfLineHeight, formatClock(total), "", 1, "R", false, 0, "")
+
+	signatureLabels := []string(template.SignatureLabels)
+	if len(signatureLabels) == 0 {
+		signatureLabels = []string{labels["employee"], labels["client"]}
+	}
+	writePdfSignatures(pdf, signatureLabels, labels, translate)
+	return pdf.Output(writer)
+}
+
+// createTimesheetTable returns the columns and the lines of the table in the layout. The duration is always the last
+// column.
+func createTimesheetTable(timesheet Timesheet, layout string, labels map[string]string, location *time.Location) ([]pdfColumn, [][]string, time.Duration) {
+	var lines [][]string
+	var total time.Duration
+	if layout == model.ReportLayoutDailyTotals {
+		columns := []pdfColumn{{labels["date"], 130, "L"}, {labels["duration"], 50, "R"}}
+		var dates []string
+		durations := make(map[string]time.Duration)
+		for _, row := range timesheet.Rows {
+			date := row.TimeEntry.StartTime.In(location).Format(dateFormat)
+			if _, ok := durations[date]; !ok {
+				dates = append(dates, date)
+			}
+			durations[date] += row.TimeEntry.Duration()
+			total += row.TimeEntry.Duration()
+		}
+		for _, date := range dates {
+			lines = append(lines, []string{date, formatClock(durations[date])})
+		}
+		return columns, lines, total
+	}
+
+	// The timesheet of a user shows the projects, the timesheet of a project shows the users
+	columns := []pdfColumn{{labels["date"], 25, "L"}, {labels["start"], 15, "L"}, {labels["end"], 15, "L"},
+		{labels["project"], 40, "L"}, {labels["description"], 65, "L"}, {labels["duration"], 20, "R"}}
+	if timesheet.UserID == nil {
+		columns[3] = pdfColumn{labels["user"], 40, "L"}
+	}
+	for _, row := range timesheet.Rows {
+		timeEntry := row.TimeEntry
+		subject := timeEntry.Project.Name
+		if timesheet.UserID == nil {
+			subject = timeEntry.UserId.String()
+		}
+		lines = append(lines, []string{
+			timeEntry.StartTime.In(location).Format(dateFormat),
+			formatTime(timeEntry.StartTime, location),
+			formatTime(timeEntry.EndTime, location),
+			subject,
+			timeEntry.Description,
+			formatClock(timeEntry.Duration()),
+		})
+		total += timeEntry.Duration()
+	}
+	return columns, lines, total
+}
+
+func writePdfTableHeader(pdf *gofpdf.Fpdf, columns []pdfColumn, translate func(string) string) {
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for _, column := range columns {
+		pdf.CellFormat(column.width, pdfLineHeight, translate(column.title), "B", 0, column.align, true, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+// writePdfSignatures writes the signature lines side by side, two per row.
+func writePdfSignatures(pdf *gofpdf.Fpdf, signatureLabels []string, labels map[string]string, translate func(string) string) {
+	_, pageHeight := pdf.GetPageSize()
+	rows := (len(signatureLabels) + 1) / 2
+	if pdf.GetY()+float64(rows)*pdfSignatureHeight > pageHeight-pdfMargin-pdfLineHeight {
+		pdf.AddPage()
+	}
+	pdf.SetFont("Helvetica", "", 8)
+	for i := 0; i < len(signatureLabels); i += 2 {
+		// leave space to sign above the line
+		y := pdf.GetY() + pdfSignatureHeight - pdfLineHeight
+		for j := i; j < i+2 && j < len(signatureLabels); j++ {
+			x := pdfMargin + float64(j-i)*(pdfSignatureWidth+30)
+			pdf.Line(x, y, x+pdfSignatureWidth, y)
+			pdf.SetXY(x, y+1)
+			text := fmt.Sprintf("%v (%v)", labels["signature"], signatureLabels[j])
+			pdf.CellFormat(pdfSignatureWidth, 4, fitText(pdf, translate(text), pdfSignatureWidth), "", 0, "L", false, 0, "")
+		}
+		pdf.SetY(y + pdfLineHeight)
+	}
+}
+
+// fitText shortens the translated text until it fits into the width. The text is already in the single byte
+// encoding of the font, so it can be shortened byte by byte.
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+// formatPeriod formats the period [from, to) with the last included day.
+func formatPeriod(from time.Time, to time.Time) string {
+	return fmt.Sprintf("%v - %v", from.Format(dateFormat), to.AddDate(0, 0, -1).Format(dateFormat))
+}
+
+// formatClock formats the duration as hours and minutes, e.g. 7:30.
+func formatClock(duration time.Duration) string {
+	minutes := int64(duration.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
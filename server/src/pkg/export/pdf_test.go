@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteTimesheetPdfOfUser(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	first := entry(userId, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	first.Project = model.Project{Name: "Website"}
+	first.Description = "Planung für Müller"
+	second := entry(userId, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 90*time.Minute)
+	second.Project = model.Project{Name: "Website"}
+
+	var buffer bytes.Buffer
+	err := WriteTimesheetPdf(&buffer, Timesheet{
+		Template: model.ReportTemplate{
+			Client:          "ACME",
+			Language:        "de",
+			Layout:          model.ReportLayoutEntries,
+			FooterText:      "Vertraulich",
+			SignatureLabels: model.StringList{"Mitarbeiter", "Projektleitung", "Kunde"},
+		},
+		TeamName: "Team A",
+		UserID:   &userId,
+		From:     time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		Rows:     NewRows([]model.TimeEntry{first, second}),
+	}, time.UTC)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(buffer.String(), "%PDF-"))
+	text := readPdfText(t, buffer.Bytes())
+	for _, expected := range []string{"Stundenzettel", "Team A", "ACME", "2023-09-01 - 2023-09-30", userId.String(),
+		"Planung f\xfcr M\xfcller", "4:00", "1:30", "Summe", "5:30", `Datum, Unterschrift \(Projektleitung\)`,
+		"Vertraulich", "Seite 1/1"} {
+		assert.Contains(t, text, expected)
+	}
+}
+
+func Test_WriteTimesheetPdfOfProjectWithDailyTotals(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	otherUserId := uuid.Must(uuid.NewV4())
+	var timeEntries []model.TimeEntry
+	for day := 1; day <= 30; day++ {
+		timeEntries = append(timeEntries,
+			entry(userId, time.Date(2023, 9, day, 8, 0, 0, 0, time.UTC), 2*time.Hour),
+			entry(otherUserId, time.Date(2023, 9, day, 9, 0, 0, 0, time.UTC), time.Hour))
+	}
+
+	var buffer bytes.Buffer
+	err := WriteTimesheetPdf(&buffer, Timesheet{
+		Template:    model.ReportTemplate{Title: "Monthly report", Layout: model.ReportLayoutDailyTotals},
+		TeamName:    "Team A",
+		ProjectName: "Website",
+		From:        time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		Rows:        NewRows(timeEntries),
+	}, time.UTC)
+	assert.Nil(t, err)
+	text := readPdfText(t, buffer.Bytes())
+	for _, expected := range []string{"Monthly report", "Project", "Website", "2023-09-30", "3:00", "Total", "90:00",
+		`Date, signature \(Employee\)`, `Date, signature \(Client\)`} {
+		assert.Contains(t, text, expected)
+	}
+	assert.NotContains(t, text, userId.String())
+}
+
+func Test_WriteTimesheetPdfFailsWithUnknownLayout(t *testing.T) {
+	var buffer bytes.Buffer
+	err := WriteTimesheetPdf(&buffer, Timesheet{Template: model.ReportTemplate{Layout: "pivot"}}, time.UTC)
+	assert.NotNil(t, err)
+}
+
+func Test_formatClock(t *testing.T) {
+	assert.Equal(t, "0:00", formatClock(0))
+	assert.Equal(t, "7:30", formatClock(7*time.Hour+30*time.Minute))
+	assert.Equal(t, "123:05", formatClock(123*time.Hour+5*time.Minute))
+}
+
+var pdfStreamPattern = regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`)
+
+// readPdfText returns the content of all streams of the PDF, compressed streams are inflated. The texts keep the
+// escaping of PDF strings, e.g. \( for a parenthesis.
+func readPdfText(t *testing.T, content []byte) string {
+	var text strings.Builder
+	for _, match := range pdfStreamPattern.FindAllSubmatch(content, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			text.Write(match[1])
+			continue
+		}
+		inflated, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		text.Write(inflated)
+	}
+	return text.String()
+}
@@ -0,0 +1,549 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// personalDataReadme describes the files of a personal data export.
+const personalDataReadme = `Export of your personal data stored by timeasy
+
+profile.json            your user id, notification settings and calendar feed
+time_entries.json       your time entries with the attributes of imported entries
+projects.json           your projects and the team projects you tracked time for
+team_memberships.json   your current and former teams with your roles
+absences.json           your absences and absence requests
+working_time.json       your working time models
+timesheets.json         your submitted timesheets
+overtime.json           your overtime accounts and corrections
+notifications.json      the notifications in your inbox
+webhooks.json           your webhook subscriptions (without secrets)
+history.json            running time entries stopped or flagged by the server and lock date changes you made
+invoices.json           your personal invoices with their lines (amounts in cents)
+
+Times are in UTC, dates are formatted as YYYY-MM-DD. Deleted data that is not removed yet has a deletedAt time.
+Your name and email address are managed by the login service (Keycloak) and are not stored by timeasy.
+`
+
+type personalProfileJson struct {
+	UserID                uuid.UUID                 `json:"userId"`
+	ExportedAt            time.Time                 `json:"exportedAt"`
+	NotificationSettings  *notificationSettingsJson `json:"notificationSettings,omitempty"`
+	CalendarFeedCreatedAt *time.Time                `json:"calendarFeedCreatedAt,omitempty"`
+}
+
+type notificationSettingsJson struct {
+	Channels   []string `json:"channels"`
+	MutedTypes []string `json:"mutedTypes"`
+	Email      string   `json:"email,omitempty"`
+	WebhookURL string   `json:"webhookUrl,omitempty"`
+}
+
+type timeEntryJson struct {
+	ID          uuid.UUID   `json:"id"`
+	ProjectID   uuid.UUID   `json:"projectId"`
+	StartTime   time.Time   `json:"startTime"`
+	EndTime     *time.Time  `json:"endTime,omitempty"`
+	Description string      `json:"description"`
+	Import      *importJson `json:"import,omitempty"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+	DeletedAt   *time.Time  `json:"deletedAt,omitempty"`
+}
+
+type importJson struct {
+	Source     string   `json:"source"`
+	ExternalID string   `json:"externalId,omitempty"`
+	Task       string   `json:"task,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Billable   *bool    `json:"billable,omitempty"`
+}
+
+type projectJson struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Client    string     `json:"client,omitempty"`
+	Billable  bool       `json:"billable"`
+	TeamID    *uuid.UUID `json:"teamId,omitempty"`
+	Own       bool       `json:"own"`
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type teamMembershipJson struct {
+	TeamID             uuid.UUID  `json:"teamId"`
+	TeamName           string     `json:"teamName"`
+	Roles              []string   `json:"roles"`
+	HolidayCalendarID  *uuid.UUID `json:"holidayCalendarId,omitempty"`
+	RunningTimerPolicy string     `json:"runningTimerPolicy,omitempty"`
+	MemberSince        time.Time  `json:"memberSince"`
+	MemberUntil        *time.Time `json:"memberUntil,omitempty"`
+}
+
+type absenceJson struct {
+	ID           uuid.UUID  `json:"id"`
+	TeamID       uuid.UUID  `json:"teamId"`
+	Type         string     `json:"type"`
+	StartDate    string     `json:"startDate"`
+	EndDate      string     `json:"endDate"`
+	HalfDayStart bool       `json:"halfDayStart"`
+	HalfDayEnd   bool       `json:"halfDayEnd"`
+	Status       string     `json:"status"`
+	Comment      string     `json:"comment,omitempty"`
+	ReviewedBy   *uuid.UUID `json:"reviewedBy,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	DeletedAt    *time.Time `json:"deletedAt,omitempty"`
+}
+
+type workingTimeModelJson struct {
+	ID                 uuid.UUID  `json:"id"`
+	TeamID             uuid.UUID  `json:"teamId"`
+	ValidFrom          string     `json:"validFrom"`
+	PartTimePercentage int        `json:"partTimePercentage"`
+	MondayMinutes      int        `json:"mondayMinutes"`
+	TuesdayMinutes     int        `json:"tuesdayMinutes"`
+	WednesdayMinutes   int        `json:"wednesdayMinutes"`
+	ThursdayMinutes    int        `json:"thursdayMinutes"`
+	FridayMinutes      int        `json:"fridayMinutes"`
+	SaturdayMinutes    int        `json:"saturdayMinutes"`
+	SundayMinutes      int        `json:"sundayMinutes"`
+	DeletedAt          *time.Time `json:"deletedAt,omitempty"`
+}
+
+type timesheetJson struct {
+	ID          uuid.UUID  `json:"id"`
+	TeamID      uuid.UUID  `json:"teamId"`
+	PeriodType  string     `json:"periodType"`
+	StartDate   string     `json:"startDate"`
+	EndDate     string     `json:"endDate"`
+	Status      string     `json:"status"`
+	Comment     string     `json:"comment,omitempty"`
+	SubmittedAt time.Time  `json:"submittedAt"`
+	ReviewedBy  *uuid.UUID `json:"reviewedBy,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewedAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+}
+
+type overtimeJson struct {
+	Accounts    []overtimeAccountJson    `json:"accounts"`
+	Corrections []overtimeCorrectionJson `json:"corrections"`
+}
+
+type overtimeAccountJson struct {
+	StartDate             string     `json:"startDate"`
+	OpeningBalanceMinutes int        `json:"openingBalanceMinutes"`
+	DeletedAt             *time.Time `json:"deletedAt,omitempty"`
+}
+
+type overtimeCorrectionJson struct {
+	Date      string     `json:"date"`
+	Minutes   int        `json:"minutes"`
+	Type      string     `json:"type"`
+	Comment   string     `json:"comment,omitempty"`
+	CreatedBy uuid.UUID  `json:"createdBy"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type notificationJson struct {
+	Type      string     `json:"type"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+type webhookSubscriptionJson struct {
+	ID         uuid.UUID  `json:"id"`
+	TeamID     *uuid.UUID `json:"teamId,omitempty"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"eventTypes"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	DeletedAt  *time.Time `json:"deletedAt,omitempty"`
+}
+
+type historyJson struct {
+	RunningTimerNotices []runningTimerNoticeJson `json:"runningTimerNotices"`
+	LockDateChanges     []lockDateChangeJson     `json:"lockDateChanges"`
+}
+
+type runningTimerNoticeJson struct {
+	TimeEntryID uuid.UUID  `json:"timeEntryId"`
+	Action      string     `json:"action"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type lockDateChangeJson struct {
+	TeamID      uuid.UUID `json:"teamId"`
+	OldLockDate string    `json:"oldLockDate,omitempty"`
+	NewLockDate string    `json:"newLockDate,omitempty"`
+	Comment     string    `json:"comment,omitempty"`
+	ChangedAt   time.Time `json:"changedAt"`
+}
+
+type invoiceJson struct {
+	ID              uuid.UUID         `json:"id"`
+	Number          string            `json:"number"`
+	Status          string            `json:"status"`
+	Client          string            `json:"client,omitempty"`
+	ProjectID       *uuid.UUID        `json:"projectId,omitempty"`
+	From            string            `json:"from"`
+	To              string            `json:"to"`
+	Currency        string            `json:"currency"`
+	HourlyRate      int64             `json:"hourlyRate"`
+	RoundingMode    string            `json:"roundingMode"`
+	RoundingMinutes int               `json:"roundingMinutes"`
+	TotalMinutes    int64             `json:"totalMinutes"`
+	TotalAmount     int64             `json:"totalAmount"`
+	Lines           []invoiceLineJson `json:"lines"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	DeletedAt       *time.Time        `json:"deletedAt,omitempty"`
+}
+
+type invoiceLineJson struct {
+	ProjectID      uuid.UUID `json:"projectId"`
+	ProjectName    string    `json:"projectName"`
+	Task           string    `json:"task,omitempty"`
+	Day            string    `json:"day"`
+	TrackedMinutes int64     `json:"trackedMinutes"`
+	BilledMinutes  int64     `json:"billedMinutes"`
+	HourlyRate     int64     `json:"hourlyRate"`
+	Amount         int64     `json:"amount"`
+	TimeEntryCount int       `json:"timeEntryCount"`
+}
+
+// WritePersonalDataZip writes the personal data of a user as ZIP file with a JSON file per kind of data and a README.
+func WritePersonalDataZip(writer io.Writer, data model.PersonalData, now time.Time) error {
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", newPersonalProfileJson(data, now)},
+		{"time_entries.json", newTimeEntriesJson(data)},
+		{"projects.json", newProjectsJson(data)},
+		{"team_memberships.json", newTeamMembershipsJson(data)},
+		{"absences.json", newAbsencesJson(data)},
+		{"working_time.json", newWorkingTimeModelsJson(data)},
+		{"timesheets.json", newTimesheetsJson(data)},
+		{"overtime.json", newOvertimeJson(data)},
+		{"notifications.json", newNotificationsJson(data)},
+		{"webhooks.json", newWebhookSubscriptionsJson(data)},
+		{"history.json", newHistoryJson(data)},
+		{"invoices.json", newInvoicesJson(data)},
+	}
+	zipWriter := zip.NewWriter(writer)
+	if err := writeZipFile(zipWriter, "README.txt", []byte(personalDataReadme), now); err != nil {
+		return err
+	}
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.content, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeZipFile(zipWriter, file.name, content, now); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+func writeZipFile(zipWriter *zip.Writer, name string, content []byte, now time.Time) error {
+	fileWriter, err := zipWriter.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return err
+	}
+	_, err = fileWriter.Write(content)
+	return err
+}
+
+func newPersonalProfileJson(data model.PersonalData, now time.Time) personalProfileJson {
+	profile := personalProfileJson{UserID: data.UserID, ExportedAt: now.UTC()}
+	for _, settings := range data.NotificationSettings {
+		profile.NotificationSettings = &notificationSettingsJson{
+			Channels:   nonNilStrings(settings.Channels),
+			MutedTypes: nonNilStrings(settings.MutedTypes),
+			Email:      settings.Email,
+			WebhookURL: settings.WebhookURL,
+		}
+	}
+	for _, token := range data.CalendarFeedTokens {
+		createdAt := token.CreatedAt.UTC()
+		profile.CalendarFeedCreatedAt = &createdAt
+	}
+	return profile
+}
+
+func newTimeEntriesJson(data model.PersonalData) []timeEntryJson {
+	imports := make(map[uuid.UUID]*importJson)
+	for _, importedTimeEntry := range data.ImportedTimeEntries {
+		imports[importedTimeEntry.TimeEntryID] = &importJson{
+			Source:     importedTimeEntry.Source,
+			ExternalID: importedTimeEntry.ExternalID,
+			Task:       importedTimeEntry.Task,
+			Tags:       importedTimeEntry.Tags,
+			Billable:   importedTimeEntry.Billable,
+		}
+	}
+	timeEntries := []timeEntryJson{}
+	for _, timeEntry := range data.TimeEntries {
+		timeEntries = append(timeEntries, timeEntryJson{
+			ID:          timeEntry.ID,
+			ProjectID:   timeEntry.ProjectId,
+			StartTime:   timeEntry.StartTime.UTC(),
+			EndTime:     optionalTime(timeEntry.EndTime),
+			Description: timeEntry.Description,
+			Import:      imports[timeEntry.ID],
+			CreatedAt:   timeEntry.CreatedAt.UTC(),
+			UpdatedAt:   timeEntry.UpdatedAt.UTC(),
+			DeletedAt:   deletedAt(timeEntry.DeletedAt),
+		})
+	}
+	return timeEntries
+}
+
+func newProjectsJson(data model.PersonalData) []projectJson {
+	projects := []projectJson{}
+	for _, project := range data.Projects {
+		projects = append(projects, projectJson{
+			ID:        project.ID,
+			Name:      project.Name,
+			Client:    project.Client,
+			Billable:  project.Billable,
+			TeamID:    project.TeamID,
+			Own:       project.UserId == data.UserID,
+			CreatedAt: project.CreatedAt.UTC(),
+			DeletedAt: deletedAt(project.DeletedAt),
+		})
+	}
+	return projects
+}
+
+func newTeamMembershipsJson(data model.PersonalData) []teamMembershipJson {
+	memberships := []teamMembershipJson{}
+	for _, assignment := range data.TeamMemberships {
+		memberships = append(memberships, teamMembershipJson{
+			TeamID:             assignment.TeamID,
+			TeamName:           assignment.Team.Name1,
+			Roles:              nonNilStrings(assignment.Roles),
+			HolidayCalendarID:  assignment.HolidayCalendarID,
+			RunningTimerPolicy: assignment.RunningTimerPolicy,
+			MemberSince:        assignment.CreatedAt.UTC(),
+			MemberUntil:        deletedAt(assignment.DeletedAt),
+		})
+	}
+	return memberships
+}
+
+func newAbsencesJson(data model.PersonalData) []absenceJson {
+	absences := []absenceJson{}
+	for _, absence := range data.Absences {
+		absences = append(absences, absenceJson{
+			ID:           absence.ID,
+			TeamID:       absence.TeamID,
+			Type:         absence.Type,
+			StartDate:    absence.StartDate.Format(dateFormat),
+			EndDate:      absence.EndDate.Format(dateFormat),
+			HalfDayStart: absence.HalfDayStart,
+			HalfDayEnd:   absence.HalfDayEnd,
+			Status:       absence.Status,
+			Comment:      absence.Comment,
+			ReviewedBy:   absence.ReviewedBy,
+			CreatedAt:    absence.CreatedAt.UTC(),
+			DeletedAt:    deletedAt(absence.DeletedAt),
+		})
+	}
+	return absences
+}
+
+func newWorkingTimeModelsJson(data model.PersonalData) []workingTimeModelJson {
+	workingTimeModels := []workingTimeModelJson{}
+	for _, workingTimeModel := range data.WorkingTimeModels {
+		workingTimeModels = append(workingTimeModels, workingTimeModelJson{
+			ID:                 workingTimeModel.ID,
+			TeamID:             workingTimeModel.TeamID,
+			ValidFrom:          workingTimeModel.ValidFrom.Format(dateFormat),
+			PartTimePercentage: workingTimeModel.PartTimePercentage,
+			MondayMinutes:      workingTimeModel.MondayMinutes,
+			TuesdayMinutes:     workingTimeModel.TuesdayMinutes,
+			WednesdayMinutes:   workingTimeModel.WednesdayMinutes,
+			ThursdayMinutes:    workingTimeModel.ThursdayMinutes,
+			FridayMinutes:      workingTimeModel.FridayMinutes,
+			SaturdayMinutes:    workingTimeModel.SaturdayMinutes,
+			SundayMinutes:      workingTimeModel.SundayMinutes,
+			DeletedAt:          deletedAt(workingTimeModel.DeletedAt),
+		})
+	}
+	return workingTimeModels
+}
+
+func newTimesheetsJson(data model.PersonalData) []timesheetJson {
+	timesheets := []timesheetJson{}
+	for _, timesheet := range data.Timesheets {
+		timesheets = append(timesheets, timesheetJson{
+			ID:          timesheet.ID,
+			TeamID:      timesheet.TeamID,
+			PeriodType:  timesheet.PeriodType,
+			StartDate:   timesheet.StartDate.Format(dateFormat),
+			EndDate:     timesheet.EndDate.Format(dateFormat),
+			Status:      timesheet.Status,
+			Comment:     timesheet.Comment,
+			SubmittedAt: timesheet.SubmittedAt.UTC(),
+			ReviewedBy:  timesheet.ReviewedBy,
+			ReviewedAt:  timesheet.ReviewedAt,
+			DeletedAt:   deletedAt(timesheet.DeletedAt),
+		})
+	}
+	return timesheets
+}
+
+func newOvertimeJson(data model.PersonalData) overtimeJson {
+	overtime := overtimeJson{Accounts: []overtimeAccountJson{}, Corrections: []overtimeCorrectionJson{}}
+	for _, account := range data.OvertimeAccounts {
+		overtime.Accounts = append(overtime.Accounts, overtimeAccountJson{
+			StartDate:             account.StartDate.Format(dateFormat),
+			OpeningBalanceMinutes: account.OpeningBalanceMinutes,
+			DeletedAt:             deletedAt(account.DeletedAt),
+		})
+	}
+	for _, correction := range data.OvertimeCorrections {
+		overtime.Corrections = append(overtime.Corrections, overtimeCorrectionJson{
+			Date:      correction.Date.Format(dateFormat),
+			Minutes:   correction.Minutes,
+			Type:      correction.Type,
+			Comment:   correction.Comment,
+			CreatedBy: correction.CreatedBy,
+			DeletedAt: deletedAt(correction.DeletedAt),
+		})
+	}
+	return overtime
+}
+
+func newNotificationsJson(data model.PersonalData) []notificationJson {
+	notifications := []notificationJson{}
+	for _, notification := range data.Notifications {
+		notifications = append(notifications, notificationJson{
+			Type:      notification.Type,
+			Subject:   notification.Subject,
+			Body:      notification.Body,
+			CreatedAt: notification.CreatedAt.UTC(),
+			ReadAt:    notification.ReadAt,
+			DeletedAt: deletedAt(notification.DeletedAt),
+		})
+	}
+	return notifications
+}
+
+func newWebhookSubscriptionsJson(data model.PersonalData) []webhookSubscriptionJson {
+	subscriptions := []webhookSubscriptionJson{}
+	for _, subscription := range data.WebhookSubscriptions {
+		subscriptions = append(subscriptions, webhookSubscriptionJson{
+			ID:         subscription.ID,
+			TeamID:     subscription.TeamID,
+			URL:        subscription.URL,
+			EventTypes: nonNilStrings(subscription.EventTypes),
+			Active:     subscription.Active,
+			CreatedAt:  subscription.CreatedAt.UTC(),
+			DeletedAt:  deletedAt(subscription.DeletedAt),
+		})
+	}
+	return subscriptions
+}
+
+func newHistoryJson(data model.PersonalData) historyJson {
+	history := historyJson{RunningTimerNotices: []runningTimerNoticeJson{}, LockDateChanges: []lockDateChangeJson{}}
+	for _, notice := range data.RunningTimerNotices {
+		history.RunningTimerNotices = append(history.RunningTimerNotices, runningTimerNoticeJson{
+			TimeEntryID: notice.TimeEntryID,
+			Action:      notice.Action,
+			EndTime:     notice.EndTime,
+			CreatedAt:   notice.CreatedAt.UTC(),
+		})
+	}
+	for _, change := range data.PeriodLockChanges {
+		history.LockDateChanges = append(history.LockDateChanges, lockDateChangeJson{
+			TeamID:      change.TeamID,
+			OldLockDate: optionalDate(change.OldLockDate),
+			NewLockDate: optionalDate(change.NewLockDate),
+			Comment:     change.Comment,
+			ChangedAt:   change.CreatedAt.UTC(),
+		})
+	}
+	return history
+}
+
+func newInvoicesJson(data model.PersonalData) []invoiceJson {
+	invoices := []invoiceJson{}
+	for _, invoice := range data.Invoices {
+		lines := []invoiceLineJson{}
+		for _, line := range invoice.Lines {
+			lines = append(lines, invoiceLineJson{
+				ProjectID:      line.ProjectID,
+				ProjectName:    line.ProjectName,
+				Task:           line.Task,
+				Day:            line.Day.Format(dateFormat),
+				TrackedMinutes: line.TrackedMinutes,
+				BilledMinutes:  line.BilledMinutes,
+				HourlyRate:     line.HourlyRate,
+				Amount:         line.Amount,
+				TimeEntryCount: line.TimeEntryCount,
+			})
+		}
+		invoices = append(invoices, invoiceJson{
+			ID:              invoice.ID,
+			Number:          invoice.Number,
+			Status:          invoice.Status,
+			Client:          invoice.Client,
+			ProjectID:       invoice.ProjectID,
+			From:            invoice.From.Format(dateFormat),
+			To:              invoice.To.Format(dateFormat),
+			Currency:        invoice.Currency,
+			HourlyRate:      invoice.HourlyRate,
+			RoundingMode:    invoice.RoundingMode,
+			RoundingMinutes: invoice.RoundingMinutes,
+			TotalMinutes:    invoice.TotalMinutes,
+			TotalAmount:     invoice.TotalAmount,
+			Lines:           lines,
+			CreatedAt:       invoice.CreatedAt.UTC(),
+			DeletedAt:       deletedAt(invoice.DeletedAt),
+		})
+	}
+	return invoices
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func optionalDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(dateFormat)
+}
+
+func deletedAt(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	return optionalTime(deletedAt.Time)
+}
+
+// nonNilStrings makes empty lists appear as [] instead of null in the JSON files.
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
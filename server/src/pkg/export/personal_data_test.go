@@ -0,0 +1,99 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func Test_WritePersonalDataZip(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	teamId := uuid.Must(uuid.NewV4())
+	ownProject := model.Project{ID: uuid.Must(uuid.NewV4()), Name: "Own", UserId: userId}
+	teamProject := model.Project{ID: uuid.Must(uuid.NewV4()), Name: "Team", UserId: uuid.Must(uuid.NewV4()), TeamID: &teamId}
+	start := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2023, 9, 6, 12, 0, 0, 0, time.UTC)
+	importedEntry := model.TimeEntry{ID: uuid.Must(uuid.NewV4()), UserId: userId, ProjectId: ownProject.ID,
+		StartTime: start, EndTime: start.Add(time.Hour), Description: "imported"}
+	runningEntry := model.TimeEntry{ID: uuid.Must(uuid.NewV4()), UserId: userId, ProjectId: teamProject.ID,
+		StartTime: start.Add(2 * time.Hour)}
+	runningEntry.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+	membership := model.UserTeamAssignment{UserID: userId, TeamID: teamId, Team: model.Team{ID: teamId, Name1: "Team A"},
+		Roles: model.RoleList{model.RoleUser}}
+	billable := true
+	data := model.PersonalData{
+		UserID:          userId,
+		TimeEntries:     []model.TimeEntry{importedEntry, runningEntry},
+		Projects:        []model.Project{ownProject, teamProject},
+		TeamMemberships: []model.UserTeamAssignment{membership},
+		ImportedTimeEntries: []model.ImportedTimeEntry{{TimeEntryID: importedEntry.ID, UserID: userId,
+			Source: model.ImportSourceToggl, ExternalID: "1001", Tags: model.StringList{"meeting"}, Billable: &billable}},
+		WebhookSubscriptions: []model.WebhookSubscription{{URL: "https://example.com/hook", Secret: "secret", Active: true}},
+		NotificationSettings: []model.NotificationSettings{{UserID: userId, Channels: model.StringList{"INBOX"}}},
+		Invoices: []model.Invoice{{IssuerID: userId, Number: "2023-0001", Status: model.InvoiceStatusDraft,
+			Client: "ACME", From: start, To: start.AddDate(0, 0, 1), Currency: "EUR", HourlyRate: 8000,
+			TotalMinutes: 60, TotalAmount: 8000, Lines: []model.InvoiceLine{{ProjectID: ownProject.ID,
+				ProjectName: "Website", Day: start, TrackedMinutes: 60, BilledMinutes: 60, HourlyRate: 8000, Amount: 8000,
+				TimeEntryCount: 1}}}},
+	}
+	now := time.Date(2023, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	var buffer bytes.Buffer
+	err := WritePersonalDataZip(&buffer, data, now)
+	assert.Nil(t, err)
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.Nil(t, err)
+	files := make(map[string][]byte)
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		assert.Nil(t, err)
+		files[file.Name], err = io.ReadAll(reader)
+		assert.Nil(t, err)
+		reader.Close()
+	}
+	assert.Equal(t, 13, len(files))
+	assert.Contains(t, string(files["README.txt"]), "time_entries.json")
+
+	var profile map[string]any
+	assert.Nil(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, userId.String(), profile["userId"])
+	assert.Equal(t, "2023-10-01T09:00:00Z", profile["exportedAt"])
+
+	var timeEntries []map[string]any
+	assert.Nil(t, json.Unmarshal(files["time_entries.json"], &timeEntries))
+	assert.Equal(t, 2, len(timeEntries))
+	assert.Equal(t, "2023-09-04T09:00:00Z", timeEntries[0]["endTime"])
+	assert.Equal(t, map[string]any{"source": model.ImportSourceToggl, "externalId": "1001", "tags": []any{"meeting"},
+		"billable": true}, timeEntries[0]["import"])
+	assert.Nil(t, timeEntries[0]["deletedAt"])
+	assert.Nil(t, timeEntries[1]["endTime"])
+	assert.Equal(t, "2023-09-06T12:00:00Z", timeEntries[1]["deletedAt"])
+
+	var projects []map[string]any
+	assert.Nil(t, json.Unmarshal(files["projects.json"], &projects))
+	assert.Equal(t, true, projects[0]["own"])
+	assert.Equal(t, false, projects[1]["own"])
+
+	var memberships []map[string]any
+	assert.Nil(t, json.Unmarshal(files["team_memberships.json"], &memberships))
+	assert.Equal(t, "Team A", memberships[0]["teamName"])
+	assert.Equal(t, []any{model.RoleUser}, memberships[0]["roles"])
+
+	var invoices []map[string]any
+	assert.Nil(t, json.Unmarshal(files["invoices.json"], &invoices))
+	assert.Equal(t, "2023-0001", invoices[0]["number"])
+	assert.Equal(t, float64(8000), invoices[0]["totalAmount"])
+	assert.Equal(t, 1, len(invoices[0]["lines"].([]any)))
+
+	// Secrets are not exported, empty lists are written as []:
+	assert.NotContains(t, string(files["webhooks.json"]), "secret")
+	assert.Equal(t, "[]", string(files["absences.json"]))
+}
@@ -0,0 +1,198 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Layouts of the XLSX export, they match the Excel exports of the app
+const (
+	// XlsxLayoutAllEntries writes one line per time entry
+	XlsxLayoutAllEntries = "allentries"
+	// XlsxLayoutOneLinePerDay writes one line per day with the first start, the last end and the breaks in between
+	XlsxLayoutOneLinePerDay = "onelineperday"
+)
+
+// minPauseColumns is the number of breaks the app reserves columns for in the one line per day layout
+const minPauseColumns = 5
+
+type XlsxOptions struct {
+	// Layout is XlsxLayoutAllEntries (default) or XlsxLayoutOneLinePerDay
+	Layout string
+	// Language of the headers, "en" (default) or "de"
+	Language string
+	// Location is the time zone of dates and times, UTC is used if it is not set
+	Location *time.Location
+	// WithUser adds a user column and groups the rows by user, e.g. for the export of a team
+	WithUser bool
+}
+
+// IsKnownXlsxLayout returns true if the layout is supported, an empty layout selects the default.
+func IsKnownXlsxLayout(layout string) bool {
+	return layout == "" || layout == XlsxLayoutAllEntries || layout == XlsxLayoutOneLinePerDay
+}
+
+// xlsxDay holds the rows of a user on a day
+type xlsxDay struct {
+	userId uuid.UUID
+	date   string
+	rows   []Row
+	total  time.Duration
+}
+
+// WriteXlsx writes the rows as spreadsheet in the given layout. Durations are written as times with the format
+// [h]:mm, so they can be summed up in the spreadsheet. Each day gets its sum and the last line contains the totals.
+func WriteXlsx(writer io.Writer, rows []Row, options XlsxOptions) error {
+	if !IsKnownXlsxLayout(options.Layout) {
+		return fmt.Errorf("unknown layout %v", options.Layout)
+	}
+	if !IsKnownLanguage(options.Language) {
+		return fmt.Errorf("unknown language %v", options.Language)
+	}
+	if options.Language == "" {
+		options.Language = "en"
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	labels := labelsByLanguage[options.Language]
+	days := groupRowsByDay(rows, options)
+	var cells [][]xlsxCell
+	if options.Layout == XlsxLayoutOneLinePerDay {
+		cells = createOneLinePerDayCells(days, labels, options)
+	} else {
+		cells = createAllEntriesCells(days, labels, options)
+	}
+	return writeXlsx(writer, labels["sheet"], cells)
+}
+
+func groupRowsByDay(rows []Row, options XlsxOptions) []xlsxDay {
+	sortedRows := make([]Row, len(rows))
+	copy(sortedRows, rows)
+	sort.SliceStable(sortedRows, func(i, j int) bool {
+		if options.WithUser && sortedRows[i].TimeEntry.UserId != sortedRows[j].TimeEntry.UserId {
+			return sortedRows[i].TimeEntry.UserId.String() < sortedRows[j].TimeEntry.UserId.String()
+		}
+		return sortedRows[i].TimeEntry.StartTime.Before(sortedRows[j].TimeEntry.StartTime)
+	})
+	var days []xlsxDay
+	for _, row := range sortedRows {
+		date := row.TimeEntry.StartTime.In(options.Location).Format(dateFormat)
+		last := len(days) - 1
+		if last < 0 || days[last].date != date || days[last].userId != row.TimeEntry.UserId {
+			days = append(days, xlsxDay{userId: row.TimeEntry.UserId, date: date})
+			last++
+		}
+		days[last].rows = append(days[last].rows, row)
+		days[last].total += row.TimeEntry.Duration()
+	}
+	return days
+}
+
+func createAllEntriesCells(days []xlsxDay, labels map[string]string, options XlsxOptions) [][]xlsxCell {
+	header := []string{labels["date"], labels["start"], labels["end"], labels["project"], labels["description"],
+		labels["duration"], labels["dayTotal"]}
+	cells := [][]xlsxCell{createHeaderCells(header, labels, options)}
+	var total time.Duration
+	for _, day := range days {
+		for i, row := range day.rows {
+			timeEntry := row.TimeEntry
+			line := []xlsxCell{
+				textCell(day.date),
+				textCell(formatTime(timeEntry.StartTime, options.Location)),
+				textCell(formatTime(timeEntry.EndTime, options.Location)),
+				textCell(timeEntry.Project.Name),
+				textCell(timeEntry.Description),
+				durationCell(timeEntry.Duration()),
+				{},
+			}
+			if i == len(day.rows)-1 {
+				line[len(line)-1] = durationCell(day.total)
+			}
+			cells = append(cells, withUserCell(line, day.userId, options))
+		}
+		total += day.total
+	}
+	totals := make([]xlsxCell, len(header))
+	totals[0] = xlsxCell{text: labels["total"], style: styleTotal}
+	totals[5] = totalDurationCell(total)
+	totals[6] = totals[5]
+	return append(cells, withUserCell(totals, uuid.Nil, options))
+}
+
+func createOneLinePerDayCells(days []xlsxDay, labels map[string]string, options XlsxOptions) [][]xlsxCell {
+	pauseColumns := minPauseColumns
+	dayPauses := make([][][2]time.Time, len(days))
+	for i, day := range days {
+		dayPauses[i] = getPauses(day.rows)
+		if len(dayPauses[i]) > pauseColumns {
+			pauseColumns = len(dayPauses[i])
+		}
+	}
+
+	header := []string{labels["date"], labels["start"], labels["end"]}
+	for i := 1; i <= pauseColumns; i++ {
+		header = append(header, fmt.Sprintf("%v %d %v", labels["pause"], i, labels["start"]),
+			fmt.Sprintf("%v %d %v", labels["pause"], i, labels["end"]))
+	}
+	header = append(header, labels["total"])
+	cells := [][]xlsxCell{createHeaderCells(header, labels, options)}
+	var total time.Duration
+	for i, day := range days {
+		line := make([]xlsxCell, len(header))
+		line[0] = textCell(day.date)
+		line[1] = textCell(formatTime(day.rows[0].TimeEntry.StartTime, options.Location))
+		line[2] = textCell(formatTime(day.rows[len(day.rows)-1].TimeEntry.EndTime, options.Location))
+		for j, pause := range dayPauses[i] {
+			line[3+2*j] = textCell(formatTime(pause[0], options.Location))
+			line[4+2*j] = textCell(formatTime(pause[1], options.Location))
+		}
+		line[len(line)-1] = durationCell(day.total)
+		cells = append(cells, withUserCell(line, day.userId, options))
+		total += day.total
+	}
+	totals := make([]xlsxCell, len(header))
+	totals[0] = xlsxCell{text: labels["total"], style: styleTotal}
+	totals[len(totals)-1] = totalDurationCell(total)
+	return append(cells, withUserCell(totals, uuid.Nil, options))
+}
+
+// getPauses returns the gaps between the finished entries of a day like the app does. Overlapping and adjacent
+// entries have no gap.
+func getPauses(rows []Row) [][2]time.Time {
+	var pauses [][2]time.Time
+	for i := 0; i < len(rows)-1; i++ {
+		end := rows[i].TimeEntry.EndTime
+		nextStart := rows[i+1].TimeEntry.StartTime
+		if !end.IsZero() && end.Before(nextStart) {
+			pauses = append(pauses, [2]time.Time{end, nextStart})
+		}
+	}
+	return pauses
+}
+
+func createHeaderCells(header []string, labels map[string]string, options XlsxOptions) []xlsxCell {
+	var cells []xlsxCell
+	if options.WithUser {
+		cells = append(cells, xlsxCell{text: labels["user"], style: styleHeader})
+	}
+	for _, text := range header {
+		cells = append(cells, xlsxCell{text: text, style: styleHeader})
+	}
+	return cells
+}
+
+func withUserCell(line []xlsxCell, userId uuid.UUID, options XlsxOptions) []xlsxCell {
+	if !options.WithUser {
+		return line
+	}
+	userCell := xlsxCell{}
+	if userId != uuid.Nil {
+		userCell = textCell(userId.String())
+	}
+	return append([]xlsxCell{userCell}, line...)
+}
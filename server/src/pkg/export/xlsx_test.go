@@ -0,0 +1,130 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteXlsxAllEntries(t *testing.T) {
+	userId := uuid.Must(uuid.NewV4())
+	first := entry(userId, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	first.Project = model.Project{Name: "project"}
+	first.Description = "<planning> & review"
+	second := entry(userId, time.Date(2023, 9, 4, 12, 30, 0, 0, time.UTC), 2*time.Hour)
+	third := entry(userId, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 6*time.Hour)
+
+	var buffer bytes.Buffer
+	err := WriteXlsx(&buffer, NewRows([]model.TimeEntry{third, second, first}), XlsxOptions{})
+	assert.Nil(t, err)
+	sheet := readXlsxSheet(t, buffer.Bytes())
+	assert.Equal(t, [][]string{
+		{"Date", "Start", "End", "Project", "Description", "Duration", "Day total"},
+		{"2023-09-04", "08:00", "12:00", "project", "<planning> & review", "0.16666666666666666", ""},
+		{"2023-09-04", "12:30", "14:30", "", "", "0.08333333333333333", "0.25"},
+		{"2023-09-05", "08:00", "14:00", "", "", "0.25", "0.25"},
+		{"Total", "", "", "", "", "0.5", "0.5"},
+	}, sheet)
+}
+
+func Test_WriteXlsxOneLinePerDay(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	assert.Nil(t, err)
+	userId := uuid.Must(uuid.NewV4())
+	otherUserId := uuid.Must(uuid.NewV4())
+	rows := NewRows([]model.TimeEntry{
+		entry(userId, time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC), 4*time.Hour),
+		entry(userId, time.Date(2023, 9, 4, 10, 30, 0, 0, time.UTC), time.Hour),
+		// adjacent entries have no break in between:
+		entry(userId, time.Date(2023, 9, 4, 11, 30, 0, 0, time.UTC), time.Hour),
+		entry(otherUserId, time.Date(2023, 9, 4, 7, 0, 0, 0, time.UTC), 3*time.Hour),
+	})
+
+	var buffer bytes.Buffer
+	err = WriteXlsx(&buffer, rows, XlsxOptions{
+		Layout:   XlsxLayoutOneLinePerDay,
+		Language: "de",
+		Location: location,
+		WithUser: true,
+	})
+	assert.Nil(t, err)
+	sheet := readXlsxSheet(t, buffer.Bytes())
+	assert.Equal(t, 4, len(sheet))
+	assert.Equal(t, []string{"Benutzer", "Datum", "Start", "Ende", "Pause 1 Start", "Pause 1 Ende"}, sheet[0][:6])
+	assert.Equal(t, "Pause 5 Ende", sheet[0][13])
+	assert.Equal(t, "Summe", sheet[0][14])
+
+	lines := map[string][]string{sheet[1][0]: sheet[1], sheet[2][0]: sheet[2]}
+	assert.Equal(t, []string{userId.String(), "2023-09-04", "08:00", "14:30", "12:00", "12:30", ""},
+		lines[userId.String()][:7])
+	assert.Equal(t, "0.25", lines[userId.String()][14])
+	assert.Equal(t, []string{otherUserId.String(), "2023-09-04", "09:00", "12:00", ""}, lines[otherUserId.String()][:5])
+	assert.Equal(t, []string{"", "Summe"}, sheet[3][:2])
+	assert.Equal(t, "0.375", sheet[3][14])
+}
+
+func Test_WriteXlsxFailsWithUnknownLayout(t *testing.T) {
+	var buffer bytes.Buffer
+	err := WriteXlsx(&buffer, nil, XlsxOptions{Layout: "pivot"})
+	assert.NotNil(t, err)
+	err = WriteXlsx(&buffer, nil, XlsxOptions{Language: "fr"})
+	assert.NotNil(t, err)
+}
+
+func Test_columnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+type xlsxTestSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Reference string `xml:"r,attr"`
+			Value     string `xml:"v"`
+			Text      string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXlsxSheet reads the values of the sheet, empty cells are filled up to the last cell of the header.
+func readXlsxSheet(t *testing.T, content []byte) [][]string {
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+	var sheet xlsxTestSheet
+	for _, file := range zipReader.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		assert.Nil(t, err)
+		data, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		assert.Nil(t, xml.Unmarshal(data, &sheet))
+	}
+	assert.NotEmpty(t, sheet.Rows)
+	width := len(sheet.Rows[0].Cells)
+	var values [][]string
+	for _, row := range sheet.Rows {
+		line := make([]string, width)
+		for _, cell := range row.Cells {
+			column := 0
+			for _, letter := range cell.Reference {
+				if letter >= 'A' && letter <= 'Z' {
+					column = column*26 + int(letter-'A') + 1
+				}
+			}
+			line[column-1] = cell.Value + cell.Text
+		}
+		values = append(values, line)
+	}
+	return values
+}
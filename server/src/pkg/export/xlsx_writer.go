@@ -0,0 +1,155 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Styles of the cells, defined by the cellXfs in xlsxStyles
+const (
+	styleDefault = iota
+	styleDuration
+	styleHeader
+	styleTotal
+	styleTotalDuration
+)
+
+// xlsxCell is either a text or, if isNumber is set, a number
+type xlsxCell struct {
+	text     string
+	number   float64
+	isNumber bool
+	style    int
+}
+
+func textCell(text string) xlsxCell {
+	return xlsxCell{text: text}
+}
+
+// durationCell stores the duration as fraction of a day, the way spreadsheets store times
+func durationCell(duration time.Duration) xlsxCell {
+	return xlsxCell{number: duration.Hours() / 24, isNumber: true, style: styleDuration}
+}
+
+func totalDurationCell(duration time.Duration) xlsxCell {
+	cell := durationCell(duration)
+	cell.style = styleTotalDuration
+	return cell
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%v" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+const xlsxWorkbookRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="[h]:mm"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="5">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>
+</cellXfs>
+</styleSheet>`
+
+// writeXlsx writes a workbook with a single sheet. Only the parts that spreadsheet applications require are written,
+// texts are stored as inline strings.
+func writeXlsx(writer io.Writer, sheetName string, rows [][]xlsxCell) error {
+	var escapedSheetName bytes.Buffer
+	if err := xml.EscapeText(&escapedSheetName, []byte(sheetName)); err != nil {
+		return err
+	}
+	sheet, err := createXlsxSheet(rows)
+	if err != nil {
+		return err
+	}
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", []byte(xlsxContentTypes)},
+		{"_rels/.rels", []byte(xlsxRootRelationships)},
+		{"xl/workbook.xml", []byte(fmt.Sprintf(xlsxWorkbook, escapedSheetName.String()))},
+		{"xl/_rels/workbook.xml.rels", []byte(xlsxWorkbookRelationships)},
+		{"xl/styles.xml", []byte(xlsxStyles)},
+		{"xl/worksheets/sheet1.xml", sheet},
+	}
+	zipWriter := zip.NewWriter(writer)
+	for _, file := range files {
+		fileWriter, err := zipWriter.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fileWriter.Write(file.content); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+func createXlsxSheet(rows [][]xlsxCell) ([]byte, error) {
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for rowIndex, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, rowIndex+1)
+		for columnIndex, cell := range row {
+			reference := columnName(columnIndex) + strconv.Itoa(rowIndex+1)
+			switch {
+			case cell.isNumber:
+				fmt.Fprintf(&sheet, `<c r="%v" s="%d"><v>%v</v></c>`, reference, cell.style,
+					strconv.FormatFloat(cell.number, 'g', -1, 64))
+			case cell.text != "":
+				fmt.Fprintf(&sheet, `<c r="%v" s="%d" t="inlineStr"><is><t xml:space="preserve">`, reference, cell.style)
+				if err := xml.EscapeText(&sheet, []byte(cell.text)); err != nil {
+					return nil, err
+				}
+				sheet.WriteString(`</t></is></c>`)
+			}
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	return sheet.Bytes(), nil
+}
+
+// columnName returns the name of the column with the given zero based index (A, B, ..., Z, AA, AB, ...).
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,148 @@
+package holiday
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"timeasy-server/pkg/domain/model"
+)
+
+const RegionGermany = "DE"
+
+// The German federal states:
+const (
+	RegionBadenWuerttemberg     = "BW"
+	RegionBayern                = "BY"
+	RegionBerlin                = "BE"
+	RegionBrandenburg           = "BB"
+	RegionBremen                = "HB"
+	RegionHamburg               = "HH"
+	RegionHessen                = "HE"
+	RegionMecklenburgVorpommern = "MV"
+	RegionNiedersachsen         = "NI"
+	RegionNordrheinWestfalen    = "NW"
+	RegionRheinlandPfalz        = "RP"
+	RegionSaarland              = "SL"
+	RegionSachsen               = "SN"
+	RegionSachsenAnhalt         = "ST"
+	RegionSchleswigHolstein     = "SH"
+	RegionThueringen            = "TH"
+)
+
+var regions = []string{
+	RegionGermany,
+	RegionBadenWuerttemberg,
+	RegionBayern,
+	RegionBerlin,
+	RegionBrandenburg,
+	RegionBremen,
+	RegionHamburg,
+	RegionHessen,
+	RegionMecklenburgVorpommern,
+	RegionNiedersachsen,
+	RegionNordrheinWestfalen,
+	RegionRheinlandPfalz,
+	RegionSaarland,
+	RegionSachsen,
+	RegionSachsenAnhalt,
+	RegionSchleswigHolstein,
+	RegionThueringen,
+}
+
+// IsValidRegion checks if public holidays can be calculated for the given region.
+func IsValidRegion(region string) bool {
+	for _, validRegion := range regions {
+		if validRegion == region {
+			return true
+		}
+	}
+	return false
+}
+
+// GermanHolidays returns the public holidays of the given year in the given region sorted by date. Holidays that are
+// only observed in some municipalities of a state (e.g. Fronleichnam in parts of Sachsen) are not included.
+func GermanHolidays(year int, region string) ([]model.Holiday, error) {
+	if !IsValidRegion(region) {
+		return nil, fmt.Errorf("%v is not a valid region", region)
+	}
+	easter := EasterSunday(year)
+	holidays := []model.Holiday{
+		{Date: date(year, time.January, 1), Name: "Neujahr"},
+		{Date: easter.AddDate(0, 0, -2), Name: "Karfreitag"},
+		{Date: easter.AddDate(0, 0, 1), Name: "Ostermontag"},
+		{Date: date(year, time.May, 1), Name: "Tag der Arbeit"},
+		{Date: easter.AddDate(0, 0, 39), Name: "Christi Himmelfahrt"},
+		{Date: easter.AddDate(0, 0, 50), Name: "Pfingstmontag"},
+		{Date: date(year, time.October, 3), Name: "Tag der Deutschen Einheit"},
+		{Date: date(year, time.December, 25), Name: "1. Weihnachtstag"},
+		{Date: date(year, time.December, 26), Name: "2. Weihnachtstag"},
+	}
+	addIf := func(condition bool, holiday model.Holiday) {
+		if condition {
+			holidays = append(holidays, holiday)
+		}
+	}
+	in := func(validRegions ...string) bool {
+		for _, validRegion := range validRegions {
+			if validRegion == region {
+				return true
+			}
+		}
+		return false
+	}
+
+	addIf(in(RegionBadenWuerttemberg, RegionBayern, RegionSachsenAnhalt),
+		model.Holiday{Date: date(year, time.January, 6), Name: "Heilige Drei Könige"})
+	addIf((in(RegionBerlin) && year >= 2019) || (in(RegionMecklenburgVorpommern) && year >= 2023),
+		model.Holiday{Date: date(year, time.March, 8), Name: "Internationaler Frauentag"})
+	addIf(in(RegionBrandenburg), model.Holiday{Date: easter, Name: "Ostersonntag"})
+	addIf(in(RegionBrandenburg), model.Holiday{Date: easter.AddDate(0, 0, 49), Name: "Pfingstsonntag"})
+	addIf(in(RegionBadenWuerttemberg, RegionBayern, RegionHessen, RegionNordrheinWestfalen, RegionRheinlandPfalz, RegionSaarland),
+		model.Holiday{Date: easter.AddDate(0, 0, 60), Name: "Fronleichnam"})
+	addIf(in(RegionSaarland), model.Holiday{Date: date(year, time.August, 15), Name: "Mariä Himmelfahrt"})
+	addIf(in(RegionThueringen) && year >= 2019, model.Holiday{Date: date(year, time.September, 20), Name: "Weltkindertag"})
+	reformationDay := in(RegionBrandenburg, RegionMecklenburgVorpommern, RegionSachsen, RegionSachsenAnhalt, RegionThueringen) ||
+		(in(RegionBremen, RegionHamburg, RegionNiedersachsen, RegionSchleswigHolstein) && year >= 2018) ||
+		year == 2017 // 500 years of reformation were celebrated nationwide
+	addIf(reformationDay, model.Holiday{Date: date(year, time.October, 31), Name: "Reformationstag"})
+	addIf(in(RegionBadenWuerttemberg, RegionBayern, RegionNordrheinWestfalen, RegionRheinlandPfalz, RegionSaarland),
+		model.Holiday{Date: date(year, time.November, 1), Name: "Allerheiligen"})
+	addIf(in(RegionSachsen), model.Holiday{Date: dayOfRepentance(year), Name: "Buß- und Bettag"})
+
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays, nil
+}
+
+// EasterSunday calculates the date of easter sunday in the gregorian calendar (anonymous gregorian algorithm).
+func EasterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// dayOfRepentance returns the wednesday before november 23rd.
+func dayOfRepentance(year int) time.Time {
+	day := date(year, time.November, 22)
+	for day.Weekday() != time.Wednesday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
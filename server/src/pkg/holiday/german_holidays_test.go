@@ -0,0 +1,67 @@
+package holiday
+
+import (
+	"testing"
+	"time"
+
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EasterSunday(t *testing.T) {
+	assert.Equal(t, date(2019, time.April, 21), EasterSunday(2019))
+	assert.Equal(t, date(2023, time.April, 9), EasterSunday(2023))
+	assert.Equal(t, date(2024, time.March, 31), EasterSunday(2024))
+	assert.Equal(t, date(2025, time.April, 20), EasterSunday(2025))
+	assert.Equal(t, date(2038, time.April, 25), EasterSunday(2038))
+}
+
+func Test_GermanHolidaysNationwide(t *testing.T) {
+	holidays, err := GermanHolidays(2023, RegionGermany)
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(holidays))
+	assert.Equal(t, date(2023, time.January, 1), holidays[0].Date)
+	assert.Equal(t, "Karfreitag", holidays[1].Name)
+	assert.Equal(t, date(2023, time.April, 7), holidays[1].Date)
+	assert.Equal(t, "Christi Himmelfahrt", holidays[4].Name)
+	assert.Equal(t, date(2023, time.May, 18), holidays[4].Date)
+	assert.Equal(t, "Pfingstmontag", holidays[5].Name)
+	assert.Equal(t, date(2023, time.May, 29), holidays[5].Date)
+}
+
+func Test_GermanHolidaysOfStates(t *testing.T) {
+	holidays, err := GermanHolidays(2023, RegionBadenWuerttemberg)
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(holidays))
+	assert.True(t, containsHoliday(holidays, date(2023, time.January, 6), "Heilige Drei Könige"))
+	assert.True(t, containsHoliday(holidays, date(2023, time.June, 8), "Fronleichnam"))
+	assert.True(t, containsHoliday(holidays, date(2023, time.November, 1), "Allerheiligen"))
+
+	holidays, err = GermanHolidays(2023, RegionSachsen)
+	assert.Nil(t, err)
+	assert.True(t, containsHoliday(holidays, date(2023, time.October, 31), "Reformationstag"))
+	assert.True(t, containsHoliday(holidays, date(2023, time.November, 22), "Buß- und Bettag"))
+
+	holidays, err = GermanHolidays(2017, RegionNordrheinWestfalen)
+	assert.Nil(t, err)
+	assert.True(t, containsHoliday(holidays, date(2017, time.October, 31), "Reformationstag"))
+
+	holidays, err = GermanHolidays(2018, RegionNordrheinWestfalen)
+	assert.Nil(t, err)
+	assert.False(t, containsHoliday(holidays, date(2018, time.October, 31), "Reformationstag"))
+}
+
+func Test_GermanHolidaysFailsWithInvalidRegion(t *testing.T) {
+	_, err := GermanHolidays(2023, "XX")
+	assert.NotNil(t, err)
+}
+
+func containsHoliday(holidays []model.Holiday, day time.Time, name string) bool {
+	for _, holiday := range holidays {
+		if holiday.Date.Equal(day) && holiday.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// AppProject is a project of the database of the tracking app. It keeps the id of the app, so a later sync of the
+// app updates the imported project instead of creating it again.
+type AppProject struct {
+	// Line is the row of the project in the Projects table, it is used to report errors
+	Line    int
+	ID      uuid.UUID
+	Name    string
+	Deleted bool
+}
+
+// AppTimeEntry is a time entry of the database of the tracking app with the ids of the app.
+type AppTimeEntry struct {
+	// Line is the row of the entry in the TimeEntries table, it is used to report errors
+	Line        int
+	ID          uuid.UUID
+	ProjectID   uuid.UUID
+	StartTime   time.Time
+	EndTime     time.Time
+	Description string
+}
+
+// AppDatabase is the content of the SQLite database of the tracking app (timeasy.db).
+type AppDatabase struct {
+	Projects    []AppProject
+	TimeEntries []AppTimeEntry
+}
+
+// ReadAppDatabase reads the projects and time entries of the SQLite database file of the tracking app. The times are
+// stored as milliseconds since the epoch in UTC, an end time of 0 marks a running entry.
+// Rows with invalid ids are returned as record errors, the line is the row number within its table.
+func ReadAppDatabase(path string) (*AppDatabase, []RecordError, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer db.Close()
+	projectColumns, err := tableColumns(db, "Projects")
+	if err != nil {
+		return nil, nil, err
+	}
+	timeEntryColumns, err := tableColumns(db, "TimeEntries")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(projectColumns) == 0 || len(timeEntryColumns) == 0 {
+		return nil, nil, fmt.Errorf("the file is not a database of the timeasy app, the tables Projects and TimeEntries are missing")
+	}
+
+	var appDatabase AppDatabase
+	var recordErrors []RecordError
+	projects, projectErrors, err := readAppProjects(db, projectColumns["deleted"])
+	if err != nil {
+		return nil, nil, err
+	}
+	appDatabase.Projects = projects
+	recordErrors = append(recordErrors, projectErrors...)
+	timeEntries, timeEntryErrors, err := readAppTimeEntries(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	appDatabase.TimeEntries = timeEntries
+	recordErrors = append(recordErrors, timeEntryErrors...)
+	return &appDatabase, recordErrors, nil
+}
+
+// tableColumns returns the columns of the table, the result is empty if the table does not exist.
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, fmt.Errorf("the file cannot be read as SQLite database: %v", err)
+	}
+	defer rows.Close()
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// readAppProjects reads the projects, the deleted column was added by a migration of the app.
+func readAppProjects(db *sql.DB, hasDeletedColumn bool) ([]AppProject, []RecordError, error) {
+	query := "SELECT id, name, 0 FROM Projects ORDER BY rowid"
+	if hasDeletedColumn {
+		query = "SELECT id, name, deleted FROM Projects ORDER BY rowid"
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var projects []AppProject
+	var recordErrors []RecordError
+	line := 0
+	for rows.Next() {
+		line++
+		var id, name sql.NullString
+		var deleted sql.NullInt64
+		if err := rows.Scan(&id, &name, &deleted); err != nil {
+			return nil, nil, err
+		}
+		projectId, err := uuid.FromString(id.String)
+		if err != nil {
+			recordErrors = append(recordErrors, newRecordError(line, "project has an invalid id %q", id.String))
+			continue
+		}
+		projects = append(projects, AppProject{Line: line, ID: projectId, Name: name.String, Deleted: deleted.Int64 != 0})
+	}
+	return projects, recordErrors, rows.Err()
+}
+
+func readAppTimeEntries(db *sql.DB) ([]AppTimeEntry, []RecordError, error) {
+	rows, err := db.Query("SELECT id, projectId, startTime, endTime, description FROM TimeEntries ORDER BY rowid")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var timeEntries []AppTimeEntry
+	var recordErrors []RecordError
+	line := 0
+	for rows.Next() {
+		line++
+		var id, projectId, description sql.NullString
+		var startTime, endTime sql.NullInt64
+		if err := rows.Scan(&id, &projectId, &startTime, &endTime, &description); err != nil {
+			return nil, nil, err
+		}
+		timeEntryId, err := uuid.FromString(id.String)
+		if err != nil {
+			recordErrors = append(recordErrors, newRecordError(line, "time entry has an invalid id %q", id.String))
+			continue
+		}
+		timeEntryProjectId, err := uuid.FromString(projectId.String)
+		if err != nil {
+			recordErrors = append(recordErrors, newRecordError(line, "time entry has an invalid project id %q",
+				projectId.String))
+			continue
+		}
+		if startTime.Int64 <= 0 {
+			recordErrors = append(recordErrors, newRecordError(line, "time entry has no start time"))
+			continue
+		}
+		timeEntry := AppTimeEntry{
+			Line:        line,
+			ID:          timeEntryId,
+			ProjectID:   timeEntryProjectId,
+			StartTime:   time.UnixMilli(startTime.Int64).UTC(),
+			Description: description.String,
+		}
+		if endTime.Int64 > 0 {
+			timeEntry.EndTime = time.UnixMilli(endTime.Int64).UTC()
+		}
+		timeEntries = append(timeEntries, timeEntry)
+	}
+	return timeEntries, recordErrors, rows.Err()
+}
@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func createAppDatabase(t *testing.T, statements ...string) string {
+	path := filepath.Join(t.TempDir(), "timeasy.db")
+	db, err := sql.Open("sqlite", path)
+	assert.Nil(t, err)
+	defer db.Close()
+	for _, statement := range append([]string{
+		"CREATE TABLE Projects (id TEXT, name TEXT, created INTEGER, updated INTEGER)",
+		"CREATE TABLE TimeEntries (id TEXT, startTime INTEGER, endTime INTEGER, description TEXT, created INTEGER, " +
+			"updated INTEGER, projectId TEXT, FOREIGN KEY(projectId) REFERENCES Projects(id))",
+	}, statements...) {
+		_, err := db.Exec(statement)
+		assert.Nil(t, err)
+	}
+	return path
+}
+
+func Test_ReadAppDatabase(t *testing.T) {
+	projectId := uuid.Must(uuid.NewV1())
+	deletedProjectId := uuid.Must(uuid.NewV1())
+	timeEntryId := uuid.Must(uuid.NewV1())
+	runningId := uuid.Must(uuid.NewV1())
+	start := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	path := createAppDatabase(t,
+		"ALTER TABLE Projects ADD COLUMN deleted INTEGER DEFAULT 0",
+		"INSERT INTO Projects VALUES ('"+projectId.String()+"', 'Website', 1, 1, 0)",
+		"INSERT INTO Projects VALUES ('"+deletedProjectId.String()+"', 'Old', 1, 1, 1)",
+		"INSERT INTO Projects VALUES ('invalid', 'Broken', 1, 1, 0)",
+		"INSERT INTO TimeEntries VALUES ('"+timeEntryId.String()+"', 1693814400000, 1693823400000, 'Mockups', 1, 1, '"+
+			projectId.String()+"')",
+		"INSERT INTO TimeEntries VALUES ('"+runningId.String()+"', 1693814400000, 0, NULL, 1, 1, '"+
+			projectId.String()+"')",
+		"INSERT INTO TimeEntries VALUES ('"+uuid.Must(uuid.NewV1()).String()+"', 1693814400000, 0, '', 1, 1, 'x')",
+	)
+
+	appDatabase, recordErrors, err := ReadAppDatabase(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []AppProject{
+		{Line: 1, ID: projectId, Name: "Website"},
+		{Line: 2, ID: deletedProjectId, Name: "Old", Deleted: true},
+	}, appDatabase.Projects)
+	assert.Equal(t, []AppTimeEntry{
+		{Line: 1, ID: timeEntryId, ProjectID: projectId, StartTime: start, EndTime: start.Add(150 * time.Minute),
+			Description: "Mockups"},
+		{Line: 2, ID: runningId, ProjectID: projectId, StartTime: start},
+	}, appDatabase.TimeEntries)
+	assert.Equal(t, 2, len(recordErrors))
+	assert.Equal(t, 3, recordErrors[0].Line)
+	assert.Equal(t, 3, recordErrors[1].Line)
+}
+
+func Test_ReadAppDatabaseWithoutDeletedColumn(t *testing.T) {
+	projectId := uuid.Must(uuid.NewV1())
+	path := createAppDatabase(t, "INSERT INTO Projects VALUES ('"+projectId.String()+"', 'Website', 1, 1)")
+
+	appDatabase, recordErrors, err := ReadAppDatabase(path)
+	assert.Nil(t, err)
+	assert.Empty(t, recordErrors)
+	assert.Equal(t, []AppProject{{Line: 1, ID: projectId, Name: "Website"}}, appDatabase.Projects)
+	assert.Empty(t, appDatabase.TimeEntries)
+}
+
+func Test_ReadAppDatabaseOfOtherFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "other.db")
+	db, err := sql.Open("sqlite", path)
+	assert.Nil(t, err)
+	_, err = db.Exec("CREATE TABLE Other (id TEXT)")
+	assert.Nil(t, err)
+	db.Close()
+
+	_, _, err = ReadAppDatabase(path)
+	assert.NotNil(t, err)
+
+	path = filepath.Join(t.TempDir(), "timeentries.csv")
+	assert.Nil(t, os.WriteFile(path, []byte("Date,Project\n"), 0o600))
+	_, _, err = ReadAppDatabase(path)
+	assert.NotNil(t, err)
+
+	_, _, err = ReadAppDatabase(filepath.Join(t.TempDir(), "missing.db"))
+	assert.NotNil(t, err)
+}
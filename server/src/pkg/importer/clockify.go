@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ReadClockifyCsv reads the CSV file of the detailed report of Clockify. Its times have no time zone, they are read in
+// the location of the options. The date format depends on the settings of the workspace, the default is MM/DD/YYYY.
+func ReadClockifyCsv(reader io.Reader, options FileOptions) ([]Record, []RecordError, error) {
+	table, err := newCsvTable(reader, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := table.requireColumns("start date", "start time", "end date", "end time"); err != nil {
+		return nil, nil, err
+	}
+	if options.DateFormat == "" {
+		options.DateFormat = "01/02/2006"
+	}
+	return table.readLines(func(value func(title string) string) (Record, error) {
+		record := Record{
+			Client:      value("client"),
+			Project:     value("project"),
+			Task:        value("task"),
+			Description: value("description"),
+			Billable:    parseBillable(value("billable")),
+			Tags:        splitTags(value("tags")),
+		}
+		if record.Project == "" {
+			record.Project = NoProject
+		}
+		var err error
+		record.StartTime, err = parseDateTime(value("start date"), value("start time"), options)
+		if err != nil {
+			return record, err
+		}
+		record.EndTime, err = parseDateTime(value("end date"), value("end time"), options)
+		if err != nil {
+			return record, err
+		}
+		record.StartTime = record.StartTime.UTC()
+		record.EndTime = record.EndTime.UTC()
+		return record, checkTimes(record)
+	})
+}
+
+type clockifyName struct {
+	Name       string `json:"name"`
+	ClientName string `json:"clientName"`
+}
+
+// clockifyTimeEntry is a time entry of the detailed report of Clockify or of its API with hydrated entries
+type clockifyTimeEntry struct {
+	Id           string         `json:"id"`
+	ReportId     string         `json:"_id"`
+	Description  string         `json:"description"`
+	Billable     bool           `json:"billable"`
+	ProjectName  string         `json:"projectName"`
+	ClientName   string         `json:"clientName"`
+	TaskName     string         `json:"taskName"`
+	Project      *clockifyName  `json:"project"`
+	Task         *clockifyName  `json:"task"`
+	Tags         []clockifyName `json:"tags"`
+	TimeInterval struct {
+		Start time.Time  `json:"start"`
+		End   *time.Time `json:"end"`
+	} `json:"timeInterval"`
+}
+
+// ReadClockifyJson reads the JSON of the detailed report of Clockify (the entries are in "timeentries") or a list of
+// hydrated time entries of its API. The times contain their time zone.
+func ReadClockifyJson(reader io.Reader) ([]Record, []RecordError, error) {
+	var entries []clockifyTimeEntry
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if firstJsonByte(content) == '[' {
+		err = json.Unmarshal(content, &entries)
+	} else {
+		var report struct {
+			TimeEntries []clockifyTimeEntry `json:"timeentries"`
+		}
+		err = json.Unmarshal(content, &report)
+		entries = report.TimeEntries
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("the file is not a detailed report of Clockify: %w", err)
+	}
+	var records []Record
+	var recordErrors []RecordError
+	for i, entry := range entries {
+		record := Record{
+			Line:        i + 1,
+			StartTime:   entry.TimeInterval.Start.UTC(),
+			Client:      entry.ClientName,
+			Project:     entry.ProjectName,
+			Task:        entry.TaskName,
+			Description: entry.Description,
+			Billable:    entry.Billable,
+			ExternalID:  entry.Id,
+		}
+		if record.ExternalID == "" {
+			record.ExternalID = entry.ReportId
+		}
+		if entry.TimeInterval.End != nil {
+			record.EndTime = entry.TimeInterval.End.UTC()
+		}
+		if entry.Project != nil {
+			record.Project = entry.Project.Name
+			record.Client = entry.Project.ClientName
+		}
+		if entry.Task != nil {
+			record.Task = entry.Task.Name
+		}
+		for _, tag := range entry.Tags {
+			record.Tags = append(record.Tags, tag.Name)
+		}
+		if record.Project == "" {
+			record.Project = NoProject
+		}
+		if err := checkTimes(record); err != nil {
+			recordErrors = append(recordErrors, newRecordError(record.Line, "%v", err))
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, recordErrors, nil
+}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadClockifyCsv(t *testing.T) {
+	file := "Project,Client,Description,Task,User,Group,Email,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal),Billable Rate (USD),Billable Amount (USD)\n" +
+		"Website,ACME,Mockups,Design,Jane,,jane@example.com,\"meeting, remote\",Yes,09/04/2023,08:00:00 AM,09/04/2023,10:30:00 AM,02:30:00,2.50,100.00,250.00\n" +
+		"Website,ACME,Review,,Jane,,jane@example.com,,No,09/04/2023,01:00:00 PM,09/04/2023,02:00:00 PM,01:00:00,1.00,0.00,0.00\n" +
+		"Website,ACME,Broken,,Jane,,jane@example.com,,No,2023-09-04,01:00:00 PM,09/04/2023,02:00:00 PM,01:00:00,1.00,0.00,0.00\n"
+	records, recordErrors, err := ReadClockifyCsv(strings.NewReader(file), FileOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, Record{
+		Line:        2,
+		StartTime:   time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2023, 9, 4, 10, 30, 0, 0, time.UTC),
+		Client:      "ACME",
+		Project:     "Website",
+		Task:        "Design",
+		Description: "Mockups",
+		Tags:        []string{"meeting", "remote"},
+		Billable:    true,
+	}, records[0])
+	assert.Equal(t, time.Date(2023, 9, 4, 13, 0, 0, 0, time.UTC), records[1].StartTime)
+	assert.Equal(t, 1, len(recordErrors))
+	assert.Equal(t, 4, recordErrors[0].Line)
+
+	// The date format of the workspace can be configured:
+	file = "Project,Start Date,Start Time,End Date,End Time\n" +
+		"Website,04.09.2023,08:00,04.09.2023,09:00\n"
+	records, recordErrors, err = ReadClockifyCsv(strings.NewReader(file), FileOptions{DateFormat: "02.01.2006"})
+	assert.Nil(t, err)
+	assert.Empty(t, recordErrors)
+	assert.Equal(t, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), records[0].StartTime)
+}
+
+func Test_ReadClockifyJson(t *testing.T) {
+	report := `{"totals": [], "timeentries": [
+		{"_id": "64f5a1", "description": "Mockups", "billable": true, "projectName": "Website", "clientName": "ACME",
+		 "taskName": "Design", "tags": [{"name": "meeting"}],
+		 "timeInterval": {"start": "2023-09-04T08:00:00+02:00", "end": "2023-09-04T10:30:00+02:00", "duration": 9000}},
+		{"_id": "64f5a2", "description": "Running", "timeInterval": {"start": "2023-09-05T08:00:00Z", "end": null}}
+	]}`
+	records, recordErrors, err := ReadClockifyJson(strings.NewReader(report))
+	assert.Nil(t, err)
+	assert.Equal(t, []Record{{
+		Line:        1,
+		StartTime:   time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2023, 9, 4, 8, 30, 0, 0, time.UTC),
+		Client:      "ACME",
+		Project:     "Website",
+		Task:        "Design",
+		Description: "Mockups",
+		Tags:        []string{"meeting"},
+		Billable:    true,
+		ExternalID:  "64f5a1",
+	}}, records)
+	assert.Equal(t, 1, len(recordErrors))
+
+	hydrated := `[{"id": "64f5b1", "description": "Review", "billable": false,
+		"project": {"name": "Website", "clientName": "ACME"}, "task": {"name": "QA"}, "tags": [],
+		"timeInterval": {"start": "2023-09-04T13:00:00Z", "end": "2023-09-04T14:00:00Z"}}]`
+	records, recordErrors, err = ReadClockifyJson(strings.NewReader(hydrated))
+	assert.Nil(t, err)
+	assert.Empty(t, recordErrors)
+	assert.Equal(t, "64f5b1", records[0].ExternalID)
+	assert.Equal(t, "Website", records[0].Project)
+	assert.Equal(t, "ACME", records[0].Client)
+	assert.Equal(t, "QA", records[0].Task)
+}
@@ -0,0 +1,227 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields of a time entry which can be mapped to the columns of a CSV file
+const (
+	FieldDate        = "date"
+	FieldStart       = "start"
+	FieldEnd         = "end"
+	FieldDuration    = "duration"
+	FieldProject     = "project"
+	FieldDescription = "description"
+)
+
+var fields = []string{FieldDate, FieldStart, FieldEnd, FieldDuration, FieldProject, FieldDescription}
+
+// Default formats, they match the CSV export
+const (
+	DefaultDateFormat = "2006-01-02"
+	DefaultTimeFormat = "15:04"
+)
+
+type CsvOptions struct {
+	// Mapping maps fields to the titles of the columns in the header line. Fields which are not mapped are looked up
+	// by their own name, so files of the CSV export can be imported without a mapping.
+	Mapping map[string]string
+	// Delimiter separates the fields, a comma is used if it is not set
+	Delimiter rune
+	// DateFormat and TimeFormat are layouts of the time package. Without a date column the start and end columns
+	// contain date and time separated by a space.
+	DateFormat string
+	TimeFormat string
+	// DecimalComma reads durations in decimal hours with a decimal comma instead of a point
+	DecimalComma bool
+	// Location is the time zone of dates and times, UTC is used if it is not set
+	Location *time.Location
+}
+
+// ParseMapping parses a comma separated list of field=column pairs, e.g. "date=Datum,start=Von".
+func ParseMapping(mappingList string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(mappingList) == "" {
+		return mapping, nil
+	}
+	for _, pair := range strings.Split(mappingList, ",") {
+		field, column, found := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !found || strings.TrimSpace(column) == "" {
+			return nil, fmt.Errorf("the mapping %q must have the form field=column", pair)
+		}
+		if !isKnownField(field) {
+			return nil, fmt.Errorf("unknown field %v, allowed are %v", field, strings.Join(fields, ", "))
+		}
+		mapping[field] = strings.TrimSpace(column)
+	}
+	return mapping, nil
+}
+
+var formatReplacer = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02", "HH", "15", "mm", "04",
+	"ss", "05")
+
+// ParseFormat converts a date or time format like DD.MM.YYYY or HH:mm into a layout of the time package.
+// Layouts of the time package are returned unchanged.
+func ParseFormat(format string) string {
+	return formatReplacer.Replace(format)
+}
+
+// ReadCsv reads the time entries of a CSV file with a header line. Lines with invalid values are returned as
+// RecordErrors, an error is only returned if the file cannot be read at all.
+// Each line needs a start, an end or a duration and a project. An end before the start belongs to the next day.
+func ReadCsv(reader io.Reader, options CsvOptions) ([]Record, []RecordError, error) {
+	if options.DateFormat == "" {
+		options.DateFormat = DefaultDateFormat
+	}
+	if options.TimeFormat == "" {
+		options.TimeFormat = DefaultTimeFormat
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	table, err := newCsvTable(reader, options.Delimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+	columns, err := findColumns(table.columns, options.Mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+	return table.readLines(func(value func(title string) string) (Record, error) {
+		return parseCsvRecord(func(field string) string {
+			title, ok := columns[field]
+			if !ok {
+				return ""
+			}
+			return value(title)
+		}, columns, options)
+	})
+}
+
+// findColumns returns the title of the column of each field found in the header line.
+func findColumns(indexes map[string]int, mapping map[string]string) (map[string]string, error) {
+	columns := make(map[string]string)
+	for _, field := range fields {
+		title, mapped := mapping[field]
+		if !mapped {
+			title = field
+		}
+		title = strings.ToLower(title)
+		if _, ok := indexes[title]; !ok {
+			if mapped {
+				return nil, fmt.Errorf("the column %v of the field %v does not exist", mapping[field], field)
+			}
+			continue
+		}
+		columns[field] = title
+	}
+	if _, ok := columns[FieldStart]; !ok {
+		return nil, fmt.Errorf("the file has no column for the start")
+	}
+	if _, ok := columns[FieldProject]; !ok {
+		return nil, fmt.Errorf("the file has no column for the project")
+	}
+	_, hasEnd := columns[FieldEnd]
+	_, hasDuration := columns[FieldDuration]
+	if !hasEnd && !hasDuration {
+		return nil, fmt.Errorf("the file has neither a column for the end nor for the duration")
+	}
+	return columns, nil
+}
+
+func parseCsvRecord(value func(field string) string, columns map[string]string, options CsvOptions) (Record, error) {
+	var record Record
+	record.Project = value(FieldProject)
+	if record.Project == "" {
+		return record, fmt.Errorf("the project is missing")
+	}
+	record.Description = value(FieldDescription)
+
+	timeLayout := options.DateFormat + " " + options.TimeFormat
+	datePrefix := ""
+	if _, ok := columns[FieldDate]; ok {
+		date := value(FieldDate)
+		if _, err := time.Parse(options.DateFormat, date); err != nil {
+			return record, fmt.Errorf("the date %q does not match the format %v", date, options.DateFormat)
+		}
+		timeLayout = options.TimeFormat
+		datePrefix = date + " "
+	}
+	parseTime := func(field string) (time.Time, error) {
+		text := value(field)
+		if _, err := time.Parse(timeLayout, text); err != nil {
+			return time.Time{}, fmt.Errorf("the %v %q does not match the format %v", field, text, timeLayout)
+		}
+		if datePrefix != "" {
+			return time.ParseInLocation(options.DateFormat+" "+options.TimeFormat, datePrefix+text, options.Location)
+		}
+		return time.ParseInLocation(timeLayout, text, options.Location)
+	}
+
+	startTime, err := parseTime(FieldStart)
+	if err != nil {
+		return record, err
+	}
+	var endTime time.Time
+	if value(FieldEnd) != "" {
+		endTime, err = parseTime(FieldEnd)
+		if err != nil {
+			return record, err
+		}
+		if endTime.Before(startTime) && datePrefix != "" {
+			endTime = endTime.AddDate(0, 0, 1)
+		}
+	} else {
+		duration, err := parseDuration(value(FieldDuration), options.DecimalComma)
+		if err != nil {
+			return record, err
+		}
+		endTime = startTime.Add(duration)
+	}
+	if !endTime.After(startTime) {
+		return record, fmt.Errorf("the end must be after the start")
+	}
+	record.StartTime = startTime.UTC()
+	record.EndTime = endTime.UTC()
+	return record, nil
+}
+
+// parseDuration parses a duration in decimal hours (e.g. 1.5) or in hours and minutes (e.g. 1:30).
+func parseDuration(text string, decimalComma bool) (time.Duration, error) {
+	if text == "" {
+		return 0, fmt.Errorf("the end and the duration are missing")
+	}
+	if hoursText, minutesText, found := strings.Cut(text, ":"); found {
+		hours, err := strconv.Atoi(hoursText)
+		if err == nil {
+			var minutes int
+			minutes, err = strconv.Atoi(minutesText)
+			if err == nil && hours >= 0 && minutes >= 0 && minutes < 60 {
+				return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+			}
+		}
+		return 0, fmt.Errorf("the duration %q is invalid", text)
+	}
+	if decimalComma {
+		text = strings.Replace(text, ",", ".", 1)
+	}
+	hours, err := strconv.ParseFloat(text, 64)
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("the duration %q is invalid", text)
+	}
+	return time.Duration(hours * float64(time.Hour)).Round(time.Second), nil
+}
+
+func isKnownField(field string) bool {
+	for _, knownField := range fields {
+		if field == knownField {
+			return true
+		}
+	}
+	return false
+}
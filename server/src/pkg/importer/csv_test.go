@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadCsvOfExport(t *testing.T) {
+	file := "date,start,end,duration,netduration,project,description,user\n" +
+		"2023-09-04,08:00,09:30,1.50,1.50,project,\"meeting, planning\",6f4d1fa0-5a4f-4bbc-a7b5-0a1a2e2a9f1e\n" +
+		"2023-09-04,22:00,01:00,3.00,3.00,project,night shift,6f4d1fa0-5a4f-4bbc-a7b5-0a1a2e2a9f1e\n"
+	records, recordErrors, err := ReadCsv(strings.NewReader(file), CsvOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, recordErrors)
+	assert.Equal(t, []Record{
+		{Line: 2, StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 4, 9, 30, 0, 0, time.UTC),
+			Project: "project", Description: "meeting, planning"},
+		{Line: 3, StartTime: time.Date(2023, 9, 4, 22, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 5, 1, 0, 0, 0, time.UTC),
+			Project: "project", Description: "night shift"},
+	}, records)
+}
+
+func Test_ReadCsvWithMapping(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	assert.Nil(t, err)
+	mapping, err := ParseMapping("date=Datum, start=Beginn, duration=Dauer, project=Projekt, description=Notiz")
+	assert.Nil(t, err)
+	file := "\ufeffDatum;Beginn;Dauer;Projekt;Notiz\n" +
+		"04.09.2023;08:00;1,5;Kunde A;Workshop\n" +
+		"05.09.2023;8 Uhr;2;Kunde A;\n" +
+		"06.09.2023;08:00;2:15;;\n" +
+		"07.09.2023;08:00;2:15;Kunde B;\n"
+	records, recordErrors, err := ReadCsv(strings.NewReader(file), CsvOptions{
+		Mapping:      mapping,
+		Delimiter:    ';',
+		DateFormat:   ParseFormat("DD.MM.YYYY"),
+		TimeFormat:   ParseFormat("HH:mm"),
+		DecimalComma: true,
+		Location:     location,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC), records[0].StartTime)
+	assert.Equal(t, time.Date(2023, 9, 4, 7, 30, 0, 0, time.UTC), records[0].EndTime)
+	assert.Equal(t, "Workshop", records[0].Description)
+	assert.Equal(t, 5, records[1].Line)
+	assert.Equal(t, 135*time.Minute, records[1].EndTime.Sub(records[1].StartTime))
+	assert.Equal(t, 2, len(recordErrors))
+	assert.Equal(t, 3, recordErrors[0].Line)
+	assert.Equal(t, 4, recordErrors[1].Line)
+	assert.Equal(t, "line 4: the project is missing", recordErrors[1].Error())
+}
+
+func Test_ReadCsvWithoutDateColumn(t *testing.T) {
+	file := "start,end,project\n" +
+		"2023-09-04 22:00,2023-09-05 01:00,project\n" +
+		"2023-09-04 10:00,2023-09-04 09:00,project\n"
+	records, recordErrors, err := ReadCsv(strings.NewReader(file), CsvOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, 3*time.Hour, records[0].EndTime.Sub(records[0].StartTime))
+	assert.Equal(t, 1, len(recordErrors))
+	assert.Equal(t, "the end must be after the start", recordErrors[0].Message)
+}
+
+func Test_ReadCsvFailsWithMissingColumns(t *testing.T) {
+	for _, file := range []string{"", "date,start,end\n", "start,project\n"} {
+		_, _, err := ReadCsv(strings.NewReader(file), CsvOptions{})
+		assert.NotNil(t, err, file)
+	}
+	_, _, err := ReadCsv(strings.NewReader("date,start,end,project\n"), CsvOptions{Mapping: map[string]string{FieldProject: "Projekt"}})
+	assert.NotNil(t, err)
+}
+
+func Test_ParseMapping(t *testing.T) {
+	mapping, err := ParseMapping("")
+	assert.Nil(t, err)
+	assert.Empty(t, mapping)
+	for _, mappingList := range []string{"date", "date=", "user=User"} {
+		_, err = ParseMapping(mappingList)
+		assert.NotNil(t, err, mappingList)
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	assert.Equal(t, "02.01.2006", ParseFormat("DD.MM.YYYY"))
+	assert.Equal(t, "01/02/06", ParseFormat("MM/DD/YY"))
+	assert.Equal(t, "15:04:05", ParseFormat("HH:mm:ss"))
+	assert.Equal(t, "2006-01-02", ParseFormat("2006-01-02"))
+}
@@ -0,0 +1,64 @@
+// Package importer reads time entries from the files of other time tracking tools and spreadsheets.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Record is a time entry read from an import file. The project is referenced by its name, because the projects of
+// the source do not exist in timeasy yet.
+type Record struct {
+	// Line is the line of the record in the file, it is used to report errors
+	Line        int
+	StartTime   time.Time
+	EndTime     time.Time
+	Project     string
+	Description string
+	// Client, Task, Tags and Billable are only known by some sources
+	Client   string
+	Task     string
+	Tags     []string
+	Billable bool
+	// ExternalID is the id of the entry in the source, it is empty if the file has no ids
+	ExternalID string
+}
+
+// FileOptions describe the dates and times of files without time zone, e.g. the CSV exports of Toggl and Clockify.
+// Empty formats are detected.
+type FileOptions struct {
+	DateFormat string
+	TimeFormat string
+	// Location is the time zone of dates and times, UTC is used if it is not set
+	Location *time.Location
+}
+
+// RecordError describes why a line of the file cannot be imported.
+type RecordError struct {
+	Line    int
+	Message string
+}
+
+func (err RecordError) Error() string {
+	return fmt.Sprintf("line %d: %v", err.Line, err.Message)
+}
+
+// IsJson returns whether the content starts like a JSON document instead of a CSV file.
+func IsJson(content []byte) bool {
+	first := firstJsonByte(content)
+	return first == '{' || first == '['
+}
+
+// firstJsonByte returns the first byte of the content after white space, or 0 for empty content.
+func firstJsonByte(content []byte) byte {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(content, []byte("\ufeff")), " \t\r\n")
+	if len(trimmed) == 0 {
+		return 0
+	}
+	return trimmed[0]
+}
+
+func newRecordError(line int, format string, a ...any) RecordError {
+	return RecordError{Line: line, Message: fmt.Sprintf(format, a...)}
+}
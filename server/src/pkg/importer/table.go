@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts tried for the dates and times of files if no format is given
+var (
+	defaultDateLayouts = []string{"2006-01-02", "01/02/2006", "02.01.2006"}
+	defaultTimeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "03:04 PM", "3:04:05 PM", "3:04 PM"}
+)
+
+// csvTable is a CSV file with a header line whose columns are accessed by their titles.
+type csvTable struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCsvTable(reader io.Reader, delimiter rune) (*csvTable, error) {
+	csvReader := csv.NewReader(reader)
+	if delimiter != 0 {
+		csvReader.Comma = delimiter
+	}
+	csvReader.FieldsPerRecord = -1
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("the header line cannot be read: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, title := range header {
+		if i == 0 {
+			// Excel writes a byte order mark
+			title = strings.TrimPrefix(title, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(title))] = i
+	}
+	return &csvTable{reader: csvReader, columns: columns}, nil
+}
+
+// requireColumns returns an error if one of the columns is missing.
+func (table *csvTable) requireColumns(titles ...string) error {
+	for _, title := range titles {
+		if _, ok := table.columns[title]; !ok {
+			return fmt.Errorf("the file has no column %q", title)
+		}
+	}
+	return nil
+}
+
+// readLines calls parse for each line. The errors of lines are collected as RecordErrors.
+func (table *csvTable) readLines(parse func(value func(title string) string) (Record, error)) ([]Record, []RecordError, error) {
+	var records []Record
+	var recordErrors []RecordError
+	for {
+		values, err := table.reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			recordErrors = append(recordErrors, newRecordError(parseError.StartLine, "%v", parseError.Err))
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := table.reader.FieldPos(0)
+		value := func(title string) string {
+			index, ok := table.columns[title]
+			if !ok || index >= len(values) {
+				return ""
+			}
+			return strings.TrimSpace(values[index])
+		}
+		record, err := parse(value)
+		if err != nil {
+			recordErrors = append(recordErrors, newRecordError(line, "%v", err))
+			continue
+		}
+		record.Line = line
+		records = append(records, record)
+	}
+	return records, recordErrors, nil
+}
+
+// parseDateTime parses a date and a time of the columns of a file in the format or location of the options.
+func parseDateTime(date string, clock string, options FileOptions) (time.Time, error) {
+	location := options.Location
+	if location == nil {
+		location = time.UTC
+	}
+	dateLayouts := defaultDateLayouts
+	if options.DateFormat != "" {
+		dateLayouts = []string{options.DateFormat}
+	}
+	timeLayouts := defaultTimeLayouts
+	if options.TimeFormat != "" {
+		timeLayouts = []string{options.TimeFormat}
+	}
+	for _, dateLayout := range dateLayouts {
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			continue
+		}
+		for _, timeLayout := range timeLayouts {
+			dateTime, err := time.ParseInLocation(dateLayout+" "+timeLayout, date+" "+clock, location)
+			if err == nil {
+				return dateTime, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("the time %q is invalid", clock)
+	}
+	return time.Time{}, fmt.Errorf("the date %q is invalid", date)
+}
+
+// parseBillable parses the billable flag of the exports, e.g. "Yes" or "true".
+func parseBillable(text string) bool {
+	switch strings.ToLower(text) {
+	case "yes", "ja":
+		return true
+	}
+	billable, _ := strconv.ParseBool(text)
+	return billable
+}
+
+// splitTags splits the comma separated tags of a CSV column.
+func splitTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// checkTimes returns an error if the end of the record is not after its start.
+func checkTimes(record Record) error {
+	if record.StartTime.IsZero() || record.EndTime.IsZero() {
+		return fmt.Errorf("the start and the end are required, running entries cannot be imported")
+	}
+	if !record.EndTime.After(record.StartTime) {
+		return fmt.Errorf("the end must be after the start")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Entries without project get this project, because every time entry of timeasy belongs to a project
+const NoProject = "No project"
+
+// ReadTogglCsv reads the CSV file of the detailed report of Toggl Track. Its times have no time zone, they are read in
+// the location of the options.
+func ReadTogglCsv(reader io.Reader, options FileOptions) ([]Record, []RecordError, error) {
+	table, err := newCsvTable(reader, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := table.requireColumns("start date", "start time", "end date", "end time"); err != nil {
+		return nil, nil, err
+	}
+	return table.readLines(func(value func(title string) string) (Record, error) {
+		record := Record{
+			Client:      value("client"),
+			Project:     value("project"),
+			Task:        value("task"),
+			Description: value("description"),
+			Billable:    parseBillable(value("billable")),
+			Tags:        splitTags(value("tags")),
+		}
+		if record.Project == "" {
+			record.Project = NoProject
+		}
+		var err error
+		record.StartTime, err = parseDateTime(value("start date"), value("start time"), options)
+		if err != nil {
+			return record, err
+		}
+		record.EndTime, err = parseDateTime(value("end date"), value("end time"), options)
+		if err != nil {
+			return record, err
+		}
+		record.StartTime = record.StartTime.UTC()
+		record.EndTime = record.EndTime.UTC()
+		return record, checkTimes(record)
+	})
+}
+
+// togglTimeEntry is a time entry of the JSON of the detailed report of Toggl Track
+type togglTimeEntry struct {
+	Id          int64     `json:"id"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Client      string    `json:"client"`
+	Project     string    `json:"project"`
+	Task        string    `json:"task"`
+	Billable    any       `json:"billable"`
+	IsBillable  bool      `json:"is_billable"`
+	Tags        []string  `json:"tags"`
+}
+
+// ReadTogglJson reads the JSON of the detailed report of Toggl Track, either the report object with the entries in
+// "data" or only the list of entries. The times contain their time zone.
+func ReadTogglJson(reader io.Reader) ([]Record, []RecordError, error) {
+	var entries []togglTimeEntry
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if firstJsonByte(content) == '[' {
+		err = json.Unmarshal(content, &entries)
+	} else {
+		var report struct {
+			Data []togglTimeEntry `json:"data"`
+		}
+		err = json.Unmarshal(content, &report)
+		entries = report.Data
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("the file is not a detailed report of Toggl Track: %w", err)
+	}
+	var records []Record
+	var recordErrors []RecordError
+	for i, entry := range entries {
+		record := Record{
+			Line:        i + 1,
+			StartTime:   entry.Start.UTC(),
+			EndTime:     entry.End.UTC(),
+			Client:      entry.Client,
+			Project:     entry.Project,
+			Task:        entry.Task,
+			Description: entry.Description,
+			Tags:        entry.Tags,
+			Billable:    entry.IsBillable,
+		}
+		// The billable amount replaced the flag in older reports
+		if billable, ok := entry.Billable.(bool); ok {
+			record.Billable = record.Billable || billable
+		}
+		if entry.Id != 0 {
+			record.ExternalID = strconv.FormatInt(entry.Id, 10)
+		}
+		if record.Project == "" {
+			record.Project = NoProject
+		}
+		if err := checkTimes(record); err != nil {
+			recordErrors = append(recordErrors, newRecordError(record.Line, "%v", err))
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, recordErrors, nil
+}
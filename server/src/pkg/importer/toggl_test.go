@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadTogglCsv(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	assert.Nil(t, err)
+	file := "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount (EUR)\n" +
+		"Jane,jane@example.com,ACME,Website,Design,Mockups,Yes,2023-09-04,08:00:00,2023-09-04,10:30:00,02:30:00,\"meeting, remote\",\n" +
+		"Jane,jane@example.com,,,,Admin,No,2023-09-04,23:00:00,2023-09-05,00:30:00,01:30:00,,\n" +
+		"Jane,jane@example.com,,,,Running,No,2023-09-05,08:00:00,,,,,\n"
+	records, recordErrors, err := ReadTogglCsv(strings.NewReader(file), FileOptions{Location: location})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, Record{
+		Line:        2,
+		StartTime:   time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2023, 9, 4, 8, 30, 0, 0, time.UTC),
+		Client:      "ACME",
+		Project:     "Website",
+		Task:        "Design",
+		Description: "Mockups",
+		Tags:        []string{"meeting", "remote"},
+		Billable:    true,
+	}, records[0])
+	assert.Equal(t, NoProject, records[1].Project)
+	assert.False(t, records[1].Billable)
+	assert.Equal(t, 90*time.Minute, records[1].EndTime.Sub(records[1].StartTime))
+	assert.Equal(t, 1, len(recordErrors))
+	assert.Equal(t, 4, recordErrors[0].Line)
+
+	_, _, err = ReadTogglCsv(strings.NewReader("Project,Description\n"), FileOptions{})
+	assert.NotNil(t, err)
+}
+
+func Test_ReadTogglJson(t *testing.T) {
+	file := `{"total_count": 2, "data": [
+		{"id": 1001, "description": "Mockups", "start": "2023-09-04T08:00:00+02:00", "end": "2023-09-04T10:30:00+02:00",
+		 "dur": 9000000, "client": "ACME", "project": "Website", "task": null, "billable": 125.5, "is_billable": true,
+		 "tags": ["meeting"]},
+		{"id": 1002, "description": "Running", "start": "2023-09-05T08:00:00+02:00", "end": null, "project": null}
+	]}`
+	records, recordErrors, err := ReadTogglJson(strings.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, []Record{{
+		Line:        1,
+		StartTime:   time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2023, 9, 4, 8, 30, 0, 0, time.UTC),
+		Client:      "ACME",
+		Project:     "Website",
+		Description: "Mockups",
+		Tags:        []string{"meeting"},
+		Billable:    true,
+		ExternalID:  "1001",
+	}}, records)
+	assert.Equal(t, 1, len(recordErrors))
+	assert.Equal(t, 2, recordErrors[0].Line)
+
+	records, _, err = ReadTogglJson(strings.NewReader(`[{"id": 7, "start": "2023-09-04T08:00:00Z", "end": "2023-09-04T09:00:00Z", "billable": true}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+	assert.True(t, records[0].Billable)
+	assert.Equal(t, NoProject, records[0].Project)
+
+	_, _, err = ReadTogglJson(strings.NewReader("Project,Description\n"))
+	assert.NotNil(t, err)
+}
+
+func Test_IsJson(t *testing.T) {
+	assert.True(t, IsJson([]byte("  {\"data\": []}")))
+	assert.True(t, IsJson([]byte("\ufeff[]")))
+	assert.False(t, IsJson([]byte("Project,Description\n")))
+	assert.False(t, IsJson(nil))
+}
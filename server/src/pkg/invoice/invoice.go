@@ -0,0 +1,148 @@
+package invoice
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// Rates are the hourly rates in cents. The rate of a project overrides the default rate.
+type Rates struct {
+	Default  int64
+	Projects map[uuid.UUID]int64
+}
+
+// Rounding rounds the billed time of each line to a multiple of the increment, no rounding if it is 0.
+type Rounding struct {
+	Minutes int
+	Mode    string
+}
+
+// IsValidRoundingMode returns true for the known modes, an empty mode is rounding to the nearest increment.
+func IsValidRoundingMode(mode string) bool {
+	switch mode {
+	case "", model.RoundingModeNearest, model.RoundingModeUp, model.RoundingModeDown:
+		return true
+	}
+	return false
+}
+
+type lineKey struct {
+	projectId uuid.UUID
+	task      string
+	day       time.Time
+}
+
+// NewLines groups the time entries by project, task and day (UTC) and calculates the billed time and the amount of
+// each line. The task of an entry is the task of the import or else its description. Running entries are ignored.
+// The lines are sorted by day, project name and task, the positions start with 1.
+func NewLines(entries []model.BillableTimeEntry, rates Rates, rounding Rounding) []model.InvoiceLine {
+	linesByKey := make(map[lineKey]*model.InvoiceLine)
+	var lines []*model.InvoiceLine
+	trackedByKey := make(map[lineKey]time.Duration)
+	for _, entry := range entries {
+		duration := entry.TimeEntry.Duration()
+		if duration <= 0 {
+			continue
+		}
+		task := entry.Task
+		if task == "" {
+			task = entry.TimeEntry.Description
+		}
+		startTime := entry.TimeEntry.StartTime.UTC()
+		key := lineKey{
+			projectId: entry.TimeEntry.ProjectId,
+			task:      task,
+			day:       time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, time.UTC),
+		}
+		line, ok := linesByKey[key]
+		if !ok {
+			line = &model.InvoiceLine{
+				ProjectID:   key.projectId,
+				ProjectName: entry.TimeEntry.Project.Name,
+				Task:        task,
+				Day:         key.day,
+				HourlyRate:  rates.rate(key.projectId),
+			}
+			linesByKey[key] = line
+			lines = append(lines, line)
+		}
+		trackedByKey[key] += duration
+		line.TimeEntryCount++
+	}
+	for key, line := range linesByKey {
+		tracked := trackedByKey[key]
+		line.TrackedMinutes = int64(tracked.Round(time.Minute) / time.Minute)
+		line.BilledMinutes = RoundMinutes(tracked, rounding)
+		line.Amount = Amount(line.BilledMinutes, line.HourlyRate)
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].Day.Equal(lines[j].Day) {
+			return lines[i].Day.Before(lines[j].Day)
+		}
+		if lines[i].ProjectName != lines[j].ProjectName {
+			return lines[i].ProjectName < lines[j].ProjectName
+		}
+		return lines[i].Task < lines[j].Task
+	})
+	result := make([]model.InvoiceLine, len(lines))
+	for i, line := range lines {
+		line.Position = i + 1
+		result[i] = *line
+	}
+	return result
+}
+
+// RoundMinutes rounds the duration to whole minutes and then to the increment of the rounding.
+func RoundMinutes(duration time.Duration, rounding Rounding) int64 {
+	minutes := int64(duration.Round(time.Minute) / time.Minute)
+	if rounding.Minutes <= 0 {
+		return minutes
+	}
+	increment := int64(rounding.Minutes)
+	remainder := minutes % increment
+	if remainder == 0 {
+		return minutes
+	}
+	switch rounding.Mode {
+	case model.RoundingModeUp:
+		return minutes - remainder + increment
+	case model.RoundingModeDown:
+		return minutes - remainder
+	default:
+		if 2*remainder >= increment {
+			return minutes - remainder + increment
+		}
+		return minutes - remainder
+	}
+}
+
+// Amount returns the amount in cents of the minutes at the hourly rate in cents, half cents are rounded up.
+func Amount(minutes int64, hourlyRate int64) int64 {
+	return (minutes*hourlyRate*2 + 60) / 120
+}
+
+// Totals returns the sum of the billed minutes and of the amounts of the lines.
+func Totals(lines []model.InvoiceLine) (int64, int64) {
+	var minutes, amount int64
+	for _, line := range lines {
+		minutes += line.BilledMinutes
+		amount += line.Amount
+	}
+	return minutes, amount
+}
+
+// FormatNumber returns the invoice number of the sequence number in the year, e.g. 2023-0042.
+func FormatNumber(year int, sequence int) string {
+	return fmt.Sprintf("%d-%04d", year, sequence)
+}
+
+func (rates Rates) rate(projectId uuid.UUID) int64 {
+	if rate, ok := rates.Projects[projectId]; ok {
+		return rate
+	}
+	return rates.Default
+}
@@ -0,0 +1,84 @@
+package invoice
+
+import (
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func billableEntry(project model.Project, description string, startTime time.Time, duration time.Duration) model.BillableTimeEntry {
+	return model.BillableTimeEntry{TimeEntry: model.TimeEntry{
+		ProjectId:   project.ID,
+		Project:     project,
+		Description: description,
+		StartTime:   startTime,
+		EndTime:     startTime.Add(duration),
+	}}
+}
+
+func Test_NewLines(t *testing.T) {
+	website := model.Project{ID: uuid.Must(uuid.NewV4()), Name: "Website"}
+	app := model.Project{ID: uuid.Must(uuid.NewV4()), Name: "App"}
+	day1 := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	imported := billableEntry(website, "Call with the client", day1.Add(5*time.Hour), 20*time.Minute)
+	imported.Task = "Design"
+	running := billableEntry(app, "Running", day2, 0)
+	running.TimeEntry.EndTime = time.Time{}
+	entries := []model.BillableTimeEntry{
+		billableEntry(website, "Design", day1, 50*time.Minute),
+		billableEntry(app, "Backend", day1.Add(time.Hour), 100*time.Minute),
+		imported,
+		billableEntry(website, "Design", day2, 10*time.Minute),
+		running,
+	}
+	rates := Rates{Default: 8000, Projects: map[uuid.UUID]int64{app.ID: 9000}}
+
+	lines := NewLines(entries, rates, Rounding{Minutes: 15, Mode: model.RoundingModeUp})
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, 1, lines[0].Position)
+	assert.Equal(t, "App", lines[0].ProjectName)
+	assert.Equal(t, "Backend", lines[0].Task)
+	assert.Equal(t, int64(100), lines[0].TrackedMinutes)
+	assert.Equal(t, int64(105), lines[0].BilledMinutes)
+	assert.Equal(t, int64(9000), lines[0].HourlyRate)
+	assert.Equal(t, int64(15750), lines[0].Amount)
+	// The imported entry is grouped by its task:
+	assert.Equal(t, "Website", lines[1].ProjectName)
+	assert.Equal(t, "Design", lines[1].Task)
+	assert.Equal(t, 2, lines[1].TimeEntryCount)
+	assert.Equal(t, int64(70), lines[1].TrackedMinutes)
+	assert.Equal(t, int64(75), lines[1].BilledMinutes)
+	assert.Equal(t, int64(10000), lines[1].Amount)
+	assert.Equal(t, day2.Truncate(24*time.Hour), lines[2].Day)
+	assert.Equal(t, int64(15), lines[2].BilledMinutes)
+	assert.Equal(t, 3, lines[2].Position)
+
+	minutes, amount := Totals(lines)
+	assert.Equal(t, int64(195), minutes)
+	assert.Equal(t, int64(15750+10000+2000), amount)
+}
+
+func Test_RoundMinutes(t *testing.T) {
+	assert.Equal(t, int64(37), RoundMinutes(37*time.Minute+20*time.Second, Rounding{}))
+	assert.Equal(t, int64(45), RoundMinutes(37*time.Minute, Rounding{Minutes: 15, Mode: model.RoundingModeUp}))
+	assert.Equal(t, int64(30), RoundMinutes(37*time.Minute, Rounding{Minutes: 15, Mode: model.RoundingModeDown}))
+	assert.Equal(t, int64(30), RoundMinutes(37*time.Minute, Rounding{Minutes: 15}))
+	assert.Equal(t, int64(45), RoundMinutes(38*time.Minute, Rounding{Minutes: 15, Mode: model.RoundingModeNearest}))
+	assert.Equal(t, int64(30), RoundMinutes(30*time.Minute, Rounding{Minutes: 15, Mode: model.RoundingModeUp}))
+}
+
+func Test_Amount(t *testing.T) {
+	assert.Equal(t, int64(8000), Amount(60, 8000))
+	assert.Equal(t, int64(2000), Amount(15, 8000))
+	// 7 minutes at 1.00 are 11.67 cents
+	assert.Equal(t, int64(12), Amount(7, 100))
+	assert.Equal(t, int64(0), Amount(0, 8000))
+}
+
+func Test_FormatNumber(t *testing.T) {
+	assert.Equal(t, "2023-0042", FormatNumber(2023, 42))
+}
@@ -0,0 +1,71 @@
+package job
+
+import (
+	"context"
+	"time"
+	"timeasy-server/pkg/event"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/golang/glog"
+)
+
+const RunningTimerJobName = "running-timers"
+const OutboxCleanupJobName = "outbox-cleanup"
+const WebhookDeliveryJobName = "webhook-deliveries"
+const DataExportJobName = "data-exports"
+const ErasureJobName = "erasures"
+
+// NewRunningTimerJob stops or flags forgotten running time entries.
+func NewRunningTimerJob(runningTimerUsecase usecase.RunningTimerUsecase) JobFunc {
+	return func(ctx context.Context, scheduledAt time.Time) error {
+		notices, err := runningTimerUsecase.CheckRunningTimers(scheduledAt)
+		if len(notices) > 0 {
+			glog.Infof("handled %v forgotten running time entries", len(notices))
+		}
+		return err
+	}
+}
+
+// NewOutboxCleanupJob removes the domain events that were published before the retention time.
+func NewOutboxCleanupJob(eventBus event.EventBus, retention time.Duration) JobFunc {
+	return func(ctx context.Context, scheduledAt time.Time) error {
+		deleted, err := eventBus.DeletePublishedEvents(scheduledAt.Add(-retention))
+		if deleted > 0 {
+			glog.Infof("removed %v published domain events", deleted)
+		}
+		return err
+	}
+}
+
+// NewWebhookDeliveryJob sends the enqueued webhook deliveries and retries the failed ones that are due.
+func NewWebhookDeliveryJob(webhookUsecase usecase.WebhookUsecase) JobFunc {
+	return func(ctx context.Context, scheduledAt time.Time) error {
+		delivered, err := webhookUsecase.DeliverDueWebhooks(scheduledAt)
+		if delivered > 0 {
+			glog.Infof("delivered %v webhooks", delivered)
+		}
+		return err
+	}
+}
+
+// NewDataExportJob generates the requested personal data exports and removes the expired ones.
+func NewDataExportJob(dataExportUsecase usecase.DataExportUsecase) JobFunc {
+	return func(ctx context.Context, scheduledAt time.Time) error {
+		generated, err := dataExportUsecase.ProcessDataExports(scheduledAt)
+		if generated > 0 {
+			glog.Infof("generated %v personal data exports", generated)
+		}
+		return err
+	}
+}
+
+// NewErasureJob erases the data of the users whose confirmed erasure requests are due.
+func NewErasureJob(erasureUsecase usecase.ErasureUsecase) JobFunc {
+	return func(ctx context.Context, scheduledAt time.Time) error {
+		executed, err := erasureUsecase.ExecuteDueErasures(scheduledAt)
+		if executed > 0 {
+			glog.Infof("erased the personal data of %v users", executed)
+		}
+		return err
+	}
+}
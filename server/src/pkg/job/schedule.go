@@ -0,0 +1,164 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule calculates when a job runs next.
+type Schedule interface {
+	// Next returns the first point in time after t the job has to run.
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a cron expression with the five fields minute, hour, day of month, month and day of week.
+// The fields support "*", values, ranges ("1-5"), lists ("1,15") and steps ("*/15"). Additionally the descriptors
+// "@hourly", "@daily", "@weekly", "@monthly" and "@every <duration>" are supported. Schedules are evaluated in UTC.
+func ParseSchedule(expression string) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	switch expression {
+	case "@hourly":
+		expression = "0 * * * *"
+	case "@daily", "@midnight":
+		expression = "0 0 * * *"
+	case "@weekly":
+		expression = "0 0 * * 0"
+	case "@monthly":
+		expression = "0 0 1 * *"
+	}
+	if strings.HasPrefix(expression, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expression, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval in schedule %q: %w", expression, err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("the interval of schedule %q must be at least one second", expression)
+		}
+		return &intervalSchedule{interval: interval}, nil
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have five fields", expression)
+	}
+	var schedule cronSchedule
+	var err error
+	if schedule.minutes, _, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if schedule.hours, _, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfMonth, schedule.anyDayOfMonth, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if schedule.months, _, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if schedule.daysOfWeek, schedule.anyDayOfWeek, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	// 7 is sunday as well:
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+	return &schedule, nil
+}
+
+// intervalSchedule runs at multiples of the interval, so that all server instances calculate the same points in time.
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (schedule *intervalSchedule) Next(t time.Time) time.Time {
+	return t.UTC().Truncate(schedule.interval).Add(schedule.interval)
+}
+
+type cronSchedule struct {
+	minutes       uint64
+	hours         uint64
+	daysOfMonth   uint64
+	months        uint64
+	daysOfWeek    uint64
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+func (schedule *cronSchedule) Next(t time.Time) time.Time {
+	next := t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Every valid expression matches at least once within a few years (e.g. february 29th):
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if schedule.months&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !schedule.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if schedule.hours&(1<<uint(next.Hour())) == 0 {
+			next = next.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if schedule.minutes&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// matchesDay follows the cron convention: if day of month and day of week are both restricted, either must match.
+func (schedule *cronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonthMatches := schedule.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeekMatches := schedule.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if schedule.anyDayOfMonth || schedule.anyDayOfWeek {
+		return dayOfMonthMatches && dayOfWeekMatches
+	}
+	return dayOfMonthMatches || dayOfWeekMatches
+}
+
+// parseField returns the allowed values of a cron field as bit set and whether the field is "*".
+func parseField(field string, min int, max int) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart := part
+		step := 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[index+1:])
+			if err != nil || step < 1 {
+				return 0, false, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart = part[:index]
+		}
+		start, end := min, max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, false, fmt.Errorf("invalid value in %q", part)
+			}
+			end = start
+			if len(bounds) == 2 {
+				end, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, false, fmt.Errorf("invalid range in %q", part)
+				}
+			} else if step > 1 {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, false, fmt.Errorf("%q is out of the range %v-%v", part, min, max)
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, field == "*", nil
+}
@@ -0,0 +1,60 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseScheduleEveryFifteenMinutes(t *testing.T) {
+	schedule, err := ParseSchedule("*/15 * * * *")
+	assert.Nil(t, err)
+	next := schedule.Next(time.Date(2023, 9, 4, 8, 7, 30, 0, time.UTC))
+	assert.Equal(t, time.Date(2023, 9, 4, 8, 15, 0, 0, time.UTC), next)
+	next = schedule.Next(next)
+	assert.Equal(t, time.Date(2023, 9, 4, 8, 30, 0, 0, time.UTC), next)
+}
+
+func Test_ParseScheduleWorkdaysAtNight(t *testing.T) {
+	schedule, err := ParseSchedule("30 2 * * 1-5")
+	assert.Nil(t, err)
+	// Friday evening, the next run is on monday:
+	next := schedule.Next(time.Date(2023, 9, 8, 20, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2023, 9, 11, 2, 30, 0, 0, time.UTC), next)
+}
+
+func Test_ParseScheduleDayOfMonthOrDayOfWeek(t *testing.T) {
+	// On the first of the month and on sundays:
+	schedule, err := ParseSchedule("0 0 1 * 7")
+	assert.Nil(t, err)
+	next := schedule.Next(time.Date(2023, 9, 28, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), next)
+	next = schedule.Next(next)
+	assert.Equal(t, time.Date(2023, 10, 8, 0, 0, 0, 0, time.UTC), next)
+}
+
+func Test_ParseScheduleLeapDay(t *testing.T) {
+	schedule, err := ParseSchedule("0 12 29 2 *")
+	assert.Nil(t, err)
+	next := schedule.Next(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), next)
+}
+
+func Test_ParseScheduleDescriptors(t *testing.T) {
+	schedule, err := ParseSchedule("@daily")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 9, 5, 0, 0, 0, 0, time.UTC), schedule.Next(time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)))
+
+	schedule, err = ParseSchedule("@every 15m")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 9, 4, 8, 15, 0, 0, time.UTC), schedule.Next(time.Date(2023, 9, 4, 8, 7, 30, 0, time.UTC)))
+}
+
+func Test_ParseScheduleFailsWithInvalidExpressions(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *",
+		"a * * * *", "@every 10ms", "@every often"} {
+		_, err := ParseSchedule(expression)
+		assert.NotNil(t, err, expression)
+	}
+}
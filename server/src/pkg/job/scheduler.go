@@ -0,0 +1,224 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/golang/glog"
+)
+
+// JobFunc is the work of a job. The context is cancelled when the server shuts down.
+type JobFunc func(ctx context.Context, scheduledAt time.Time) error
+
+type JobInfo struct {
+	Name     string
+	Schedule string
+	NextRun  time.Time
+}
+
+// Scheduler runs the registered jobs according to their schedules. If several server instances share the database
+// every scheduled point in time of a job is run by only one of them.
+type Scheduler interface {
+	Register(name string, schedule string, job JobFunc) error
+	GetJobs() []JobInfo
+	GetJobRuns(name string, limit int) ([]model.JobRun, error)
+	Start()
+	// Stop cancels the context of the running jobs and waits until they are finished or the context expires.
+	Stop(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	name       string
+	expression string
+	schedule   Schedule
+	run        JobFunc
+	nextRun    time.Time
+}
+
+type scheduler struct {
+	repo      repository.JobRunRepository
+	instance  string
+	jobs      map[string]*scheduledJob
+	mutex     sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	waitGroup sync.WaitGroup
+	started   bool
+}
+
+func NewScheduler(repo repository.JobRunRepository) Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return &scheduler{
+		repo:     repo,
+		instance: fmt.Sprintf("%v-%v", hostname, os.Getpid()),
+		jobs:     make(map[string]*scheduledJob),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register adds a job with a cron expression (see ParseSchedule). Jobs must be registered before the scheduler is
+// started.
+func (scheduler *scheduler) Register(name string, expression string, job JobFunc) error {
+	schedule, err := ParseSchedule(expression)
+	if err != nil {
+		return err
+	}
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if scheduler.started {
+		return fmt.Errorf("job %v cannot be registered after the scheduler was started", name)
+	}
+	if _, exists := scheduler.jobs[name]; exists {
+		return fmt.Errorf("a job with the name %v is already registered", name)
+	}
+	scheduler.jobs[name] = &scheduledJob{
+		name:       name,
+		expression: expression,
+		schedule:   schedule,
+		run:        job,
+	}
+	return nil
+}
+
+func (scheduler *scheduler) GetJobs() []JobInfo {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	var jobs []JobInfo
+	for _, job := range scheduler.jobs {
+		jobs = append(jobs, JobInfo{
+			Name:     job.name,
+			Schedule: job.expression,
+			NextRun:  job.nextRun,
+		})
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs
+}
+
+func (scheduler *scheduler) GetJobRuns(name string, limit int) ([]model.JobRun, error) {
+	return scheduler.repo.GetJobRuns(name, limit)
+}
+
+func (scheduler *scheduler) Start() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if scheduler.started {
+		return
+	}
+	scheduler.started = true
+	for _, job := range scheduler.jobs {
+		scheduler.waitGroup.Add(1)
+		go scheduler.loop(job)
+	}
+}
+
+func (scheduler *scheduler) Stop(ctx context.Context) error {
+	scheduler.cancel()
+	done := make(chan bool)
+	go func() {
+		scheduler.waitGroup.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (scheduler *scheduler) loop(job *scheduledJob) {
+	defer scheduler.waitGroup.Done()
+	var lastRun time.Time
+	for {
+		now := time.Now().UTC()
+		if now.Before(lastRun) {
+			now = lastRun
+		}
+		nextRun := job.schedule.Next(now)
+		if nextRun.IsZero() {
+			glog.Errorf("job %v has no further runs", job.name)
+			return
+		}
+		scheduler.mutex.Lock()
+		job.nextRun = nextRun
+		scheduler.mutex.Unlock()
+
+		timer := time.NewTimer(time.Until(nextRun))
+		select {
+		case <-scheduler.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			scheduler.runJob(job, nextRun)
+			lastRun = nextRun
+		}
+	}
+}
+
+// runJob runs the job for the scheduled point in time unless another instance is running it or has already run it.
+func (scheduler *scheduler) runJob(job *scheduledJob, scheduledAt time.Time) {
+	unlock, locked, err := scheduler.repo.TryLock(job.name)
+	if err != nil {
+		glog.Errorf("locking job %v failed: %v", job.name, err)
+		return
+	}
+	if !locked {
+		glog.V(1).Infof("job %v is running on another instance", job.name)
+		return
+	}
+	defer unlock()
+	if _, err := scheduler.repo.GetJobRun(job.name, scheduledAt); err == nil {
+		glog.V(1).Infof("job %v has already run for %v", job.name, scheduledAt)
+		return
+	}
+
+	jobRun := model.JobRun{
+		Name:        job.name,
+		ScheduledAt: scheduledAt,
+		Instance:    scheduler.instance,
+		StartedAt:   time.Now().UTC(),
+		Status:      model.JobRunStatusRunning,
+	}
+	err = scheduler.repo.AddJobRun(&jobRun)
+	if err != nil {
+		glog.Errorf("recording the run of job %v failed: %v", job.name, err)
+		return
+	}
+	err = scheduler.execute(job, scheduledAt)
+	finishedAt := time.Now().UTC()
+	jobRun.FinishedAt = &finishedAt
+	jobRun.Status = model.JobRunStatusSucceeded
+	if err != nil {
+		glog.Errorf("job %v failed: %v", job.name, err)
+		jobRun.Status = model.JobRunStatusFailed
+		jobRun.Error = err.Error()
+	}
+	err = scheduler.repo.UpdateJobRun(&jobRun)
+	if err != nil {
+		glog.Errorf("recording the result of job %v failed: %v", job.name, err)
+	}
+}
+
+// execute runs the job and turns a panic into an error, so that a broken job does not stop the server.
+func (scheduler *scheduler) execute(job *scheduledJob, scheduledAt time.Time) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job %v panicked: %v", job.name, recovered)
+		}
+	}()
+	return job.run(scheduler.ctx, scheduledAt)
+}
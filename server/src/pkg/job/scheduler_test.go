@@ -0,0 +1,159 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	log.Println("Testmain")
+
+	pool, resource := test.SetupDatabase()
+	code := m.Run()
+	test.TeardownDatabase(pool, resource)
+
+	os.Exit(code)
+}
+
+func newTestScheduler(t *testing.T) *scheduler {
+	return NewScheduler(database.NewGormJobRunRepository(test.DB)).(*scheduler)
+}
+
+func Test_scheduler_RunJobOnlyOncePerScheduledTime(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	scheduler := newTestScheduler(t)
+	runs := 0
+	err := scheduler.Register("counter", "@hourly", func(ctx context.Context, scheduledAt time.Time) error {
+		runs++
+		return nil
+	})
+	assert.Nil(t, err)
+
+	scheduledAt := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	scheduler.runJob(scheduler.jobs["counter"], scheduledAt)
+	// A second instance comes too late:
+	newTestScheduler(t).runJob(scheduler.jobs["counter"], scheduledAt)
+	scheduler.runJob(scheduler.jobs["counter"], scheduledAt.Add(time.Hour))
+	assert.Equal(t, 2, runs)
+
+	jobRuns, err := scheduler.GetJobRuns("counter", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(jobRuns))
+	assert.True(t, scheduledAt.Add(time.Hour).Equal(jobRuns[0].ScheduledAt))
+	assert.Equal(t, model.JobRunStatusSucceeded, jobRuns[0].Status)
+	assert.NotNil(t, jobRuns[0].FinishedAt)
+	assert.Equal(t, scheduler.instance, jobRuns[0].Instance)
+}
+
+func Test_scheduler_RunJobIsSkippedWhileLockedByAnotherInstance(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	scheduler := newTestScheduler(t)
+	runs := 0
+	err := scheduler.Register("counter", "@hourly", func(ctx context.Context, scheduledAt time.Time) error {
+		runs++
+		return nil
+	})
+	assert.Nil(t, err)
+
+	unlock, locked, err := scheduler.repo.TryLock("counter")
+	assert.Nil(t, err)
+	assert.True(t, locked)
+	scheduler.runJob(scheduler.jobs["counter"], time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, 0, runs)
+
+	unlock()
+	scheduler.runJob(scheduler.jobs["counter"], time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, 1, runs)
+}
+
+func Test_scheduler_FailingJobsAreRecorded(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	scheduler := newTestScheduler(t)
+	err := scheduler.Register("failing", "@daily", func(ctx context.Context, scheduledAt time.Time) error {
+		return errors.New("database is gone")
+	})
+	assert.Nil(t, err)
+	err = scheduler.Register("panicking", "@daily", func(ctx context.Context, scheduledAt time.Time) error {
+		panic("nil pointer")
+	})
+	assert.Nil(t, err)
+
+	scheduledAt := time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)
+	scheduler.runJob(scheduler.jobs["failing"], scheduledAt)
+	scheduler.runJob(scheduler.jobs["panicking"], scheduledAt)
+
+	jobRuns, err := scheduler.GetJobRuns("failing", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(jobRuns))
+	assert.Equal(t, model.JobRunStatusFailed, jobRuns[0].Status)
+	assert.Equal(t, "database is gone", jobRuns[0].Error)
+
+	jobRuns, err = scheduler.GetJobRuns("panicking", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(jobRuns))
+	assert.Equal(t, model.JobRunStatusFailed, jobRuns[0].Status)
+	assert.Contains(t, jobRuns[0].Error, "nil pointer")
+}
+
+func Test_scheduler_StopWaitsForRunningJobs(t *testing.T) {
+	teardownTest := test.SetupTest(t)
+	defer teardownTest(t)
+
+	scheduler := newTestScheduler(t)
+	started := make(chan bool, 1)
+	cancelled := false
+	err := scheduler.Register("blocking", "@every 1s", func(ctx context.Context, scheduledAt time.Time) error {
+		started <- true
+		<-ctx.Done()
+		cancelled = true
+		return ctx.Err()
+	})
+	assert.Nil(t, err)
+	scheduler.Start()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job was not started")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = scheduler.Stop(ctx)
+	assert.Nil(t, err)
+	assert.True(t, cancelled)
+
+	jobRuns, err := scheduler.GetJobRuns("blocking", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(jobRuns))
+	assert.Equal(t, model.JobRunStatusFailed, jobRuns[0].Status)
+}
+
+func Test_scheduler_RegisterFails(t *testing.T) {
+	scheduler := NewScheduler(nil)
+	job := func(ctx context.Context, scheduledAt time.Time) error { return nil }
+	err := scheduler.Register("invalid", "every day", job)
+	assert.NotNil(t, err)
+	err = scheduler.Register("daily", "@daily", job)
+	assert.Nil(t, err)
+	err = scheduler.Register("daily", "@hourly", job)
+	assert.NotNil(t, err)
+
+	jobs := scheduler.GetJobs()
+	assert.Equal(t, 1, len(jobs))
+	assert.Equal(t, "daily", jobs[0].Name)
+}
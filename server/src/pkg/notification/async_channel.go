@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/golang/glog"
+)
+
+// AsyncChannel sends the messages of a slow channel, e.g. email, in the background, so that the action that triggers
+// a notification does not wait for the mail server or the webhook of the user.
+type AsyncChannel interface {
+	Channel
+	// Stop sends the queued messages and waits until they are sent or the context expires.
+	Stop(ctx context.Context) error
+}
+
+type asyncMessage struct {
+	settings model.NotificationSettings
+	message  Message
+}
+
+type asyncChannel struct {
+	channel Channel
+	name    string
+	queue   chan asyncMessage
+	mutex   sync.RWMutex
+	done    chan bool
+	stopped bool
+}
+
+// NewAsyncChannel starts a worker that sends the messages of the channel one after another. Send fails if more than
+// queueSize messages are waiting, failing deliveries are logged.
+func NewAsyncChannel(name string, channel Channel, queueSize int) AsyncChannel {
+	asyncChannel := &asyncChannel{
+		channel: channel,
+		name:    name,
+		queue:   make(chan asyncMessage, queueSize),
+		done:    make(chan bool),
+	}
+	go asyncChannel.loop()
+	return asyncChannel
+}
+
+// Send queues a copy of the settings and the message, so the caller may change them afterwards.
+func (channel *asyncChannel) Send(settings *model.NotificationSettings, message *Message) error {
+	channel.mutex.RLock()
+	defer channel.mutex.RUnlock()
+	if channel.stopped {
+		return fmt.Errorf("the %v channel is stopped", channel.name)
+	}
+	select {
+	case channel.queue <- asyncMessage{settings: *settings, message: *message}:
+		return nil
+	default:
+		return fmt.Errorf("the queue of the %v channel is full", channel.name)
+	}
+}
+
+func (channel *asyncChannel) Stop(ctx context.Context) error {
+	channel.mutex.Lock()
+	if !channel.stopped {
+		channel.stopped = true
+		close(channel.queue)
+	}
+	channel.mutex.Unlock()
+	select {
+	case <-channel.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (channel *asyncChannel) loop() {
+	defer close(channel.done)
+	for queued := range channel.queue {
+		if err := channel.send(&queued); err != nil {
+			glog.Errorf("sending notification %v to user %v via %v failed: %v", queued.message.Type,
+				queued.message.UserID, channel.name, err)
+		}
+	}
+}
+
+// send turns a panic into an error, so that a broken channel does not stop the worker.
+func (channel *asyncChannel) send(queued *asyncMessage) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panicked: %v", recovered)
+		}
+	}()
+	return channel.channel.Send(&queued.settings, &queued.message)
+}
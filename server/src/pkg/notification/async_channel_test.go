@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingChannel struct {
+	release chan bool
+	mutex   sync.Mutex
+	sent    []string
+}
+
+func (channel *blockingChannel) Send(settings *model.NotificationSettings, message *Message) error {
+	<-channel.release
+	channel.mutex.Lock()
+	defer channel.mutex.Unlock()
+	channel.sent = append(channel.sent, message.Subject)
+	return nil
+}
+
+func Test_asyncChannel_SendDoesNotWaitForTheChannel(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	blocking := &blockingChannel{release: make(chan bool)}
+	channel := NewAsyncChannel("test", blocking, 1)
+	settings := model.NotificationSettings{UserID: userId}
+
+	// The worker takes the first message and blocks, the second one waits in the queue:
+	err = channel.Send(&settings, &Message{UserID: userId, Subject: "first"})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool { return len(channel.(*asyncChannel).queue) == 0 }, time.Second, time.Millisecond)
+	message := Message{UserID: userId, Subject: "second"}
+	err = channel.Send(&settings, &message)
+	assert.Nil(t, err)
+	message.Subject = "changed"
+	err = channel.Send(&settings, &Message{UserID: userId, Subject: "third"})
+	assert.NotNil(t, err)
+
+	close(blocking.release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = channel.Stop(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first", "second"}, blocking.sent)
+
+	err = channel.Send(&settings, &Message{UserID: userId, Subject: "fourth"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+// Message is a rendered notification that is delivered through the channels the user has enabled.
+type Message struct {
+	UserID    uuid.UUID
+	Type      string
+	Subject   string
+	Body      string
+	Data      map[string]string
+	CreatedAt time.Time
+}
+
+// Channel delivers messages to a user, e.g. by email. The settings contain the address of the user for the channel.
+type Channel interface {
+	Send(settings *model.NotificationSettings, message *Message) error
+}
@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+)
+
+type inboxChannel struct {
+	repo repository.NotificationRepository
+}
+
+// NewInboxChannel creates a channel that stores the messages in the in-app inbox of the user.
+func NewInboxChannel(repo repository.NotificationRepository) Channel {
+	return &inboxChannel{
+		repo: repo,
+	}
+}
+
+func (channel *inboxChannel) Send(settings *model.NotificationSettings, message *Message) error {
+	notification := model.Notification{
+		UserID:  message.UserID,
+		Type:    message.Type,
+		Subject: message.Subject,
+		Body:    message.Body,
+	}
+	return channel.repo.AddNotification(&notification)
+}
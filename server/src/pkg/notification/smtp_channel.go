@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"timeasy-server/pkg/domain/model"
+)
+
+type smtpChannel struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewSmtpChannel creates a channel that sends the messages as plain text emails. Without username no authentication
+// is used.
+func NewSmtpChannel(host string, port int, username string, password string, from string) Channel {
+	return &smtpChannel{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (channel *smtpChannel) Send(settings *model.NotificationSettings, message *Message) error {
+	if settings.Email == "" {
+		return fmt.Errorf("user %v has no email address", settings.UserID)
+	}
+	var auth smtp.Auth
+	if channel.username != "" {
+		auth = smtp.PlainAuth("", channel.username, channel.password, channel.host)
+	}
+	address := net.JoinHostPort(channel.host, strconv.Itoa(channel.port))
+	return smtp.SendMail(address, auth, channel.from, []string{settings.Email}, channel.createMail(settings.Email, message))
+}
+
+func (channel *smtpChannel) createMail(to string, message *Message) []byte {
+	var mail strings.Builder
+	mail.WriteString(fmt.Sprintf("From: %v\r\n", channel.from))
+	mail.WriteString(fmt.Sprintf("To: %v\r\n", to))
+	mail.WriteString(fmt.Sprintf("Subject: %v\r\n", removeLineBreaks(message.Subject)))
+	mail.WriteString(fmt.Sprintf("Date: %v\r\n", message.CreatedAt.Format("Mon, 02 Jan 2006 15:04:05 -0700")))
+	mail.WriteString("MIME-Version: 1.0\r\n")
+	mail.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	mail.WriteString("\r\n")
+	mail.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	mail.WriteString("\r\n")
+	return []byte(mail.String())
+}
+
+// removeLineBreaks prevents that the subject injects further headers.
+func removeLineBreaks(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
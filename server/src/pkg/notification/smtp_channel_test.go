@@ -0,0 +1,135 @@
+package notification
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSmtpServer accepts a single mail and records the envelope and the content.
+type fakeSmtpServer struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     string
+	done     chan bool
+}
+
+func startFakeSmtpServer(t *testing.T) *fakeSmtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := &fakeSmtpServer{
+		listener: listener,
+		done:     make(chan bool, 1),
+	}
+	go server.serve()
+	return server
+}
+
+func (server *fakeSmtpServer) port() int {
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (server *fakeSmtpServer) serve() {
+	defer func() { server.done <- true }()
+	connection, err := server.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer connection.Close()
+	reader := bufio.NewReader(connection)
+	reply := func(line string) {
+		connection.Write([]byte(line + "\r\n"))
+	}
+	reply("220 localhost fake smtp")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		upperCommand := strings.ToUpper(command)
+		switch {
+		case strings.HasPrefix(upperCommand, "EHLO"), strings.HasPrefix(upperCommand, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upperCommand, "MAIL FROM:"):
+			server.from = strings.Trim(command[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(upperCommand, "RCPT TO:"):
+			server.to = append(server.to, strings.Trim(command[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case upperCommand == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			server.data = data.String()
+			reply("250 OK")
+		case upperCommand == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func Test_smtpChannel_Send(t *testing.T) {
+	server := startFakeSmtpServer(t)
+	defer server.listener.Close()
+
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	channel := NewSmtpChannel("127.0.0.1", server.port(), "", "", "timeasy@example.com")
+	settings := model.NotificationSettings{
+		UserID: userId,
+		Email:  "user@example.com",
+	}
+	message := Message{
+		UserID:    userId,
+		Type:      model.NotificationTimesheetApproved,
+		Subject:   "Timesheet approved\r\nBcc: someone@example.com",
+		Body:      "Your timesheet was approved.\nHave a nice day.",
+		CreatedAt: time.Date(2023, 9, 11, 8, 0, 0, 0, time.UTC),
+	}
+	err = channel.Send(&settings, &message)
+	assert.Nil(t, err)
+	<-server.done
+
+	assert.Equal(t, "timeasy@example.com", server.from)
+	assert.Equal(t, []string{"user@example.com"}, server.to)
+	assert.Contains(t, server.data, "To: user@example.com\r\n")
+	assert.Contains(t, server.data, "Subject: Timesheet approved  Bcc: someone@example.com\r\n")
+	assert.Contains(t, server.data, "Your timesheet was approved.\r\nHave a nice day.\r\n")
+}
+
+func Test_smtpChannel_SendFailsWithoutEmailAddress(t *testing.T) {
+	channel := NewSmtpChannel("127.0.0.1", 25, "", "", "timeasy@example.com")
+	err := channel.Send(&model.NotificationSettings{}, &Message{Subject: "test"})
+	assert.NotNil(t, err)
+}
+
+func Test_smtpChannel_SendFailsIfServerIsNotReachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	channel := NewSmtpChannel("127.0.0.1", port, "", "", "timeasy@example.com")
+	err = channel.Send(&model.NotificationSettings{Email: "user@example.com"}, &Message{Subject: "test"})
+	assert.NotNil(t, err)
+}
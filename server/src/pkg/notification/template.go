@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"strings"
+	"text/template"
+	"timeasy-server/pkg/domain/model"
+)
+
+// DefaultTemplates are used for all notification types without a stored template.
+var DefaultTemplates = map[string]model.NotificationTemplate{
+	model.NotificationTimesheetSubmitted: {
+		Type:    model.NotificationTimesheetSubmitted,
+		Subject: "Timesheet {{.StartDate}} - {{.EndDate}} submitted",
+		Body:    "A timesheet for the period {{.StartDate}} - {{.EndDate}} was submitted to team {{.TeamName}} and waits for your approval.",
+	},
+	model.NotificationTimesheetApproved: {
+		Type:    model.NotificationTimesheetApproved,
+		Subject: "Timesheet {{.StartDate}} - {{.EndDate}} approved",
+		Body:    "Your timesheet for the period {{.StartDate}} - {{.EndDate}} was approved.",
+	},
+	model.NotificationTimesheetRejected: {
+		Type:    model.NotificationTimesheetRejected,
+		Subject: "Timesheet {{.StartDate}} - {{.EndDate}} rejected",
+		Body:    "Your timesheet for the period {{.StartDate}} - {{.EndDate}} was rejected.{{if .Comment}}\n\nComment: {{.Comment}}{{end}}",
+	},
+	model.NotificationAbsenceRequested: {
+		Type:    model.NotificationAbsenceRequested,
+		Subject: "Absence {{.StartDate}} - {{.EndDate}} requested",
+		Body:    "An absence ({{.AbsenceType}}) from {{.StartDate}} until {{.EndDate}} was requested in team {{.TeamName}} and waits for your approval.",
+	},
+	model.NotificationAbsenceApproved: {
+		Type:    model.NotificationAbsenceApproved,
+		Subject: "Absence {{.StartDate}} - {{.EndDate}} approved",
+		Body:    "Your absence ({{.AbsenceType}}) from {{.StartDate}} until {{.EndDate}} was approved.",
+	},
+	model.NotificationAbsenceRejected: {
+		Type:    model.NotificationAbsenceRejected,
+		Subject: "Absence {{.StartDate}} - {{.EndDate}} rejected",
+		Body:    "Your absence ({{.AbsenceType}}) from {{.StartDate}} until {{.EndDate}} was rejected.{{if .Comment}}\n\nComment: {{.Comment}}{{end}}",
+	},
+	model.NotificationAddedToTeam: {
+		Type:    model.NotificationAddedToTeam,
+		Subject: "Welcome to team {{.TeamName}}",
+		Body:    "You were added to team {{.TeamName}} with the roles {{.Roles}}.",
+	},
+	model.NotificationRunningTimerStopped: {
+		Type:    model.NotificationRunningTimerStopped,
+		Subject: "Your time entry was stopped",
+		Body:    "Your time entry started at {{.StartTime}} was still running and has been stopped at {{.EndTime}}. Please check it.",
+	},
+	model.NotificationRunningTimerFlagged: {
+		Type:    model.NotificationRunningTimerFlagged,
+		Subject: "Your time entry is still running",
+		Body:    "Your time entry started at {{.StartTime}} is still running. Please stop it or correct its end time.",
+	},
+	model.NotificationDataExportReady: {
+		Type:    model.NotificationDataExportReady,
+		Subject: "Your data export is ready",
+		Body:    "The export of the personal data of user {{.UserId}} can be downloaded at {{.DownloadPath}} until {{.ExpiresAt}}.",
+	},
+	model.NotificationErasureScheduled: {
+		Type:    model.NotificationErasureScheduled,
+		Subject: "Your data will be erased",
+		Body:    "The erasure of the personal data of user {{.UserId}} was confirmed. All data will be deleted permanently on {{.DueAt}}, until then the erasure can be cancelled.",
+	},
+}
+
+func IsKnownNotificationType(notificationType string) bool {
+	_, ok := DefaultTemplates[notificationType]
+	return ok
+}
+
+// CheckTemplate returns an error if the subject or the body of the template cannot be parsed.
+func CheckTemplate(notificationTemplate *model.NotificationTemplate) error {
+	_, err := template.New("subject").Parse(notificationTemplate.Subject)
+	if err != nil {
+		return err
+	}
+	_, err = template.New("body").Parse(notificationTemplate.Body)
+	return err
+}
+
+// Render fills the template with the data. Missing values are rendered as empty strings.
+func Render(notificationTemplate *model.NotificationTemplate, data map[string]string) (string, string, error) {
+	subject, err := renderText(notificationTemplate.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := renderText(notificationTemplate.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func renderText(text string, data map[string]string) (string, error) {
+	parsedTemplate, err := template.New("text").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var result strings.Builder
+	err = parsedTemplate.Execute(&result, data)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"testing"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenderDefaultTemplate(t *testing.T) {
+	template := DefaultTemplates[model.NotificationTimesheetRejected]
+	subject, body, err := Render(&template, map[string]string{
+		"StartDate": "2023-09-04",
+		"EndDate":   "2023-09-10",
+		"Comment":   "friday is missing",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Timesheet 2023-09-04 - 2023-09-10 rejected", subject)
+	assert.Equal(t, "Your timesheet for the period 2023-09-04 - 2023-09-10 was rejected.\n\nComment: friday is missing", body)
+}
+
+func Test_RenderWithMissingValues(t *testing.T) {
+	template := model.NotificationTemplate{
+		Subject: "Hello {{.Name}}",
+		Body:    "{{.Unknown}}done",
+	}
+	subject, body, err := Render(&template, map[string]string{"Name": "Jane"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello Jane", subject)
+	assert.Equal(t, "done", body)
+}
+
+func Test_CheckTemplateFailsWithInvalidSyntax(t *testing.T) {
+	err := CheckTemplate(&model.NotificationTemplate{Subject: "{{.Name", Body: "body"})
+	assert.NotNil(t, err)
+	err = CheckTemplate(&model.NotificationTemplate{Subject: "subject", Body: "{{if .Comment}}"})
+	assert.NotNil(t, err)
+}
+
+func Test_AllNotificationTypesHaveDefaultTemplates(t *testing.T) {
+	for notificationType, template := range DefaultTemplates {
+		assert.Equal(t, notificationType, template.Type)
+		assert.Nil(t, CheckTemplate(&template))
+	}
+}
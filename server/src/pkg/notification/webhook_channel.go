@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/webhook"
+
+	"github.com/gofrs/uuid"
+)
+
+type webhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel creates a channel that posts the messages as JSON to the webhook url of the user. The client
+// refuses private networks like the one of the webhook subscriptions, see webhook.NewHttpClient.
+func NewWebhookChannel(timeout time.Duration, allowPrivateNetworks bool) Channel {
+	return &webhookChannel{
+		client: webhook.NewHttpClient(timeout, allowPrivateNetworks),
+	}
+}
+
+type webhookPayload struct {
+	Type          string            `json:"type"`
+	UserId        uuid.UUID         `json:"userId"`
+	Subject       string            `json:"subject"`
+	Body          string            `json:"body"`
+	Data          map[string]string `json:"data,omitempty"`
+	CreatedAtUnix int64             `json:"createdAtUnix"`
+}
+
+func (channel *webhookChannel) Send(settings *model.NotificationSettings, message *Message) error {
+	if settings.WebhookURL == "" {
+		return fmt.Errorf("user %v has no webhook url", settings.UserID)
+	}
+	payload, err := json.Marshal(webhookPayload{
+		Type:          message.Type,
+		UserId:        message.UserID,
+		Subject:       message.Subject,
+		Body:          message.Body,
+		Data:          message.Data,
+		CreatedAtUnix: message.CreatedAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	response, err := channel.client.Post(settings.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("the webhook of user %v responded with status %v", settings.UserID, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_webhookChannel_Send(t *testing.T) {
+	var receivedPayload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		err := json.NewDecoder(r.Body).Decode(&receivedPayload)
+		assert.Nil(t, err)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	channel := NewWebhookChannel(time.Second, true)
+	message := Message{
+		UserID:    userId,
+		Type:      model.NotificationRunningTimerFlagged,
+		Subject:   "still running",
+		Body:      "please stop it",
+		Data:      map[string]string{"StartTime": "2023-09-11 08:00"},
+		CreatedAt: time.Date(2023, 9, 11, 20, 0, 0, 0, time.UTC),
+	}
+	err = channel.Send(&model.NotificationSettings{UserID: userId, WebhookURL: server.URL}, &message)
+	assert.Nil(t, err)
+	assert.Equal(t, model.NotificationRunningTimerFlagged, receivedPayload.Type)
+	assert.Equal(t, userId, receivedPayload.UserId)
+	assert.Equal(t, "please stop it", receivedPayload.Body)
+	assert.Equal(t, "2023-09-11 08:00", receivedPayload.Data["StartTime"])
+	assert.Equal(t, message.CreatedAt.Unix(), receivedPayload.CreatedAtUnix)
+}
+
+func Test_webhookChannel_SendFailsIfWebhookRespondsWithError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	channel := NewWebhookChannel(time.Second, true)
+	err := channel.Send(&model.NotificationSettings{WebhookURL: server.URL}, &Message{Subject: "test"})
+	assert.NotNil(t, err)
+}
+
+func Test_webhookChannel_SendRefusesPrivateNetworks(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	channel := NewWebhookChannel(time.Second, false)
+	err := channel.Send(&model.NotificationSettings{WebhookURL: server.URL}, &Message{Subject: "test"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, requests)
+}
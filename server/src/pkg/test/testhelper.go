@@ -62,6 +62,29 @@ func SetupDatabase() (*dockertest.Pool, *dockertest.Resource) {
 	DB.AutoMigrate(&model.TimeEntry{})
 	DB.AutoMigrate(&model.Team{})
 	DB.AutoMigrate(&model.UserTeamAssignment{})
+	DB.AutoMigrate(&model.WorkingTimeModel{})
+	DB.AutoMigrate(&model.Absence{})
+	DB.AutoMigrate(&model.HolidayCalendar{})
+	DB.AutoMigrate(&model.CustomHoliday{})
+	DB.AutoMigrate(&model.Timesheet{})
+	DB.AutoMigrate(&model.PeriodLockChange{})
+	DB.AutoMigrate(&model.OvertimeAccount{})
+	DB.AutoMigrate(&model.OvertimeCorrection{})
+	DB.AutoMigrate(&model.RunningTimerNotice{})
+	DB.AutoMigrate(&model.Notification{})
+	DB.AutoMigrate(&model.NotificationSettings{})
+	DB.AutoMigrate(&model.NotificationTemplate{})
+	DB.AutoMigrate(&model.JobRun{})
+	DB.AutoMigrate(&model.OutboxEvent{})
+	DB.AutoMigrate(&model.WebhookSubscription{})
+	DB.AutoMigrate(&model.WebhookDelivery{})
+	DB.AutoMigrate(&model.ReportTemplate{})
+	DB.AutoMigrate(&model.CalendarFeedToken{})
+	DB.AutoMigrate(&model.ImportedTimeEntry{})
+	DB.AutoMigrate(&model.DataExport{})
+	DB.AutoMigrate(&model.ErasureRequest{})
+	DB.AutoMigrate(&model.Invoice{})
+	DB.AutoMigrate(&model.InvoiceLine{})
 	return pool, resource
 }
 
@@ -85,7 +108,67 @@ func SetupTest(tb testing.TB) func(tb testing.TB) {
 }
 
 func deleteAllEntities(db *gorm.DB) error {
-	err := db.Exec("DELETE FROM time_entries")
+	err := db.Exec("DELETE FROM invoice_lines")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM invoices")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM erasure_requests")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM data_exports")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM imported_time_entries")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM calendar_feed_tokens")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM report_templates")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM webhook_deliveries")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM webhook_subscriptions")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM outbox_events")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM running_timer_notices")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM job_runs")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM notifications")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM notification_settings")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM notification_templates")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM time_entries")
 	if err.Error != nil {
 		return err.Error
 	}
@@ -93,6 +176,38 @@ func deleteAllEntities(db *gorm.DB) error {
 	if err.Error != nil {
 		return err.Error
 	}
+	err = db.Exec("DELETE FROM overtime_corrections")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM overtime_accounts")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM period_lock_changes")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM timesheets")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM absences")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM working_time_models")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM custom_holidays")
+	if err.Error != nil {
+		return err.Error
+	}
+	err = db.Exec("DELETE FROM holiday_calendars")
+	if err.Error != nil {
+		return err.Error
+	}
 	err = db.Exec("DELETE FROM user_team_assignments")
 	if err.Error != nil {
 		return err.Error
@@ -0,0 +1,361 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type AbsenceHandler interface {
+	GetAbsences(context *gin.Context)
+	GetAbsenceById(context *gin.Context)
+	GetAbsencesOfTeam(context *gin.Context)
+	RequestAbsence(context *gin.Context)
+	DeleteAbsence(context *gin.Context)
+	ApproveAbsence(context *gin.Context)
+	RejectAbsence(context *gin.Context)
+}
+
+type absenceHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.AbsenceUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewAbsenceHandler(tokenVerifier TokenVerifier, usecase usecase.AbsenceUsecase, teamUsecase usecase.TeamUsecase) AbsenceHandler {
+	return &absenceHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type absenceInputDto struct {
+	TeamId       uuid.UUID `json:"teamId" binding:"required"`
+	Type         string    `json:"type" binding:"required"`
+	StartDate    string    `json:"startDate" binding:"required"`
+	EndDate      string    `json:"endDate" binding:"required"`
+	HalfDayStart bool      `json:"halfDayStart"`
+	HalfDayEnd   bool      `json:"halfDayEnd"`
+	Comment      string    `json:"comment"`
+}
+
+type absenceDto struct {
+	Id         uuid.UUID
+	UserId     uuid.UUID
+	Status     string
+	ReviewedBy *uuid.UUID `json:",omitempty"`
+	absenceInputDto
+}
+
+type absenceReviewDto struct {
+	Comment string `json:"comment"`
+}
+
+func (handler *absenceHandler) GetAbsences(context *gin.Context) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	absences, err := handler.usecase.GetAbsencesOfUser(userId, from, to)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, convertAbsencesToDtos(absences))
+}
+
+func (handler *absenceHandler) GetAbsenceById(context *gin.Context) {
+	absence, token, ok := handler.getAbsence(context)
+	if !ok {
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if absence.UserID != userId && !handler.teamUsecase.IsUserManagerInTeam(userId, absence.TeamID) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			// We just say that the absence was not found:
+			context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("absence with id %v not found", absence.ID)})
+			return
+		}
+	}
+	context.JSON(http.StatusOK, createDtoFromAbsence(absence))
+}
+
+func (handler *absenceHandler) GetAbsencesOfTeam(context *gin.Context) {
+	teamId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.isManagerOfTeam(context, token, teamId) {
+		return
+	}
+	absences, err := handler.usecase.GetAbsencesOfTeam(teamId, from, to)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	status := context.Query("status")
+	var filteredAbsences []model.Absence
+	for _, absence := range absences {
+		if status == "" || absence.Status == status {
+			filteredAbsences = append(filteredAbsences, absence)
+		}
+	}
+	context.JSON(http.StatusOK, convertAbsencesToDtos(filteredAbsences))
+}
+
+func (handler *absenceHandler) RequestAbsence(context *gin.Context) {
+	var input absenceInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	absence := model.Absence{
+		UserID:       userId,
+		TeamID:       input.TeamId,
+		Type:         input.Type,
+		HalfDayStart: input.HalfDayStart,
+		HalfDayEnd:   input.HalfDayEnd,
+		Comment:      input.Comment,
+	}
+	absence.StartDate, err = time.Parse(dateFormat, input.StartDate)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the start date in the format YYYY-MM-DD"})
+		return
+	}
+	absence.EndDate, err = time.Parse(dateFormat, input.EndDate)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the end date in the format YYYY-MM-DD"})
+		return
+	}
+	err = handler.usecase.RequestAbsence(&absence)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"id": absence.ID})
+}
+
+func (handler *absenceHandler) DeleteAbsence(context *gin.Context) {
+	absence, token, ok := handler.getAbsence(context)
+	if !ok {
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// Users may withdraw their own requests. Once an absence is reviewed only team admins may delete it.
+	ownRequest := absence.UserID == userId && absence.Status == model.AbsenceStatusRequested
+	if !ownRequest && !handler.teamUsecase.IsUserAdminInTeam(userId, absence.TeamID) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to delete this absence"})
+			return
+		}
+	}
+	err = handler.usecase.DeleteAbsence(absence.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("absence %v deleted", absence.ID)})
+}
+
+func (handler *absenceHandler) ApproveAbsence(context *gin.Context) {
+	handler.reviewAbsence(context, true)
+}
+
+func (handler *absenceHandler) RejectAbsence(context *gin.Context) {
+	handler.reviewAbsence(context, false)
+}
+
+func (handler *absenceHandler) reviewAbsence(context *gin.Context, approve bool) {
+	var review absenceReviewDto
+	// The comment is optional, so the body may also be empty:
+	if context.Request.ContentLength > 0 {
+		if err := context.ShouldBindJSON(&review); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	absence, token, ok := handler.getAbsence(context)
+	if !ok {
+		return
+	}
+	if !handler.isManagerOfTeam(context, token, absence.TeamID) {
+		return
+	}
+	reviewerId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if approve {
+		absence, err = handler.usecase.ApproveAbsence(absence.ID, reviewerId)
+	} else {
+		absence, err = handler.usecase.RejectAbsence(absence.ID, reviewerId, review.Comment)
+	}
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, createDtoFromAbsence(absence))
+}
+
+// getAbsence reads the absence given in the path and verifies the token. If something is wrong the error response is
+// already written.
+func (handler *absenceHandler) getAbsence(context *gin.Context) (*model.Absence, AuthToken, bool) {
+	absenceId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	absence, err := handler.usecase.GetAbsenceById(absenceId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("absence with id %v not found", absenceId)})
+		return nil, nil, false
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return absence, token, true
+}
+
+// isManagerOfTeam checks if the authenticated user is an admin or manager of the team or a global admin. If not the
+// error response is already written.
+func (handler *absenceHandler) isManagerOfTeam(context *gin.Context, token AuthToken, teamId uuid.UUID) bool {
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if handler.teamUsecase.IsUserManagerInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to manage the absences of this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *absenceHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var entityExistsError *usecase.EntityExistsError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityExistsError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *absenceHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func convertAbsencesToDtos(absences []model.Absence) []absenceDto {
+	var dtos []absenceDto
+	for _, absence := range absences {
+		dtos = append(dtos, createDtoFromAbsence(&absence))
+	}
+	return dtos
+}
+
+func createDtoFromAbsence(absence *model.Absence) absenceDto {
+	dto := absenceDto{
+		Id:         absence.ID,
+		UserId:     absence.UserID,
+		Status:     absence.Status,
+		ReviewedBy: absence.ReviewedBy,
+	}
+	dto.TeamId = absence.TeamID
+	dto.Type = absence.Type
+	dto.StartDate = absence.StartDate.Format(dateFormat)
+	dto.EndDate = absence.EndDate.Format(dateFormat)
+	dto.HalfDayStart = absence.HalfDayStart
+	dto.HalfDayEnd = absence.HalfDayEnd
+	dto.Comment = absence.Comment
+	return dto
+}
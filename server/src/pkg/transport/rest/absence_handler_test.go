@@ -0,0 +1,188 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_absenceHandler_RequestAbsence(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"teamId\": \"%v\", \"type\": \"VACATION\", \"startDate\": \"2023-09-04\", \"endDate\": \"2023-09-08\"}", team.ID))
+	req, err := http.NewRequest("POST", "/api/v1/absences", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	absencesFromDb, err := handlerTest.AbsenceUsecase.GetAbsencesOfUser(userId, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(absencesFromDb))
+	assert.Equal(t, model.AbsenceStatusRequested, absencesFromDb[0].Status)
+}
+
+func Test_absenceHandler_RequestAbsenceFailsWithInvalidType(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"teamId\": \"%v\", \"type\": \"PARTY\", \"startDate\": \"2023-09-04\", \"endDate\": \"2023-09-08\"}", team.ID))
+	req, err := http.NewRequest("POST", "/api/v1/absences", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_absenceHandler_ApproveAbsence(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	absence := addAbsence(t, handlerTest, memberId, team)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/absences/%v/approve", absence.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var absenceFromService absenceDto
+	err = json.Unmarshal(w.Body.Bytes(), &absenceFromService)
+	assert.Nil(t, err)
+	assert.Equal(t, model.AbsenceStatusApproved, absenceFromService.Status)
+	assert.Equal(t, userId, *absenceFromService.ReviewedBy)
+}
+
+func Test_absenceHandler_ApproveAbsenceFailsIfUserIsNoManager(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	absence := addAbsence(t, handlerTest, userId, team)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/absences/%v/approve", absence.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	absenceFromDb, err := handlerTest.AbsenceUsecase.GetAbsenceById(absence.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.AbsenceStatusRequested, absenceFromDb.Status)
+}
+
+func Test_absenceHandler_GetAbsencesOfTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	addAbsence(t, handlerTest, memberId, team)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/absences?status=REQUESTED", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var absencesFromService []absenceDto
+	err = json.Unmarshal(w.Body.Bytes(), &absencesFromService)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(absencesFromService))
+	assert.Equal(t, memberId, absencesFromService[0].UserId)
+	assert.Equal(t, "2023-09-04", absencesFromService[0].StartDate)
+}
+
+func addAbsence(t *testing.T, handlerTest *HandlerTest, userId uuid.UUID, team model.Team) model.Absence {
+	absence := model.Absence{
+		UserID:    userId,
+		TeamID:    team.ID,
+		Type:      model.AbsenceTypeVacation,
+		StartDate: time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC),
+	}
+	err := handlerTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+	return absence
+}
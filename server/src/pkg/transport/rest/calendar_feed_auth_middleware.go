@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/glog"
+)
+
+// calendarFeedUserIdKey is the key of the user id in the gin context after a successful authentication
+const calendarFeedUserIdKey = "calendarFeedUserId"
+
+// calendarFeedAuthMiddleware authenticates calendar apps by the secret token in the path parameter "token", because
+// they cannot get a bearer token from Keycloak.
+type calendarFeedAuthMiddleware struct {
+	usecase usecase.CalendarFeedUsecase
+}
+
+func NewCalendarFeedAuthMiddleware(usecase usecase.CalendarFeedUsecase) AuthMiddleware {
+	return &calendarFeedAuthMiddleware{
+		usecase: usecase,
+	}
+}
+
+func (mw *calendarFeedAuthMiddleware) HandlerFunc() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userId, err := mw.usecase.GetUserIdOfCalendarFeedToken(c.Param("token"))
+		if err != nil {
+			glog.Errorf("error verifying calendar feed token: %v", err)
+			c.String(http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+		c.Set(calendarFeedUserIdKey, userId)
+		c.Next()
+	}
+}
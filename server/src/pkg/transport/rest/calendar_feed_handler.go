@@ -0,0 +1,175 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"timeasy-server/pkg/export"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// Rolling window of the calendar feed in days if no date range is given
+const (
+	defaultCalendarFeedDays = 90
+	maxCalendarFeedDays     = 366
+)
+
+type CalendarFeedHandler interface {
+	GetCalendarFeed(context *gin.Context)
+	GetCalendarFeedToken(context *gin.Context)
+	RegenerateCalendarFeedToken(context *gin.Context)
+	DeleteCalendarFeedToken(context *gin.Context)
+}
+
+type calendarFeedHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.CalendarFeedUsecase
+}
+
+func NewCalendarFeedHandler(tokenVerifier TokenVerifier, usecase usecase.CalendarFeedUsecase) CalendarFeedHandler {
+	return &calendarFeedHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+	}
+}
+
+type calendarFeedTokenDto struct {
+	// Token is only returned when it is generated
+	Token         string `json:",omitempty"`
+	Path          string `json:",omitempty"`
+	CreatedAtUnix int64
+}
+
+// GetCalendarFeed returns the time entries of the user as iCalendar. The user is authenticated by the secret token in
+// the path (see calendarFeedAuthMiddleware). The query parameters "from" and "to" (YYYY-MM-DD) select a fixed date
+// range, otherwise the entries of the last "days" days (default 90) are returned. "projectId" may be repeated.
+func (handler *calendarFeedHandler) GetCalendarFeed(context *gin.Context) {
+	userId, ok := context.MustGet(calendarFeedUserIdKey).(uuid.UUID)
+	if !ok {
+		context.String(http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if from.IsZero() && to.IsZero() {
+		days := defaultCalendarFeedDays
+		if daysParam := context.Query("days"); daysParam != "" {
+			days, err = strconv.Atoi(daysParam)
+			if err != nil || days < 1 || days > maxCalendarFeedDays {
+				context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be a number between 1 and %d", maxCalendarFeedDays)})
+				return
+			}
+		}
+		now := time.Now().UTC()
+		from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -days)
+	}
+	filter := usecase.ExportFilter{From: from, To: to}
+	for _, idString := range context.QueryArray("projectId") {
+		projectId, err := uuid.FromString(idString)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid id", idString)})
+			return
+		}
+		filter.ProjectIds = append(filter.ProjectIds, projectId)
+	}
+	rows, err := handler.usecase.GetCalendarFeedRows(userId, filter)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	var buffer bytes.Buffer
+	if err := export.WriteICalendar(&buffer, rows, "Timeasy", time.Now()); err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.Data(http.StatusOK, "text/calendar; charset=utf-8", buffer.Bytes())
+}
+
+// GetCalendarFeedToken returns when the calendar feed of the user was enabled. The token itself is not stored and
+// can only be regenerated.
+func (handler *calendarFeedHandler) GetCalendarFeedToken(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	feedToken, err := handler.usecase.GetCalendarFeedToken(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, calendarFeedTokenDto{CreatedAtUnix: feedToken.CreatedAt.Unix()})
+}
+
+// RegenerateCalendarFeedToken enables the calendar feed of the user or replaces its token. The returned path of the
+// feed contains the new token and has to be added to the calendar app again.
+func (handler *calendarFeedHandler) RegenerateCalendarFeedToken(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	token, err := handler.usecase.RegenerateCalendarFeedToken(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	feedToken, err := handler.usecase.GetCalendarFeedToken(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, calendarFeedTokenDto{
+		Token:         token,
+		Path:          fmt.Sprintf("/api/v1/calendarfeed/%v/timeentries.ics", token),
+		CreatedAtUnix: feedToken.CreatedAt.Unix(),
+	})
+}
+
+// DeleteCalendarFeedToken disables the calendar feed of the user.
+func (handler *calendarFeedHandler) DeleteCalendarFeedToken(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	err := handler.usecase.DeleteCalendarFeedToken(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (handler *calendarFeedHandler) getUserId(context *gin.Context) (uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	return userId, true
+}
+
+func (handler *calendarFeedHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
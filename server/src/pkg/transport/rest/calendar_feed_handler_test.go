@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_calendarFeedHandler_RegenerateCalendarFeedToken(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/calendarfeedtoken", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 404, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/calendarfeedtoken", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var feedToken calendarFeedTokenDto
+	err = json.Unmarshal(w.Body.Bytes(), &feedToken)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", feedToken.Token)
+	assert.Equal(t, fmt.Sprintf("/api/v1/calendarfeed/%v/timeentries.ics", feedToken.Token), feedToken.Path)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/calendarfeedtoken", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var storedToken calendarFeedTokenDto
+	err = json.Unmarshal(w.Body.Bytes(), &storedToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "", storedToken.Token)
+	assert.Equal(t, feedToken.CreatedAtUnix, storedToken.CreatedAtUnix)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("DELETE", "/api/v1/calendarfeedtoken", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", feedToken.Path, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+}
+
+func Test_calendarFeedHandler_GetCalendarFeed(t *testing.T) {
+	// The feed does not use the bearer token:
+	verifier := tokenVerifierMock{}
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	project := addProject(t, handlerTest, "project", userId)
+	otherProject := addProject(t, handlerTest, "other project", userId)
+	now := time.Now().UTC()
+	addTimeEntryWithDuration(t, handlerTest, userId, project, now.AddDate(0, 0, -2), time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, otherProject, now.AddDate(0, 0, -1), time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, now.AddDate(0, 0, -100), time.Hour)
+	feedToken, err := handlerTest.CalendarFeedUsecase.RegenerateCalendarFeedToken(userId)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/calendarfeed/%v/timeentries.ics", feedToken), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	// The default window of 90 days excludes the oldest entry:
+	assert.Equal(t, 2, strings.Count(w.Body.String(), "BEGIN:VEVENT"))
+
+	w = httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/calendarfeed/%v/timeentries.ics?days=120&projectId=%v", feedToken, project.ID)
+	req, err = http.NewRequest("GET", url, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, 2, strings.Count(w.Body.String(), "BEGIN:VEVENT"))
+	assert.Contains(t, w.Body.String(), "SUMMARY:project\r\n")
+	assert.NotContains(t, w.Body.String(), "SUMMARY:other project")
+
+	for _, query := range []string{"days=0", "days=1000", "projectId=1", "from=2023-13-01"} {
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/calendarfeed/%v/timeentries.ics?%v", feedToken, query), nil)
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, query)
+	}
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/calendarfeed/unknown/timeentries.ics", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code)
+}
+
+func Test_calendarFeedHandler_TokenIsNotLogged(t *testing.T) {
+	var logs bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &logs
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	verifier := tokenVerifierMock{}
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	feedToken, err := handlerTest.CalendarFeedUsecase.RegenerateCalendarFeedToken(userId)
+	assert.Nil(t, err)
+
+	for _, token := range []string{feedToken, "unknown-token"} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/calendarfeed/%v/timeentries.ics", token), nil)
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.NotContains(t, logs.String(), token)
+	}
+	assert.Contains(t, logs.String(), "/api/v1/calendarfeed/REDACTED/timeentries.ics")
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type ComplianceHandler interface {
+	GetViolations(context *gin.Context)
+	GetViolationsOfTeam(context *gin.Context)
+}
+
+type complianceHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.ComplianceUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewComplianceHandler(tokenVerifier TokenVerifier, usecase usecase.ComplianceUsecase, teamUsecase usecase.TeamUsecase) ComplianceHandler {
+	return &complianceHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+// complianceDayDto contains all violations of a user on a single day.
+type complianceDayDto struct {
+	UserId     uuid.UUID
+	Date       string
+	Violations []complianceViolationDto
+}
+
+type complianceViolationDto struct {
+	Type            string
+	ActualMinutes   int64
+	RequiredMinutes int64
+}
+
+// GetViolations returns the violations of the authenticated user.
+func (handler *complianceHandler) GetViolations(context *gin.Context) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	violations, err := handler.usecase.GetViolationsOfUser(userId, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, convertViolationsToDtos(violations))
+}
+
+// GetViolationsOfTeam returns the violations of all members of the team. Only admins of the team may see them.
+func (handler *complianceHandler) GetViolationsOfTeam(context *gin.Context) {
+	teamId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to see the compliance report of this team"})
+			return
+		}
+	}
+	violations, err := handler.usecase.GetViolationsOfTeam(teamId, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, convertViolationsToDtos(violations))
+}
+
+func (handler *complianceHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *complianceHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// convertViolationsToDtos groups the violations by user and day. The violations must be sorted by user and day.
+func convertViolationsToDtos(violations []model.ComplianceViolation) []complianceDayDto {
+	dtos := []complianceDayDto{}
+	for _, violation := range violations {
+		date := violation.Day.Format(dateFormat)
+		last := len(dtos) - 1
+		if last < 0 || dtos[last].UserId != violation.UserID || dtos[last].Date != date {
+			dtos = append(dtos, complianceDayDto{
+				UserId: violation.UserID,
+				Date:   date,
+			})
+			last++
+		}
+		dtos[last].Violations = append(dtos[last].Violations, complianceViolationDto{
+			Type:            violation.Type,
+			ActualMinutes:   int64(violation.Actual.Minutes()),
+			RequiredMinutes: int64(violation.Required.Minutes()),
+		})
+	}
+	return dtos
+}
@@ -0,0 +1,158 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_complianceHandler_GetViolations(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := addProject(t, handlerTest, "project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 7, 0, 0, 0, time.UTC), 11*time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 5, 7, 0, 0, 0, time.UTC), 5*time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/compliance?from=2023-09-01&to=2023-09-30", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var days []complianceDayDto
+	err = json.Unmarshal(w.Body.Bytes(), &days)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(days))
+	assert.Equal(t, "2023-09-04", days[0].Date)
+	assert.Equal(t, 2, len(days[0].Violations))
+	assert.Equal(t, model.ViolationMaxWorkingTimeExceeded, days[0].Violations[0].Type)
+	assert.Equal(t, int64(660), days[0].Violations[0].ActualMinutes)
+	assert.Equal(t, int64(600), days[0].Violations[0].RequiredMinutes)
+	assert.Equal(t, model.ViolationBreakTooShort, days[0].Violations[1].Type)
+}
+
+func Test_complianceHandler_GetViolationsOfTeamAsAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	project := addTeamProject(t, handlerTest, "project", userId, team)
+	addTimeEntryWithDuration(t, handlerTest, memberId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 7*time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/compliance", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var days []complianceDayDto
+	err = json.Unmarshal(w.Body.Bytes(), &days)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(days))
+	assert.Equal(t, memberId, days[0].UserId)
+	assert.Equal(t, model.ViolationBreakTooShort, days[0].Violations[0].Type)
+	assert.Equal(t, int64(30), days[0].Violations[0].RequiredMinutes)
+}
+
+func Test_complianceHandler_GetViolationsOfTeamFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/compliance", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_complianceHandler_AddTimeEntryWithComplianceWarnings(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := addProject(t, handlerTest, "project", userId)
+	startTime := time.Date(2023, 9, 4, 7, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(7 * time.Hour)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"description\": \"%v\", \"startTimeUTCUnix\": %v, \"EndTimeUTCUnix\": %v, \"projectId\": \"%v\"}",
+		"entry", startTime.Unix(), endTime.Unix(), project.ID))
+	req, err := http.NewRequest("POST", "/api/v1/timeentries?complianceWarnings=true", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var response struct {
+		Id       uuid.UUID          `json:"id"`
+		Warnings []complianceDayDto `json:"warnings"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Nil(t, err)
+	assert.NotEqual(t, uuid.Nil, response.Id)
+	assert.Equal(t, 1, len(response.Warnings))
+	assert.Equal(t, "2023-09-04", response.Warnings[0].Date)
+	assert.Equal(t, model.ViolationBreakTooShort, response.Warnings[0].Violations[0].Type)
+}
@@ -0,0 +1,215 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type DataExportHandler interface {
+	RequestDataExport(context *gin.Context)
+	GetDataExports(context *gin.Context)
+	GetDataExportById(context *gin.Context)
+	DownloadDataExport(context *gin.Context)
+}
+
+type dataExportHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.DataExportUsecase
+}
+
+func NewDataExportHandler(tokenVerifier TokenVerifier, usecase usecase.DataExportUsecase) DataExportHandler {
+	return &dataExportHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+	}
+}
+
+type dataExportDto struct {
+	Id              uuid.UUID
+	UserId          uuid.UUID
+	RequestedBy     uuid.UUID
+	Status          string
+	Size            int    `json:",omitempty"`
+	Error           string `json:",omitempty"`
+	CreatedAtUnix   int64
+	CompletedAtUnix int64 `json:",omitempty"`
+	ExpiresAtUnix   int64 `json:",omitempty"`
+	// DownloadPath is only set while the file can be downloaded
+	DownloadPath string `json:",omitempty"`
+}
+
+// RequestDataExport exports all personal data of the user as ZIP file. Admins can export the data of another user
+// with the query parameter "userId". Small exports are generated directly (201), large exports are generated in the
+// background (202) and the requester is notified when the file can be downloaded.
+func (handler *dataExportHandler) RequestDataExport(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	exportedUserId := userId
+	if userIdParam := context.Query("userId"); userIdParam != "" {
+		var err error
+		exportedUserId, err = uuid.FromString(userIdParam)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid user id", userIdParam)})
+			return
+		}
+		if exportedUserId != userId && !handler.checkAdmin(context, token) {
+			return
+		}
+	}
+	dataExport, err := handler.usecase.RequestDataExport(exportedUserId, userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	status := http.StatusCreated
+	if dataExport.Status == model.DataExportStatusPending {
+		status = http.StatusAccepted
+	}
+	context.JSON(status, handler.createDtoFromDataExport(dataExport, time.Now().UTC()))
+}
+
+// GetDataExports returns the data exports of the user, the latest first.
+func (handler *dataExportHandler) GetDataExports(context *gin.Context) {
+	_, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	dataExports, err := handler.usecase.GetDataExportsOfUser(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	dtos := []dataExportDto{}
+	for _, dataExport := range dataExports {
+		dtos = append(dtos, handler.createDtoFromDataExport(&dataExport, now))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+// GetDataExportById returns the status of a data export of the user or of an export the user requested.
+func (handler *dataExportHandler) GetDataExportById(context *gin.Context) {
+	dataExport, ok := handler.getDataExport(context)
+	if !ok {
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromDataExport(dataExport, time.Now().UTC()))
+}
+
+// DownloadDataExport returns the ZIP file of a data export until it expires.
+func (handler *dataExportHandler) DownloadDataExport(context *gin.Context) {
+	dataExport, ok := handler.getDataExport(context)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	if dataExport.Status != model.DataExportStatusReady {
+		context.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("the data export is not ready (%v)", dataExport.Status)})
+		return
+	}
+	if dataExport.IsExpired(now) {
+		context.JSON(http.StatusGone, gin.H{"error": "the data export has expired, please request a new one"})
+		return
+	}
+	content, err := handler.usecase.GetDataExportContent(dataExport.ID, now)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("timeasy-data-%v.zip", dataExport.CompletedAt.UTC().Format("2006-01-02"))
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%v\"", filename))
+	context.Data(http.StatusOK, "application/zip", content)
+}
+
+// getDataExport loads the export of the id parameter. Only the exported user, the requester and admins may access it.
+func (handler *dataExportHandler) getDataExport(context *gin.Context) (*model.DataExport, bool) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return nil, false
+	}
+	id, err := uuid.FromString(context.Param("id"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify a valid id"})
+		return nil, false
+	}
+	dataExport, err := handler.usecase.GetDataExportById(id)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if dataExport.UserID != userId && dataExport.RequestedBy != userId && !handler.checkAdmin(context, token) {
+		return nil, false
+	}
+	return dataExport, true
+}
+
+func (handler *dataExportHandler) verifyToken(context *gin.Context) (AuthToken, uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	return token, userId, true
+}
+
+func (handler *dataExportHandler) checkAdmin(context *gin.Context, token AuthToken) bool {
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "only admins may export the data of other users"})
+		return false
+	}
+	return true
+}
+
+func (handler *dataExportHandler) createDtoFromDataExport(dataExport *model.DataExport, now time.Time) dataExportDto {
+	dto := dataExportDto{
+		Id:            dataExport.ID,
+		UserId:        dataExport.UserID,
+		RequestedBy:   dataExport.RequestedBy,
+		Status:        dataExport.Status,
+		Size:          dataExport.Size,
+		Error:         dataExport.LastError,
+		CreatedAtUnix: dataExport.CreatedAt.Unix(),
+	}
+	if dataExport.CompletedAt != nil {
+		dto.CompletedAtUnix = dataExport.CompletedAt.Unix()
+	}
+	if dataExport.ExpiresAt != nil {
+		dto.ExpiresAtUnix = dataExport.ExpiresAt.Unix()
+	}
+	if dataExport.Status == model.DataExportStatusReady && !dataExport.IsExpired(now) {
+		dto.DownloadPath = fmt.Sprintf(usecase.DataExportDownloadPath, dataExport.ID)
+	}
+	return dto
+}
+
+func (handler *dataExportHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,138 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_dataExportHandler_RequestDataExport(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := addProject(t, handlerTest, "Project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/dataexports", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var dataExport dataExportDto
+	err = json.Unmarshal(w.Body.Bytes(), &dataExport)
+	assert.Nil(t, err)
+	assert.Equal(t, model.DataExportStatusReady, dataExport.Status)
+	assert.Equal(t, userId, dataExport.UserId)
+	assert.NotEmpty(t, dataExport.DownloadPath)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", dataExport.DownloadPath, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(t, dataExport.Size, w.Body.Len())
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/dataexports", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var dataExports []dataExportDto
+	err = json.Unmarshal(w.Body.Bytes(), &dataExports)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(dataExports))
+	assert.Equal(t, dataExport.Id, dataExports[0].Id)
+}
+
+func Test_dataExportHandler_RequestDataExportOfOtherUser(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	// Only admins may export the data of other users:
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/dataexports?userId="+otherUserId.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	otherExport, err := handlerTest.DataExportUsecase.RequestDataExport(otherUserId, otherUserId)
+	assert.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/dataexports/"+otherExport.ID.String()+"/download", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/dataexports/"+uuid.Must(uuid.NewV4()).String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 404, w.Code)
+}
+
+func Test_dataExportHandler_RequestDataExportAsAdmin(t *testing.T) {
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(adminId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/dataexports?userId="+otherUserId.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var dataExport dataExportDto
+	err = json.Unmarshal(w.Body.Bytes(), &dataExport)
+	assert.Nil(t, err)
+	assert.Equal(t, otherUserId, dataExport.UserId)
+	assert.Equal(t, adminId, dataExport.RequestedBy)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", dataExport.DownloadPath, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+}
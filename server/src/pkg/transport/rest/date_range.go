@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const dateFormat = "2006-01-02"
+
+// getDateRangeFromQuery reads the optional query parameters "from" and "to" (both in the format YYYY-MM-DD).
+// Both days are included in the range, so the returned end is the beginning of the day after "to".
+// A missing parameter results in a zero time which means that the range is open on this side.
+func getDateRangeFromQuery(context *gin.Context) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if fromParam := context.Query("from"); fromParam != "" {
+		from, err = time.Parse(dateFormat, fromParam)
+		if err != nil {
+			return from, to, fmt.Errorf("please specify the start date in the format YYYY-MM-DD")
+		}
+	}
+	if toParam := context.Query("to"); toParam != "" {
+		to, err = time.Parse(dateFormat, toParam)
+		if err != nil {
+			return from, to, fmt.Errorf("please specify the end date in the format YYYY-MM-DD")
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("the start date must not be after the end date")
+	}
+	return from, to, nil
+}
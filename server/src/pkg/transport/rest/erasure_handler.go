@@ -0,0 +1,243 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type ErasureHandler interface {
+	RequestErasure(context *gin.Context)
+	GetErasureRequests(context *gin.Context)
+	GetErasureRequestById(context *gin.Context)
+	ConfirmErasure(context *gin.Context)
+	CancelErasure(context *gin.Context)
+	GetErasureReceipt(context *gin.Context)
+}
+
+type erasureHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.ErasureUsecase
+}
+
+func NewErasureHandler(tokenVerifier TokenVerifier, usecase usecase.ErasureUsecase) ErasureHandler {
+	return &erasureHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+	}
+}
+
+type erasureRequestDto struct {
+	Id              uuid.UUID
+	UserId          uuid.UUID
+	RequestedBy     uuid.UUID
+	Status          string
+	CreatedAtUnix   int64
+	DueAtUnix       int64
+	ConfirmedBy     *uuid.UUID `json:",omitempty"`
+	ConfirmedAtUnix int64      `json:",omitempty"`
+	CompletedAtUnix int64      `json:",omitempty"`
+}
+
+// RequestErasure requests the erasure of all personal data of the user. Admins can request the erasure of another
+// user with the query parameter "userId". The data is erased after the grace period if an admin has confirmed it.
+func (handler *erasureHandler) RequestErasure(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	erasedUserId := userId
+	if userIdParam := context.Query("userId"); userIdParam != "" {
+		var err error
+		erasedUserId, err = uuid.FromString(userIdParam)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid user id", userIdParam)})
+			return
+		}
+		if erasedUserId != userId && !handler.checkAdmin(context, token) {
+			return
+		}
+	}
+	request, err := handler.usecase.RequestErasure(erasedUserId, userId, time.Now().UTC())
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, handler.createDtoFromErasureRequest(request))
+}
+
+// GetErasureRequests returns the erasure requests of the user, the latest first. Admins get the open requests of all
+// users with the query parameter "open=true".
+func (handler *erasureHandler) GetErasureRequests(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	var requests []model.ErasureRequest
+	var err error
+	if open, _ := strconv.ParseBool(context.Query("open")); open {
+		if !handler.checkAdmin(context, token) {
+			return
+		}
+		requests, err = handler.usecase.GetOpenErasureRequests()
+	} else {
+		requests, err = handler.usecase.GetErasureRequestsOfUser(userId)
+	}
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []erasureRequestDto{}
+	for _, request := range requests {
+		dtos = append(dtos, handler.createDtoFromErasureRequest(&request))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *erasureHandler) GetErasureRequestById(context *gin.Context) {
+	request, _, ok := handler.getErasureRequest(context)
+	if !ok {
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromErasureRequest(request))
+}
+
+// ConfirmErasure confirms a request, only admins may confirm erasures.
+func (handler *erasureHandler) ConfirmErasure(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	if !handler.checkAdmin(context, token) {
+		return
+	}
+	id, err := uuid.FromString(context.Param("id"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify a valid id"})
+		return
+	}
+	request, err := handler.usecase.ConfirmErasure(id, userId, time.Now().UTC())
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromErasureRequest(request))
+}
+
+// CancelErasure cancels a request within the grace period.
+func (handler *erasureHandler) CancelErasure(context *gin.Context) {
+	request, _, ok := handler.getErasureRequest(context)
+	if !ok {
+		return
+	}
+	request, err := handler.usecase.CancelErasure(request.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromErasureRequest(request))
+}
+
+// GetErasureReceipt returns the receipt of a completed erasure as JSON file.
+func (handler *erasureHandler) GetErasureReceipt(context *gin.Context) {
+	request, _, ok := handler.getErasureRequest(context)
+	if !ok {
+		return
+	}
+	if request.Status != model.ErasureStatusCompleted {
+		context.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("the erasure is not completed (%v)", request.Status)})
+		return
+	}
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"timeasy-erasure-receipt-%v.json\"", request.ID))
+	context.Data(http.StatusOK, "application/json", []byte(request.Receipt))
+}
+
+// getErasureRequest loads the request of the id parameter. Only the erased user, the requester and admins may access
+// it.
+func (handler *erasureHandler) getErasureRequest(context *gin.Context) (*model.ErasureRequest, uuid.UUID, bool) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+	id, err := uuid.FromString(context.Param("id"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify a valid id"})
+		return nil, uuid.Nil, false
+	}
+	request, err := handler.usecase.GetErasureRequestById(id)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	if request.UserID != userId && request.RequestedBy != userId && !handler.checkAdmin(context, token) {
+		return nil, uuid.Nil, false
+	}
+	return request, userId, true
+}
+
+func (handler *erasureHandler) verifyToken(context *gin.Context) (AuthToken, uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	return token, userId, true
+}
+
+func (handler *erasureHandler) checkAdmin(context *gin.Context, token AuthToken) bool {
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "only admins may manage the erasures of other users"})
+		return false
+	}
+	return true
+}
+
+func (handler *erasureHandler) createDtoFromErasureRequest(request *model.ErasureRequest) erasureRequestDto {
+	dto := erasureRequestDto{
+		Id:            request.ID,
+		UserId:        request.UserID,
+		RequestedBy:   request.RequestedBy,
+		Status:        request.Status,
+		CreatedAtUnix: request.CreatedAt.Unix(),
+		DueAtUnix:     request.DueAt.Unix(),
+		ConfirmedBy:   request.ConfirmedBy,
+	}
+	if request.ConfirmedAt != nil {
+		dto.ConfirmedAtUnix = request.ConfirmedAt.Unix()
+	}
+	if request.CompletedAt != nil {
+		dto.CompletedAtUnix = request.CompletedAt.Unix()
+	}
+	return dto
+}
+
+func (handler *erasureHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityExistsError *usecase.EntityExistsError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityExistsError), errors.As(err, &invalidValueError):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_erasureHandler_RequestErasure(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/erasurerequests", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var request erasureRequestDto
+	err = json.Unmarshal(w.Body.Bytes(), &request)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusRequested, request.Status)
+	assert.Equal(t, userId, request.UserId)
+	assert.Greater(t, request.DueAtUnix, request.CreatedAtUnix)
+
+	// Only one open request:
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/erasurerequests", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+
+	// Only admins may confirm and see the open requests of all users:
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/erasurerequests/"+request.Id.String()+"/confirm", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/erasurerequests?open=true", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/erasurerequests/"+request.Id.String()+"/cancel", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &request)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusCancelled, request.Status)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/erasurerequests", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var requests []erasureRequestDto
+	err = json.Unmarshal(w.Body.Bytes(), &requests)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, request.Id, requests[0].Id)
+
+	// Other users may not request the erasure:
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/erasurerequests?userId="+otherUserId.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_erasureHandler_ConfirmErasure(t *testing.T) {
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(adminId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := addProject(t, handlerTest, "Project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+	request, err := handlerTest.ErasureUsecase.RequestErasure(userId, userId, time.Now().UTC())
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/erasurerequests?open=true", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var requests []erasureRequestDto
+	err = json.Unmarshal(w.Body.Bytes(), &requests)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, request.ID, requests[0].Id)
+
+	// The receipt is only available after the erasure:
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/erasurerequests/"+request.ID.String()+"/receipt", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/erasurerequests/"+request.ID.String()+"/confirm", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var confirmedRequest erasureRequestDto
+	err = json.Unmarshal(w.Body.Bytes(), &confirmedRequest)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusConfirmed, confirmedRequest.Status)
+	assert.Equal(t, adminId, *confirmedRequest.ConfirmedBy)
+
+	executed, err := handlerTest.ErasureUsecase.ExecuteDueErasures(request.DueAt.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, executed)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/erasurerequests/"+request.ID.String()+"/receipt", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var receipt model.ErasureReceipt
+	err = json.Unmarshal(w.Body.Bytes(), &receipt)
+	assert.Nil(t, err)
+	assert.Equal(t, userId, receipt.UserID)
+	assert.Equal(t, adminId, receipt.ConfirmedBy)
+	assert.Equal(t, int64(1), receipt.Deleted["time_entries"])
+}
@@ -0,0 +1,243 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/export"
+	"timeasy-server/pkg/usecase"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+type ExportHandler interface {
+	ExportTimeEntriesAsCsv(context *gin.Context)
+	ExportTimeEntriesAsXlsx(context *gin.Context)
+}
+
+type exportHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.ExportUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewExportHandler(tokenVerifier TokenVerifier, usecase usecase.ExportUsecase, teamUsecase usecase.TeamUsecase) ExportHandler {
+	return &exportHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+// ExportTimeEntriesAsCsv streams the time entries of the user or, with the query parameter "teamId", of the team as
+// CSV file. Besides the filters of getExportRows the query parameters "columns" (comma separated list), "delimiter"
+// (a single character or "tab"), "decimalComma" and "timezone" (e.g. Europe/Berlin) configure the format.
+func (handler *exportHandler) ExportTimeEntriesAsCsv(context *gin.Context) {
+	options, err := handler.getCsvOptions(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rows, ok := handler.getExportRows(context)
+	if !ok {
+		return
+	}
+	context.Header("Content-Type", "text/csv; charset=utf-8")
+	context.Header("Content-Disposition", "attachment; filename=\"timeentries.csv\"")
+	context.Status(http.StatusOK)
+	if err := export.WriteCsv(context.Writer, rows, options); err != nil {
+		// The header is already sent, so the error can only be reported by aborting the response
+		_ = context.Error(err)
+		context.Abort()
+	}
+}
+
+// ExportTimeEntriesAsXlsx returns the time entries of the user or, with the query parameter "teamId", of the team as
+// spreadsheet in the layouts of the app. Besides the filters of getExportRows the query parameters "layout"
+// (allentries or onelineperday), "language" (en or de) and "timezone" (e.g. Europe/Berlin) configure the format.
+func (handler *exportHandler) ExportTimeEntriesAsXlsx(context *gin.Context) {
+	options := export.XlsxOptions{
+		Layout:   context.Query("layout"),
+		Language: context.Query("language"),
+		WithUser: context.Query("teamId") != "",
+	}
+	if !export.IsKnownXlsxLayout(options.Layout) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown layout %v, allowed are %v and %v",
+			options.Layout, export.XlsxLayoutAllEntries, export.XlsxLayoutOneLinePerDay)})
+		return
+	}
+	if !export.IsKnownLanguage(options.Language) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown language %v", options.Language)})
+		return
+	}
+	var err error
+	options.Location, err = handler.getLocationFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rows, ok := handler.getExportRows(context)
+	if !ok {
+		return
+	}
+	// The spreadsheet is a zip archive which is written at once, so errors can still be reported
+	var buffer bytes.Buffer
+	if err := export.WriteXlsx(&buffer, rows, options); err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.Header("Content-Disposition", "attachment; filename=\"timeentries.xlsx\"")
+	context.Data(http.StatusOK, xlsxContentType, buffer.Bytes())
+}
+
+// getExportRows reads the filters from the query parameters "from", "to" (YYYY-MM-DD), "teamId", "projectId",
+// "client" and "userId" (the last three may be repeated) and loads the time entries. Only team admins may export the entries of a team and
+// filter them by user. If something is wrong the error response is already written.
+func (handler *exportHandler) getExportRows(context *gin.Context) ([]export.Row, bool) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	filter := usecase.ExportFilter{From: from, To: to}
+	filter.ProjectIds, err = handler.getIdsFromQuery(context, "projectId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	filter.UserIds, err = handler.getIdsFromQuery(context, "userId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	filter.Clients = context.QueryArray("client")
+
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	var rows []export.Row
+	if context.Query("teamId") != "" {
+		teamId, err := uuid.FromString(context.Query("teamId"))
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid team id", context.Query("teamId"))})
+			return nil, false
+		}
+		if !handler.checkTeamAdmin(context, token, userId, teamId) {
+			return nil, false
+		}
+		rows, err = handler.usecase.GetExportRowsOfTeam(teamId, filter)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return nil, false
+		}
+	} else {
+		if len(filter.UserIds) > 0 {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "the time entries can only be filtered by user in a team export"})
+			return nil, false
+		}
+		rows, err = handler.usecase.GetExportRowsOfUser(userId, filter)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+	return rows, true
+}
+
+func (handler *exportHandler) getCsvOptions(context *gin.Context) (export.CsvOptions, error) {
+	var options export.CsvOptions
+	var err error
+	options.Columns, err = export.ParseColumns(context.Query("columns"))
+	if err != nil {
+		return options, err
+	}
+	switch delimiter := context.Query("delimiter"); {
+	case delimiter == "":
+	case delimiter == "tab":
+		options.Delimiter = '\t'
+	case utf8.RuneCountInString(delimiter) == 1:
+		options.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+		if options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' {
+			return options, fmt.Errorf("%q can not be used as delimiter", delimiter)
+		}
+	default:
+		return options, fmt.Errorf("the delimiter must be a single character or \"tab\"")
+	}
+	if decimalComma := context.Query("decimalComma"); decimalComma != "" {
+		options.DecimalComma, err = strconv.ParseBool(decimalComma)
+		if err != nil {
+			return options, fmt.Errorf("decimalComma must be true or false")
+		}
+	}
+	options.Location, err = handler.getLocationFromQuery(context)
+	return options, err
+}
+
+func (handler *exportHandler) getLocationFromQuery(context *gin.Context) (*time.Location, error) {
+	timezone := context.Query("timezone")
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a valid time zone", timezone)
+	}
+	return location, nil
+}
+
+func (handler *exportHandler) getIdsFromQuery(context *gin.Context, name string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, idString := range context.QueryArray(name) {
+		id, err := uuid.FromString(idString)
+		if err != nil {
+			return nil, fmt.Errorf("%v is not a valid id", idString)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (handler *exportHandler) checkTeamAdmin(context *gin.Context, token AuthToken, userId uuid.UUID, teamId uuid.UUID) bool {
+	if handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to export the time entries of this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *exportHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,203 @@
+package rest
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_exportHandler_ExportTimeEntriesAsCsv(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := addProject(t, handlerTest, "project", userId)
+	otherProject := addProject(t, handlerTest, "other project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC), 90*time.Minute)
+	addTimeEntryWithDuration(t, handlerTest, userId, otherProject, time.Date(2023, 9, 5, 6, 0, 0, 0, time.UTC), time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 10, 4, 6, 0, 0, 0, time.UTC), time.Hour)
+
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/export/csv?from=2023-09-01&to=2023-09-30&projectId=%v&columns=date,start,end,duration,project"+
+		"&delimiter=;&decimalComma=true&timezone=Europe/Berlin", project.ID)
+	req, err := http.NewRequest("GET", url, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "date;start;end;duration;project\n2023-09-04;08:00;09:30;1,50;project\n", w.Body.String())
+}
+
+func Test_exportHandler_ExportTimeEntriesOfTeamAsCsv(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", userId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	project := addTeamProject(t, handlerTest, "team project", userId, team)
+	addTimeEntryWithDuration(t, handlerTest, memberId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/export/csv?teamId=%v&userId=%v&columns=user,duration", team.ID, memberId)
+	req, err := http.NewRequest("GET", url, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	assert.Equal(t, fmt.Sprintf("user,duration\n%v,2.00\n", memberId), w.Body.String())
+}
+
+func Test_exportHandler_ExportTimeEntriesOfTeamFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/export/csv?teamId=%v", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_exportHandler_ExportTimeEntriesAsCsvFailsWithInvalidOptions(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	for _, query := range []string{"columns=date,client", "delimiter=;;", "decimalComma=ja", "timezone=Mars/Base",
+		"from=2023-09-31", fmt.Sprintf("userId=%v", userId)} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/export/csv?"+query, nil)
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, query)
+	}
+}
+
+func Test_exportHandler_ExportTimeEntriesAsXlsx(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := addProject(t, handlerTest, "project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 6, 0, 0, 0, time.UTC), 90*time.Minute)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/export/xlsx?from=2023-09-01&to=2023-09-30&projectId=%v&layout=onelineperday&language=de", project.ID)
+	req, err := http.NewRequest("GET", url, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	assert.Equal(t, xlsxContentType, w.Header().Get("Content-Type"))
+
+	zipReader, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.Nil(t, err)
+	var sheet string
+	for _, file := range zipReader.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, err := file.Open()
+			assert.Nil(t, err)
+			content, err := io.ReadAll(reader)
+			assert.Nil(t, err)
+			sheet = string(content)
+		}
+	}
+	assert.Contains(t, sheet, "Pause 1 Start")
+	assert.Contains(t, sheet, "2023-09-04")
+	assert.Contains(t, sheet, "07:30")
+}
+
+func Test_exportHandler_ExportTimeEntriesAsXlsxFailsWithUnknownLayout(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	for _, query := range []string{"layout=pivot", "language=fr", "timezone=Mars/Base"} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/v1/export/xlsx?"+query, nil)
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, query)
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/job"
+	"timeasy-server/pkg/notification"
 	"timeasy-server/pkg/test"
 	"timeasy-server/pkg/usecase"
+	"timeasy-server/pkg/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
@@ -54,16 +59,56 @@ func (t *authTokenMock) HasRole(role string) (bool, error) {
 }
 
 type HandlerTest struct {
-	ProjectUsecase   usecase.ProjectUsecase
-	TimeEntryUsecase usecase.TimeEntryUsecase
-	TeamUsecase      usecase.TeamUsecase
-	SyncUsecase      usecase.SyncUsecase
-	ProjectHandler   ProjectHandler
-	TimeEntryHandler TimeEntryHandler
-	TeamHandler      TeamHandler
-	SyncHandler      SyncHandler
-	Router           *gin.Engine
-	tokenVerifier    TokenVerifier
+	ProjectUsecase      usecase.ProjectUsecase
+	TimeEntryUsecase    usecase.TimeEntryUsecase
+	TeamUsecase         usecase.TeamUsecase
+	SyncUsecase         usecase.SyncUsecase
+	ReportUsecase       usecase.ReportUsecase
+	WorkingTimeUsecase  usecase.WorkingTimeUsecase
+	AbsenceUsecase      usecase.AbsenceUsecase
+	HolidayUsecase      usecase.HolidayUsecase
+	TimesheetUsecase    usecase.TimesheetUsecase
+	PeriodLockUsecase   usecase.PeriodLockUsecase
+	ComplianceUsecase   usecase.ComplianceUsecase
+	OvertimeUsecase     usecase.OvertimeUsecase
+	MissingTimeUsecase  usecase.MissingTimeUsecase
+	RunningTimerUsecase usecase.RunningTimerUsecase
+	NotificationUsecase usecase.NotificationUsecase
+	Scheduler           job.Scheduler
+	WebhookUsecase      usecase.WebhookUsecase
+	ExportUsecase       usecase.ExportUsecase
+	PdfReportUsecase    usecase.PdfReportUsecase
+	CalendarFeedUsecase usecase.CalendarFeedUsecase
+	ImportUsecase       usecase.ImportUsecase
+	DataExportUsecase   usecase.DataExportUsecase
+	ErasureUsecase      usecase.ErasureUsecase
+	InvoiceUsecase      usecase.InvoiceUsecase
+	ProjectHandler      ProjectHandler
+	TimeEntryHandler    TimeEntryHandler
+	TeamHandler         TeamHandler
+	SyncHandler         SyncHandler
+	ReportHandler       ReportHandler
+	WorkingTimeHandler  WorkingTimeHandler
+	AbsenceHandler      AbsenceHandler
+	HolidayHandler      HolidayHandler
+	TimesheetHandler    TimesheetHandler
+	PeriodLockHandler   PeriodLockHandler
+	ComplianceHandler   ComplianceHandler
+	OvertimeHandler     OvertimeHandler
+	MissingTimeHandler  MissingTimeHandler
+	RunningTimerHandler RunningTimerHandler
+	NotificationHandler NotificationHandler
+	JobHandler          JobHandler
+	WebhookHandler      WebhookHandler
+	ExportHandler       ExportHandler
+	PdfReportHandler    PdfReportHandler
+	CalendarFeedHandler CalendarFeedHandler
+	ImportHandler       ImportHandler
+	DataExportHandler   DataExportHandler
+	ErasureHandler      ErasureHandler
+	InvoiceHandler      InvoiceHandler
+	Router              *gin.Engine
+	tokenVerifier       TokenVerifier
 }
 
 type ErrorResult struct {
@@ -84,27 +129,99 @@ func (t *HandlerTest) SetupTest(tb testing.TB) func(tb testing.TB) {
 }
 
 func (t *HandlerTest) initUsecases() {
+	notificationRepo := database.NewGormNotificationRepository(test.DB)
+	t.NotificationUsecase = usecase.NewNotificationUsecase(notificationRepo, map[string]notification.Channel{
+		model.NotificationChannelInbox:   notification.NewInboxChannel(notificationRepo),
+		model.NotificationChannelWebhook: notification.NewWebhookChannel(time.Second, true),
+	})
+
 	teamRepo := database.NewGormTeamRepository(test.DB)
-	t.TeamUsecase = usecase.NewTeamUsecase(teamRepo)
+	t.TeamUsecase = usecase.NewTeamUsecase(teamRepo, t.NotificationUsecase)
 
 	projectRepo := database.NewGormProjectRepository(test.DB, teamRepo)
 	t.ProjectUsecase = usecase.NewProjectUsecase(projectRepo, t.TeamUsecase)
 
+	timesheetRepo := database.NewGormTimesheetRepository(test.DB)
+	t.TimesheetUsecase = usecase.NewTimesheetUsecase(timesheetRepo, t.TeamUsecase, t.NotificationUsecase)
+
+	periodLockRepo := database.NewGormPeriodLockRepository(test.DB)
+	t.PeriodLockUsecase = usecase.NewPeriodLockUsecase(periodLockRepo, t.TeamUsecase)
+
 	timeEntryRepo := database.NewGormTimeEntryRepository(test.DB)
-	t.TimeEntryUsecase = usecase.NewTimeEntryUsecase(timeEntryRepo, t.ProjectUsecase)
+	t.TimeEntryUsecase = usecase.NewTimeEntryUsecase(timeEntryRepo, t.ProjectUsecase, t.TimesheetUsecase, t.PeriodLockUsecase)
 
 	syncRepo := database.NewGormSyncRepository(test.DB)
-	t.SyncUsecase = usecase.NewSyncUsecase(syncRepo)
+	t.SyncUsecase = usecase.NewSyncUsecase(syncRepo, t.TimeEntryUsecase)
+
+	absenceRepo := database.NewGormAbsenceRepository(test.DB)
+	t.AbsenceUsecase = usecase.NewAbsenceUsecase(absenceRepo, t.TeamUsecase, t.NotificationUsecase)
+
+	t.ComplianceUsecase = usecase.NewComplianceUsecase(t.TimeEntryUsecase, t.TeamUsecase)
+	t.ReportUsecase = usecase.NewReportUsecase(t.TimeEntryUsecase, t.TeamUsecase, t.AbsenceUsecase)
+
+	holidayCalendarRepo := database.NewGormHolidayCalendarRepository(test.DB)
+	t.HolidayUsecase = usecase.NewHolidayUsecase(holidayCalendarRepo, t.TeamUsecase)
+
+	workingTimeRepo := database.NewGormWorkingTimeModelRepository(test.DB)
+	t.WorkingTimeUsecase = usecase.NewWorkingTimeUsecase(workingTimeRepo, t.TeamUsecase, t.AbsenceUsecase, t.HolidayUsecase)
+
+	overtimeRepo := database.NewGormOvertimeRepository(test.DB)
+	t.OvertimeUsecase = usecase.NewOvertimeUsecase(overtimeRepo, t.TimeEntryUsecase, t.WorkingTimeUsecase)
+	t.MissingTimeUsecase = usecase.NewMissingTimeUsecase(t.TimeEntryUsecase, t.WorkingTimeUsecase, t.TeamUsecase)
+
+	runningTimerRepo := database.NewGormRunningTimerRepository(test.DB)
+	t.RunningTimerUsecase = usecase.NewRunningTimerUsecase(runningTimerRepo, t.TimeEntryUsecase, t.TeamUsecase, t.WorkingTimeUsecase,
+		t.NotificationUsecase, 12*time.Hour)
+
+	t.Scheduler = job.NewScheduler(database.NewGormJobRunRepository(test.DB))
+	t.WebhookUsecase = usecase.NewWebhookUsecase(database.NewGormWebhookRepository(test.DB), t.ProjectUsecase,
+		webhook.NewHttpSender(time.Second, true))
+	t.ExportUsecase = usecase.NewExportUsecase(t.TimeEntryUsecase, t.ProjectUsecase, t.TeamUsecase)
+	t.PdfReportUsecase = usecase.NewPdfReportUsecase(database.NewGormReportTemplateRepository(test.DB), t.ExportUsecase,
+		t.ProjectUsecase, t.TeamUsecase)
+	t.CalendarFeedUsecase = usecase.NewCalendarFeedUsecase(database.NewGormCalendarFeedRepository(test.DB), t.ExportUsecase)
+	t.ImportUsecase = usecase.NewImportUsecase(database.NewGormImportedTimeEntryRepository(test.DB),
+		t.TimeEntryUsecase, t.ProjectUsecase, t.TeamUsecase, t.SyncUsecase)
+	t.DataExportUsecase = usecase.NewDataExportUsecase(database.NewGormDataExportRepository(test.DB), t.NotificationUsecase,
+		24*time.Hour)
+	t.ErasureUsecase = usecase.NewErasureUsecase(database.NewGormErasureRepository(test.DB), t.NotificationUsecase,
+		14*24*time.Hour)
+	t.InvoiceUsecase = usecase.NewInvoiceUsecase(database.NewGormInvoiceRepository(test.DB), t.ProjectUsecase, t.TeamUsecase)
 }
 
 func (t *HandlerTest) initHandlers() {
 	authMiddleware := NewJwtAuthMiddleware(t.tokenVerifier)
 	t.ProjectHandler = NewProjectHandler(t.tokenVerifier, t.ProjectUsecase, t.TeamUsecase)
-	t.TimeEntryHandler = NewTimeEntryHandler(t.tokenVerifier, t.TimeEntryUsecase)
+	t.TimeEntryHandler = NewTimeEntryHandler(t.tokenVerifier, t.TimeEntryUsecase, t.ComplianceUsecase)
 	t.TeamHandler = NewTeamHandler(t.tokenVerifier, t.TeamUsecase)
-	t.SyncHandler = NewSyncHandler(t.tokenVerifier, t.SyncUsecase)
-
-	t.Router = SetupRouter(authMiddleware, t.TeamHandler, t.ProjectHandler, t.TimeEntryHandler, t.SyncHandler)
+	t.SyncHandler = NewSyncHandler(t.tokenVerifier, t.SyncUsecase, t.ErasureUsecase)
+	t.ReportHandler = NewReportHandler(t.tokenVerifier, t.ReportUsecase, t.TeamUsecase)
+	t.WorkingTimeHandler = NewWorkingTimeHandler(t.tokenVerifier, t.WorkingTimeUsecase, t.TeamUsecase)
+	t.AbsenceHandler = NewAbsenceHandler(t.tokenVerifier, t.AbsenceUsecase, t.TeamUsecase)
+	t.HolidayHandler = NewHolidayHandler(t.tokenVerifier, t.HolidayUsecase, t.TeamUsecase)
+	t.TimesheetHandler = NewTimesheetHandler(t.tokenVerifier, t.TimesheetUsecase, t.TeamUsecase)
+	t.PeriodLockHandler = NewPeriodLockHandler(t.tokenVerifier, t.PeriodLockUsecase, t.TeamUsecase)
+	t.ComplianceHandler = NewComplianceHandler(t.tokenVerifier, t.ComplianceUsecase, t.TeamUsecase)
+	t.OvertimeHandler = NewOvertimeHandler(t.tokenVerifier, t.OvertimeUsecase, t.TeamUsecase)
+	t.MissingTimeHandler = NewMissingTimeHandler(t.tokenVerifier, t.MissingTimeUsecase, t.TeamUsecase)
+	t.RunningTimerHandler = NewRunningTimerHandler(t.tokenVerifier, t.RunningTimerUsecase, t.TeamUsecase)
+	t.NotificationHandler = NewNotificationHandler(t.tokenVerifier, t.NotificationUsecase)
+	t.JobHandler = NewJobHandler(t.tokenVerifier, t.Scheduler)
+	t.WebhookHandler = NewWebhookHandler(t.tokenVerifier, t.WebhookUsecase, t.TeamUsecase)
+	t.ExportHandler = NewExportHandler(t.tokenVerifier, t.ExportUsecase, t.TeamUsecase)
+	t.PdfReportHandler = NewPdfReportHandler(t.tokenVerifier, t.PdfReportUsecase, t.TeamUsecase)
+	t.CalendarFeedHandler = NewCalendarFeedHandler(t.tokenVerifier, t.CalendarFeedUsecase)
+	t.ImportHandler = NewImportHandler(t.tokenVerifier, t.ImportUsecase, t.TeamUsecase)
+	t.DataExportHandler = NewDataExportHandler(t.tokenVerifier, t.DataExportUsecase)
+	t.ErasureHandler = NewErasureHandler(t.tokenVerifier, t.ErasureUsecase)
+	t.InvoiceHandler = NewInvoiceHandler(t.tokenVerifier, t.InvoiceUsecase, t.TeamUsecase)
+	calendarFeedAuthMiddleware := NewCalendarFeedAuthMiddleware(t.CalendarFeedUsecase)
+
+	t.Router = SetupRouter(authMiddleware, calendarFeedAuthMiddleware, t.TeamHandler, t.ProjectHandler, t.TimeEntryHandler, t.SyncHandler,
+		t.ReportHandler, t.WorkingTimeHandler, t.AbsenceHandler, t.HolidayHandler, t.TimesheetHandler, t.PeriodLockHandler,
+		t.ComplianceHandler, t.OvertimeHandler, t.MissingTimeHandler, t.RunningTimerHandler,
+		t.NotificationHandler, t.JobHandler, t.WebhookHandler, t.ExportHandler, t.PdfReportHandler, t.CalendarFeedHandler, t.ImportHandler,
+		t.DataExportHandler, t.ErasureHandler, t.InvoiceHandler)
 }
 
 func AssertErrorMessageEquals(t *testing.T, responseBody []byte, expectedMessage string) {
@@ -0,0 +1,373 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type HolidayHandler interface {
+	GetHolidayCalendarsOfTeam(context *gin.Context)
+	AddHolidayCalendar(context *gin.Context)
+	UpdateHolidayCalendar(context *gin.Context)
+	DeleteHolidayCalendar(context *gin.Context)
+	GetHolidays(context *gin.Context)
+	AddCustomHoliday(context *gin.Context)
+	DeleteCustomHoliday(context *gin.Context)
+	AssignHolidayCalendarToTeam(context *gin.Context)
+	AssignHolidayCalendarToUser(context *gin.Context)
+}
+
+type holidayHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.HolidayUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewHolidayHandler(tokenVerifier TokenVerifier, usecase usecase.HolidayUsecase, teamUsecase usecase.TeamUsecase) HolidayHandler {
+	return &holidayHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type holidayCalendarInputDto struct {
+	Name   string `json:"name" binding:"required"`
+	Region string `json:"region" binding:"required"`
+}
+
+type holidayCalendarDto struct {
+	Id     uuid.UUID
+	TeamId uuid.UUID
+	holidayCalendarInputDto
+}
+
+type customHolidayInputDto struct {
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+type holidayDto struct {
+	Date string
+	Name string
+}
+
+type holidayCalendarAssignmentDto struct {
+	HolidayCalendarId *uuid.UUID `json:"holidayCalendarId"`
+}
+
+func (handler *holidayHandler) GetHolidayCalendarsOfTeam(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.checkAccessToTeam(context, teamId, true) {
+		return
+	}
+	calendars, err := handler.usecase.GetHolidayCalendarsOfTeam(teamId)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var dtos []holidayCalendarDto
+	for _, calendar := range calendars {
+		dtos = append(dtos, handler.createDtoFromHolidayCalendar(&calendar))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *holidayHandler) AddHolidayCalendar(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.checkAccessToTeam(context, teamId, false) {
+		return
+	}
+	var input holidayCalendarInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	calendar := model.HolidayCalendar{
+		TeamID: teamId,
+		Name:   input.Name,
+		Region: input.Region,
+	}
+	err = handler.usecase.AddHolidayCalendar(&calendar)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"id": calendar.ID})
+}
+
+func (handler *holidayHandler) UpdateHolidayCalendar(context *gin.Context) {
+	calendar, ok := handler.getHolidayCalendar(context, false)
+	if !ok {
+		return
+	}
+	var input holidayCalendarInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	calendar.Name = input.Name
+	calendar.Region = input.Region
+	err := handler.usecase.UpdateHolidayCalendar(calendar)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("holiday calendar %v updated", calendar.ID)})
+}
+
+func (handler *holidayHandler) DeleteHolidayCalendar(context *gin.Context) {
+	calendar, ok := handler.getHolidayCalendar(context, false)
+	if !ok {
+		return
+	}
+	err := handler.usecase.DeleteHolidayCalendar(calendar.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("holiday calendar %v deleted", calendar.ID)})
+}
+
+// GetHolidays returns the public and custom holidays of the calendar. Without a date range the holidays of the
+// current year are returned.
+func (handler *holidayHandler) GetHolidays(context *gin.Context) {
+	calendar, ok := handler.getHolidayCalendar(context, true)
+	if !ok {
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	if from.IsZero() {
+		from = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = time.Date(from.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	holidays, err := handler.usecase.GetHolidays(calendar.ID, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []holidayDto{}
+	for _, holiday := range holidays {
+		dtos = append(dtos, holidayDto{
+			Date: holiday.Date.Format(dateFormat),
+			Name: holiday.Name,
+		})
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *holidayHandler) AddCustomHoliday(context *gin.Context) {
+	calendar, ok := handler.getHolidayCalendar(context, false)
+	if !ok {
+		return
+	}
+	var input customHolidayInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.Parse(dateFormat, input.Date)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the date of the holiday in the format YYYY-MM-DD"})
+		return
+	}
+	customHoliday := model.CustomHoliday{
+		HolidayCalendarID: calendar.ID,
+		Date:              date,
+		Name:              input.Name,
+	}
+	err = handler.usecase.AddCustomHoliday(&customHoliday)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"id": customHoliday.ID})
+}
+
+func (handler *holidayHandler) DeleteCustomHoliday(context *gin.Context) {
+	calendar, ok := handler.getHolidayCalendar(context, false)
+	if !ok {
+		return
+	}
+	customHolidayId, err := handler.getIdParam(context, "holidayId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	customHoliday, err := handler.usecase.GetCustomHolidayById(customHolidayId)
+	if err != nil || customHoliday.HolidayCalendarID != calendar.ID {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("custom holiday with id %v not found", customHolidayId)})
+		return
+	}
+	err = handler.usecase.DeleteCustomHoliday(customHoliday.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("custom holiday %v deleted", customHoliday.ID)})
+}
+
+func (handler *holidayHandler) AssignHolidayCalendarToTeam(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.checkAccessToTeam(context, teamId, false) {
+		return
+	}
+	var input holidayCalendarAssignmentDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = handler.usecase.AssignHolidayCalendarToTeam(teamId, input.HolidayCalendarId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("holiday calendar of team %v updated", teamId)})
+}
+
+func (handler *holidayHandler) AssignHolidayCalendarToUser(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := handler.getIdParam(context, "userId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.checkAccessToTeam(context, teamId, false) {
+		return
+	}
+	var input holidayCalendarAssignmentDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = handler.usecase.AssignHolidayCalendarToUser(userId, teamId, input.HolidayCalendarId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("holiday calendar of user %v in team %v updated", userId, teamId)})
+}
+
+// getHolidayCalendar reads the calendar from the request path and checks the access to the team it belongs to. If
+// something is wrong the error response is already written.
+func (handler *holidayHandler) getHolidayCalendar(context *gin.Context, readOnly bool) (*model.HolidayCalendar, bool) {
+	calendarId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	calendar, err := handler.usecase.GetHolidayCalendarById(calendarId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !handler.checkAccessToTeam(context, calendar.TeamID, readOnly) {
+		return nil, false
+	}
+	return calendar, true
+}
+
+// checkAccessToTeam checks if the authenticated user may access the holiday calendars of the team. Members of the team
+// may read them, only team admins may change them. Global admins may do both. If the access is denied the error
+// response is already written.
+func (handler *holidayHandler) checkAccessToTeam(context *gin.Context, teamId uuid.UUID, readOnly bool) bool {
+	_, err := handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return false
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if readOnly && handler.teamUsecase.DoesUserBelongToTeam(userId, teamId) {
+		return true
+	}
+	if handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to manage the holiday calendars of this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *holidayHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *holidayHandler) createDtoFromHolidayCalendar(calendar *model.HolidayCalendar) holidayCalendarDto {
+	dto := holidayCalendarDto{
+		Id:     calendar.ID,
+		TeamId: calendar.TeamID,
+	}
+	dto.Name = calendar.Name
+	dto.Region = calendar.Region
+	return dto
+}
+
+func (handler *holidayHandler) getIdParam(context *gin.Context, paramName string) (uuid.UUID, error) {
+	id := context.Param(paramName)
+	if id == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid %v", paramName)
+	}
+	result, err := uuid.FromString(id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return result, nil
+}
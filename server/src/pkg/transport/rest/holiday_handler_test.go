@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_holidayHandler_AddHolidayCalendar(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"name\": \"Munich\", \"region\": \"BY\"}")
+	req, err := http.NewRequest("POST", fmt.Sprintf("/api/v1/teams/%v/holidaycalendars", team.ID), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	calendarsFromDb, err := handlerTest.HolidayUsecase.GetHolidayCalendarsOfTeam(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(calendarsFromDb))
+	assert.Equal(t, "BY", calendarsFromDb[0].Region)
+}
+
+func Test_holidayHandler_AddHolidayCalendarFailsIfUserIsNoTeamAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"name\": \"Munich\", \"region\": \"BY\"}")
+	req, err := http.NewRequest("POST", fmt.Sprintf("/api/v1/teams/%v/holidaycalendars", team.ID), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	calendarsFromDb, err := handlerTest.HolidayUsecase.GetHolidayCalendarsOfTeam(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(calendarsFromDb))
+}
+
+func Test_holidayHandler_GetHolidaysAsTeamMember(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	calendar := addHolidayCalendar(t, handlerTest, team, "NW")
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/holidaycalendars/%v/holidays?from=2023-01-01&to=2023-12-31", calendar.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var holidays []holidayDto
+	err = json.Unmarshal(w.Body.Bytes(), &holidays)
+	assert.Nil(t, err)
+	assert.Equal(t, 11, len(holidays))
+	assert.Equal(t, "2023-01-01", holidays[0].Date)
+	assert.Equal(t, "Fronleichnam", holidays[6].Name)
+}
+
+func Test_holidayHandler_AssignHolidayCalendarToTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	calendar := addHolidayCalendar(t, handlerTest, team, "HE")
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"holidayCalendarId\": \"%v\"}", calendar.ID))
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/holidaycalendar", team.ID), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	teamFromDb, err := handlerTest.TeamUsecase.GetTeamById(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, calendar.ID, *teamFromDb.HolidayCalendarID)
+}
+
+func addHolidayCalendar(t *testing.T, handlerTest *HandlerTest, team model.Team, region string) model.HolidayCalendar {
+	calendar := model.HolidayCalendar{
+		TeamID: team.ID,
+		Name:   "calendar " + region,
+		Region: region,
+	}
+	err := handlerTest.HolidayUsecase.AddHolidayCalendar(&calendar)
+	assert.Nil(t, err)
+	return calendar
+}
@@ -0,0 +1,342 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/importer"
+	"timeasy-server/pkg/usecase"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+// maxImportFileSize limits the size of uploaded import files
+const maxImportFileSize = 20 << 20
+
+type ImportHandler interface {
+	ImportTimeEntriesFromCsv(context *gin.Context)
+	ImportTimeEntriesFromToggl(context *gin.Context)
+	ImportTimeEntriesFromClockify(context *gin.Context)
+	ImportAppDatabase(context *gin.Context)
+}
+
+type importHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.ImportUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewImportHandler(tokenVerifier TokenVerifier, usecase usecase.ImportUsecase, teamUsecase usecase.TeamUsecase) ImportHandler {
+	return &importHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type importResultDto struct {
+	DryRun      bool
+	Imported    int
+	Skipped     int
+	NewProjects []string
+	Errors      []importErrorDto
+}
+
+type importErrorDto struct {
+	Line  int
+	Error string
+}
+
+// ImportTimeEntriesFromCsv imports the time entries of a CSV file into the account of the user. The file is either
+// the request body or the form file "file". The query parameters "mapping" (e.g. date=Datum,start=Von), "delimiter"
+// (a single character or "tab"), "dateFormat" (e.g. DD.MM.YYYY), "timeFormat" (e.g. HH:mm), "decimalComma" and
+// "timezone" (e.g. Europe/Berlin) describe the file. See getImportOptions for the options of the import.
+func (handler *importHandler) ImportTimeEntriesFromCsv(context *gin.Context) {
+	options, err := handler.getCsvOptions(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, importOptions, ok := handler.getImportOptions(context)
+	if !ok {
+		return
+	}
+	file, ok := handler.openImportFile(context)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	records, recordErrors, err := importer.ReadCsv(file, options)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	handler.importRecords(context, userId, model.ImportSourceCsv, records, recordErrors, importOptions)
+}
+
+// ImportTimeEntriesFromToggl imports the detailed report of Toggl Track as CSV or JSON file. The query parameters
+// "dateFormat", "timeFormat" and "timezone" describe the times of CSV files. See getImportOptions for the options of
+// the import.
+func (handler *importHandler) ImportTimeEntriesFromToggl(context *gin.Context) {
+	handler.importToolExport(context, model.ImportSourceToggl, importer.ReadTogglCsv, importer.ReadTogglJson)
+}
+
+// ImportTimeEntriesFromClockify imports the detailed report of Clockify as CSV or JSON file. The query parameters
+// "dateFormat" (default MM/DD/YYYY), "timeFormat" and "timezone" describe the times of CSV files. See
+// getImportOptions for the options of the import.
+func (handler *importHandler) ImportTimeEntriesFromClockify(context *gin.Context) {
+	handler.importToolExport(context, model.ImportSourceClockify, importer.ReadClockifyCsv, importer.ReadClockifyJson)
+}
+
+// ImportAppDatabase imports the SQLite database file of the tracking app (timeasy.db) with its projects and time
+// entries. The ids of the app are kept, so a later sync of the app does not duplicate anything. The query parameter
+// "dryRun=true" only validates the entries, the projects of the app always belong to the user.
+func (handler *importHandler) ImportAppDatabase(context *gin.Context) {
+	userId, importOptions, ok := handler.getImportOptions(context)
+	if !ok {
+		return
+	}
+	if importOptions.TeamID != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "the projects of the app cannot be imported into a team"})
+		return
+	}
+	file, ok := handler.openImportFile(context)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	// SQLite reads from files only
+	tempFile, err := os.CreateTemp("", "timeasy-import-*.db")
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = io.Copy(tempFile, file)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("the import file cannot be read: %v", err)})
+		return
+	}
+	appDatabase, recordErrors, err := importer.ReadAppDatabase(tempFile.Name())
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := handler.usecase.ImportAppDatabase(userId, appDatabase, recordErrors, importOptions.DryRun)
+	handler.writeImportResult(context, result, err)
+}
+
+// importToolExport imports the CSV or JSON export of another time tracking tool. The format is detected from the
+// content.
+func (handler *importHandler) importToolExport(context *gin.Context, source string,
+	readCsv func(io.Reader, importer.FileOptions) ([]importer.Record, []importer.RecordError, error),
+	readJson func(io.Reader) ([]importer.Record, []importer.RecordError, error)) {
+	location, err := handler.getLocationFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fileOptions := importer.FileOptions{
+		DateFormat: importer.ParseFormat(context.Query("dateFormat")),
+		TimeFormat: importer.ParseFormat(context.Query("timeFormat")),
+		Location:   location,
+	}
+	userId, importOptions, ok := handler.getImportOptions(context)
+	if !ok {
+		return
+	}
+	file, ok := handler.openImportFile(context)
+	if !ok {
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("the import file cannot be read: %v", err)})
+		return
+	}
+	var records []importer.Record
+	var recordErrors []importer.RecordError
+	if importer.IsJson(content) {
+		records, recordErrors, err = readJson(bytes.NewReader(content))
+	} else {
+		records, recordErrors, err = readCsv(bytes.NewReader(content), fileOptions)
+	}
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	handler.importRecords(context, userId, source, records, recordErrors, importOptions)
+}
+
+// importRecords imports the records and writes the result.
+func (handler *importHandler) importRecords(context *gin.Context, userId uuid.UUID, source string, records []importer.Record,
+	recordErrors []importer.RecordError, options usecase.ImportOptions) {
+	result, err := handler.usecase.ImportTimeEntries(userId, source, records, recordErrors, options)
+	handler.writeImportResult(context, result, err)
+}
+
+// writeImportResult writes the result of an import. A failed import is answered with 400 and the errors.
+func (handler *importHandler) writeImportResult(context *gin.Context, result *usecase.ImportResult, err error) {
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dto := importResultDto{
+		DryRun:      result.DryRun,
+		Imported:    result.Imported,
+		Skipped:     result.Skipped,
+		NewProjects: append([]string{}, result.NewProjects...),
+		Errors:      []importErrorDto{},
+	}
+	for _, recordError := range result.Errors {
+		dto.Errors = append(dto.Errors, importErrorDto{Line: recordError.Line, Error: recordError.Message})
+	}
+	if !result.DryRun && len(result.Errors) > 0 {
+		context.JSON(http.StatusBadRequest, dto)
+		return
+	}
+	context.JSON(http.StatusOK, dto)
+}
+
+// openImportFile returns the uploaded form file "file" or the request body.
+func (handler *importHandler) openImportFile(context *gin.Context) (io.ReadCloser, bool) {
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, maxImportFileSize)
+	if !strings.HasPrefix(context.ContentType(), "multipart/form-data") {
+		return context.Request.Body, true
+	}
+	fileHeader, err := context.FormFile("file")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("the import file cannot be read: %v", err)})
+		return nil, false
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("the import file cannot be read: %v", err)})
+		return nil, false
+	}
+	return file, true
+}
+
+func (handler *importHandler) getCsvOptions(context *gin.Context) (importer.CsvOptions, error) {
+	var options importer.CsvOptions
+	var err error
+	options.Mapping, err = importer.ParseMapping(context.Query("mapping"))
+	if err != nil {
+		return options, err
+	}
+	switch delimiter := context.Query("delimiter"); {
+	case delimiter == "":
+	case delimiter == "tab":
+		options.Delimiter = '\t'
+	case utf8.RuneCountInString(delimiter) == 1:
+		options.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+		if options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' {
+			return options, fmt.Errorf("%q can not be used as delimiter", delimiter)
+		}
+	default:
+		return options, fmt.Errorf("the delimiter must be a single character or \"tab\"")
+	}
+	options.DateFormat = importer.ParseFormat(context.Query("dateFormat"))
+	options.TimeFormat = importer.ParseFormat(context.Query("timeFormat"))
+	if decimalComma := context.Query("decimalComma"); decimalComma != "" {
+		options.DecimalComma, err = strconv.ParseBool(decimalComma)
+		if err != nil {
+			return options, fmt.Errorf("decimalComma must be true or false")
+		}
+	}
+	options.Location, err = handler.getLocationFromQuery(context)
+	return options, err
+}
+
+// getImportOptions authenticates the user and reads the options of the import: with "dryRun=true" the entries are
+// only validated, with "teamId" new projects are created in the team, which only admins of the team may do.
+// If a line is invalid nothing is imported and the errors of all lines are returned. Entries which were imported
+// before are skipped.
+func (handler *importHandler) getImportOptions(context *gin.Context) (uuid.UUID, usecase.ImportOptions, bool) {
+	var options usecase.ImportOptions
+	if dryRun := context.Query("dryRun"); dryRun != "" {
+		var err error
+		options.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "dryRun must be true or false"})
+			return uuid.Nil, options, false
+		}
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, options, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, options, false
+	}
+	if teamIdParam := context.Query("teamId"); teamIdParam != "" {
+		teamId, err := uuid.FromString(teamIdParam)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid team id", teamIdParam)})
+			return uuid.Nil, options, false
+		}
+		if !handler.checkTeamAdmin(context, token, userId, teamId) {
+			return uuid.Nil, options, false
+		}
+		options.TeamID = &teamId
+	}
+	return userId, options, true
+}
+
+func (handler *importHandler) getLocationFromQuery(context *gin.Context) (*time.Location, error) {
+	timezone := context.Query("timezone")
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a valid time zone", timezone)
+	}
+	return location, nil
+}
+
+func (handler *importHandler) checkTeamAdmin(context *gin.Context, token AuthToken, userId uuid.UUID, teamId uuid.UUID) bool {
+	if handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to import projects into this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *importHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError), errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
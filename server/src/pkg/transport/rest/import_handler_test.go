@@ -0,0 +1,289 @@
+package rest
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_importHandler_ImportTimeEntriesFromCsv(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	addProject(t, handlerTest, "Kunde A", userId)
+	file := "Datum;Von;Bis;Projekt;Notiz\n" +
+		"04.09.2023;08:00;10:00;Kunde A;Workshop\n" +
+		"05.09.2023;08:00;09:30;Kunde B;\n"
+	query := url.Values{}
+	query.Set("mapping", "date=Datum,start=Von,end=Bis,project=Projekt,description=Notiz")
+	query.Set("delimiter", ";")
+	query.Set("dateFormat", "DD.MM.YYYY")
+	query.Set("timezone", "Europe/Berlin")
+	query.Set("dryRun", "true")
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/import/csv?"+query.Encode(), strings.NewReader(file))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "text/csv")
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var result importResultDto
+	err = json.Unmarshal(w.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, []string{"Kunde B"}, result.NewProjects)
+	timeEntries, err := handlerTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, timeEntries)
+
+	// The real run as form upload:
+	query.Del("dryRun")
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "timeentries.csv")
+	assert.Nil(t, err)
+	_, err = part.Write([]byte(file))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/import/csv?"+query.Encode(), &body)
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	timeEntries, err = handlerTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(timeEntries))
+}
+
+func Test_importHandler_ImportTimeEntriesFromCsvWithErrors(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	file := "date,start,end,project\n" +
+		"2023-09-04,08:00,10:00,project\n" +
+		"2023-09-05,8 Uhr,10:00,project\n"
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/import/csv", strings.NewReader(file))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	var result importResultDto
+	err = json.Unmarshal(w.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 1, len(result.Errors))
+	assert.Equal(t, 3, result.Errors[0].Line)
+	timeEntries, err := handlerTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, timeEntries)
+
+	for _, query := range []string{"mapping=user=User", "delimiter=;;", "dryRun=maybe", "timezone=Mars/Olympus"} {
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("POST", "/api/v1/import/csv?"+query, strings.NewReader(file))
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, query)
+	}
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/import/csv", strings.NewReader("date,start\n"))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_importHandler_ImportTimeEntriesFromToggl(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	report := `{"data": [{"id": 1001, "description": "Mockups", "start": "2023-09-04T08:00:00+02:00",
+		"end": "2023-09-04T10:30:00+02:00", "client": "ACME", "project": "Website", "is_billable": true, "tags": []}]}`
+	for _, expectedImported := range []int{1, 0} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/api/v1/import/toggl", strings.NewReader(report))
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+		var result importResultDto
+		err = json.Unmarshal(w.Body.Bytes(), &result)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedImported, result.Imported)
+		assert.Equal(t, 1-expectedImported, result.Skipped)
+	}
+	timeEntries, err := handlerTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timeEntries))
+
+	// The CSV report in the time zone of the user:
+	file := "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags\n" +
+		"Jane,jane@example.com,ACME,Website,,Review,No,2023-09-05,08:00:00,2023-09-05,09:00:00,01:00:00,\n"
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/import/toggl?timezone=Europe/Berlin", strings.NewReader(file))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	projects, err := handlerTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(projects))
+	assert.Equal(t, "ACME", projects[0].Client)
+
+	// Only team admins may import into a team:
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", otherUserId)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/import/toggl?teamId="+team.ID.String(), strings.NewReader(report))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+}
+
+func Test_importHandler_ImportTimeEntriesFromClockify(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	file := "Project,Client,Description,Task,User,Group,Email,Tags,Billable,Start Date,Start Time,End Date,End Time\n" +
+		"Website,ACME,Mockups,Design,Jane,,jane@example.com,meeting,Yes,04.09.2023,08:00,04.09.2023,10:30\n"
+	w := httptest.NewRecorder()
+	path := "/api/v1/import/clockify?dateFormat=DD.MM.YYYY&timeFormat=HH:mm&teamId=" + team.ID.String()
+	req, err := http.NewRequest("POST", path, strings.NewReader(file))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var result importResultDto
+	err = json.Unmarshal(w.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, []string{"Website"}, result.NewProjects)
+	projects, err := handlerTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(projects))
+	assert.Equal(t, team.ID, *projects[0].TeamID)
+	assert.True(t, projects[0].Billable)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/import/clockify", strings.NewReader("Project,Description\n"))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
+
+func Test_importHandler_ImportAppDatabase(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	projectId := uuid.Must(uuid.NewV1())
+	timeEntryId := uuid.Must(uuid.NewV1())
+	path := filepath.Join(t.TempDir(), "timeasy.db")
+	db, err := sql.Open("sqlite", path)
+	assert.Nil(t, err)
+	for _, statement := range []string{
+		"CREATE TABLE Projects (id TEXT, name TEXT, created INTEGER, updated INTEGER, deleted INTEGER DEFAULT 0)",
+		"CREATE TABLE TimeEntries (id TEXT, startTime INTEGER, endTime INTEGER, description TEXT, created INTEGER, " +
+			"updated INTEGER, projectId TEXT)",
+		"INSERT INTO Projects VALUES ('" + projectId.String() + "', 'Website', 1, 1, 0)",
+		"INSERT INTO TimeEntries VALUES ('" + timeEntryId.String() + "', 1693814400000, 1693823400000, 'Mockups', 1, 1, '" +
+			projectId.String() + "')",
+	} {
+		_, err = db.Exec(statement)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, db.Close())
+	file, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/import/appdatabase?dryRun=true", bytes.NewReader(file))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var result importResultDto
+	err = json.Unmarshal(w.Body.Bytes(), &result)
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, []string{"Website"}, result.NewProjects)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/import/appdatabase", bytes.NewReader(file))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	timeEntry, err := handlerTest.TimeEntryUsecase.GetTimeEntryById(timeEntryId)
+	assert.Nil(t, err)
+	assert.Equal(t, projectId, timeEntry.ProjectId)
+	assert.Equal(t, "Mockups", timeEntry.Description)
+
+	// Other files are rejected:
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/import/appdatabase", strings.NewReader("Date,Project\n"))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
@@ -0,0 +1,347 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/export"
+	"timeasy-server/pkg/invoice"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type InvoiceHandler interface {
+	CreateInvoice(context *gin.Context)
+	GetInvoices(context *gin.Context)
+	GetInvoiceById(context *gin.Context)
+	GetInvoicePdf(context *gin.Context)
+	CancelInvoice(context *gin.Context)
+}
+
+type invoiceHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.InvoiceUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewInvoiceHandler(tokenVerifier TokenVerifier, usecase usecase.InvoiceUsecase, teamUsecase usecase.TeamUsecase) InvoiceHandler {
+	return &invoiceHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+// invoiceInputDto selects the time entries of a client or project in the period from StartDate to EndDate (both
+// included). The rates are in cents per hour.
+type invoiceInputDto struct {
+	TeamId          *uuid.UUID       `json:"teamId"`
+	Client          string           `json:"client"`
+	ProjectId       *uuid.UUID       `json:"projectId"`
+	StartDate       string           `json:"startDate" binding:"required"`
+	EndDate         string           `json:"endDate" binding:"required"`
+	Currency        string           `json:"currency"`
+	HourlyRate      int64            `json:"hourlyRate"`
+	ProjectRates    []projectRateDto `json:"projectRates"`
+	RoundingMinutes int              `json:"roundingMinutes"`
+	RoundingMode    string           `json:"roundingMode"`
+}
+
+type projectRateDto struct {
+	ProjectId  uuid.UUID `json:"projectId" binding:"required"`
+	HourlyRate int64     `json:"hourlyRate"`
+}
+
+type invoiceDto struct {
+	Id              uuid.UUID
+	Number          string
+	Status          string
+	TeamId          *uuid.UUID `json:",omitempty"`
+	CreatedBy       uuid.UUID
+	CreatedAtUnix   int64
+	Client          string     `json:",omitempty"`
+	ProjectId       *uuid.UUID `json:",omitempty"`
+	StartDate       string
+	EndDate         string
+	Currency        string
+	HourlyRate      int64
+	RoundingMinutes int
+	RoundingMode    string
+	TotalMinutes    int64
+	TotalAmount     int64
+	// Lines and TimeEntryIds are only returned for a single invoice
+	Lines        []invoiceLineDto `json:",omitempty"`
+	TimeEntryIds []uuid.UUID      `json:",omitempty"`
+}
+
+type invoiceLineDto struct {
+	Position       int
+	ProjectId      uuid.UUID
+	ProjectName    string
+	Task           string
+	Date           string
+	TrackedMinutes int64
+	BilledMinutes  int64
+	HourlyRate     int64
+	Amount         int64
+	TimeEntryCount int
+}
+
+// CreateInvoice creates a numbered draft invoice of the billable, not yet invoiced time entries of the user or, with
+// "teamId", of the team. Only team admins may create the invoices of a team. The invoiced entries cannot be changed
+// until the invoice is cancelled.
+func (handler *invoiceHandler) CreateInvoice(context *gin.Context) {
+	var input invoiceInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	if input.TeamId != nil && !handler.checkTeamAdmin(context, token, userId, *input.TeamId) {
+		return
+	}
+	startDate, err := time.Parse(dateFormat, input.StartDate)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the start date in the format YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse(dateFormat, input.EndDate)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the end date in the format YYYY-MM-DD"})
+		return
+	}
+	request := usecase.InvoiceRequest{
+		Filter: model.InvoiceFilter{
+			TeamID:    input.TeamId,
+			UserID:    userId,
+			Client:    input.Client,
+			ProjectID: input.ProjectId,
+			From:      startDate,
+			To:        endDate.AddDate(0, 0, 1),
+		},
+		CreatedBy: userId,
+		Currency:  input.Currency,
+		Rates:     invoice.Rates{Default: input.HourlyRate, Projects: make(map[uuid.UUID]int64)},
+		Rounding:  invoice.Rounding{Minutes: input.RoundingMinutes, Mode: input.RoundingMode},
+	}
+	for _, projectRate := range input.ProjectRates {
+		request.Rates.Projects[projectRate.ProjectId] = projectRate.HourlyRate
+	}
+	newInvoice, err := handler.usecase.CreateInvoice(request, time.Now().UTC())
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, handler.createDtoFromInvoice(newInvoice, nil))
+}
+
+// GetInvoices returns the personal invoices of the user or, with the query parameter "teamId", the invoices of the
+// team, the latest first.
+func (handler *invoiceHandler) GetInvoices(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	var invoices []model.Invoice
+	var err error
+	if teamIdParam := context.Query("teamId"); teamIdParam != "" {
+		teamId, err := uuid.FromString(teamIdParam)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid team id", teamIdParam)})
+			return
+		}
+		if !handler.checkTeamAdmin(context, token, userId, teamId) {
+			return
+		}
+		invoices, err = handler.usecase.GetInvoicesOfTeam(teamId)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		invoices, err = handler.usecase.GetInvoicesOfUser(userId)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	dtos := []invoiceDto{}
+	for _, invoice := range invoices {
+		dtos = append(dtos, handler.createDtoFromInvoice(&invoice, nil))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+// GetInvoiceById returns the invoice with its lines and the ids of the invoiced time entries.
+func (handler *invoiceHandler) GetInvoiceById(context *gin.Context) {
+	invoice, ok := handler.getInvoice(context)
+	if !ok {
+		return
+	}
+	timeEntryIds, err := handler.usecase.GetTimeEntryIdsOfInvoice(invoice.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromInvoice(invoice, timeEntryIds))
+}
+
+// GetInvoicePdf returns the invoice as PDF, the query parameter "language" (en or de) selects the language.
+func (handler *invoiceHandler) GetInvoicePdf(context *gin.Context) {
+	language := context.Query("language")
+	if !export.IsKnownLanguage(language) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown language %v", language)})
+		return
+	}
+	invoice, ok := handler.getInvoice(context)
+	if !ok {
+		return
+	}
+	issuerName := invoice.IssuerID.String()
+	if invoice.TeamID != nil {
+		team, err := handler.teamUsecase.GetTeamById(*invoice.TeamID)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return
+		}
+		issuerName = team.Name1
+	}
+	var buffer bytes.Buffer
+	if err := export.WriteInvoicePdf(&buffer, *invoice, issuerName, language); err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"invoice-%v.pdf\"", invoice.Number))
+	context.Data(http.StatusOK, "application/pdf", buffer.Bytes())
+}
+
+// CancelInvoice cancels a draft invoice, its time entries can be changed and invoiced again.
+func (handler *invoiceHandler) CancelInvoice(context *gin.Context) {
+	invoice, ok := handler.getInvoice(context)
+	if !ok {
+		return
+	}
+	invoice, err := handler.usecase.CancelInvoice(invoice.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromInvoice(invoice, nil))
+}
+
+// getInvoice loads the invoice of the id parameter. Personal invoices are only accessible by their user, team
+// invoices by the admins of the team.
+func (handler *invoiceHandler) getInvoice(context *gin.Context) (*model.Invoice, bool) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return nil, false
+	}
+	id, err := uuid.FromString(context.Param("id"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify a valid id"})
+		return nil, false
+	}
+	invoice, err := handler.usecase.GetInvoiceById(id)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if invoice.TeamID != nil {
+		if !handler.checkTeamAdmin(context, token, userId, *invoice.TeamID) {
+			return nil, false
+		}
+	} else if invoice.IssuerID != userId {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to access this invoice"})
+		return nil, false
+	}
+	return invoice, true
+}
+
+func (handler *invoiceHandler) verifyToken(context *gin.Context) (AuthToken, uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	return token, userId, true
+}
+
+func (handler *invoiceHandler) checkTeamAdmin(context *gin.Context, token AuthToken, userId uuid.UUID, teamId uuid.UUID) bool {
+	if handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to manage the invoices of this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *invoiceHandler) createDtoFromInvoice(invoice *model.Invoice, timeEntryIds []uuid.UUID) invoiceDto {
+	dto := invoiceDto{
+		Id:              invoice.ID,
+		Number:          invoice.Number,
+		Status:          invoice.Status,
+		TeamId:          invoice.TeamID,
+		CreatedBy:       invoice.CreatedBy,
+		CreatedAtUnix:   invoice.CreatedAt.Unix(),
+		Client:          invoice.Client,
+		ProjectId:       invoice.ProjectID,
+		StartDate:       invoice.From.Format(dateFormat),
+		EndDate:         invoice.To.AddDate(0, 0, -1).Format(dateFormat),
+		Currency:        invoice.Currency,
+		HourlyRate:      invoice.HourlyRate,
+		RoundingMinutes: invoice.RoundingMinutes,
+		RoundingMode:    invoice.RoundingMode,
+		TotalMinutes:    invoice.TotalMinutes,
+		TotalAmount:     invoice.TotalAmount,
+		TimeEntryIds:    timeEntryIds,
+	}
+	for _, line := range invoice.Lines {
+		dto.Lines = append(dto.Lines, invoiceLineDto{
+			Position:       line.Position,
+			ProjectId:      line.ProjectID,
+			ProjectName:    line.ProjectName,
+			Task:           line.Task,
+			Date:           line.Day.Format(dateFormat),
+			TrackedMinutes: line.TrackedMinutes,
+			BilledMinutes:  line.BilledMinutes,
+			HourlyRate:     line.HourlyRate,
+			Amount:         line.Amount,
+			TimeEntryCount: line.TimeEntryCount,
+		})
+	}
+	return dto
+}
+
+func (handler *invoiceHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityIncompleteError), errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
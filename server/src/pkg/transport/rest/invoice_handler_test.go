@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_invoiceHandler_CreateInvoiceOfTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "Team", userId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	project := model.Project{Name: "Website", UserId: userId, Client: "ACME", Billable: true}
+	assert.Nil(t, handlerTest.ProjectUsecase.AddProject(&project))
+	assert.Nil(t, handlerTest.ProjectUsecase.AssignProjectToTeam(&project, &team))
+	timeEntry := addTimeEntryWithDuration(t, handlerTest, memberId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC),
+		90*time.Minute)
+	ownTimeEntry := addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC),
+		time.Hour)
+
+	input := invoiceInputDto{
+		TeamId:     &team.ID,
+		ProjectId:  &project.ID,
+		StartDate:  "2023-09-01",
+		EndDate:    "2023-09-30",
+		Currency:   "USD",
+		HourlyRate: 10000,
+	}
+	inputJson, err := json.Marshal(input)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/invoices", bytes.NewReader(inputJson))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 201, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var invoice invoiceDto
+	err = json.Unmarshal(w.Body.Bytes(), &invoice)
+	assert.Nil(t, err)
+	assert.Equal(t, model.InvoiceStatusDraft, invoice.Status)
+	assert.True(t, strings.HasSuffix(invoice.Number, "-0001"))
+	assert.Equal(t, "2023-09-30", invoice.EndDate)
+	assert.Equal(t, 2, len(invoice.Lines))
+	assert.Equal(t, "2023-09-04", invoice.Lines[0].Date)
+	assert.Equal(t, int64(15000), invoice.Lines[0].Amount)
+	assert.Equal(t, int64(25000), invoice.TotalAmount)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/invoices/"+invoice.Id.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &invoice)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(invoice.TimeEntryIds))
+	assert.Contains(t, invoice.TimeEntryIds, timeEntry.ID)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/invoices/"+invoice.Id.String()+"/pdf?language=de", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "%PDF-"))
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/invoices?teamId="+team.ID.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var invoices []invoiceDto
+	err = json.Unmarshal(w.Body.Bytes(), &invoices)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(invoices))
+
+	// The invoiced entries are locked:
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("DELETE", "/api/v1/timeentries/"+ownTimeEntry.ID.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/invoices", bytes.NewReader(inputJson))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/invoices/"+invoice.Id.String()+"/cancel", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &invoice)
+	assert.Nil(t, err)
+	assert.Equal(t, model.InvoiceStatusCancelled, invoice.Status)
+}
+
+func Test_invoiceHandler_CreateInvoiceOfTeamAsMember(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	ownerId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "Team", ownerId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	inputJson, err := json.Marshal(invoiceInputDto{TeamId: &team.ID, Client: "ACME", StartDate: "2023-09-01",
+		EndDate: "2023-09-30"})
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/v1/invoices", bytes.NewReader(inputJson))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/invoices?teamId="+team.ID.String(), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// Invalid dates:
+	inputJson, err = json.Marshal(invoiceInputDto{Client: "ACME", StartDate: "01.09.2023", EndDate: "2023-09-30"})
+	assert.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/invoices", bytes.NewReader(inputJson))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+}
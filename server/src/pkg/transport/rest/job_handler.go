@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/job"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type JobHandler interface {
+	GetJobs(context *gin.Context)
+	GetJobRuns(context *gin.Context)
+}
+
+type jobHandler struct {
+	tokenVerifier TokenVerifier
+	scheduler     job.Scheduler
+}
+
+func NewJobHandler(tokenVerifier TokenVerifier, scheduler job.Scheduler) JobHandler {
+	return &jobHandler{
+		tokenVerifier: tokenVerifier,
+		scheduler:     scheduler,
+	}
+}
+
+const defaultJobRunLimit = 50
+
+type jobDto struct {
+	Name        string
+	Schedule    string
+	NextRunUnix int64      `json:",omitempty"`
+	LastRun     *jobRunDto `json:",omitempty"`
+}
+
+type jobRunDto struct {
+	Id              uuid.UUID
+	Name            string
+	Instance        string
+	ScheduledAtUnix int64
+	StartedAtUnix   int64
+	FinishedAtUnix  int64 `json:",omitempty"`
+	Status          string
+	Error           string `json:",omitempty"`
+}
+
+// GetJobs returns the background jobs with their next and last run. Only global admins may see them.
+func (handler *jobHandler) GetJobs(context *gin.Context) {
+	if !handler.checkAdmin(context) {
+		return
+	}
+	dtos := []jobDto{}
+	for _, jobInfo := range handler.scheduler.GetJobs() {
+		dto := jobDto{
+			Name:     jobInfo.Name,
+			Schedule: jobInfo.Schedule,
+		}
+		if !jobInfo.NextRun.IsZero() {
+			dto.NextRunUnix = jobInfo.NextRun.Unix()
+		}
+		jobRuns, err := handler.scheduler.GetJobRuns(jobInfo.Name, 1)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(jobRuns) > 0 {
+			lastRun := handler.createDtoFromJobRun(&jobRuns[0])
+			dto.LastRun = &lastRun
+		}
+		dtos = append(dtos, dto)
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+// GetJobRuns returns the history of a job, the latest run first. The number of runs can be limited with the query
+// parameter "limit".
+func (handler *jobHandler) GetJobRuns(context *gin.Context) {
+	if !handler.checkAdmin(context) {
+		return
+	}
+	limit := defaultJobRunLimit
+	if limitParam := context.Query("limit"); limitParam != "" {
+		parsedLimit, err := strconv.Atoi(limitParam)
+		if err != nil || parsedLimit < 1 {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "please specify a positive limit"})
+			return
+		}
+		limit = parsedLimit
+	}
+	jobRuns, err := handler.scheduler.GetJobRuns(context.Param("name"), limit)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []jobRunDto{}
+	for _, jobRun := range jobRuns {
+		dtos = append(dtos, handler.createDtoFromJobRun(&jobRun))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *jobHandler) checkAdmin(context *gin.Context) bool {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return false
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "only admins may see the background jobs"})
+		return false
+	}
+	return true
+}
+
+func (handler *jobHandler) createDtoFromJobRun(jobRun *model.JobRun) jobRunDto {
+	dto := jobRunDto{
+		Id:              jobRun.ID,
+		Name:            jobRun.Name,
+		Instance:        jobRun.Instance,
+		ScheduledAtUnix: jobRun.ScheduledAt.Unix(),
+		StartedAtUnix:   jobRun.StartedAt.Unix(),
+		Status:          jobRun.Status,
+		Error:           jobRun.Error,
+	}
+	if jobRun.FinishedAt != nil {
+		dto.FinishedAtUnix = jobRun.FinishedAt.Unix()
+	}
+	return dto
+}
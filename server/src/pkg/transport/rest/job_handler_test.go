@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_jobHandler_GetJobs(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	err = handlerTest.Scheduler.Register("cleanup", "30 2 * * *", func(ctx context.Context, scheduledAt time.Time) error {
+		return nil
+	})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/jobs", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var jobs []jobDto
+	err = json.Unmarshal(w.Body.Bytes(), &jobs)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(jobs))
+	assert.Equal(t, "cleanup", jobs[0].Name)
+	assert.Equal(t, "30 2 * * *", jobs[0].Schedule)
+	assert.Nil(t, jobs[0].LastRun)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/jobs/cleanup/runs?limit=10", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var jobRuns []jobRunDto
+	err = json.Unmarshal(w.Body.Bytes(), &jobRuns)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(jobRuns))
+}
+
+func Test_jobHandler_GetJobsFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/jobs", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type MissingTimeHandler interface {
+	GetMissingTimes(context *gin.Context)
+	GetMissingTimesOfTeam(context *gin.Context)
+}
+
+type missingTimeHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.MissingTimeUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewMissingTimeHandler(tokenVerifier TokenVerifier, usecase usecase.MissingTimeUsecase, teamUsecase usecase.TeamUsecase) MissingTimeHandler {
+	return &missingTimeHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type missingTimeDto struct {
+	UserId          uuid.UUID
+	Date            string
+	Reason          string
+	TargetMinutes   int64
+	RecordedMinutes int64
+	TimeEntryId     *uuid.UUID `json:",omitempty"`
+}
+
+// GetMissingTimes returns the missing times of the authenticated user, so that users can fix gaps themselves.
+func (handler *missingTimeHandler) GetMissingTimes(context *gin.Context) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	missingTimes, err := handler.usecase.GetMissingTimesOfUser(userId, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.convertMissingTimesToDtos(missingTimes))
+}
+
+// GetMissingTimesOfTeam returns the missing times of all members of the team. Only admins of the team may see them.
+func (handler *missingTimeHandler) GetMissingTimesOfTeam(context *gin.Context) {
+	teamId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to see the missing times of this team"})
+			return
+		}
+	}
+	missingTimes, err := handler.usecase.GetMissingTimesOfTeam(teamId, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.convertMissingTimesToDtos(missingTimes))
+}
+
+func (handler *missingTimeHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *missingTimeHandler) convertMissingTimesToDtos(missingTimes []model.MissingTime) []missingTimeDto {
+	dtos := []missingTimeDto{}
+	for _, missingTime := range missingTimes {
+		dtos = append(dtos, missingTimeDto{
+			UserId:          missingTime.UserID,
+			Date:            missingTime.Day.Format(dateFormat),
+			Reason:          missingTime.Reason,
+			TargetMinutes:   int64(missingTime.Target.Minutes()),
+			RecordedMinutes: int64(missingTime.Recorded.Minutes()),
+			TimeEntryId:     missingTime.TimeEntryID,
+		})
+	}
+	return dtos
+}
+
+func (handler *missingTimeHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
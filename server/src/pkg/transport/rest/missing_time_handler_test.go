@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_missingTimeHandler_GetMissingTimes(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	addWorkingTimeModel(t, handlerTest, userId, team, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	project := addProject(t, handlerTest, "project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 8*time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 6*time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/missingtimes?from=2023-09-04&to=2023-09-05", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var missingTimes []missingTimeDto
+	err = json.Unmarshal(w.Body.Bytes(), &missingTimes)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(missingTimes))
+	assert.Equal(t, "2023-09-05", missingTimes[0].Date)
+	assert.Equal(t, model.MissingTimeBelowTarget, missingTimes[0].Reason)
+	assert.Equal(t, int64(480), missingTimes[0].TargetMinutes)
+	assert.Equal(t, int64(360), missingTimes[0].RecordedMinutes)
+}
+
+func Test_missingTimeHandler_GetMissingTimesOfTeamFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/missingtimes?from=2023-09-01", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_missingTimeHandler_GetMissingTimesOfTeamAsAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	addWorkingTimeModel(t, handlerTest, memberId, team, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/missingtimes?from=2023-09-04&to=2023-09-04", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var missingTimes []missingTimeDto
+	err = json.Unmarshal(w.Body.Bytes(), &missingTimes)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(missingTimes))
+	assert.Equal(t, memberId, missingTimes[0].UserId)
+	assert.Equal(t, model.MissingTimeNoTimeRecorded, missingTimes[0].Reason)
+}
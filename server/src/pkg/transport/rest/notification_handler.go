@@ -0,0 +1,292 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type NotificationHandler interface {
+	GetNotifications(context *gin.Context)
+	MarkNotificationAsRead(context *gin.Context)
+	GetNotificationSettings(context *gin.Context)
+	SetNotificationSettings(context *gin.Context)
+	GetNotificationTemplates(context *gin.Context)
+	SetNotificationTemplate(context *gin.Context)
+	ResetNotificationTemplate(context *gin.Context)
+}
+
+type notificationHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(tokenVerifier TokenVerifier, usecase usecase.NotificationUsecase) NotificationHandler {
+	return &notificationHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+	}
+}
+
+type notificationDto struct {
+	Id            uuid.UUID
+	Type          string
+	Subject       string
+	Body          string
+	CreatedAtUnix int64
+	ReadAtUnix    int64 `json:",omitempty"`
+}
+
+type notificationSettingsDto struct {
+	// Channels are the enabled channels: EMAIL, WEBHOOK and INBOX
+	Channels   []string `json:"channels"`
+	MutedTypes []string `json:"mutedTypes"`
+	Email      string   `json:"email"`
+	WebhookUrl string   `json:"webhookUrl"`
+}
+
+type notificationTemplateDto struct {
+	Type    string `json:"type"`
+	Subject string `json:"subject" binding:"required"`
+	Body    string `json:"body" binding:"required"`
+}
+
+// GetNotifications returns the inbox of the user. With the query parameter "unread=true" only unread notifications
+// are returned.
+func (handler *notificationHandler) GetNotifications(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	notifications, err := handler.usecase.GetNotificationsOfUser(userId, context.Query("unread") == "true")
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []notificationDto{}
+	for _, notification := range notifications {
+		dtos = append(dtos, handler.createDtoFromNotification(&notification))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *notificationHandler) MarkNotificationAsRead(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	id, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	notification, err := handler.usecase.GetNotificationById(id)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	if notification.UserID != userId {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you can only read your own notifications"})
+		return
+	}
+	notification, err = handler.usecase.MarkNotificationAsRead(id)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromNotification(notification))
+}
+
+func (handler *notificationHandler) GetNotificationSettings(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	settings, err := handler.usecase.GetNotificationSettings(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromSettings(settings))
+}
+
+func (handler *notificationHandler) SetNotificationSettings(context *gin.Context) {
+	userId, ok := handler.getUserId(context)
+	if !ok {
+		return
+	}
+	var input notificationSettingsDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	settings := model.NotificationSettings{
+		UserID:     userId,
+		Channels:   model.StringList(input.Channels),
+		MutedTypes: model.StringList(input.MutedTypes),
+		Email:      input.Email,
+		WebhookURL: input.WebhookUrl,
+	}
+	err := handler.usecase.SetNotificationSettings(&settings)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromSettings(&settings))
+}
+
+// GetNotificationTemplates returns the templates of all notification types. Only global admins may see them.
+func (handler *notificationHandler) GetNotificationTemplates(context *gin.Context) {
+	if !handler.checkAdmin(context) {
+		return
+	}
+	templates, err := handler.usecase.GetNotificationTemplates()
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []notificationTemplateDto{}
+	for _, template := range templates {
+		dtos = append(dtos, notificationTemplateDto{
+			Type:    template.Type,
+			Subject: template.Subject,
+			Body:    template.Body,
+		})
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+// SetNotificationTemplate replaces the built-in template of a notification type. Subject and body are go text
+// templates, the available values depend on the type.
+func (handler *notificationHandler) SetNotificationTemplate(context *gin.Context) {
+	if !handler.checkAdmin(context) {
+		return
+	}
+	var input notificationTemplateDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	template := model.NotificationTemplate{
+		Type:    context.Param("type"),
+		Subject: input.Subject,
+		Body:    input.Body,
+	}
+	err := handler.usecase.SetNotificationTemplate(&template)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	input.Type = template.Type
+	context.JSON(http.StatusOK, input)
+}
+
+// ResetNotificationTemplate restores the built-in template of a notification type.
+func (handler *notificationHandler) ResetNotificationTemplate(context *gin.Context) {
+	if !handler.checkAdmin(context) {
+		return
+	}
+	err := handler.usecase.ResetNotificationTemplate(context.Param("type"))
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+// getUserId verifies the token and returns the id of the user. If something is wrong the error response is already
+// written.
+func (handler *notificationHandler) getUserId(context *gin.Context) (uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	return userId, true
+}
+
+func (handler *notificationHandler) checkAdmin(context *gin.Context) bool {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return false
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "only admins may manage the notification templates"})
+		return false
+	}
+	return true
+}
+
+func (handler *notificationHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *notificationHandler) createDtoFromNotification(notification *model.Notification) notificationDto {
+	dto := notificationDto{
+		Id:            notification.ID,
+		Type:          notification.Type,
+		Subject:       notification.Subject,
+		Body:          notification.Body,
+		CreatedAtUnix: notification.CreatedAt.Unix(),
+	}
+	if notification.ReadAt != nil {
+		dto.ReadAtUnix = notification.ReadAt.Unix()
+	}
+	return dto
+}
+
+func (handler *notificationHandler) createDtoFromSettings(settings *model.NotificationSettings) notificationSettingsDto {
+	dto := notificationSettingsDto{
+		Channels:   []string(settings.Channels),
+		MutedTypes: []string(settings.MutedTypes),
+		Email:      settings.Email,
+		WebhookUrl: settings.WebhookURL,
+	}
+	if dto.Channels == nil {
+		dto.Channels = []string{}
+	}
+	if dto.MutedTypes == nil {
+		dto.MutedTypes = []string{}
+	}
+	return dto
+}
+
+func (handler *notificationHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
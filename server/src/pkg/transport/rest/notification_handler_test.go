@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_notificationHandler_GetNotificationsAndMarkAsRead(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	err = handlerTest.NotificationUsecase.Notify(userId, model.NotificationRunningTimerFlagged, map[string]string{"StartTime": "2023-09-05 08:00 UTC"})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/notifications?unread=true", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var notifications []notificationDto
+	err = json.Unmarshal(w.Body.Bytes(), &notifications)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, model.NotificationRunningTimerFlagged, notifications[0].Type)
+	assert.Contains(t, notifications[0].Body, "2023-09-05 08:00 UTC")
+	assert.Equal(t, int64(0), notifications[0].ReadAtUnix)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("PUT", fmt.Sprintf("/api/v1/notifications/%v/read", notifications[0].Id), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/notifications?unread=true", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &notifications)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(notifications))
+}
+
+func Test_notificationHandler_MarkNotificationOfOtherUserAsReadFails(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	err = handlerTest.NotificationUsecase.Notify(otherUserId, model.NotificationRunningTimerFlagged, map[string]string{})
+	assert.Nil(t, err)
+	notifications, err := handlerTest.NotificationUsecase.GetNotificationsOfUser(otherUserId, false)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/notifications/%v/read", notifications[0].ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func Test_notificationHandler_SetNotificationSettings(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/notificationsettings", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var settings notificationSettingsDto
+	err = json.Unmarshal(w.Body.Bytes(), &settings)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{model.NotificationChannelInbox}, settings.Channels)
+
+	w = httptest.NewRecorder()
+	reader := strings.NewReader("{\"channels\": [\"INBOX\", \"WEBHOOK\"], \"mutedTypes\": [\"ADDED_TO_TEAM\"], \"webhookUrl\": \"https://example.com/hook\"}")
+	req, err = http.NewRequest("PUT", "/api/v1/notificationsettings", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/notificationsettings", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	err = json.Unmarshal(w.Body.Bytes(), &settings)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{model.NotificationChannelInbox, model.NotificationChannelWebhook}, settings.Channels)
+	assert.Equal(t, []string{model.NotificationAddedToTeam}, settings.MutedTypes)
+	assert.Equal(t, "https://example.com/hook", settings.WebhookUrl)
+
+	w = httptest.NewRecorder()
+	reader = strings.NewReader("{\"channels\": [\"PIGEON\"]}")
+	req, err = http.NewRequest("PUT", "/api/v1/notificationsettings", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func Test_notificationHandler_SetNotificationTemplateFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"subject\": \"Approved\", \"body\": \"Well done!\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/notificationtemplates/%v", model.NotificationTimesheetApproved), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func Test_notificationHandler_SetNotificationTemplate(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(true, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"subject\": \"Approved: {{.StartDate}}\", \"body\": \"Well done!\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/notificationtemplates/%v", model.NotificationTimesheetApproved), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/notificationtemplates", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var templates []notificationTemplateDto
+	err = json.Unmarshal(w.Body.Bytes(), &templates)
+	assert.Nil(t, err)
+	found := false
+	for _, template := range templates {
+		if template.Type == model.NotificationTimesheetApproved {
+			found = true
+			assert.Equal(t, "Approved: {{.StartDate}}", template.Subject)
+		}
+	}
+	assert.True(t, found)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/notificationtemplates/%v", model.NotificationTimesheetApproved), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
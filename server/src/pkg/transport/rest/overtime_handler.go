@@ -0,0 +1,366 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type OvertimeHandler interface {
+	GetOvertimeBalance(context *gin.Context)
+	GetOvertimeBalanceOfUser(context *gin.Context)
+	GetOvertimeAccountOfUser(context *gin.Context)
+	SetOvertimeAccountOfUser(context *gin.Context)
+	GetOvertimeCorrectionsOfUser(context *gin.Context)
+	AddOvertimeCorrection(context *gin.Context)
+	DeleteOvertimeCorrection(context *gin.Context)
+}
+
+type overtimeHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.OvertimeUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewOvertimeHandler(tokenVerifier TokenVerifier, usecase usecase.OvertimeUsecase, teamUsecase usecase.TeamUsecase) OvertimeHandler {
+	return &overtimeHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type overtimeAccountInputDto struct {
+	// StartDate in the format YYYY-MM-DD
+	StartDate             string `json:"startDate" binding:"required"`
+	OpeningBalanceMinutes int    `json:"openingBalanceMinutes"`
+}
+
+type overtimeAccountDto struct {
+	Id     uuid.UUID
+	UserId uuid.UUID
+	overtimeAccountInputDto
+}
+
+type overtimeCorrectionInputDto struct {
+	// Date in the format YYYY-MM-DD
+	Date    string `json:"date" binding:"required"`
+	Minutes int    `json:"minutes" binding:"required"`
+	Type    string `json:"type" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+type overtimeCorrectionDto struct {
+	Id        uuid.UUID
+	UserId    uuid.UUID
+	CreatedBy uuid.UUID
+	overtimeCorrectionInputDto
+}
+
+type overtimeBalanceDto struct {
+	UserId                uuid.UUID
+	StartDate             string
+	EndDate               string
+	OpeningBalanceMinutes int64
+	WorkedMinutes         int64
+	TargetMinutes         int64
+	CorrectionMinutes     int64
+	BalanceMinutes        int64
+	Days                  []dailyOvertimeDto
+}
+
+type dailyOvertimeDto struct {
+	Date              string
+	WorkedMinutes     int64
+	TargetMinutes     int64
+	CorrectionMinutes int64 `json:",omitempty"`
+	BalanceMinutes    int64
+}
+
+// GetOvertimeBalance returns the overtime balance of the authenticated user.
+func (handler *overtimeHandler) GetOvertimeBalance(context *gin.Context) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	handler.writeOvertimeBalance(context, userId, from, to)
+}
+
+func (handler *overtimeHandler) GetOvertimeBalanceOfUser(context *gin.Context) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, _, ok := handler.checkAccessToUser(context, true)
+	if !ok {
+		return
+	}
+	handler.writeOvertimeBalance(context, userId, from, to)
+}
+
+func (handler *overtimeHandler) GetOvertimeAccountOfUser(context *gin.Context) {
+	userId, _, ok := handler.checkAccessToUser(context, true)
+	if !ok {
+		return
+	}
+	account, err := handler.usecase.GetOvertimeAccount(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromOvertimeAccount(account))
+}
+
+func (handler *overtimeHandler) SetOvertimeAccountOfUser(context *gin.Context) {
+	userId, _, ok := handler.checkAccessToUser(context, false)
+	if !ok {
+		return
+	}
+	var input overtimeAccountInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	startDate, err := time.Parse(dateFormat, input.StartDate)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the start date in the format YYYY-MM-DD"})
+		return
+	}
+	account := model.OvertimeAccount{
+		UserID:                userId,
+		StartDate:             startDate,
+		OpeningBalanceMinutes: input.OpeningBalanceMinutes,
+	}
+	err = handler.usecase.SetOvertimeAccount(&account)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromOvertimeAccount(&account))
+}
+
+func (handler *overtimeHandler) GetOvertimeCorrectionsOfUser(context *gin.Context) {
+	userId, _, ok := handler.checkAccessToUser(context, true)
+	if !ok {
+		return
+	}
+	corrections, err := handler.usecase.GetOvertimeCorrectionsOfUser(userId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []overtimeCorrectionDto{}
+	for _, correction := range corrections {
+		dtos = append(dtos, handler.createDtoFromOvertimeCorrection(&correction))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *overtimeHandler) AddOvertimeCorrection(context *gin.Context) {
+	userId, authUserId, ok := handler.checkAccessToUser(context, false)
+	if !ok {
+		return
+	}
+	var input overtimeCorrectionInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := time.Parse(dateFormat, input.Date)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the date in the format YYYY-MM-DD"})
+		return
+	}
+	correction := model.OvertimeCorrection{
+		UserID:    userId,
+		Date:      date,
+		Minutes:   input.Minutes,
+		Type:      input.Type,
+		Comment:   input.Comment,
+		CreatedBy: authUserId,
+	}
+	err = handler.usecase.AddOvertimeCorrection(&correction)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromOvertimeCorrection(&correction))
+}
+
+func (handler *overtimeHandler) DeleteOvertimeCorrection(context *gin.Context) {
+	userId, _, ok := handler.checkAccessToUser(context, false)
+	if !ok {
+		return
+	}
+	correctionId, err := handler.getIdParam(context, "correctionId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	correction, err := handler.usecase.GetOvertimeCorrectionById(correctionId)
+	if err != nil || correction.UserID != userId {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("overtime correction with id %v not found", correctionId)})
+		return
+	}
+	err = handler.usecase.DeleteOvertimeCorrection(correctionId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("overtime correction %v deleted", correctionId)})
+}
+
+func (handler *overtimeHandler) writeOvertimeBalance(context *gin.Context, userId uuid.UUID, from time.Time, to time.Time) {
+	balance, err := handler.usecase.GetOvertimeBalance(userId, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromOvertimeBalance(balance))
+}
+
+// checkAccessToUser reads the team and the user from the request path and checks if the authenticated user may access
+// the overtime account of the user. Managers of the team may read it, only team admins may change it. Global admins
+// may do both. It returns the user of the path and the authenticated user. If the access is denied the error response
+// is already written.
+func (handler *overtimeHandler) checkAccessToUser(context *gin.Context, readOnly bool) (uuid.UUID, uuid.UUID, bool) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	userId, err := handler.getIdParam(context, "userId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !handler.teamUsecase.DoesUserBelongToTeam(userId, teamId) {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("user %v is not a member of team %v", userId, teamId)})
+		return uuid.Nil, uuid.Nil, false
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	authUserId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	if readOnly && handler.teamUsecase.IsUserManagerInTeam(authUserId, teamId) {
+		return userId, authUserId, true
+	}
+	if handler.teamUsecase.IsUserAdminInTeam(authUserId, teamId) {
+		return userId, authUserId, true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to manage the overtime account of this user"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userId, authUserId, true
+}
+
+func (handler *overtimeHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *overtimeHandler) createDtoFromOvertimeAccount(account *model.OvertimeAccount) overtimeAccountDto {
+	dto := overtimeAccountDto{
+		Id:     account.ID,
+		UserId: account.UserID,
+	}
+	dto.StartDate = account.StartDate.Format(dateFormat)
+	dto.OpeningBalanceMinutes = account.OpeningBalanceMinutes
+	return dto
+}
+
+func (handler *overtimeHandler) createDtoFromOvertimeCorrection(correction *model.OvertimeCorrection) overtimeCorrectionDto {
+	dto := overtimeCorrectionDto{
+		Id:        correction.ID,
+		UserId:    correction.UserID,
+		CreatedBy: correction.CreatedBy,
+	}
+	dto.Date = correction.Date.Format(dateFormat)
+	dto.Minutes = correction.Minutes
+	dto.Type = correction.Type
+	dto.Comment = correction.Comment
+	return dto
+}
+
+func (handler *overtimeHandler) createDtoFromOvertimeBalance(balance *model.OvertimeBalance) overtimeBalanceDto {
+	dto := overtimeBalanceDto{
+		UserId:    balance.UserID,
+		StartDate: balance.StartDate.Format(dateFormat),
+		// The end of the range is exclusive, so we show the last day that is included:
+		EndDate:               balance.EndDate.AddDate(0, 0, -1).Format(dateFormat),
+		OpeningBalanceMinutes: int64(balance.OpeningBalance.Minutes()),
+		WorkedMinutes:         int64(balance.Worked.Minutes()),
+		TargetMinutes:         int64(balance.Target.Minutes()),
+		CorrectionMinutes:     int64(balance.Corrections.Minutes()),
+		BalanceMinutes:        int64(balance.Balance.Minutes()),
+		Days:                  []dailyOvertimeDto{},
+	}
+	for _, day := range balance.Days {
+		dto.Days = append(dto.Days, dailyOvertimeDto{
+			Date:              day.Day.Format(dateFormat),
+			WorkedMinutes:     int64(day.Worked.Minutes()),
+			TargetMinutes:     int64(day.Target.Minutes()),
+			CorrectionMinutes: int64(day.Correction.Minutes()),
+			BalanceMinutes:    int64(day.Balance.Minutes()),
+		})
+	}
+	return dto
+}
+
+func (handler *overtimeHandler) getIdParam(context *gin.Context, paramName string) (uuid.UUID, error) {
+	id := context.Param(paramName)
+	if id == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid %v", paramName)
+	}
+	result, err := uuid.FromString(id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_overtimeHandler_SetOvertimeAccountAndAddCorrection(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"startDate\": \"2023-09-01\", \"openingBalanceMinutes\": 300}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/users/%v/overtimeaccount", team.ID, memberId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	reader = strings.NewReader("{\"date\": \"2023-09-29\", \"minutes\": -240, \"type\": \"PAYOUT\", \"comment\": \"paid with salary\"}")
+	req, err = http.NewRequest("POST", fmt.Sprintf("/api/v1/teams/%v/users/%v/overtimecorrections", team.ID, memberId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var correction overtimeCorrectionDto
+	err = json.Unmarshal(w.Body.Bytes(), &correction)
+	assert.Nil(t, err)
+	assert.Equal(t, memberId, correction.UserId)
+	assert.Equal(t, userId, correction.CreatedBy)
+	assert.Equal(t, -240, correction.Minutes)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/users/%v/overtime?to=2023-09-30", team.ID, memberId), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var balance overtimeBalanceDto
+	err = json.Unmarshal(w.Body.Bytes(), &balance)
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-09-01", balance.StartDate)
+	assert.Equal(t, "2023-09-30", balance.EndDate)
+	assert.Equal(t, int64(300), balance.OpeningBalanceMinutes)
+	assert.Equal(t, int64(-240), balance.CorrectionMinutes)
+	assert.Equal(t, int64(60), balance.BalanceMinutes)
+	assert.Equal(t, 30, len(balance.Days))
+}
+
+func Test_overtimeHandler_AddCorrectionFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+	err = handlerTest.OvertimeUsecase.SetOvertimeAccount(&model.OvertimeAccount{
+		UserID:    userId,
+		StartDate: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"date\": \"2023-09-29\", \"minutes\": 600, \"type\": \"OTHER\"}")
+	req, err := http.NewRequest("POST", fmt.Sprintf("/api/v1/teams/%v/users/%v/overtimecorrections", team.ID, userId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	// As manager the user may read the account:
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/users/%v/overtimeaccount", team.ID, userId), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_overtimeHandler_GetOwnOvertimeBalance(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	addWorkingTimeModel(t, handlerTest, userId, team, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err = handlerTest.OvertimeUsecase.SetOvertimeAccount(&model.OvertimeAccount{
+		UserID:    userId,
+		StartDate: time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	project := addProject(t, handlerTest, "project", userId)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 10*time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/v1/overtime?from=2023-09-04&to=2023-09-04", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var balance overtimeBalanceDto
+	err = json.Unmarshal(w.Body.Bytes(), &balance)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(600), balance.WorkedMinutes)
+	assert.Equal(t, int64(480), balance.TargetMinutes)
+	assert.Equal(t, int64(120), balance.BalanceMinutes)
+	assert.Equal(t, 1, len(balance.Days))
+}
@@ -0,0 +1,270 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/export"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+const monthFormat = "2006-01"
+
+type PdfReportHandler interface {
+	GetMonthlyTimesheet(context *gin.Context)
+	GetReportTemplate(context *gin.Context)
+	SetReportTemplate(context *gin.Context)
+	ResetReportTemplate(context *gin.Context)
+}
+
+type pdfReportHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.PdfReportUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewPdfReportHandler(tokenVerifier TokenVerifier, usecase usecase.PdfReportUsecase, teamUsecase usecase.TeamUsecase) PdfReportHandler {
+	return &pdfReportHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type reportTemplateDto struct {
+	Title           string   `json:"title"`
+	Client          string   `json:"client"`
+	Layout          string   `json:"layout"`
+	Language        string   `json:"language"`
+	HeaderText      string   `json:"headerText"`
+	FooterText      string   `json:"footerText"`
+	SignatureLabels []string `json:"signatureLabels"`
+}
+
+// GetMonthlyTimesheet returns the timesheet of the month (query parameter "month" in the format YYYY-MM) as PDF.
+// The timesheet is created either for a user (query parameter "userId") or for a project (query parameter
+// "projectId") of the team. Users may get their own timesheet, managers of the team all timesheets.
+// The optional query parameter "timezone" (e.g. Europe/Berlin) defines the time zone of the times.
+func (handler *pdfReportHandler) GetMonthlyTimesheet(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	month, err := time.Parse(monthFormat, context.Query("month"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the month in the format YYYY-MM"})
+		return
+	}
+	var userId, projectId *uuid.UUID
+	if context.Query("userId") != "" {
+		id, err := uuid.FromString(context.Query("userId"))
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid id", context.Query("userId"))})
+			return
+		}
+		userId = &id
+	}
+	if context.Query("projectId") != "" {
+		id, err := uuid.FromString(context.Query("projectId"))
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid id", context.Query("projectId"))})
+			return
+		}
+		projectId = &id
+	}
+	location := time.UTC
+	if timezone := context.Query("timezone"); timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid time zone", timezone)})
+			return
+		}
+	}
+
+	token, authenticatedUserId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	if userId == nil || *userId != authenticatedUserId {
+		if !handler.checkTeamManager(context, token, authenticatedUserId, teamId) {
+			return
+		}
+	}
+	timesheet, err := handler.usecase.GetMonthlyTimesheet(teamId, month, userId, projectId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	var buffer bytes.Buffer
+	if err := export.WriteTimesheetPdf(&buffer, *timesheet, location); err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"timesheet-%v.pdf\"", month.Format(monthFormat)))
+	context.Data(http.StatusOK, "application/pdf", buffer.Bytes())
+}
+
+// GetReportTemplate returns the template of the PDF timesheets of the team. Managers of the team may see it.
+func (handler *pdfReportHandler) GetReportTemplate(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	if !handler.checkTeamManager(context, token, userId, teamId) {
+		return
+	}
+	template, err := handler.usecase.GetReportTemplate(teamId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromTemplate(template))
+}
+
+// SetReportTemplate customizes the PDF timesheets of the team. Only admins of the team may change the template.
+func (handler *pdfReportHandler) SetReportTemplate(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var input reportTemplateDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	if !handler.checkTeamAdmin(context, token, userId, teamId) {
+		return
+	}
+	template := model.ReportTemplate{
+		TeamID:          teamId,
+		Title:           input.Title,
+		Client:          input.Client,
+		Layout:          input.Layout,
+		Language:        input.Language,
+		HeaderText:      input.HeaderText,
+		FooterText:      input.FooterText,
+		SignatureLabels: input.SignatureLabels,
+	}
+	err = handler.usecase.SetReportTemplate(&template)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromTemplate(&template))
+}
+
+// ResetReportTemplate restores the default template of the team.
+func (handler *pdfReportHandler) ResetReportTemplate(context *gin.Context) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	if !handler.checkTeamAdmin(context, token, userId, teamId) {
+		return
+	}
+	err = handler.usecase.ResetReportTemplate(teamId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (handler *pdfReportHandler) createDtoFromTemplate(template *model.ReportTemplate) reportTemplateDto {
+	signatureLabels := []string{}
+	signatureLabels = append(signatureLabels, template.SignatureLabels...)
+	return reportTemplateDto{
+		Title:           template.Title,
+		Client:          template.Client,
+		Layout:          template.Layout,
+		Language:        template.Language,
+		HeaderText:      template.HeaderText,
+		FooterText:      template.FooterText,
+		SignatureLabels: signatureLabels,
+	}
+}
+
+func (handler *pdfReportHandler) verifyToken(context *gin.Context) (AuthToken, uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	return token, userId, true
+}
+
+func (handler *pdfReportHandler) checkTeamManager(context *gin.Context, token AuthToken, userId uuid.UUID, teamId uuid.UUID) bool {
+	if handler.teamUsecase.IsUserManagerInTeam(userId, teamId) {
+		return true
+	}
+	return handler.checkGlobalAdmin(context, token, "you are not allowed to see the reports of this team")
+}
+
+func (handler *pdfReportHandler) checkTeamAdmin(context *gin.Context, token AuthToken, userId uuid.UUID, teamId uuid.UUID) bool {
+	if handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		return true
+	}
+	return handler.checkGlobalAdmin(context, token, "you are not allowed to change the report template of this team")
+}
+
+func (handler *pdfReportHandler) checkGlobalAdmin(context *gin.Context, token AuthToken, message string) bool {
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": message})
+		return false
+	}
+	return true
+}
+
+func (handler *pdfReportHandler) getIdParam(context *gin.Context, name string) (uuid.UUID, error) {
+	idString := context.Param(name)
+	id, err := uuid.FromString(idString)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%v is not a valid id", idString)
+	}
+	return id, nil
+}
+
+func (handler *pdfReportHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
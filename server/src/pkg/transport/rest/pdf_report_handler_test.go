@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_pdfReportHandler_GetMonthlyTimesheet(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	project := addTeamProject(t, handlerTest, "team project", adminId, team)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+
+	// A user may get the own timesheet:
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/teams/%v/report/timesheet?month=2023-09&userId=%v&timezone=Europe/Berlin", team.ID, userId)
+	req, err := http.NewRequest("GET", url, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "%PDF-"))
+
+	// but not the timesheet of another user or of a project:
+	for _, query := range []string{fmt.Sprintf("userId=%v", adminId), fmt.Sprintf("projectId=%v", project.ID)} {
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/report/timesheet?month=2023-09&%v", team.ID, query), nil)
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 403, w.Code, query)
+	}
+}
+
+func Test_pdfReportHandler_GetMonthlyTimesheetOfProject(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	project := addTeamProject(t, handlerTest, "team project", userId, team)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+
+	w := httptest.NewRecorder()
+	url := fmt.Sprintf("/api/v1/teams/%v/report/timesheet?month=2023-09&projectId=%v", team.ID, project.ID)
+	req, err := http.NewRequest("GET", url, nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "%PDF-"))
+
+	for _, query := range []string{"month=09/2023", "", fmt.Sprintf("month=2023-09&userId=%v&projectId=%v", userId, project.ID)} {
+		w = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/report/timesheet?%v", team.ID, query), nil)
+		assert.Nil(t, err)
+		handlerTest.Router.ServeHTTP(w, req)
+		assert.Equal(t, 400, w.Code, query)
+	}
+}
+
+func Test_pdfReportHandler_SetReportTemplate(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	input := reportTemplateDto{
+		Client:          "ACME",
+		Layout:          model.ReportLayoutDailyTotals,
+		Language:        "de",
+		SignatureLabels: []string{"Mitarbeiter", "Kunde"},
+	}
+	body, err := json.Marshal(input)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/reporttemplate", team.ID), bytes.NewReader(body))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/reporttemplate", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var template reportTemplateDto
+	err = json.Unmarshal(w.Body.Bytes(), &template)
+	assert.Nil(t, err)
+	assert.Equal(t, input, template)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/teams/%v/reporttemplate", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	storedTemplate, err := handlerTest.PdfReportUsecase.GetReportTemplate(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "", storedTemplate.Client)
+}
+
+func Test_pdfReportHandler_SetReportTemplateFailsIfUserIsNoAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/reporttemplate", team.ID),
+		strings.NewReader(`{"client":"ACME"}`))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
@@ -0,0 +1,220 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type PeriodLockHandler interface {
+	GetLockDate(context *gin.Context)
+	SetLockDate(context *gin.Context)
+	GetLockDateChanges(context *gin.Context)
+}
+
+type periodLockHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.PeriodLockUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewPeriodLockHandler(tokenVerifier TokenVerifier, usecase usecase.PeriodLockUsecase, teamUsecase usecase.TeamUsecase) PeriodLockHandler {
+	return &periodLockHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type lockDateInputDto struct {
+	// LockDate in the format YYYY-MM-DD, null removes the lock
+	LockDate *string `json:"lockDate"`
+	Comment  string  `json:"comment"`
+}
+
+type lockDateDto struct {
+	TeamId   uuid.UUID
+	LockDate *string
+}
+
+type lockDateChangeDto struct {
+	Id          uuid.UUID
+	ChangedBy   uuid.UUID
+	ChangedAt   int64
+	OldLockDate *string
+	NewLockDate *string
+	Comment     string `json:",omitempty"`
+}
+
+func (handler *periodLockHandler) GetLockDate(context *gin.Context) {
+	teamId, token, ok := handler.getTeamAndToken(context)
+	if !ok {
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.teamUsecase.DoesUserBelongToTeam(userId, teamId) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not a member of this team"})
+			return
+		}
+	}
+	lockDate, err := handler.usecase.GetLockDate(teamId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, lockDateDto{
+		TeamId:   teamId,
+		LockDate: handler.formatDate(lockDate),
+	})
+}
+
+// SetLockDate moves the lock date of the team. Only admins of the team may do this.
+func (handler *periodLockHandler) SetLockDate(context *gin.Context) {
+	teamId, token, ok := handler.getTeamAndToken(context)
+	if !ok {
+		return
+	}
+	var input lockDateInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		context.JSON(http.StatusForbidden, gin.H{"error": "only admins of the team may change its lock date"})
+		return
+	}
+	var lockDate *time.Time
+	if input.LockDate != nil {
+		parsedDate, err := time.Parse(dateFormat, *input.LockDate)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the lock date in the format YYYY-MM-DD"})
+			return
+		}
+		lockDate = &parsedDate
+	}
+	change, err := handler.usecase.SetLockDate(teamId, lockDate, userId, input.Comment)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromChange(change))
+}
+
+func (handler *periodLockHandler) GetLockDateChanges(context *gin.Context) {
+	teamId, token, ok := handler.getTeamAndToken(context)
+	if !ok {
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.teamUsecase.IsUserManagerInTeam(userId, teamId) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to see the lock date changes of this team"})
+			return
+		}
+	}
+	changes, err := handler.usecase.GetLockDateChanges(teamId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []lockDateChangeDto{}
+	for _, change := range changes {
+		dtos = append(dtos, handler.createDtoFromChange(&change))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+// getTeamAndToken reads the team from the request path and verifies the token. If something is wrong the error
+// response is already written.
+func (handler *periodLockHandler) getTeamAndToken(context *gin.Context) (uuid.UUID, AuthToken, bool) {
+	teamId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return teamId, nil, false
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return teamId, nil, false
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return teamId, nil, false
+	}
+	return teamId, token, true
+}
+
+func (handler *periodLockHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *periodLockHandler) createDtoFromChange(change *model.PeriodLockChange) lockDateChangeDto {
+	return lockDateChangeDto{
+		Id:          change.ID,
+		ChangedBy:   change.ChangedBy,
+		ChangedAt:   change.CreatedAt.Unix(),
+		OldLockDate: handler.formatDate(change.OldLockDate),
+		NewLockDate: handler.formatDate(change.NewLockDate),
+		Comment:     change.Comment,
+	}
+}
+
+func (handler *periodLockHandler) formatDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formattedDate := date.Format(dateFormat)
+	return &formattedDate
+}
+
+func (handler *periodLockHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
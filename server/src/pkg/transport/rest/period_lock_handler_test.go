@@ -0,0 +1,161 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_periodLockHandler_SetLockDate(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"lockDate\": \"2023-09-30\", \"comment\": \"september is closed\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/lockdate", team.ID), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var change lockDateChangeDto
+	err = json.Unmarshal(w.Body.Bytes(), &change)
+	assert.Nil(t, err)
+	assert.Nil(t, change.OldLockDate)
+	assert.Equal(t, "2023-09-30", *change.NewLockDate)
+	assert.Equal(t, userId, change.ChangedBy)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/lockdate", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var lockDate lockDateDto
+	err = json.Unmarshal(w.Body.Bytes(), &lockDate)
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-09-30", *lockDate.LockDate)
+}
+
+func Test_periodLockHandler_SetLockDateFailsIfUserIsNoAdminOfTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"lockDate\": \"2023-09-30\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/lockdate", team.ID), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	lockDate, err := handlerTest.PeriodLockUsecase.GetLockDate(team.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, lockDate)
+}
+
+func Test_periodLockHandler_GetLockDateChanges(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	lockDate := time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC)
+	_, err = handlerTest.PeriodLockUsecase.SetLockDate(team.ID, &lockDate, userId, "august")
+	assert.Nil(t, err)
+	lockDate = time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	_, err = handlerTest.PeriodLockUsecase.SetLockDate(team.ID, &lockDate, userId, "september")
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/lockdate/changes", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var changes []lockDateChangeDto
+	err = json.Unmarshal(w.Body.Bytes(), &changes)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, "september", changes[0].Comment)
+	assert.Equal(t, "2023-08-31", *changes[0].OldLockDate)
+	assert.Equal(t, "2023-09-30", *changes[0].NewLockDate)
+}
+
+func Test_periodLockHandler_TimeEntryOnOrBeforeLockDateCannotBeAdded(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	project := addTeamProject(t, handlerTest, "project", userId, team)
+	lockDate := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	_, err = handlerTest.PeriodLockUsecase.SetLockDate(team.ID, &lockDate, userId, "")
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	startTime := time.Date(2023, 9, 30, 10, 0, 0, 0, time.UTC)
+	reader := strings.NewReader(fmt.Sprintf("{\"description\": \"%v\", \"startTimeUTCUnix\": %v, \"projectId\": \"%v\"}",
+		"entry", startTime.Unix(), project.ID))
+	req, err := http.NewRequest("POST", "/api/v1/timeentries", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
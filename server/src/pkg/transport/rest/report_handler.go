@@ -0,0 +1,212 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type ReportHandler interface {
+	GetTimeEntriesOfTeam(context *gin.Context)
+	GetTeamReport(context *gin.Context)
+}
+
+type reportHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.ReportUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewReportHandler(tokenVerifier TokenVerifier, usecase usecase.ReportUsecase, teamUsecase usecase.TeamUsecase) ReportHandler {
+	return &reportHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type teamTimeEntryDto struct {
+	UserId uuid.UUID
+	timeEntryDto
+}
+
+type teamReportDto struct {
+	TeamId       uuid.UUID
+	From         string `json:",omitempty"`
+	To           string `json:",omitempty"`
+	TotalSeconds int64
+	Users        []userDurationDto
+	Projects     []projectDurationDto
+	Days         []dayDurationDto
+	Absences     []absenceDto
+}
+
+type userDurationDto struct {
+	UserId          uuid.UUID
+	DurationSeconds int64
+}
+
+type projectDurationDto struct {
+	ProjectId       uuid.UUID
+	ProjectName     string
+	DurationSeconds int64
+}
+
+type dayDurationDto struct {
+	Date            string
+	DurationSeconds int64
+}
+
+func (handler *reportHandler) GetTimeEntriesOfTeam(context *gin.Context) {
+	teamId, from, to, ok := handler.getTeamReportParams(context)
+	if !ok {
+		return
+	}
+	timeEntries, err := handler.usecase.GetTimeEntriesOfTeam(teamId, from, to)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.convertTimeEntriesToDtos(timeEntries))
+}
+
+func (handler *reportHandler) GetTeamReport(context *gin.Context) {
+	teamId, from, to, ok := handler.getTeamReportParams(context)
+	if !ok {
+		return
+	}
+	report, err := handler.usecase.GetTeamReport(teamId, from, to)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromTeamReport(report))
+}
+
+// getTeamReportParams reads the team and the date range from the request and checks if the authenticated user
+// is allowed to see the data of the team. If something is wrong the error response is already written.
+func (handler *reportHandler) getTeamReportParams(context *gin.Context) (uuid.UUID, time.Time, time.Time, bool) {
+	var from, to time.Time
+	teamId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return teamId, from, to, false
+	}
+	from, to, err = getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return teamId, from, to, false
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return teamId, from, to, false
+	}
+
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return teamId, from, to, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return teamId, from, to, false
+	}
+	if !handler.teamUsecase.IsUserManagerInTeam(userId, teamId) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return teamId, from, to, false
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to see the time entries of this team"})
+			return teamId, from, to, false
+		}
+	}
+	return teamId, from, to, true
+}
+
+func (handler *reportHandler) convertTimeEntriesToDtos(timeEntries []model.TimeEntry) []teamTimeEntryDto {
+	var dtos []teamTimeEntryDto
+	for _, timeEntry := range timeEntries {
+		dto := teamTimeEntryDto{
+			UserId: timeEntry.UserId,
+		}
+		dto.Id = timeEntry.ID
+		dto.Description = timeEntry.Description
+		dto.StartTimeUTCUnix = timeEntry.StartTime.Unix()
+		if !timeEntry.EndTime.IsZero() {
+			dto.EndTimeUTCUnix = timeEntry.EndTime.Unix()
+		}
+		dto.ProjectId = timeEntry.ProjectId
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
+
+func (handler *reportHandler) createDtoFromTeamReport(report *model.TeamReport) teamReportDto {
+	dto := teamReportDto{
+		TeamId:       report.TeamID,
+		TotalSeconds: int64(report.Total.Seconds()),
+	}
+	if !report.From.IsZero() {
+		dto.From = report.From.Format(dateFormat)
+	}
+	if !report.To.IsZero() {
+		// The end of the range is exclusive, so we show the last day that is included:
+		dto.To = report.To.AddDate(0, 0, -1).Format(dateFormat)
+	}
+	for userId, duration := range report.ByUser {
+		dto.Users = append(dto.Users, userDurationDto{
+			UserId:          userId,
+			DurationSeconds: int64(duration.Seconds()),
+		})
+	}
+	sort.Slice(dto.Users, func(i, j int) bool {
+		return dto.Users[i].UserId.String() < dto.Users[j].UserId.String()
+	})
+	for projectId, duration := range report.ByProject {
+		dto.Projects = append(dto.Projects, projectDurationDto{
+			ProjectId:       projectId,
+			ProjectName:     report.ProjectNames[projectId],
+			DurationSeconds: int64(duration.Seconds()),
+		})
+	}
+	sort.Slice(dto.Projects, func(i, j int) bool {
+		return dto.Projects[i].ProjectName < dto.Projects[j].ProjectName
+	})
+	var days []time.Time
+	for day := range report.ByDay {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+	for _, day := range days {
+		dto.Days = append(dto.Days, dayDurationDto{
+			Date:            day.Format(dateFormat),
+			DurationSeconds: int64(report.ByDay[day].Seconds()),
+		})
+	}
+	dto.Absences = convertAbsencesToDtos(report.Absences)
+	return dto
+}
+
+func (handler *reportHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
@@ -0,0 +1,171 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_reportHandler_GetTimeEntriesOfTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", userId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	project := addTeamProject(t, handlerTest, "team project", userId, team)
+	addTimeEntryWithDuration(t, handlerTest, memberId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, memberId, project, time.Date(2023, 10, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/timeentries?from=2023-09-01&to=2023-09-30", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var entriesFromService []teamTimeEntryDto
+	err = json.Unmarshal(w.Body.Bytes(), &entriesFromService)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entriesFromService))
+	assert.Equal(t, memberId, entriesFromService[0].UserId)
+	assert.Equal(t, project.ID, entriesFromService[0].ProjectId)
+}
+
+func Test_reportHandler_GetTimeEntriesOfTeamFailsIfUserIsNoManager(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/timeentries", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_reportHandler_GetTeamReport(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	// A manager may see the report as well:
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+	project := addTeamProject(t, handlerTest, "team project", adminId, team)
+	addTimeEntryWithDuration(t, handlerTest, adminId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addTimeEntryWithDuration(t, handlerTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/report?from=2023-09-01&to=2023-09-30", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var report teamReportDto
+	err = json.Unmarshal(w.Body.Bytes(), &report)
+	assert.Nil(t, err)
+	assert.Equal(t, team.ID, report.TeamId)
+	assert.Equal(t, "2023-09-01", report.From)
+	assert.Equal(t, "2023-09-30", report.To)
+	assert.Equal(t, int64(3*60*60), report.TotalSeconds)
+	assert.Equal(t, 2, len(report.Users))
+	assert.Equal(t, 1, len(report.Projects))
+	assert.Equal(t, "team project", report.Projects[0].ProjectName)
+	assert.Equal(t, 2, len(report.Days))
+	assert.Equal(t, "2023-09-04", report.Days[0].Date)
+	assert.Equal(t, int64(2*60*60), report.Days[0].DurationSeconds)
+}
+
+func Test_reportHandler_GetTeamReportFailsWithInvalidDate(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/report?from=01.09.2023", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func addTeamProject(t *testing.T, handlerTest *HandlerTest, name string, userId uuid.UUID, team model.Team) model.Project {
+	project := addProject(t, handlerTest, name, userId)
+	err := handlerTest.ProjectUsecase.AssignProjectToTeam(&project, &team)
+	assert.Nil(t, err)
+	return project
+}
+
+func addTimeEntryWithDuration(t *testing.T, handlerTest *HandlerTest, userId uuid.UUID, project model.Project, startTime time.Time, duration time.Duration) model.TimeEntry {
+	timeEntry := model.TimeEntry{
+		Description: "entry",
+		UserId:      userId,
+		ProjectId:   project.ID,
+		StartTime:   startTime,
+		EndTime:     startTime.Add(duration),
+	}
+	err := handlerTest.TimeEntryUsecase.AddTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	return timeEntry
+}
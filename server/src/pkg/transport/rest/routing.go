@@ -1,15 +1,26 @@
 package rest
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	ginglog "github.com/szuecs/gin-glog"
 )
 
-func SetupRouter(authMiddleware AuthMiddleware, teamHandler TeamHandler, projectHandler ProjectHandler, timeEntryHandler TimeEntryHandler, syncHandler SyncHandler) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(authMiddleware AuthMiddleware, calendarFeedAuthMiddleware AuthMiddleware, teamHandler TeamHandler, projectHandler ProjectHandler, timeEntryHandler TimeEntryHandler, syncHandler SyncHandler,
+	reportHandler ReportHandler, workingTimeHandler WorkingTimeHandler, absenceHandler AbsenceHandler, holidayHandler HolidayHandler,
+	timesheetHandler TimesheetHandler, periodLockHandler PeriodLockHandler,
+	complianceHandler ComplianceHandler, overtimeHandler OvertimeHandler,
+	missingTimeHandler MissingTimeHandler, runningTimerHandler RunningTimerHandler,
+	notificationHandler NotificationHandler, jobHandler JobHandler, webhookHandler WebhookHandler,
+	exportHandler ExportHandler, pdfReportHandler PdfReportHandler,
+	calendarFeedHandler CalendarFeedHandler, importHandler ImportHandler, dataExportHandler DataExportHandler,
+	erasureHandler ErasureHandler, invoiceHandler InvoiceHandler) *gin.Engine {
+	router := gin.New()
 
+	router.Use(redactCalendarFeedToken())
+	router.Use(gin.Logger())
 	router.Use(ginglog.Logger(3 * time.Second))
 	router.Use(gin.Recovery())
 
@@ -34,8 +45,131 @@ func SetupRouter(authMiddleware AuthMiddleware, teamHandler TeamHandler, project
 	protectedGroup.POST("/teams/:id/users", teamHandler.AddUserToTeam)
 	protectedGroup.DELETE("/teams/:id/users/:userId", teamHandler.DeleteUserFromTeam)
 	protectedGroup.PUT("/teams/:id/users/:userId/roles", teamHandler.UpdateUserRolesInTeam)
+	protectedGroup.GET("/teams/:id/timeentries", reportHandler.GetTimeEntriesOfTeam)
+	protectedGroup.GET("/teams/:id/report", reportHandler.GetTeamReport)
 	protectedGroup.GET("/sync/changed/:timestamp", syncHandler.GetChangedEntries)
 	protectedGroup.POST("/sync/changed", syncHandler.SendLocallyChangedEntries)
+	protectedGroup.GET("/teams/:id/users/:userId/workingtimes", workingTimeHandler.GetWorkingTimeModels)
+	protectedGroup.POST("/teams/:id/users/:userId/workingtimes", workingTimeHandler.AddWorkingTimeModel)
+	protectedGroup.PUT("/teams/:id/users/:userId/workingtimes/:workingTimeId", workingTimeHandler.UpdateWorkingTimeModel)
+	protectedGroup.DELETE("/teams/:id/users/:userId/workingtimes/:workingTimeId", workingTimeHandler.DeleteWorkingTimeModel)
+	protectedGroup.GET("/teams/:id/users/:userId/targettime", workingTimeHandler.GetTargetTime)
+	protectedGroup.GET("/absences", absenceHandler.GetAbsences)
+	protectedGroup.GET("/absences/:id", absenceHandler.GetAbsenceById)
+	protectedGroup.POST("/absences", absenceHandler.RequestAbsence)
+	protectedGroup.DELETE("/absences/:id", absenceHandler.DeleteAbsence)
+	protectedGroup.PUT("/absences/:id/approve", absenceHandler.ApproveAbsence)
+	protectedGroup.PUT("/absences/:id/reject", absenceHandler.RejectAbsence)
+	protectedGroup.GET("/teams/:id/absences", absenceHandler.GetAbsencesOfTeam)
+	protectedGroup.GET("/teams/:id/holidaycalendars", holidayHandler.GetHolidayCalendarsOfTeam)
+	protectedGroup.POST("/teams/:id/holidaycalendars", holidayHandler.AddHolidayCalendar)
+	protectedGroup.PUT("/teams/:id/holidaycalendar", holidayHandler.AssignHolidayCalendarToTeam)
+	protectedGroup.PUT("/teams/:id/users/:userId/holidaycalendar", holidayHandler.AssignHolidayCalendarToUser)
+	protectedGroup.PUT("/holidaycalendars/:id", holidayHandler.UpdateHolidayCalendar)
+	protectedGroup.DELETE("/holidaycalendars/:id", holidayHandler.DeleteHolidayCalendar)
+	protectedGroup.GET("/holidaycalendars/:id/holidays", holidayHandler.GetHolidays)
+	protectedGroup.POST("/holidaycalendars/:id/customholidays", holidayHandler.AddCustomHoliday)
+	protectedGroup.DELETE("/holidaycalendars/:id/customholidays/:holidayId", holidayHandler.DeleteCustomHoliday)
+	protectedGroup.GET("/timesheets", timesheetHandler.GetTimesheets)
+	protectedGroup.GET("/timesheets/:id", timesheetHandler.GetTimesheetById)
+	protectedGroup.POST("/timesheets", timesheetHandler.SubmitTimesheet)
+	protectedGroup.DELETE("/timesheets/:id", timesheetHandler.WithdrawTimesheet)
+	protectedGroup.PUT("/timesheets/:id/approve", timesheetHandler.ApproveTimesheet)
+	protectedGroup.PUT("/timesheets/:id/reject", timesheetHandler.RejectTimesheet)
+	protectedGroup.GET("/teams/:id/timesheets", timesheetHandler.GetTimesheetsOfTeam)
+	protectedGroup.GET("/teams/:id/lockdate", periodLockHandler.GetLockDate)
+	protectedGroup.PUT("/teams/:id/lockdate", periodLockHandler.SetLockDate)
+	protectedGroup.GET("/teams/:id/lockdate/changes", periodLockHandler.GetLockDateChanges)
+
+	protectedGroup.GET("/compliance", complianceHandler.GetViolations)
+	protectedGroup.GET("/teams/:id/compliance", complianceHandler.GetViolationsOfTeam)
+
+	protectedGroup.GET("/overtime", overtimeHandler.GetOvertimeBalance)
+	protectedGroup.GET("/teams/:id/users/:userId/overtime", overtimeHandler.GetOvertimeBalanceOfUser)
+	protectedGroup.GET("/teams/:id/users/:userId/overtimeaccount", overtimeHandler.GetOvertimeAccountOfUser)
+	protectedGroup.PUT("/teams/:id/users/:userId/overtimeaccount", overtimeHandler.SetOvertimeAccountOfUser)
+	protectedGroup.GET("/teams/:id/users/:userId/overtimecorrections", overtimeHandler.GetOvertimeCorrectionsOfUser)
+	protectedGroup.POST("/teams/:id/users/:userId/overtimecorrections", overtimeHandler.AddOvertimeCorrection)
+	protectedGroup.DELETE("/teams/:id/users/:userId/overtimecorrections/:correctionId", overtimeHandler.DeleteOvertimeCorrection)
+
+	protectedGroup.GET("/missingtimes", missingTimeHandler.GetMissingTimes)
+	protectedGroup.GET("/teams/:id/missingtimes", missingTimeHandler.GetMissingTimesOfTeam)
+
+	protectedGroup.PUT("/teams/:id/runningtimerpolicy", runningTimerHandler.SetRunningTimerPolicyOfTeam)
+	protectedGroup.PUT("/teams/:id/users/:userId/runningtimerpolicy", runningTimerHandler.SetRunningTimerPolicyOfUser)
+
+	protectedGroup.GET("/notifications", notificationHandler.GetNotifications)
+	protectedGroup.PUT("/notifications/:id/read", notificationHandler.MarkNotificationAsRead)
+	protectedGroup.GET("/notificationsettings", notificationHandler.GetNotificationSettings)
+	protectedGroup.PUT("/notificationsettings", notificationHandler.SetNotificationSettings)
+	protectedGroup.GET("/notificationtemplates", notificationHandler.GetNotificationTemplates)
+	protectedGroup.PUT("/notificationtemplates/:type", notificationHandler.SetNotificationTemplate)
+	protectedGroup.DELETE("/notificationtemplates/:type", notificationHandler.ResetNotificationTemplate)
+
+	protectedGroup.GET("/jobs", jobHandler.GetJobs)
+	protectedGroup.GET("/jobs/:name/runs", jobHandler.GetJobRuns)
+
+	protectedGroup.GET("/webhooks", webhookHandler.GetWebhookSubscriptions)
+	protectedGroup.POST("/webhooks", webhookHandler.AddWebhookSubscription)
+	protectedGroup.GET("/webhooks/:id", webhookHandler.GetWebhookSubscriptionById)
+	protectedGroup.PUT("/webhooks/:id", webhookHandler.UpdateWebhookSubscription)
+	protectedGroup.DELETE("/webhooks/:id", webhookHandler.DeleteWebhookSubscription)
+	protectedGroup.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+	protectedGroup.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverWebhook)
+
+	protectedGroup.GET("/export/csv", exportHandler.ExportTimeEntriesAsCsv)
+	protectedGroup.GET("/export/xlsx", exportHandler.ExportTimeEntriesAsXlsx)
+
+	protectedGroup.GET("/teams/:id/report/timesheet", pdfReportHandler.GetMonthlyTimesheet)
+	protectedGroup.GET("/teams/:id/reporttemplate", pdfReportHandler.GetReportTemplate)
+	protectedGroup.PUT("/teams/:id/reporttemplate", pdfReportHandler.SetReportTemplate)
+	protectedGroup.DELETE("/teams/:id/reporttemplate", pdfReportHandler.ResetReportTemplate)
+
+	protectedGroup.GET("/calendarfeedtoken", calendarFeedHandler.GetCalendarFeedToken)
+	protectedGroup.POST("/calendarfeedtoken", calendarFeedHandler.RegenerateCalendarFeedToken)
+	protectedGroup.DELETE("/calendarfeedtoken", calendarFeedHandler.DeleteCalendarFeedToken)
+
+	protectedGroup.POST("/import/csv", importHandler.ImportTimeEntriesFromCsv)
+	protectedGroup.POST("/import/toggl", importHandler.ImportTimeEntriesFromToggl)
+	protectedGroup.POST("/import/clockify", importHandler.ImportTimeEntriesFromClockify)
+	protectedGroup.POST("/import/appdatabase", importHandler.ImportAppDatabase)
+
+	protectedGroup.POST("/dataexports", dataExportHandler.RequestDataExport)
+	protectedGroup.GET("/dataexports", dataExportHandler.GetDataExports)
+	protectedGroup.GET("/dataexports/:id", dataExportHandler.GetDataExportById)
+	protectedGroup.GET("/dataexports/:id/download", dataExportHandler.DownloadDataExport)
+
+	protectedGroup.POST("/erasurerequests", erasureHandler.RequestErasure)
+	protectedGroup.GET("/erasurerequests", erasureHandler.GetErasureRequests)
+	protectedGroup.GET("/erasurerequests/:id", erasureHandler.GetErasureRequestById)
+	protectedGroup.POST("/erasurerequests/:id/confirm", erasureHandler.ConfirmErasure)
+	protectedGroup.POST("/erasurerequests/:id/cancel", erasureHandler.CancelErasure)
+	protectedGroup.GET("/erasurerequests/:id/receipt", erasureHandler.GetErasureReceipt)
+
+	protectedGroup.POST("/invoices", invoiceHandler.CreateInvoice)
+	protectedGroup.GET("/invoices", invoiceHandler.GetInvoices)
+	protectedGroup.GET("/invoices/:id", invoiceHandler.GetInvoiceById)
+	protectedGroup.GET("/invoices/:id/pdf", invoiceHandler.GetInvoicePdf)
+	protectedGroup.POST("/invoices/:id/cancel", invoiceHandler.CancelInvoice)
+
+	// Calendar apps cannot authenticate with Keycloak, they use the secret token of the feed instead:
+	calendarFeedGroup := router.Group("/api/v1/calendarfeed/:token")
+	calendarFeedGroup.Use(calendarFeedAuthMiddleware.HandlerFunc())
+	calendarFeedGroup.GET("/timeentries.ics", calendarFeedHandler.GetCalendarFeed)
 
 	return router
 }
+
+// redactCalendarFeedToken removes the secret token of the calendar feed from the path of the request, so the loggers
+// do not write it to the access logs. The middleware must run before the loggers. The route is resolved already, so
+// the token is still available as path parameter.
+func redactCalendarFeedToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		if token != "" && strings.HasPrefix(c.Request.URL.Path, "/api/v1/calendarfeed/") {
+			c.Request.URL.Path = strings.Replace(c.Request.URL.Path, "/"+token, "/REDACTED", 1)
+			c.Request.URL.RawPath = ""
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type RunningTimerHandler interface {
+	SetRunningTimerPolicyOfTeam(context *gin.Context)
+	SetRunningTimerPolicyOfUser(context *gin.Context)
+}
+
+type runningTimerHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.RunningTimerUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewRunningTimerHandler(tokenVerifier TokenVerifier, usecase usecase.RunningTimerUsecase, teamUsecase usecase.TeamUsecase) RunningTimerHandler {
+	return &runningTimerHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type runningTimerPolicyDto struct {
+	// Policy is one of FLAG, STOP_AT_THRESHOLD and STOP_AT_TARGET_TIME, an empty policy removes the setting
+	Policy string `json:"policy"`
+}
+
+// SetRunningTimerPolicyOfTeam defines what happens with forgotten running time entries of the team members.
+func (handler *runningTimerHandler) SetRunningTimerPolicyOfTeam(context *gin.Context) {
+	teamId, ok := handler.checkTeamAdmin(context)
+	if !ok {
+		return
+	}
+	var input runningTimerPolicyDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err := handler.usecase.SetRunningTimerPolicyOfTeam(teamId, input.Policy)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, input)
+}
+
+// SetRunningTimerPolicyOfUser overrides the policy of the team for a single member.
+func (handler *runningTimerHandler) SetRunningTimerPolicyOfUser(context *gin.Context) {
+	teamId, ok := handler.checkTeamAdmin(context)
+	if !ok {
+		return
+	}
+	userId, err := handler.getIdParam(context, "userId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var input runningTimerPolicyDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = handler.usecase.SetRunningTimerPolicyOfUser(userId, teamId, input.Policy)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, input)
+}
+
+// checkTeamAdmin reads the team from the request path and makes sure that the user is an admin of it. If something
+// is wrong the error response is already written.
+func (handler *runningTimerHandler) checkTeamAdmin(context *gin.Context) (uuid.UUID, bool) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return teamId, false
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return teamId, false
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return teamId, false
+	}
+	authUserId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return teamId, false
+	}
+	if !handler.teamUsecase.IsUserAdminInTeam(authUserId, teamId) {
+		context.JSON(http.StatusForbidden, gin.H{"error": "only admins of the team may change its running timer policy"})
+		return teamId, false
+	}
+	return teamId, true
+}
+
+func (handler *runningTimerHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *runningTimerHandler) getIdParam(context *gin.Context, paramName string) (uuid.UUID, error) {
+	id := context.Param(paramName)
+	if id == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid %v", paramName)
+	}
+	result, err := uuid.FromString(id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return result, nil
+}
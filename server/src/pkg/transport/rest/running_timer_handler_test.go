@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_runningTimerHandler_SetRunningTimerPolicyOfTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"policy\": \"STOP_AT_THRESHOLD\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/runningtimerpolicy", team.ID), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	assert.Equal(t, model.RunningTimerPolicyStopAtThreshold, handlerTest.RunningTimerUsecase.GetRunningTimerPolicyOfUser(userId))
+
+	// The stopped entry is reported on the next sync:
+	project := addProject(t, handlerTest, "project", userId)
+	timeEntry := model.TimeEntry{
+		Description: "running",
+		UserId:      userId,
+		ProjectId:   project.ID,
+		StartTime:   time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC),
+	}
+	err = handlerTest.TimeEntryUsecase.AddTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	_, err = handlerTest.RunningTimerUsecase.CheckRunningTimers(time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/sync/changed/%v", time.Now().Add(-time.Minute).Unix()), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var syncEntries SyncEntries
+	err = json.Unmarshal(w.Body.Bytes(), &syncEntries)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(syncEntries.RunningTimerNotices))
+	assert.Equal(t, timeEntry.ID, syncEntries.RunningTimerNotices[0].TimeEntryId)
+	assert.Equal(t, model.RunningTimerActionStopped, syncEntries.RunningTimerNotices[0].Action)
+	assert.Equal(t, time.Date(2023, 9, 5, 20, 0, 0, 0, time.UTC).Unix(), syncEntries.RunningTimerNotices[0].EndTimeUTCUnix)
+}
+
+func Test_runningTimerHandler_SetRunningTimerPolicyOfUserFailsIfUserIsNoAdminOfTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"policy\": \"FLAG\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/users/%v/runningtimerpolicy", team.ID, userId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func Test_runningTimerHandler_SetRunningTimerPolicyOfUserFailsWithInvalidPolicy(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"policy\": \"STOP_SOMETIME\"}")
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/teams/%v/users/%v/runningtimerpolicy", team.ID, userId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
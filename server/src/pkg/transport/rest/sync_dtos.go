@@ -61,6 +61,12 @@ func (c *ChangeType) parse(sType string) (ChangeType, error) {
 type SyncEntries struct {
 	TimeEntries []ChangedTimeEntryDto
 	Projects    []ChangedProjectDto
+	// RunningTimerNotices informs the client about running entries the server has stopped or flagged
+	RunningTimerNotices []RunningTimerNoticeDto `json:",omitempty"`
+	// WipeLocalData tells the client that the data of the user was erased after its last sync, it has to delete its
+	// local copy and sync again from the beginning
+	WipeLocalData   bool  `json:",omitempty"`
+	ErasedAtUTCUnix int64 `json:",omitempty"`
 }
 
 type ChangedTimeEntryDto struct {
@@ -73,6 +79,13 @@ type ChangedTimeEntryDto struct {
 	ChangeTimestampUTCUnix int64      `json:"changeTimestampUTCUnix" binding:"required"`
 }
 
+type RunningTimerNoticeDto struct {
+	TimeEntryId    uuid.UUID
+	Action         string
+	EndTimeUTCUnix int64 `json:",omitempty"`
+	CreatedUTCUnix int64
+}
+
 type ChangedProjectDto struct {
 	Id                     uuid.UUID
 	Name                   string     `json:"name" binding:"required"`
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -17,14 +18,17 @@ type SyncHandler interface {
 }
 
 type syncHandler struct {
-	tokenVerifier TokenVerifier
-	syncUsecase   usecase.SyncUsecase
+	tokenVerifier  TokenVerifier
+	syncUsecase    usecase.SyncUsecase
+	erasureUsecase usecase.ErasureUsecase
 }
 
-func NewSyncHandler(tokenVerifier TokenVerifier, syncUsecase usecase.SyncUsecase) SyncHandler {
+func NewSyncHandler(tokenVerifier TokenVerifier, syncUsecase usecase.SyncUsecase,
+	erasureUsecase usecase.ErasureUsecase) SyncHandler {
 	return &syncHandler{
-		tokenVerifier: tokenVerifier,
-		syncUsecase:   syncUsecase,
+		tokenVerifier:  tokenVerifier,
+		syncUsecase:    syncUsecase,
+		erasureUsecase: erasureUsecase,
 	}
 }
 
@@ -45,6 +49,16 @@ func (handler *syncHandler) GetChangedEntries(context *gin.Context) {
 		context.JSON(http.StatusBadRequest, gin.H{"error": "please provide a valid unix timestamp"})
 		return
 	}
+	erasureTime, err := handler.erasureUsecase.GetErasureTime(userId)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if unixTime > 0 && erasureTime != nil && erasureTime.After(time.Unix(unixTime, 0)) {
+		// The client still has the erased data
+		context.JSON(http.StatusOK, SyncEntries{WipeLocalData: true, ErasedAtUTCUnix: erasureTime.Unix()})
+		return
+	}
 
 	var syncEntries SyncEntries
 	entries, err := handler.syncUsecase.GetChangedTimeEntries(userId, time.Unix(unixTime, 0))
@@ -90,6 +104,23 @@ func (handler *syncHandler) GetChangedEntries(context *gin.Context) {
 		syncEntries.Projects = append(syncEntries.Projects, syncProject)
 	}
 
+	notices, err := handler.syncUsecase.GetRunningTimerNotices(userId, time.Unix(unixTime, 0))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for _, notice := range notices {
+		noticeDto := RunningTimerNoticeDto{
+			TimeEntryId:    notice.TimeEntryID,
+			Action:         notice.Action,
+			CreatedUTCUnix: notice.CreatedAt.Unix(),
+		}
+		if notice.EndTime != nil {
+			noticeDto.EndTimeUTCUnix = notice.EndTime.Unix()
+		}
+		syncEntries.RunningTimerNotices = append(syncEntries.RunningTimerNotices, noticeDto)
+	}
+
 	context.JSON(http.StatusOK, syncEntries)
 }
 
@@ -110,17 +141,52 @@ func (handler *syncHandler) SendLocallyChangedEntries(context *gin.Context) {
 		return
 	}
 
+	erasureTime, err := handler.erasureUsecase.GetErasureTime(userId)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if erasureTime != nil && handler.containsChangesBefore(syncDtos, *erasureTime) {
+		// Erased data must not be uploaded again by a client that has not deleted its local copy
+		context.JSON(http.StatusGone, gin.H{
+			"error":           "the data of the user was erased, please delete the local data",
+			"WipeLocalData":   true,
+			"ErasedAtUTCUnix": erasureTime.Unix(),
+		})
+		return
+	}
+
 	var syncData model.SyncData
 	handler.fillInClientSideChangedTimeEntries(&syncData, syncDtos.TimeEntries, userId)
 
 	err = handler.syncUsecase.UpdateAndDeleteData(syncData)
 	if err != nil {
-		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		errorCode := http.StatusInternalServerError
+		var entityLockedError *usecase.EntityLockedError
+		if errors.As(err, &entityLockedError) {
+			errorCode = http.StatusConflict
+		}
+		context.JSON(errorCode, gin.H{"error": err.Error()})
 		return
 	}
 	context.JSON(http.StatusOK, nil)
 }
 
+// containsChangesBefore checks if one of the changes was made before the time.
+func (handler *syncHandler) containsChangesBefore(syncDtos SyncEntries, before time.Time) bool {
+	for _, timeEntry := range syncDtos.TimeEntries {
+		if timeEntry.ChangeTimestampUTCUnix < before.Unix() {
+			return true
+		}
+	}
+	for _, project := range syncDtos.Projects {
+		if project.ChangeTimestampUTCUnix < before.Unix() {
+			return true
+		}
+	}
+	return false
+}
+
 func (handler *syncHandler) fillInClientSideChangedTimeEntries(syncData *model.SyncData, changedTimeEntries []ChangedTimeEntryDto, userId uuid.UUID) {
 	for _, changedTimeEntry := range changedTimeEntries {
 		timeEntry := handler.createTimeEntryFromDto(changedTimeEntry, userId)
@@ -359,3 +359,81 @@ func Test_syncHandler_GetChangedProjects(t *testing.T) {
 	assert.Equal(t, updatedProject.Name, syncEntries.Projects[1].Name)
 	assert.Equal(t, CHANGED, syncEntries.Projects[1].ChangeType)
 }
+
+func Test_syncHandler_WipeLocalDataAfterErasure(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	project := model.Project{
+		Name:   "project",
+		UserId: userId,
+	}
+	err = handlerTest.ProjectUsecase.AddProject(&project)
+	assert.Nil(t, err)
+	lastSync := time.Now().UTC().Add(-time.Hour)
+	request, err := handlerTest.ErasureUsecase.RequestErasure(userId, userId, lastSync.Add(-15*24*time.Hour))
+	assert.Nil(t, err)
+	_, err = handlerTest.ErasureUsecase.ConfirmErasure(request.ID, adminId, lastSync)
+	assert.Nil(t, err)
+	erasureTime := time.Now().UTC()
+	executed, err := handlerTest.ErasureUsecase.ExecuteDueErasures(erasureTime)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, executed)
+
+	// Clients that synced before the erasure have to wipe their data:
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/v1/sync/changed/%v", lastSync.Unix()), nil)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	var syncEntries SyncEntries
+	err = json.Unmarshal(w.Body.Bytes(), &syncEntries)
+	assert.Nil(t, err)
+	assert.True(t, syncEntries.WipeLocalData)
+	assert.Equal(t, erasureTime.Unix(), syncEntries.ErasedAtUTCUnix)
+	assert.Empty(t, syncEntries.Projects)
+
+	// A full sync is allowed:
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/sync/changed/0", nil)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	syncEntries = SyncEntries{}
+	err = json.Unmarshal(w.Body.Bytes(), &syncEntries)
+	assert.Nil(t, err)
+	assert.False(t, syncEntries.WipeLocalData)
+
+	// Erased entries cannot be uploaded again:
+	id, err := uuid.NewV4()
+	assert.Nil(t, err)
+	timeEntry := ChangedTimeEntryDto{
+		Id:                     id,
+		Description:            "erased",
+		StartTimeUTCUnix:       lastSync.Add(-time.Hour).Unix(),
+		EndTimeUTCUnix:         lastSync.Unix(),
+		ProjectId:              project.ID,
+		ChangeType:             NEW,
+		ChangeTimestampUTCUnix: lastSync.Unix(),
+	}
+	entryJson, err := json.Marshal(SyncEntries{TimeEntries: []ChangedTimeEntryDto{timeEntry}})
+	assert.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/sync/changed", bytes.NewReader(entryJson))
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 410, w.Code)
+	entries, err := handlerTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
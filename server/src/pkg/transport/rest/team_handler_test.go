@@ -162,6 +162,38 @@ func Test_teamHandler_DeleteTeamFailsIfItDoesNotExist(t *testing.T) {
 	assert.Equal(t, 1, len(teamsFromDb))
 }
 
+func Test_teamHandler_DeleteTeamFailsIfUserIsNoAdminInTeam(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team1", otherUserId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/v1/teams/%v", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	teamsFromDb, err := handlerTest.TeamUsecase.GetAllTeams()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(teamsFromDb))
+}
+
 type testTeamDto struct {
 	ID uuid.UUID
 	teamInputDto
@@ -21,14 +21,16 @@ type TimeEntryHandler interface {
 }
 
 type timeEntryHandler struct {
-	tokenVerifier TokenVerifier
-	usecase       usecase.TimeEntryUsecase
+	tokenVerifier     TokenVerifier
+	usecase           usecase.TimeEntryUsecase
+	complianceUsecase usecase.ComplianceUsecase
 }
 
-func NewTimeEntryHandler(tokenVerifier TokenVerifier, entryUsecase usecase.TimeEntryUsecase) TimeEntryHandler {
+func NewTimeEntryHandler(tokenVerifier TokenVerifier, entryUsecase usecase.TimeEntryUsecase, complianceUsecase usecase.ComplianceUsecase) TimeEntryHandler {
 	return &timeEntryHandler{
-		tokenVerifier: tokenVerifier,
-		usecase:       entryUsecase,
+		tokenVerifier:     tokenVerifier,
+		usecase:           entryUsecase,
+		complianceUsecase: complianceUsecase,
 	}
 }
 
@@ -67,19 +69,24 @@ func (handler *timeEntryHandler) AddTimeEntry(context *gin.Context) {
 		errorCode := http.StatusInternalServerError
 		var userNotFoundError *usecase.UserNotFoundError
 		var projectNotFoundError *usecase.ProjectNotFoundError
+		var entityLockedError *usecase.EntityLockedError
 
 		switch {
 		case errors.As(err, &userNotFoundError):
 			errorCode = http.StatusBadRequest
 		case errors.As(err, &projectNotFoundError):
 			errorCode = http.StatusBadRequest
+		case errors.As(err, &entityLockedError):
+			errorCode = http.StatusConflict
 		default:
 			errorCode = http.StatusInternalServerError
 		}
 		context.JSON(errorCode, gin.H{"error": err.Error()})
 		return
 	}
-	context.JSON(http.StatusOK, gin.H{"id": newEntry.ID})
+	response := gin.H{"id": newEntry.ID}
+	handler.addComplianceWarnings(context, response, &newEntry)
+	context.JSON(http.StatusOK, response)
 }
 
 func (handler *timeEntryHandler) UpdateTimeEntry(context *gin.Context) {
@@ -127,19 +134,24 @@ func (handler *timeEntryHandler) UpdateTimeEntry(context *gin.Context) {
 		errorCode := http.StatusInternalServerError
 		var userNotFoundError *usecase.UserNotFoundError
 		var projectNotFoundError *usecase.ProjectNotFoundError
+		var entityLockedError *usecase.EntityLockedError
 
 		switch {
 		case errors.As(err, &userNotFoundError):
 			errorCode = http.StatusBadRequest
 		case errors.As(err, &projectNotFoundError):
 			errorCode = http.StatusBadRequest
+		case errors.As(err, &entityLockedError):
+			errorCode = http.StatusConflict
 		default:
 			errorCode = http.StatusInternalServerError
 		}
 		context.JSON(errorCode, gin.H{"error": err.Error()})
 		return
 	}
-	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("entry %v updated", entryId)})
+	response := gin.H{"message": fmt.Sprintf("entry %v updated", entryId)}
+	handler.addComplianceWarnings(context, response, timeEntry)
+	context.JSON(http.StatusOK, response)
 }
 
 func (handler *timeEntryHandler) DeleteTimeEntry(context *gin.Context) {
@@ -176,6 +188,15 @@ func (handler *timeEntryHandler) DeleteTimeEntry(context *gin.Context) {
 		}
 	}
 	err = handler.usecase.DeleteTimeEntry(entryId)
+	if err != nil {
+		errorCode := http.StatusInternalServerError
+		var entityLockedError *usecase.EntityLockedError
+		if errors.As(err, &entityLockedError) {
+			errorCode = http.StatusConflict
+		}
+		context.JSON(errorCode, gin.H{"error": err.Error()})
+		return
+	}
 	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("entry %v deleted", entryId)})
 }
 
@@ -239,6 +260,19 @@ func (handler *timeEntryHandler) GetAllTimeEntries(context *gin.Context) {
 	context.JSON(http.StatusOK, timeEntryDtos)
 }
 
+// addComplianceWarnings adds the violations of the working time law the saved entry is involved in to the response if
+// the client asked for them with the query parameter "complianceWarnings=true". The entry is stored anyway.
+func (handler *timeEntryHandler) addComplianceWarnings(context *gin.Context, response gin.H, timeEntry *model.TimeEntry) {
+	if context.Query("complianceWarnings") != "true" {
+		return
+	}
+	violations, err := handler.complianceUsecase.CheckTimeEntry(timeEntry)
+	if err != nil {
+		return
+	}
+	response["warnings"] = convertViolationsToDtos(violations)
+}
+
 func (handler *timeEntryHandler) createEntryFromDto(dto timeEntryUpdateDto, userId uuid.UUID) model.TimeEntry {
 	timeEntry := model.TimeEntry{
 		UserId: userId,
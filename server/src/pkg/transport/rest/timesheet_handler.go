@@ -0,0 +1,350 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type TimesheetHandler interface {
+	GetTimesheets(context *gin.Context)
+	GetTimesheetById(context *gin.Context)
+	GetTimesheetsOfTeam(context *gin.Context)
+	SubmitTimesheet(context *gin.Context)
+	WithdrawTimesheet(context *gin.Context)
+	ApproveTimesheet(context *gin.Context)
+	RejectTimesheet(context *gin.Context)
+}
+
+type timesheetHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.TimesheetUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewTimesheetHandler(tokenVerifier TokenVerifier, usecase usecase.TimesheetUsecase, teamUsecase usecase.TeamUsecase) TimesheetHandler {
+	return &timesheetHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type timesheetInputDto struct {
+	TeamId     uuid.UUID `json:"teamId" binding:"required"`
+	PeriodType string    `json:"periodType" binding:"required"`
+	// any day of the week or month that is submitted
+	Date string `json:"date" binding:"required"`
+}
+
+type timesheetDto struct {
+	Id          uuid.UUID
+	UserId      uuid.UUID
+	TeamId      uuid.UUID
+	PeriodType  string
+	StartDate   string
+	EndDate     string
+	Status      string
+	Comment     string `json:",omitempty"`
+	SubmittedAt int64
+	ReviewedBy  *uuid.UUID `json:",omitempty"`
+	ReviewedAt  *int64     `json:",omitempty"`
+}
+
+type timesheetReviewDto struct {
+	Comment string `json:"comment"`
+}
+
+func (handler *timesheetHandler) GetTimesheets(context *gin.Context) {
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	timesheets, err := handler.usecase.GetTimesheetsOfUser(userId, from, to)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.convertTimesheetsToDtos(timesheets, context.Query("status")))
+}
+
+func (handler *timesheetHandler) GetTimesheetById(context *gin.Context) {
+	timesheet, token, ok := handler.getTimesheet(context)
+	if !ok {
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if timesheet.UserID != userId && !handler.teamUsecase.IsUserManagerInTeam(userId, timesheet.TeamID) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAdmin {
+			// We just say that the timesheet was not found:
+			context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("timesheet with id %v not found", timesheet.ID)})
+			return
+		}
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromTimesheet(timesheet))
+}
+
+// GetTimesheetsOfTeam returns the timesheets of all members of the team. They can be filtered by their status with the
+// query parameter "status", e.g. to show all timesheets waiting for approval.
+func (handler *timesheetHandler) GetTimesheetsOfTeam(context *gin.Context) {
+	teamId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if !handler.isManagerOfTeam(context, token, teamId) {
+		return
+	}
+	timesheets, err := handler.usecase.GetTimesheetsOfTeam(teamId, from, to)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.convertTimesheetsToDtos(timesheets, context.Query("status")))
+}
+
+func (handler *timesheetHandler) SubmitTimesheet(context *gin.Context) {
+	var input timesheetInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	timesheet := model.Timesheet{
+		UserID:     userId,
+		TeamID:     input.TeamId,
+		PeriodType: input.PeriodType,
+	}
+	timesheet.StartDate, err = time.Parse(dateFormat, input.Date)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify the date in the format YYYY-MM-DD"})
+		return
+	}
+	err = handler.usecase.SubmitTimesheet(&timesheet)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromTimesheet(&timesheet))
+}
+
+func (handler *timesheetHandler) WithdrawTimesheet(context *gin.Context) {
+	timesheet, token, ok := handler.getTimesheet(context)
+	if !ok {
+		return
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if timesheet.UserID != userId {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("timesheet with id %v not found", timesheet.ID)})
+		return
+	}
+	err = handler.usecase.WithdrawTimesheet(timesheet.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("timesheet %v withdrawn", timesheet.ID)})
+}
+
+func (handler *timesheetHandler) ApproveTimesheet(context *gin.Context) {
+	handler.reviewTimesheet(context, true)
+}
+
+func (handler *timesheetHandler) RejectTimesheet(context *gin.Context) {
+	handler.reviewTimesheet(context, false)
+}
+
+func (handler *timesheetHandler) reviewTimesheet(context *gin.Context, approve bool) {
+	var review timesheetReviewDto
+	// The comment is optional, so the body may also be empty:
+	if context.Request.ContentLength > 0 {
+		if err := context.ShouldBindJSON(&review); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	timesheet, token, ok := handler.getTimesheet(context)
+	if !ok {
+		return
+	}
+	if !handler.isManagerOfTeam(context, token, timesheet.TeamID) {
+		return
+	}
+	reviewerId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if approve {
+		timesheet, err = handler.usecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	} else {
+		timesheet, err = handler.usecase.RejectTimesheet(timesheet.ID, reviewerId, review.Comment)
+	}
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromTimesheet(timesheet))
+}
+
+// getTimesheet reads the timesheet given in the path and verifies the token. If something is wrong the error response
+// is already written.
+func (handler *timesheetHandler) getTimesheet(context *gin.Context) (*model.Timesheet, AuthToken, bool) {
+	timesheetId, err := handler.getId(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	timesheet, err := handler.usecase.GetTimesheetById(timesheetId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("timesheet with id %v not found", timesheetId)})
+		return nil, nil, false
+	}
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+	return timesheet, token, true
+}
+
+// isManagerOfTeam checks if the authenticated user is an admin or manager of the team or a global admin. If not the
+// error response is already written.
+func (handler *timesheetHandler) isManagerOfTeam(context *gin.Context, token AuthToken, teamId uuid.UUID) bool {
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if handler.teamUsecase.IsUserManagerInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to manage the timesheets of this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *timesheetHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var entityExistsError *usecase.EntityExistsError
+	var invalidValueError *usecase.InvalidValueError
+	var entityLockedError *usecase.EntityLockedError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityExistsError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityLockedError):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *timesheetHandler) getId(context *gin.Context) (uuid.UUID, error) {
+	idParam := context.Param("id")
+	if idParam == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid id")
+	}
+	id, err := uuid.FromString(idParam)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (handler *timesheetHandler) convertTimesheetsToDtos(timesheets []model.Timesheet, status string) []timesheetDto {
+	dtos := []timesheetDto{}
+	for _, timesheet := range timesheets {
+		if status == "" || timesheet.Status == status {
+			dtos = append(dtos, handler.createDtoFromTimesheet(&timesheet))
+		}
+	}
+	return dtos
+}
+
+func (handler *timesheetHandler) createDtoFromTimesheet(timesheet *model.Timesheet) timesheetDto {
+	dto := timesheetDto{
+		Id:          timesheet.ID,
+		UserId:      timesheet.UserID,
+		TeamId:      timesheet.TeamID,
+		PeriodType:  timesheet.PeriodType,
+		StartDate:   timesheet.StartDate.Format(dateFormat),
+		EndDate:     timesheet.EndDate.Format(dateFormat),
+		Status:      timesheet.Status,
+		Comment:     timesheet.Comment,
+		SubmittedAt: timesheet.SubmittedAt.Unix(),
+		ReviewedBy:  timesheet.ReviewedBy,
+	}
+	if timesheet.ReviewedAt != nil {
+		reviewedAt := timesheet.ReviewedAt.Unix()
+		dto.ReviewedAt = &reviewedAt
+	}
+	return dto
+}
@@ -0,0 +1,191 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_timesheetHandler_SubmitTimesheet(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"teamId\": \"%v\", \"periodType\": \"MONTH\", \"date\": \"2023-09-15\"}", team.ID))
+	req, err := http.NewRequest("POST", "/api/v1/timesheets", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var timesheet timesheetDto
+	err = json.Unmarshal(w.Body.Bytes(), &timesheet)
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-09-01", timesheet.StartDate)
+	assert.Equal(t, "2023-09-30", timesheet.EndDate)
+	assert.Equal(t, model.TimesheetStatusSubmitted, timesheet.Status)
+}
+
+func Test_timesheetHandler_ApproveTimesheetFailsIfUserIsNoManager(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	timesheet := addTimesheet(t, handlerTest, userId, team, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", fmt.Sprintf("/api/v1/timesheets/%v/approve", timesheet.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	timesheetFromDb, err := handlerTest.TimesheetUsecase.GetTimesheetById(timesheet.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.TimesheetStatusSubmitted, timesheetFromDb.Status)
+}
+
+func Test_timesheetHandler_GetSubmittedTimesheetsOfTeamAsManager(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	addTimesheet(t, handlerTest, memberId, team, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	approvedTimesheet := addTimesheet(t, handlerTest, memberId, team, time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC))
+	_, err = handlerTest.TimesheetUsecase.ApproveTimesheet(approvedTimesheet.ID, adminId)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/timesheets?status=SUBMITTED", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var timesheets []timesheetDto
+	err = json.Unmarshal(w.Body.Bytes(), &timesheets)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timesheets))
+	assert.Equal(t, "2023-09-04", timesheets[0].StartDate)
+}
+
+func Test_timesheetHandler_LockedTimeEntriesCannotBeChanged(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	project := addProject(t, handlerTest, "project", userId)
+	startTime := time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC)
+	timeEntry := addTimeEntryWithDuration(t, handlerTest, userId, project, startTime, time.Hour)
+	timesheet := addTimesheet(t, handlerTest, userId, team, startTime)
+	reviewerId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/v1/timeentries/%v", timeEntry.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	syncEntries := SyncEntries{
+		TimeEntries: []ChangedTimeEntryDto{{
+			Id:                     timeEntry.ID,
+			Description:            "changed",
+			StartTimeUTCUnix:       startTime.Unix(),
+			EndTimeUTCUnix:         startTime.Add(time.Hour).Unix(),
+			ProjectId:              project.ID,
+			ChangeType:             CHANGED,
+			ChangeTimestampUTCUnix: time.Now().Unix(),
+		}},
+	}
+	entryJson, err := json.Marshal(syncEntries)
+	assert.Nil(t, err)
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", "/api/v1/sync/changed", bytes.NewReader(entryJson))
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	timeEntryFromDb, err := handlerTest.TimeEntryUsecase.GetTimeEntryById(timeEntry.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "entry", timeEntryFromDb.Description)
+}
+
+func addTimesheet(t *testing.T, handlerTest *HandlerTest, userId uuid.UUID, team model.Team, day time.Time) model.Timesheet {
+	timesheet := model.Timesheet{
+		UserID:     userId,
+		TeamID:     team.ID,
+		PeriodType: model.TimesheetPeriodWeek,
+		StartDate:  day,
+	}
+	err := handlerTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	return timesheet
+}
@@ -0,0 +1,379 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type WebhookHandler interface {
+	GetWebhookSubscriptions(context *gin.Context)
+	AddWebhookSubscription(context *gin.Context)
+	GetWebhookSubscriptionById(context *gin.Context)
+	UpdateWebhookSubscription(context *gin.Context)
+	DeleteWebhookSubscription(context *gin.Context)
+	GetWebhookDeliveries(context *gin.Context)
+	RedeliverWebhook(context *gin.Context)
+}
+
+type webhookHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.WebhookUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewWebhookHandler(tokenVerifier TokenVerifier, usecase usecase.WebhookUsecase, teamUsecase usecase.TeamUsecase) WebhookHandler {
+	return &webhookHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+const defaultWebhookDeliveryLimit = 50
+
+type webhookSubscriptionInputDto struct {
+	Url string `json:"url" binding:"required"`
+	// EventTypes are the subscribed domain events, e.g. TimeEntryCreated. Without event types all events are sent.
+	EventTypes []string `json:"eventTypes"`
+	// Secret is used for the signature header, a random secret is created if it is empty
+	Secret string `json:"secret"`
+	// TeamId makes it a subscription of the team (only for team admins), otherwise it is a personal subscription
+	TeamId *uuid.UUID `json:"teamId"`
+	Active *bool      `json:"active"`
+}
+
+type webhookSubscriptionDto struct {
+	Id         uuid.UUID
+	Url        string
+	EventTypes []string
+	TeamId     *uuid.UUID `json:",omitempty"`
+	Active     bool
+	// Secret is only returned when the subscription is created or the secret is changed
+	Secret string `json:",omitempty"`
+}
+
+type webhookDeliveryDto struct {
+	Id                uuid.UUID
+	EventId           uuid.UUID
+	EventType         string
+	Payload           json.RawMessage
+	Status            string
+	Attempts          int
+	ResponseStatus    int    `json:",omitempty"`
+	LastError         string `json:",omitempty"`
+	CreatedAtUnix     int64
+	LastAttemptAtUnix int64 `json:",omitempty"`
+	NextAttemptAtUnix int64 `json:",omitempty"`
+	DeliveredAtUnix   int64 `json:",omitempty"`
+}
+
+// GetWebhookSubscriptions returns the personal subscriptions of the user or, with the query parameter "teamId", the
+// subscriptions of the team.
+func (handler *webhookHandler) GetWebhookSubscriptions(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	var subscriptions []model.WebhookSubscription
+	if context.Query("teamId") != "" {
+		teamId, err := uuid.FromString(context.Query("teamId"))
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid team id", context.Query("teamId"))})
+			return
+		}
+		if !handler.checkTeamAdmin(context, token, userId, teamId) {
+			return
+		}
+		subscriptions, err = handler.usecase.GetWebhookSubscriptionsOfTeam(teamId)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		var err error
+		subscriptions, err = handler.usecase.GetWebhookSubscriptionsOfUser(userId)
+		if err != nil {
+			context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+	dtos := []webhookSubscriptionDto{}
+	for _, subscription := range subscriptions {
+		dtos = append(dtos, handler.createDtoFromSubscription(&subscription, false))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *webhookHandler) AddWebhookSubscription(context *gin.Context) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return
+	}
+	var input webhookSubscriptionInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.TeamId != nil {
+		if _, err := handler.teamUsecase.GetTeamById(*input.TeamId); err != nil {
+			context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", *input.TeamId)})
+			return
+		}
+		if !handler.checkTeamAdmin(context, token, userId, *input.TeamId) {
+			return
+		}
+	}
+	subscription := model.WebhookSubscription{
+		UserID:     userId,
+		TeamID:     input.TeamId,
+		URL:        input.Url,
+		EventTypes: model.StringList(input.EventTypes),
+		Secret:     input.Secret,
+		Active:     input.Active == nil || *input.Active,
+	}
+	err := handler.usecase.AddWebhookSubscription(&subscription)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromSubscription(&subscription, true))
+}
+
+func (handler *webhookHandler) GetWebhookSubscriptionById(context *gin.Context) {
+	subscription, ok := handler.getAccessibleSubscription(context)
+	if !ok {
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromSubscription(subscription, false))
+}
+
+// UpdateWebhookSubscription changes url, event types and state of the subscription. The secret is only changed if a
+// new one is given. The team of a subscription cannot be changed.
+func (handler *webhookHandler) UpdateWebhookSubscription(context *gin.Context) {
+	subscription, ok := handler.getAccessibleSubscription(context)
+	if !ok {
+		return
+	}
+	var input webhookSubscriptionInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	subscription.URL = input.Url
+	subscription.EventTypes = model.StringList(input.EventTypes)
+	if input.Active != nil {
+		subscription.Active = *input.Active
+	}
+	secretChanged := input.Secret != "" && input.Secret != subscription.Secret
+	if input.Secret != "" {
+		subscription.Secret = input.Secret
+	}
+	err := handler.usecase.UpdateWebhookSubscription(subscription)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromSubscription(subscription, secretChanged))
+}
+
+func (handler *webhookHandler) DeleteWebhookSubscription(context *gin.Context) {
+	subscription, ok := handler.getAccessibleSubscription(context)
+	if !ok {
+		return
+	}
+	err := handler.usecase.DeleteWebhookSubscription(subscription.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("webhook subscription %v deleted", subscription.ID)})
+}
+
+// GetWebhookDeliveries returns the delivery log of the subscription, the latest delivery first. The number of
+// deliveries can be limited with the query parameter "limit".
+func (handler *webhookHandler) GetWebhookDeliveries(context *gin.Context) {
+	subscription, ok := handler.getAccessibleSubscription(context)
+	if !ok {
+		return
+	}
+	limit := defaultWebhookDeliveryLimit
+	if context.Query("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(context.Query("limit"))
+		if err != nil || limit <= 0 {
+			context.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%v is not a valid limit", context.Query("limit"))})
+			return
+		}
+	}
+	deliveries, err := handler.usecase.GetWebhookDeliveries(subscription.ID, limit)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dtos := []webhookDeliveryDto{}
+	for _, delivery := range deliveries {
+		dtos = append(dtos, handler.createDtoFromDelivery(&delivery))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+// RedeliverWebhook sends a logged delivery again right away and returns the result.
+func (handler *webhookHandler) RedeliverWebhook(context *gin.Context) {
+	subscription, ok := handler.getAccessibleSubscription(context)
+	if !ok {
+		return
+	}
+	deliveryId, err := handler.getIdParam(context, "deliveryId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	delivery, err := handler.usecase.GetWebhookDeliveryById(deliveryId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	if delivery.SubscriptionID != subscription.ID {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("webhook delivery with id %v not found", deliveryId)})
+		return
+	}
+	delivery, err = handler.usecase.RedeliverWebhook(deliveryId)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, handler.createDtoFromDelivery(delivery))
+}
+
+// getAccessibleSubscription loads the subscription of the path and checks that the user may manage it: personal
+// subscriptions only by their owner, team subscriptions by the admins of the team. If something is wrong the error
+// response is already written.
+func (handler *webhookHandler) getAccessibleSubscription(context *gin.Context) (*model.WebhookSubscription, bool) {
+	token, userId, ok := handler.verifyToken(context)
+	if !ok {
+		return nil, false
+	}
+	id, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	subscription, err := handler.usecase.GetWebhookSubscriptionById(id)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if subscription.TeamID != nil {
+		if !handler.checkTeamAdmin(context, token, userId, *subscription.TeamID) {
+			return nil, false
+		}
+	} else if subscription.UserID != userId {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you can only manage your own webhook subscriptions"})
+		return nil, false
+	}
+	return subscription, true
+}
+
+func (handler *webhookHandler) verifyToken(context *gin.Context) (AuthToken, uuid.UUID, bool) {
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	userId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, uuid.Nil, false
+	}
+	return token, userId, true
+}
+
+func (handler *webhookHandler) checkTeamAdmin(context *gin.Context, token AuthToken, userId uuid.UUID, teamId uuid.UUID) bool {
+	if handler.teamUsecase.IsUserAdminInTeam(userId, teamId) {
+		return true
+	}
+	isAdmin, err := token.HasRole(model.RoleAdmin)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !isAdmin {
+		context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to manage the webhooks of this team"})
+		return false
+	}
+	return true
+}
+
+func (handler *webhookHandler) getIdParam(context *gin.Context, name string) (uuid.UUID, error) {
+	idString := context.Param(name)
+	id, err := uuid.FromString(idString)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%v is not a valid id", idString)
+	}
+	return id, nil
+}
+
+func (handler *webhookHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusNotFound
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *webhookHandler) createDtoFromSubscription(subscription *model.WebhookSubscription, withSecret bool) webhookSubscriptionDto {
+	dto := webhookSubscriptionDto{
+		Id:         subscription.ID,
+		Url:        subscription.URL,
+		EventTypes: []string(subscription.EventTypes),
+		TeamId:     subscription.TeamID,
+		Active:     subscription.Active,
+	}
+	if dto.EventTypes == nil {
+		dto.EventTypes = []string{}
+	}
+	if withSecret {
+		dto.Secret = subscription.Secret
+	}
+	return dto
+}
+
+func (handler *webhookHandler) createDtoFromDelivery(delivery *model.WebhookDelivery) webhookDeliveryDto {
+	dto := webhookDeliveryDto{
+		Id:             delivery.ID,
+		EventId:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		CreatedAtUnix:  delivery.CreatedAt.Unix(),
+	}
+	if delivery.LastAttemptAt != nil {
+		dto.LastAttemptAtUnix = delivery.LastAttemptAt.Unix()
+	}
+	if delivery.Status == model.WebhookDeliveryStatusPending {
+		dto.NextAttemptAtUnix = delivery.NextAttemptAt.Unix()
+	}
+	if delivery.DeliveredAt != nil {
+		dto.DeliveredAtUnix = delivery.DeliveredAt.Unix()
+	}
+	return dto
+}
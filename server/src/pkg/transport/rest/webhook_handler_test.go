@@ -0,0 +1,273 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_webhookHandler_AddAndGetPersonalSubscription(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"url\": \"https://example.com/hook\", \"eventTypes\": [\"TimeEntryCreated\"]}")
+	req, err := http.NewRequest("POST", "/api/v1/webhooks", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var created webhookSubscriptionDto
+	err = json.Unmarshal(w.Body.Bytes(), &created)
+	assert.Nil(t, err)
+	assert.True(t, created.Active)
+	assert.Equal(t, 64, len(created.Secret))
+	assert.Equal(t, []string{model.EventTimeEntryCreated}, created.EventTypes)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", "/api/v1/webhooks", nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var subscriptions []webhookSubscriptionDto
+	err = json.Unmarshal(w.Body.Bytes(), &subscriptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(subscriptions))
+	assert.Equal(t, created.Id, subscriptions[0].Id)
+	assert.Equal(t, "https://example.com/hook", subscriptions[0].Url)
+	// The secret is only shown once
+	assert.Equal(t, "", subscriptions[0].Secret)
+}
+
+func Test_webhookHandler_AddFailsForInvalidEventType(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"url\": \"https://example.com/hook\", \"eventTypes\": [\"SomethingHappened\"]}")
+	req, err := http.NewRequest("POST", "/api/v1/webhooks", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_webhookHandler_TeamSubscriptionsOnlyForTeamAdmins(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"url\": \"https://example.com/hook\", \"teamId\": \"%v\"}", team.ID))
+	req, err := http.NewRequest("POST", "/api/v1/webhooks", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	subscription := model.WebhookSubscription{UserID: adminId, TeamID: &team.ID, URL: "https://example.com/hook", Active: true}
+	err = handlerTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("GET", fmt.Sprintf("/api/v1/webhooks?teamId=%v", team.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/webhooks/%v", subscription.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_webhookHandler_TeamAdminManagesTeamSubscription(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader(fmt.Sprintf("{\"url\": \"https://example.com/hook\", \"teamId\": \"%v\"}", team.ID))
+	req, err := http.NewRequest("POST", "/api/v1/webhooks", reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+	var created webhookSubscriptionDto
+	err = json.Unmarshal(w.Body.Bytes(), &created)
+	assert.Nil(t, err)
+	assert.Equal(t, team.ID, *created.TeamId)
+
+	w = httptest.NewRecorder()
+	reader = strings.NewReader("{\"url\": \"https://example.com/other\", \"active\": false}")
+	req, err = http.NewRequest("PUT", fmt.Sprintf("/api/v1/webhooks/%v", created.Id), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	subscription, err := handlerTest.WebhookUsecase.GetWebhookSubscriptionById(created.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/other", subscription.URL)
+	assert.False(t, subscription.Active)
+	assert.Equal(t, created.Secret, subscription.Secret)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("DELETE", fmt.Sprintf("/api/v1/webhooks/%v", created.Id), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
+
+func Test_webhookHandler_GetDeliveriesAndRedeliver(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	subscription := model.WebhookSubscription{UserID: userId, URL: server.URL, Active: true}
+	err = handlerTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+	project := addProject(t, handlerTest, "project", userId)
+	err = handlerTest.WebhookUsecase.EnqueueEvent(uuid.Must(uuid.NewV4()), time.Now(), &model.ProjectCreated{
+		ProjectEvent: model.ProjectEvent{ProjectID: project.ID, Name: project.Name, UserID: userId},
+	})
+	assert.Nil(t, err)
+	_, err = handlerTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/webhooks/%v/deliveries", subscription.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var deliveries []webhookDeliveryDto
+	err = json.Unmarshal(w.Body.Bytes(), &deliveries)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(deliveries))
+	assert.Equal(t, model.WebhookDeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, http.StatusBadGateway, deliveries[0].ResponseStatus)
+	assert.Equal(t, model.EventProjectCreated, deliveries[0].EventType)
+	assert.NotEqual(t, int64(0), deliveries[0].NextAttemptAtUnix)
+
+	w = httptest.NewRecorder()
+	req, err = http.NewRequest("POST", fmt.Sprintf("/api/v1/webhooks/%v/deliveries/%v/redeliver", subscription.ID,
+		deliveries[0].Id), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var delivery webhookDeliveryDto
+	err = json.Unmarshal(w.Body.Bytes(), &delivery)
+	assert.Nil(t, err)
+	assert.Equal(t, model.WebhookDeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.NotEqual(t, int64(0), delivery.DeliveredAtUnix)
+	assert.Equal(t, 2, requests)
+}
+
+func Test_webhookHandler_PersonalSubscriptionOfOtherUserIsForbidden(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	otherUserId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	subscription := model.WebhookSubscription{UserID: otherUserId, URL: "https://example.com/hook", Active: true}
+	err = handlerTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/webhooks/%v/deliveries", subscription.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+}
@@ -0,0 +1,313 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+)
+
+type WorkingTimeHandler interface {
+	GetWorkingTimeModels(context *gin.Context)
+	AddWorkingTimeModel(context *gin.Context)
+	UpdateWorkingTimeModel(context *gin.Context)
+	DeleteWorkingTimeModel(context *gin.Context)
+	GetTargetTime(context *gin.Context)
+}
+
+type workingTimeHandler struct {
+	tokenVerifier TokenVerifier
+	usecase       usecase.WorkingTimeUsecase
+	teamUsecase   usecase.TeamUsecase
+}
+
+func NewWorkingTimeHandler(tokenVerifier TokenVerifier, usecase usecase.WorkingTimeUsecase, teamUsecase usecase.TeamUsecase) WorkingTimeHandler {
+	return &workingTimeHandler{
+		tokenVerifier: tokenVerifier,
+		usecase:       usecase,
+		teamUsecase:   teamUsecase,
+	}
+}
+
+type workingTimeModelInputDto struct {
+	ValidFrom          string `json:"validFrom" binding:"required"`
+	PartTimePercentage int    `json:"partTimePercentage" binding:"required"`
+	MondayMinutes      int    `json:"mondayMinutes"`
+	TuesdayMinutes     int    `json:"tuesdayMinutes"`
+	WednesdayMinutes   int    `json:"wednesdayMinutes"`
+	ThursdayMinutes    int    `json:"thursdayMinutes"`
+	FridayMinutes      int    `json:"fridayMinutes"`
+	SaturdayMinutes    int    `json:"saturdayMinutes"`
+	SundayMinutes      int    `json:"sundayMinutes"`
+}
+
+type workingTimeModelDto struct {
+	Id     uuid.UUID
+	UserId uuid.UUID
+	TeamId uuid.UUID
+	workingTimeModelInputDto
+}
+
+type targetTimeDto struct {
+	UserId        uuid.UUID
+	From          string
+	To            string
+	TargetSeconds int64
+	Days          []dayTargetTimeDto
+}
+
+type dayTargetTimeDto struct {
+	Date          string
+	TargetSeconds int64
+}
+
+func (handler *workingTimeHandler) GetWorkingTimeModels(context *gin.Context) {
+	teamId, userId, ok := handler.getTeamAndUser(context, true)
+	if !ok {
+		return
+	}
+	workingTimeModels, err := handler.usecase.GetWorkingTimeModelsOfUserInTeam(userId, teamId)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var dtos []workingTimeModelDto
+	for _, workingTimeModel := range workingTimeModels {
+		dtos = append(dtos, handler.createDtoFromWorkingTimeModel(&workingTimeModel))
+	}
+	context.JSON(http.StatusOK, dtos)
+}
+
+func (handler *workingTimeHandler) AddWorkingTimeModel(context *gin.Context) {
+	teamId, userId, ok := handler.getTeamAndUser(context, false)
+	if !ok {
+		return
+	}
+	var input workingTimeModelInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	workingTimeModel := model.WorkingTimeModel{
+		UserID: userId,
+		TeamID: teamId,
+	}
+	err := handler.fillWorkingTimeModelFromDto(&workingTimeModel, input)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = handler.usecase.AddWorkingTimeModel(&workingTimeModel)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"id": workingTimeModel.ID})
+}
+
+func (handler *workingTimeHandler) UpdateWorkingTimeModel(context *gin.Context) {
+	teamId, userId, ok := handler.getTeamAndUser(context, false)
+	if !ok {
+		return
+	}
+	workingTimeModel, ok := handler.getWorkingTimeModel(context, teamId, userId)
+	if !ok {
+		return
+	}
+	var input workingTimeModelInputDto
+	if err := context.ShouldBindJSON(&input); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err := handler.fillWorkingTimeModelFromDto(workingTimeModel, input)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	err = handler.usecase.UpdateWorkingTimeModel(workingTimeModel)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("working time model %v updated", workingTimeModel.ID)})
+}
+
+func (handler *workingTimeHandler) DeleteWorkingTimeModel(context *gin.Context) {
+	teamId, userId, ok := handler.getTeamAndUser(context, false)
+	if !ok {
+		return
+	}
+	workingTimeModel, ok := handler.getWorkingTimeModel(context, teamId, userId)
+	if !ok {
+		return
+	}
+	err := handler.usecase.DeleteWorkingTimeModel(workingTimeModel.ID)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("working time model %v deleted", workingTimeModel.ID)})
+}
+
+func (handler *workingTimeHandler) GetTargetTime(context *gin.Context) {
+	_, userId, ok := handler.getTeamAndUser(context, true)
+	if !ok {
+		return
+	}
+	from, to, err := getDateRangeFromQuery(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if from.IsZero() || to.IsZero() {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "please specify a start and an end date"})
+		return
+	}
+	targetTimes, err := handler.usecase.GetDailyTargetTimes(userId, from, to)
+	if err != nil {
+		context.JSON(handler.getErrorCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	dto := targetTimeDto{
+		UserId: userId,
+		From:   from.Format(dateFormat),
+		To:     to.AddDate(0, 0, -1).Format(dateFormat),
+	}
+	for _, targetTime := range targetTimes {
+		dto.TargetSeconds += int64(targetTime.Target.Seconds())
+		dto.Days = append(dto.Days, dayTargetTimeDto{
+			Date:          targetTime.Day.Format(dateFormat),
+			TargetSeconds: int64(targetTime.Target.Seconds()),
+		})
+	}
+	context.JSON(http.StatusOK, dto)
+}
+
+// getTeamAndUser reads the team and the user from the request path and checks if the authenticated user is allowed
+// to access the working times of this user. Team admins may always do this, users may read their own data if
+// readOnly is set. If something is wrong the error response is already written.
+func (handler *workingTimeHandler) getTeamAndUser(context *gin.Context, readOnly bool) (uuid.UUID, uuid.UUID, bool) {
+	teamId, err := handler.getIdParam(context, "id")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return teamId, uuid.Nil, false
+	}
+	userId, err := handler.getIdParam(context, "userId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return teamId, userId, false
+	}
+	_, err = handler.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("team with id %v not found", teamId)})
+		return teamId, userId, false
+	}
+
+	token, err := handler.tokenVerifier.VerifyToken(context)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return teamId, userId, false
+	}
+	authUserId, err := token.GetUserId()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return teamId, userId, false
+	}
+	if readOnly && authUserId == userId {
+		return teamId, userId, true
+	}
+	if !handler.teamUsecase.IsUserAdminInTeam(authUserId, teamId) {
+		isAdmin, err := token.HasRole(model.RoleAdmin)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return teamId, userId, false
+		}
+		if !isAdmin {
+			context.JSON(http.StatusForbidden, gin.H{"error": "you are not allowed to access the working times of this user"})
+			return teamId, userId, false
+		}
+	}
+	return teamId, userId, true
+}
+
+func (handler *workingTimeHandler) getWorkingTimeModel(context *gin.Context, teamId uuid.UUID, userId uuid.UUID) (*model.WorkingTimeModel, bool) {
+	workingTimeModelId, err := handler.getIdParam(context, "workingTimeId")
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	workingTimeModel, err := handler.usecase.GetWorkingTimeModelById(workingTimeModelId)
+	if err != nil || workingTimeModel.TeamID != teamId || workingTimeModel.UserID != userId {
+		context.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("working time model with id %v not found", workingTimeModelId)})
+		return nil, false
+	}
+	return workingTimeModel, true
+}
+
+func (handler *workingTimeHandler) getErrorCode(err error) int {
+	var entityNotFoundError *usecase.EntityNotFoundError
+	var entityIncompleteError *usecase.EntityIncompleteError
+	var invalidValueError *usecase.InvalidValueError
+	switch {
+	case errors.As(err, &entityNotFoundError):
+		return http.StatusBadRequest
+	case errors.As(err, &entityIncompleteError):
+		return http.StatusBadRequest
+	case errors.As(err, &invalidValueError):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (handler *workingTimeHandler) fillWorkingTimeModelFromDto(workingTimeModel *model.WorkingTimeModel, dto workingTimeModelInputDto) error {
+	validFrom, err := time.Parse(dateFormat, dto.ValidFrom)
+	if err != nil {
+		return fmt.Errorf("please specify the date the working time model becomes valid in the format YYYY-MM-DD")
+	}
+	workingTimeModel.ValidFrom = validFrom
+	workingTimeModel.PartTimePercentage = dto.PartTimePercentage
+	workingTimeModel.MondayMinutes = dto.MondayMinutes
+	workingTimeModel.TuesdayMinutes = dto.TuesdayMinutes
+	workingTimeModel.WednesdayMinutes = dto.WednesdayMinutes
+	workingTimeModel.ThursdayMinutes = dto.ThursdayMinutes
+	workingTimeModel.FridayMinutes = dto.FridayMinutes
+	workingTimeModel.SaturdayMinutes = dto.SaturdayMinutes
+	workingTimeModel.SundayMinutes = dto.SundayMinutes
+	return nil
+}
+
+func (handler *workingTimeHandler) createDtoFromWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) workingTimeModelDto {
+	dto := workingTimeModelDto{
+		Id:     workingTimeModel.ID,
+		UserId: workingTimeModel.UserID,
+		TeamId: workingTimeModel.TeamID,
+	}
+	dto.ValidFrom = workingTimeModel.ValidFrom.Format(dateFormat)
+	dto.PartTimePercentage = workingTimeModel.PartTimePercentage
+	dto.MondayMinutes = workingTimeModel.MondayMinutes
+	dto.TuesdayMinutes = workingTimeModel.TuesdayMinutes
+	dto.WednesdayMinutes = workingTimeModel.WednesdayMinutes
+	dto.ThursdayMinutes = workingTimeModel.ThursdayMinutes
+	dto.FridayMinutes = workingTimeModel.FridayMinutes
+	dto.SaturdayMinutes = workingTimeModel.SaturdayMinutes
+	dto.SundayMinutes = workingTimeModel.SundayMinutes
+	return dto
+}
+
+func (handler *workingTimeHandler) getIdParam(context *gin.Context, paramName string) (uuid.UUID, error) {
+	id := context.Param(paramName)
+	if id == "" {
+		return uuid.Nil, fmt.Errorf("please specify a valid %v", paramName)
+	}
+	result, err := uuid.FromString(id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_workingTimeHandler_AddWorkingTimeModel(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	memberId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"validFrom\": \"2023-09-01\", \"partTimePercentage\": 50, \"mondayMinutes\": 480, \"fridayMinutes\": 240}")
+	req, err := http.NewRequest("POST", fmt.Sprintf("/api/v1/teams/%v/users/%v/workingtimes", team.ID, memberId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	modelsFromDb, err := handlerTest.WorkingTimeUsecase.GetWorkingTimeModelsOfUserInTeam(memberId, team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(modelsFromDb))
+	assert.Equal(t, 50, modelsFromDb[0].PartTimePercentage)
+	assert.Equal(t, 480, modelsFromDb[0].MondayMinutes)
+	assert.Equal(t, 240, modelsFromDb[0].FridayMinutes)
+}
+
+func Test_workingTimeHandler_AddWorkingTimeModelFailsIfUserIsNoTeamAdmin(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	w := httptest.NewRecorder()
+	reader := strings.NewReader("{\"validFrom\": \"2023-09-01\", \"partTimePercentage\": 100, \"mondayMinutes\": 600}")
+	req, err := http.NewRequest("POST", fmt.Sprintf("/api/v1/teams/%v/users/%v/workingtimes", team.ID, userId), reader)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	modelsFromDb, err := handlerTest.WorkingTimeUsecase.GetWorkingTimeModelsOfUserInTeam(userId, team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(modelsFromDb))
+}
+
+func Test_workingTimeHandler_DeleteWorkingTimeModel(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, handlerTest, "team", userId)
+	workingTimeModel := addWorkingTimeModel(t, handlerTest, userId, team, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("/api/v1/teams/%v/users/%v/workingtimes/%v", team.ID, userId, workingTimeModel.ID), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	modelsFromDb, err := handlerTest.WorkingTimeUsecase.GetWorkingTimeModelsOfUserInTeam(userId, team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(modelsFromDb))
+}
+
+func Test_workingTimeHandler_GetTargetTimeOfOwnUser(t *testing.T) {
+	userId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	token := authTokenMock{}
+	token.On("GetUserId").Return(userId, nil)
+	token.On("HasRole", model.RoleUser).Return(true, nil)
+	token.On("HasRole", model.RoleAdmin).Return(false, nil)
+
+	verifier := tokenVerifierMock{}
+	verifier.On("VerifyToken", mock.Anything).Return(&token, nil)
+
+	handlerTest := NewHandlerTest(&verifier)
+	teardownTest := handlerTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	team := addTeam(t, handlerTest, "team", adminId)
+	_, err = handlerTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	addWorkingTimeModel(t, handlerTest, userId, team, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+
+	w := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", fmt.Sprintf("/api/v1/teams/%v/users/%v/targettime?from=2023-09-04&to=2023-09-10", team.ID, userId), nil)
+	assert.Nil(t, err)
+	handlerTest.Router.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code, GetErrorMessageFromResponse(t, w.Body.Bytes()))
+
+	var targetTime targetTimeDto
+	err = json.Unmarshal(w.Body.Bytes(), &targetTime)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5*8*60*60), targetTime.TargetSeconds)
+	assert.Equal(t, 7, len(targetTime.Days))
+}
+
+func addWorkingTimeModel(t *testing.T, handlerTest *HandlerTest, userId uuid.UUID, team model.Team, validFrom time.Time) model.WorkingTimeModel {
+	workingTimeModel := model.WorkingTimeModel{
+		UserID:             userId,
+		TeamID:             team.ID,
+		ValidFrom:          validFrom,
+		PartTimePercentage: 100,
+		MondayMinutes:      8 * 60,
+		TuesdayMinutes:     8 * 60,
+		WednesdayMinutes:   8 * 60,
+		ThursdayMinutes:    8 * 60,
+		FridayMinutes:      8 * 60,
+	}
+	err := handlerTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+	return workingTimeModel
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+type AbsenceUsecase interface {
+	GetAbsenceById(id uuid.UUID) (*model.Absence, error)
+	GetAbsencesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error)
+	GetApprovedAbsencesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error)
+	GetAbsencesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error)
+	RequestAbsence(absence *model.Absence) error
+	DeleteAbsence(id uuid.UUID) error
+	ApproveAbsence(id uuid.UUID, reviewerId uuid.UUID) (*model.Absence, error)
+	RejectAbsence(id uuid.UUID, reviewerId uuid.UUID, comment string) (*model.Absence, error)
+}
+
+type absenceUsecase struct {
+	repo                repository.AbsenceRepository
+	teamUsecase         TeamUsecase
+	notificationUsecase NotificationUsecase
+}
+
+func NewAbsenceUsecase(repo repository.AbsenceRepository, teamUsecase TeamUsecase, notificationUsecase NotificationUsecase) AbsenceUsecase {
+	return &absenceUsecase{
+		repo:                repo,
+		teamUsecase:         teamUsecase,
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+func (usecase *absenceUsecase) GetAbsenceById(id uuid.UUID) (*model.Absence, error) {
+	absence, err := usecase.repo.GetAbsenceById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("absence with id %v not found", id))
+	}
+	return absence, nil
+}
+
+func (usecase *absenceUsecase) GetAbsencesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error) {
+	return usecase.repo.GetAbsencesOfUser(userId, from, to)
+}
+
+func (usecase *absenceUsecase) GetApprovedAbsencesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error) {
+	absences, err := usecase.repo.GetAbsencesOfUser(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var approvedAbsences []model.Absence
+	for _, absence := range absences {
+		if absence.Status == model.AbsenceStatusApproved {
+			approvedAbsences = append(approvedAbsences, absence)
+		}
+	}
+	return approvedAbsences, nil
+}
+
+func (usecase *absenceUsecase) GetAbsencesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Absence, error) {
+	return usecase.repo.GetAbsencesOfTeam(teamId, from, to)
+}
+
+// RequestAbsence stores a new absence. It has to be approved by an admin or manager of the team before it is taken into
+// account.
+func (usecase *absenceUsecase) RequestAbsence(absence *model.Absence) error {
+	err := usecase.checkAbsence(absence)
+	if err != nil {
+		return err
+	}
+	absence.Status = model.AbsenceStatusRequested
+	absence.ReviewedBy = nil
+	err = usecase.repo.AddAbsence(absence)
+	if err != nil {
+		return err
+	}
+	notifyManagersOfTeam(usecase.notificationUsecase, usecase.teamUsecase, absence.TeamID, absence.UserID,
+		model.NotificationAbsenceRequested, usecase.getNotificationData(absence))
+	return nil
+}
+
+func (usecase *absenceUsecase) DeleteAbsence(id uuid.UUID) error {
+	absence, err := usecase.GetAbsenceById(id)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.DeleteAbsence(absence)
+}
+
+func (usecase *absenceUsecase) ApproveAbsence(id uuid.UUID, reviewerId uuid.UUID) (*model.Absence, error) {
+	return usecase.reviewAbsence(id, reviewerId, model.AbsenceStatusApproved, "")
+}
+
+func (usecase *absenceUsecase) RejectAbsence(id uuid.UUID, reviewerId uuid.UUID, comment string) (*model.Absence, error) {
+	return usecase.reviewAbsence(id, reviewerId, model.AbsenceStatusRejected, comment)
+}
+
+func (usecase *absenceUsecase) reviewAbsence(id uuid.UUID, reviewerId uuid.UUID, status string, comment string) (*model.Absence, error) {
+	absence, err := usecase.GetAbsenceById(id)
+	if err != nil {
+		return nil, err
+	}
+	if absence.Status != model.AbsenceStatusRequested {
+		return nil, NewInvalidValueError(fmt.Sprintf("absence %v has already been reviewed", id))
+	}
+	if reviewerId == absence.UserID {
+		return nil, NewInvalidValueError(fmt.Sprintf("absence %v must be reviewed by another team manager", id))
+	}
+	absence.Status = status
+	absence.ReviewedBy = &reviewerId
+	if comment != "" {
+		absence.Comment = comment
+	}
+	err = usecase.repo.UpdateAbsence(absence)
+	if err != nil {
+		return nil, err
+	}
+	notificationType := model.NotificationAbsenceApproved
+	if status == model.AbsenceStatusRejected {
+		notificationType = model.NotificationAbsenceRejected
+	}
+	usecase.notificationUsecase.Notify(absence.UserID, notificationType, usecase.getNotificationData(absence))
+	return absence, nil
+}
+
+func (usecase *absenceUsecase) getNotificationData(absence *model.Absence) map[string]string {
+	data := map[string]string{
+		"AbsenceType": absence.Type,
+		"StartDate":   absence.StartDate.Format(notificationDateFormat),
+		"EndDate":     absence.EndDate.Format(notificationDateFormat),
+		"Comment":     absence.Comment,
+	}
+	team, err := usecase.teamUsecase.GetTeamById(absence.TeamID)
+	if err == nil {
+		data["TeamName"] = team.Name1
+	}
+	return data
+}
+
+func (usecase *absenceUsecase) checkAbsence(absence *model.Absence) error {
+	if absence.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	if absence.TeamID == uuid.Nil {
+		return NewEntityIncompleteError("the team id must not be empty")
+	}
+	if absence.StartDate.IsZero() || absence.EndDate.IsZero() {
+		return NewEntityIncompleteError("the start and end date of the absence must not be empty")
+	}
+	switch absence.Type {
+	case model.AbsenceTypeVacation, model.AbsenceTypeSickLeave, model.AbsenceTypeOther:
+	default:
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid absence type", absence.Type))
+	}
+	absence.StartDate = startOfDay(absence.StartDate)
+	absence.EndDate = startOfDay(absence.EndDate)
+	if absence.EndDate.Before(absence.StartDate) {
+		return NewInvalidValueError("the end date of the absence must not be before its start date")
+	}
+	if !usecase.teamUsecase.DoesUserBelongToTeam(absence.UserID, absence.TeamID) {
+		return NewEntityNotFoundError(fmt.Sprintf("user %v is not a member of team %v", absence.UserID, absence.TeamID))
+	}
+	overlappingAbsences, err := usecase.repo.GetAbsencesOfUser(absence.UserID, absence.StartDate, absence.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+	for _, overlappingAbsence := range overlappingAbsences {
+		if overlappingAbsence.ID != absence.ID && overlappingAbsence.Status != model.AbsenceStatusRejected {
+			return NewEntityExistsError(fmt.Sprintf("the absence overlaps with absence %v", overlappingAbsence.ID))
+		}
+	}
+	return nil
+}
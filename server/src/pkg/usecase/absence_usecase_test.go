@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_absenceUsecase_RequestAbsence(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+
+	absencesFromDb, err := usecaseTest.AbsenceUsecase.GetAbsencesOfUser(userId, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(absencesFromDb))
+	assert.Equal(t, model.AbsenceStatusRequested, absencesFromDb[0].Status)
+	assert.Equal(t, model.AbsenceTypeVacation, absencesFromDb[0].Type)
+	assert.True(t, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC).Equal(absencesFromDb[0].StartDate))
+	assert.True(t, time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC).Equal(absencesFromDb[0].EndDate))
+}
+
+func Test_absenceUsecase_RequestAbsenceFailsWithInvalidType(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	absence.Type = "HOLIDAY"
+	err := usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_absenceUsecase_RequestAbsenceFailsIfEndIsBeforeStart(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_absenceUsecase_RequestAbsenceFailsIfItOverlaps(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+
+	overlappingAbsence := newAbsence(userId, team.ID, time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 12, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.AbsenceUsecase.RequestAbsence(&overlappingAbsence)
+	assert.NotNil(t, err)
+	var entityExistsError *EntityExistsError
+	assert.True(t, errors.As(err, &entityExistsError))
+}
+
+func Test_absenceUsecase_ApproveAbsence(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+
+	approvedAbsence, err := usecaseTest.AbsenceUsecase.ApproveAbsence(absence.ID, adminId)
+	assert.Nil(t, err)
+	assert.Equal(t, model.AbsenceStatusApproved, approvedAbsence.Status)
+	assert.Equal(t, adminId, *approvedAbsence.ReviewedBy)
+
+	// An absence can only be reviewed once:
+	_, err = usecaseTest.AbsenceUsecase.RejectAbsence(absence.ID, adminId, "too late")
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	approvedAbsences, err := usecaseTest.AbsenceUsecase.GetApprovedAbsencesOfUser(userId, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(approvedAbsences))
+}
+
+func Test_absenceUsecase_RejectAbsence(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+
+	rejectedAbsence, err := usecaseTest.AbsenceUsecase.RejectAbsence(absence.ID, reviewerId, "busy week")
+	assert.Nil(t, err)
+	assert.Equal(t, model.AbsenceStatusRejected, rejectedAbsence.Status)
+	assert.Equal(t, "busy week", rejectedAbsence.Comment)
+
+	approvedAbsences, err := usecaseTest.AbsenceUsecase.GetApprovedAbsencesOfUser(userId, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(approvedAbsences))
+}
+
+func Test_absenceUsecase_ApproveAbsenceFailsForOwnAbsence(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+
+	_, err = usecaseTest.AbsenceUsecase.ApproveAbsence(absence.ID, userId)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	absenceFromDb, err := usecaseTest.AbsenceUsecase.GetAbsenceById(absence.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.AbsenceStatusRequested, absenceFromDb.Status)
+	assert.Nil(t, absenceFromDb.ReviewedBy)
+}
+
+func Test_absenceUsecase_DeleteAbsenceFailsIfItDoesNotExist(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	missingId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	err = usecaseTest.AbsenceUsecase.DeleteAbsence(missingId)
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_absenceUsecase_ApprovedAbsencesReduceTargetTime(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+
+	// Vacation from monday until wednesday noon:
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 6, 0, 0, 0, 0, time.UTC))
+	absence.HalfDayEnd = true
+	err = usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+
+	from := time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC)
+	// Requested absences do not count yet:
+	targetTime, err := usecaseTest.WorkingTimeUsecase.GetTargetTime(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 40*time.Hour, targetTime)
+
+	_, err = usecaseTest.AbsenceUsecase.ApproveAbsence(absence.ID, reviewerId)
+	assert.Nil(t, err)
+	targetTimes, err := usecaseTest.WorkingTimeUsecase.GetDailyTargetTimes(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), targetTimes[0].Target)
+	assert.Equal(t, 8*time.Hour, targetTimes[0].Absence)
+	assert.Equal(t, 4*time.Hour, targetTimes[2].Target)
+	assert.Equal(t, 4*time.Hour, targetTimes[2].Absence)
+
+	targetTime, err = usecaseTest.WorkingTimeUsecase.GetTargetTime(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 20*time.Hour, targetTime)
+}
+
+func newAbsence(userId uuid.UUID, teamId uuid.UUID, startDate time.Time, endDate time.Time) model.Absence {
+	return model.Absence{
+		UserID:    userId,
+		TeamID:    teamId,
+		Type:      model.AbsenceTypeVacation,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/export"
+
+	"github.com/gofrs/uuid"
+)
+
+// calendarFeedTokenLength is the number of random bytes of a calendar feed token
+const calendarFeedTokenLength = 32
+
+type CalendarFeedUsecase interface {
+	GetCalendarFeedToken(userId uuid.UUID) (*model.CalendarFeedToken, error)
+	RegenerateCalendarFeedToken(userId uuid.UUID) (string, error)
+	DeleteCalendarFeedToken(userId uuid.UUID) error
+	GetUserIdOfCalendarFeedToken(token string) (uuid.UUID, error)
+	GetCalendarFeedRows(userId uuid.UUID, filter ExportFilter) ([]export.Row, error)
+}
+
+type calendarFeedUsecase struct {
+	repo          repository.CalendarFeedRepository
+	exportUsecase ExportUsecase
+}
+
+func NewCalendarFeedUsecase(repo repository.CalendarFeedRepository, exportUsecase ExportUsecase) CalendarFeedUsecase {
+	return &calendarFeedUsecase{
+		repo:          repo,
+		exportUsecase: exportUsecase,
+	}
+}
+
+func (usecase *calendarFeedUsecase) GetCalendarFeedToken(userId uuid.UUID) (*model.CalendarFeedToken, error) {
+	token, err := usecase.repo.GetCalendarFeedTokenOfUser(userId)
+	if err != nil {
+		return nil, NewEntityNotFoundError("the calendar feed is not enabled")
+	}
+	return token, nil
+}
+
+// RegenerateCalendarFeedToken creates a new secret token for the calendar feed of the user and returns it. A previous
+// token becomes invalid.
+func (usecase *calendarFeedUsecase) RegenerateCalendarFeedToken(userId uuid.UUID) (string, error) {
+	secret := make([]byte, calendarFeedTokenLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(secret)
+	feedToken, err := usecase.repo.GetCalendarFeedTokenOfUser(userId)
+	if err != nil {
+		feedToken = &model.CalendarFeedToken{UserID: userId}
+	}
+	feedToken.TokenHash = hashCalendarFeedToken(token)
+	if err := usecase.repo.SaveCalendarFeedToken(feedToken); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeleteCalendarFeedToken disables the calendar feed of the user.
+func (usecase *calendarFeedUsecase) DeleteCalendarFeedToken(userId uuid.UUID) error {
+	feedToken, err := usecase.repo.GetCalendarFeedTokenOfUser(userId)
+	if err != nil {
+		return nil
+	}
+	return usecase.repo.DeleteCalendarFeedToken(feedToken)
+}
+
+// GetUserIdOfCalendarFeedToken returns the user the secret token belongs to.
+func (usecase *calendarFeedUsecase) GetUserIdOfCalendarFeedToken(token string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, NewEntityNotFoundError("unknown calendar feed token")
+	}
+	feedToken, err := usecase.repo.GetCalendarFeedTokenByHash(hashCalendarFeedToken(token))
+	if err != nil {
+		return uuid.Nil, NewEntityNotFoundError("unknown calendar feed token")
+	}
+	return feedToken.UserID, nil
+}
+
+func (usecase *calendarFeedUsecase) GetCalendarFeedRows(userId uuid.UUID, filter ExportFilter) ([]export.Row, error) {
+	return usecase.exportUsecase.GetExportRowsOfUser(userId, filter)
+}
+
+// hashCalendarFeedToken returns the hex encoded SHA-256 hash of the token. The tokens are random, so no salt is needed.
+func hashCalendarFeedToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
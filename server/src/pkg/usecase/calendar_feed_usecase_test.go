@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_calendarFeedUsecase_RegenerateCalendarFeedToken(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	var entityNotFoundError *EntityNotFoundError
+	_, err := usecaseTest.CalendarFeedUsecase.GetCalendarFeedToken(userId)
+	assert.True(t, errors.As(err, &entityNotFoundError))
+
+	token, err := usecaseTest.CalendarFeedUsecase.RegenerateCalendarFeedToken(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*calendarFeedTokenLength, len(token))
+	feedToken, err := usecaseTest.CalendarFeedUsecase.GetCalendarFeedToken(userId)
+	assert.Nil(t, err)
+	// Only the hash of the token is stored:
+	assert.NotEqual(t, token, feedToken.TokenHash)
+	tokenUserId, err := usecaseTest.CalendarFeedUsecase.GetUserIdOfCalendarFeedToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, userId, tokenUserId)
+
+	// A new token invalidates the old one:
+	newToken, err := usecaseTest.CalendarFeedUsecase.RegenerateCalendarFeedToken(userId)
+	assert.Nil(t, err)
+	assert.NotEqual(t, token, newToken)
+	_, err = usecaseTest.CalendarFeedUsecase.GetUserIdOfCalendarFeedToken(token)
+	assert.True(t, errors.As(err, &entityNotFoundError))
+	tokenUserId, err = usecaseTest.CalendarFeedUsecase.GetUserIdOfCalendarFeedToken(newToken)
+	assert.Nil(t, err)
+	assert.Equal(t, userId, tokenUserId)
+
+	err = usecaseTest.CalendarFeedUsecase.DeleteCalendarFeedToken(userId)
+	assert.Nil(t, err)
+	_, err = usecaseTest.CalendarFeedUsecase.GetUserIdOfCalendarFeedToken(newToken)
+	assert.True(t, errors.As(err, &entityNotFoundError))
+	_, err = usecaseTest.CalendarFeedUsecase.GetUserIdOfCalendarFeedToken("")
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_calendarFeedUsecase_GetCalendarFeedRows(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	otherProject := addProject(t, usecaseTest.ProjectUsecase, "other project", userId)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 8, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, otherProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+	addReportTimeEntry(t, usecaseTest, GetTestUserId(t), project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	filter := ExportFilter{From: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)}
+	rows, err := usecaseTest.CalendarFeedUsecase.GetCalendarFeedRows(userId, filter)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+
+	filter.ProjectIds = append(filter.ProjectIds, project.ID)
+	rows, err = usecaseTest.CalendarFeedUsecase.GetCalendarFeedRows(userId, filter)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "project", rows[0].TimeEntry.Project.Name)
+}
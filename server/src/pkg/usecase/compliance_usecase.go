@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"time"
+	"timeasy-server/pkg/compliance"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type ComplianceUsecase interface {
+	GetViolationsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.ComplianceViolation, error)
+	GetViolationsOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.ComplianceViolation, error)
+	CheckTimeEntry(timeEntry *model.TimeEntry) ([]model.ComplianceViolation, error)
+}
+
+type complianceUsecase struct {
+	timeEntryUsecase TimeEntryUsecase
+	teamUsecase      TeamUsecase
+}
+
+func NewComplianceUsecase(timeEntryUsecase TimeEntryUsecase, teamUsecase TeamUsecase) ComplianceUsecase {
+	return &complianceUsecase{
+		timeEntryUsecase: timeEntryUsecase,
+		teamUsecase:      teamUsecase,
+	}
+}
+
+// GetViolationsOfUser checks all time entries of the user against the working time law and returns the violations on
+// the days in the interval [from, to). A zero time leaves the respective side of the interval open.
+func (usecase *complianceUsecase) GetViolationsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.ComplianceViolation, error) {
+	from = startOfDayOrZero(from)
+	to = startOfDayOrZero(to)
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, NewInvalidValueError("the start of the date range must be before its end")
+	}
+	// The entries of the day before are needed to check the rest period of the first day:
+	queryFrom := from
+	if !queryFrom.IsZero() {
+		queryFrom = queryFrom.AddDate(0, 0, -1)
+	}
+	timeEntries, err := usecase.timeEntryUsecase.GetTimeEntriesOfUserInRange(userId, queryFrom, to)
+	if err != nil {
+		return nil, err
+	}
+	var violations []model.ComplianceViolation
+	for _, violation := range compliance.CheckTimeEntries(timeEntries) {
+		if !violation.Day.Before(from) {
+			violations = append(violations, violation)
+		}
+	}
+	return violations, nil
+}
+
+// GetViolationsOfTeam returns the violations of all members of the team. The working time law applies to the whole
+// working time of a user, so the time entries of all projects are checked.
+func (usecase *complianceUsecase) GetViolationsOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.ComplianceViolation, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	members, err := usecase.teamUsecase.GetUsersOfTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+	var violations []model.ComplianceViolation
+	for _, member := range members {
+		violationsOfUser, err := usecase.GetViolationsOfUser(member.UserID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, violationsOfUser...)
+	}
+	return violations, nil
+}
+
+// CheckTimeEntry returns the violations a stored time entry is involved in: those on the day it starts and the rest
+// period of the following day.
+func (usecase *complianceUsecase) CheckTimeEntry(timeEntry *model.TimeEntry) ([]model.ComplianceViolation, error) {
+	day := startOfDay(timeEntry.StartTime)
+	violations, err := usecase.GetViolationsOfUser(timeEntry.UserId, day, day.AddDate(0, 0, 2))
+	if err != nil {
+		return nil, err
+	}
+	var violationsOfEntry []model.ComplianceViolation
+	for _, violation := range violations {
+		if violation.Day.Equal(day) || violation.Type == model.ViolationRestPeriodTooShort {
+			violationsOfEntry = append(violationsOfEntry, violation)
+		}
+	}
+	return violationsOfEntry, nil
+}
+
+func startOfDayOrZero(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return startOfDay(t)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_complianceUsecase_GetViolationsOfUser(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 3, 14, 0, 0, 0, time.UTC), 8*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 4, 7, 0, 0, 0, time.UTC), 7*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 6, 12, 30, 0, 0, time.UTC), 4*time.Hour)
+
+	violations, err := usecaseTest.ComplianceUsecase.GetViolationsOfUser(userId,
+		time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(violations))
+	assert.Equal(t, model.ViolationBreakTooShort, violations[0].Type)
+	assert.True(t, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC).Equal(violations[0].Day))
+	assert.Equal(t, model.ViolationRestPeriodTooShort, violations[1].Type)
+	assert.Equal(t, 9*time.Hour, violations[1].Actual)
+	assert.Equal(t, userId, violations[1].UserID)
+}
+
+func Test_complianceUsecase_GetViolationsOfTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	memberId := GetTestUserId(t)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	otherUserId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	addReportTimeEntry(t, usecaseTest, memberId, project, time.Date(2023, 9, 4, 7, 0, 0, 0, time.UTC), 11*time.Hour)
+	addReportTimeEntry(t, usecaseTest, otherUserId, project, time.Date(2023, 9, 4, 7, 0, 0, 0, time.UTC), 11*time.Hour)
+
+	violations, err := usecaseTest.ComplianceUsecase.GetViolationsOfTeam(team.ID, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(violations))
+	for _, violation := range violations {
+		assert.Equal(t, memberId, violation.UserID)
+	}
+}
+
+func Test_complianceUsecase_CheckTimeEntry(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 6, 0, 0, 0, time.UTC), 6*time.Hour)
+	timeEntry := addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 4, 14, 0, 0, 0, time.UTC), 9*time.Hour)
+
+	violations, err := usecaseTest.ComplianceUsecase.CheckTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(violations))
+	assert.Equal(t, model.ViolationBreakTooShort, violations[0].Type)
+	assert.Equal(t, model.ViolationRestPeriodTooShort, violations[1].Type)
+	assert.True(t, time.Date(2023, 9, 5, 0, 0, 0, 0, time.UTC).Equal(violations[1].Day))
+}
+
+func Test_complianceUsecase_GetViolationsOfUnknownTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	_, err := usecaseTest.ComplianceUsecase.GetViolationsOfTeam(uuid.Must(uuid.NewV4()), time.Time{}, time.Time{})
+	assert.NotNil(t, err)
+}
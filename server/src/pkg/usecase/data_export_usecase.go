@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/export"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/glog"
+)
+
+// dataExportDirectLimit is the number of time entries up to which an export is generated directly when it is
+// requested, larger exports are generated in the background.
+var dataExportDirectLimit int64 = 5000
+
+// dataExportBatchSize is the number of exports generated by one run of the background job
+const dataExportBatchSize = 10
+
+// DataExportDownloadPath is the path of the REST API the file of an export is downloaded from
+const DataExportDownloadPath = "/api/v1/dataexports/%v/download"
+
+type DataExportUsecase interface {
+	RequestDataExport(userId uuid.UUID, requestedBy uuid.UUID) (*model.DataExport, error)
+	GetDataExportById(id uuid.UUID) (*model.DataExport, error)
+	GetDataExportsOfUser(userId uuid.UUID) ([]model.DataExport, error)
+	GetDataExportContent(id uuid.UUID, now time.Time) ([]byte, error)
+	ProcessDataExports(now time.Time) (int, error)
+	WritePersonalData(userId uuid.UUID, writer io.Writer, now time.Time) error
+}
+
+type dataExportUsecase struct {
+	repo                repository.DataExportRepository
+	notificationUsecase NotificationUsecase
+	retention           time.Duration
+}
+
+// NewDataExportUsecase creates the usecase. The files of the exports can be downloaded for the retention time.
+func NewDataExportUsecase(repo repository.DataExportRepository, notificationUsecase NotificationUsecase,
+	retention time.Duration) DataExportUsecase {
+	return &dataExportUsecase{
+		repo:                repo,
+		notificationUsecase: notificationUsecase,
+		retention:           retention,
+	}
+}
+
+// RequestDataExport exports all personal data of the user. Small exports are generated directly, large ones are
+// pending until the background job has generated them and notified the requester. If an export of the user is
+// already pending it is returned instead of a new one.
+func (usecase *dataExportUsecase) RequestDataExport(userId uuid.UUID, requestedBy uuid.UUID) (*model.DataExport, error) {
+	dataExports, err := usecase.repo.GetDataExportsOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, dataExport := range dataExports {
+		if dataExport.Status == model.DataExportStatusPending {
+			return &dataExport, nil
+		}
+	}
+	count, err := usecase.repo.CountTimeEntriesOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	dataExport := model.DataExport{UserID: userId, RequestedBy: requestedBy, Status: model.DataExportStatusPending}
+	if err := usecase.repo.AddDataExport(&dataExport); err != nil {
+		return nil, err
+	}
+	if count > dataExportDirectLimit {
+		return &dataExport, nil
+	}
+	if err := usecase.generateDataExport(&dataExport, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	dataExport.Content = nil
+	return &dataExport, nil
+}
+
+func (usecase *dataExportUsecase) GetDataExportById(id uuid.UUID) (*model.DataExport, error) {
+	dataExport, err := usecase.repo.GetDataExportById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("data export with id %v does not exist", id))
+	}
+	return dataExport, nil
+}
+
+func (usecase *dataExportUsecase) GetDataExportsOfUser(userId uuid.UUID) ([]model.DataExport, error) {
+	return usecase.repo.GetDataExportsOfUser(userId)
+}
+
+// GetDataExportContent returns the ZIP file of the export as long as it is ready and not expired.
+func (usecase *dataExportUsecase) GetDataExportContent(id uuid.UUID, now time.Time) ([]byte, error) {
+	dataExport, err := usecase.GetDataExportById(id)
+	if err != nil {
+		return nil, err
+	}
+	if dataExport.Status != model.DataExportStatusReady {
+		return nil, NewEntityIncompleteError(fmt.Sprintf("data export %v is not ready (%v)", id, dataExport.Status))
+	}
+	if dataExport.IsExpired(now) {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("data export %v has expired", id))
+	}
+	return usecase.repo.GetDataExportContent(id)
+}
+
+// ProcessDataExports generates the pending exports, notifies their requesters and removes the expired exports. It
+// returns the number of generated exports.
+func (usecase *dataExportUsecase) ProcessDataExports(now time.Time) (int, error) {
+	deleted, err := usecase.repo.DeleteExpiredDataExports(now)
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		glog.Infof("removed %v expired personal data exports", deleted)
+	}
+	dataExports, err := usecase.repo.GetPendingDataExports(dataExportBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	generated := 0
+	for i := range dataExports {
+		dataExport := &dataExports[i]
+		if err := usecase.generateDataExport(dataExport, now); err != nil {
+			return generated, err
+		}
+		if dataExport.Status == model.DataExportStatusReady {
+			generated++
+			usecase.notificationUsecase.Notify(dataExport.RequestedBy, model.NotificationDataExportReady, map[string]string{
+				"UserId":       dataExport.UserID.String(),
+				"DownloadPath": fmt.Sprintf(DataExportDownloadPath, dataExport.ID),
+				"ExpiresAt":    dataExport.ExpiresAt.UTC().Format(notificationTimeFormat),
+			})
+		}
+	}
+	return generated, nil
+}
+
+// WritePersonalData writes the ZIP file of all personal data of the user without storing it, e.g. for the command
+// line.
+func (usecase *dataExportUsecase) WritePersonalData(userId uuid.UUID, writer io.Writer, now time.Time) error {
+	data, err := usecase.repo.GetPersonalData(userId)
+	if err != nil {
+		return err
+	}
+	return export.WritePersonalDataZip(writer, *data, now)
+}
+
+// generateDataExport writes the file of the export. If the file cannot be written the export is marked as failed,
+// only errors of storing the export are returned.
+func (usecase *dataExportUsecase) generateDataExport(dataExport *model.DataExport, now time.Time) error {
+	var buffer bytes.Buffer
+	if err := usecase.WritePersonalData(dataExport.UserID, &buffer, now); err != nil {
+		glog.Errorf("error exporting the personal data of user %v: %v", dataExport.UserID, err)
+		dataExport.Status = model.DataExportStatusFailed
+		dataExport.LastError = err.Error()
+	} else {
+		dataExport.Status = model.DataExportStatusReady
+		dataExport.Content = buffer.Bytes()
+		dataExport.Size = buffer.Len()
+	}
+	// Failed exports are removed as well
+	expiresAt := now.Add(usecase.retention)
+	dataExport.CompletedAt = &now
+	dataExport.ExpiresAt = &expiresAt
+	return usecase.repo.UpdateDataExport(dataExport)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dataExportUsecase_RequestDataExport(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "Project", userId)
+	timeEntry := model.TimeEntry{UserId: userId, ProjectId: project.ID,
+		StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC)}
+	assert.Nil(t, usecaseTest.TimeEntryUsecase.AddTimeEntry(&timeEntry))
+
+	// Small exports are generated directly:
+	dataExport, err := usecaseTest.DataExportUsecase.RequestDataExport(userId, userId)
+	assert.Nil(t, err)
+	assert.Equal(t, model.DataExportStatusReady, dataExport.Status)
+	assert.NotNil(t, dataExport.ExpiresAt)
+	assert.Greater(t, dataExport.Size, 0)
+	content, err := usecaseTest.DataExportUsecase.GetDataExportContent(dataExport.ID, time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, dataExport.Size, len(content))
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+	assert.Equal(t, "README.txt", zipReader.File[0].Name)
+
+	// The file cannot be downloaded after it expired and is removed by the job:
+	var entityNotFoundError *EntityNotFoundError
+	_, err = usecaseTest.DataExportUsecase.GetDataExportContent(dataExport.ID, dataExport.ExpiresAt.Add(time.Second))
+	assert.True(t, errors.As(err, &entityNotFoundError))
+	_, err = usecaseTest.DataExportUsecase.ProcessDataExports(dataExport.ExpiresAt.Add(time.Second))
+	assert.Nil(t, err)
+	dataExports, err := usecaseTest.DataExportUsecase.GetDataExportsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, dataExports)
+}
+
+func Test_dataExportUsecase_ProcessDataExports(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	directLimit := dataExportDirectLimit
+	dataExportDirectLimit = -1
+	defer func() { dataExportDirectLimit = directLimit }()
+
+	userId := GetTestUserId(t)
+	adminId := GetTestUserId(t)
+	dataExport, err := usecaseTest.DataExportUsecase.RequestDataExport(userId, adminId)
+	assert.Nil(t, err)
+	assert.Equal(t, model.DataExportStatusPending, dataExport.Status)
+	var entityIncompleteError *EntityIncompleteError
+	_, err = usecaseTest.DataExportUsecase.GetDataExportContent(dataExport.ID, time.Now().UTC())
+	assert.True(t, errors.As(err, &entityIncompleteError))
+	// A pending export is not requested twice:
+	pendingExport, err := usecaseTest.DataExportUsecase.RequestDataExport(userId, userId)
+	assert.Nil(t, err)
+	assert.Equal(t, dataExport.ID, pendingExport.ID)
+
+	generated, err := usecaseTest.DataExportUsecase.ProcessDataExports(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, generated)
+	dataExport, err = usecaseTest.DataExportUsecase.GetDataExportById(dataExport.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.DataExportStatusReady, dataExport.Status)
+	_, err = usecaseTest.DataExportUsecase.GetDataExportContent(dataExport.ID, time.Now().UTC())
+	assert.Nil(t, err)
+
+	// The requester is notified:
+	notifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(adminId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, model.NotificationDataExportReady, notifications[0].Type)
+	assert.Contains(t, notifications[0].Body, dataExport.ID.String())
+}
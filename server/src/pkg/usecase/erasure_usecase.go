@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/glog"
+)
+
+type ErasureUsecase interface {
+	RequestErasure(userId uuid.UUID, requestedBy uuid.UUID, now time.Time) (*model.ErasureRequest, error)
+	ConfirmErasure(id uuid.UUID, confirmedBy uuid.UUID, now time.Time) (*model.ErasureRequest, error)
+	CancelErasure(id uuid.UUID) (*model.ErasureRequest, error)
+	GetErasureRequestById(id uuid.UUID) (*model.ErasureRequest, error)
+	GetErasureRequestsOfUser(userId uuid.UUID) ([]model.ErasureRequest, error)
+	GetOpenErasureRequests() ([]model.ErasureRequest, error)
+	ExecuteDueErasures(now time.Time) (int, error)
+	GetErasureTime(userId uuid.UUID) (*time.Time, error)
+}
+
+type erasureUsecase struct {
+	repo                repository.ErasureRepository
+	notificationUsecase NotificationUsecase
+	gracePeriod         time.Duration
+}
+
+// NewErasureUsecase creates the usecase. The data of a user is erased when the grace period after the request has
+// passed and an admin has confirmed the request.
+func NewErasureUsecase(repo repository.ErasureRepository, notificationUsecase NotificationUsecase,
+	gracePeriod time.Duration) ErasureUsecase {
+	return &erasureUsecase{
+		repo:                repo,
+		notificationUsecase: notificationUsecase,
+		gracePeriod:         gracePeriod,
+	}
+}
+
+// RequestErasure requests the erasure of all personal data of the user. Only one open request per user is allowed.
+func (usecase *erasureUsecase) RequestErasure(userId uuid.UUID, requestedBy uuid.UUID, now time.Time) (*model.ErasureRequest, error) {
+	requests, err := usecase.repo.GetErasureRequestsOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.IsOpen() {
+			return nil, NewEntityExistsError(fmt.Sprintf("the erasure of the data of user %v was already requested (%v)",
+				userId, request.ID))
+		}
+	}
+	request := model.ErasureRequest{
+		UserID:      userId,
+		RequestedBy: requestedBy,
+		Status:      model.ErasureStatusRequested,
+		DueAt:       now.Add(usecase.gracePeriod),
+	}
+	if err := usecase.repo.AddErasureRequest(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ConfirmErasure confirms the request, the data is erased by the background job when the grace period has passed.
+// The user is notified, so the erasure can still be cancelled. The admin who requested the erasure cannot confirm it.
+func (usecase *erasureUsecase) ConfirmErasure(id uuid.UUID, confirmedBy uuid.UUID, now time.Time) (*model.ErasureRequest, error) {
+	request, err := usecase.GetErasureRequestById(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.ErasureStatusRequested {
+		return nil, NewInvalidValueError(fmt.Sprintf("erasure request %v cannot be confirmed (%v)", id, request.Status))
+	}
+	if confirmedBy == request.RequestedBy {
+		return nil, NewInvalidValueError(fmt.Sprintf("erasure request %v must be confirmed by another admin", id))
+	}
+	request.Status = model.ErasureStatusConfirmed
+	request.ConfirmedBy = &confirmedBy
+	request.ConfirmedAt = &now
+	if err := usecase.repo.UpdateErasureRequest(request); err != nil {
+		return nil, err
+	}
+	usecase.notificationUsecase.Notify(request.UserID, model.NotificationErasureScheduled, map[string]string{
+		"UserId": request.UserID.String(),
+		"DueAt":  request.DueAt.UTC().Format(notificationTimeFormat),
+	})
+	return request, nil
+}
+
+// CancelErasure cancels a request which was not yet executed.
+func (usecase *erasureUsecase) CancelErasure(id uuid.UUID) (*model.ErasureRequest, error) {
+	request, err := usecase.GetErasureRequestById(id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsOpen() {
+		return nil, NewInvalidValueError(fmt.Sprintf("erasure request %v cannot be cancelled (%v)", id, request.Status))
+	}
+	request.Status = model.ErasureStatusCancelled
+	if err := usecase.repo.UpdateErasureRequest(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (usecase *erasureUsecase) GetErasureRequestById(id uuid.UUID) (*model.ErasureRequest, error) {
+	request, err := usecase.repo.GetErasureRequestById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("erasure request with id %v does not exist", id))
+	}
+	return request, nil
+}
+
+func (usecase *erasureUsecase) GetErasureRequestsOfUser(userId uuid.UUID) ([]model.ErasureRequest, error) {
+	return usecase.repo.GetErasureRequestsOfUser(userId)
+}
+
+// GetOpenErasureRequests returns the requests waiting for a confirmation or for the end of their grace period.
+func (usecase *erasureUsecase) GetOpenErasureRequests() ([]model.ErasureRequest, error) {
+	return usecase.repo.GetErasureRequestsWithStatus([]string{model.ErasureStatusRequested, model.ErasureStatusConfirmed})
+}
+
+// ExecuteDueErasures erases the data of the confirmed requests whose grace period has passed and stores the receipt
+// of each erasure together with the erased data. It returns the number of executed erasures.
+func (usecase *erasureUsecase) ExecuteDueErasures(now time.Time) (int, error) {
+	requests, err := usecase.repo.GetDueErasureRequests(now)
+	if err != nil {
+		return 0, err
+	}
+	executed := 0
+	for i := range requests {
+		request := &requests[i]
+		if err := usecase.repo.EraseUserData(request, now); err != nil {
+			return executed, err
+		}
+		glog.Infof("erased the personal data of user %v (request %v)", request.UserID, request.ID)
+		executed++
+	}
+	return executed, nil
+}
+
+// GetErasureTime returns the time of the latest erasure of the data of the user or nil if it was never erased.
+// Clients that synchronized before this time have to delete their local copy.
+func (usecase *erasureUsecase) GetErasureTime(userId uuid.UUID) (*time.Time, error) {
+	requests, err := usecase.repo.GetErasureRequestsOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	var erasureTime *time.Time
+	for _, request := range requests {
+		if request.Status == model.ErasureStatusCompleted && request.CompletedAt != nil &&
+			(erasureTime == nil || request.CompletedAt.After(*erasureTime)) {
+			erasureTime = request.CompletedAt
+		}
+	}
+	return erasureTime, nil
+}
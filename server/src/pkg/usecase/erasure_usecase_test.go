@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_erasureUsecase_ExecuteDueErasures(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	adminId := GetTestUserId(t)
+	otherUserId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "Team", userId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(otherUserId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	personalProject := addProject(t, usecaseTest.ProjectUsecase, "Personal", userId)
+	teamProject := model.Project{Name: "Team project", UserId: userId, TeamID: &team.ID}
+	assert.Nil(t, usecaseTest.ProjectUsecase.AddProject(&teamProject))
+	startTime := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	timeEntry := model.TimeEntry{UserId: userId, ProjectId: personalProject.ID, StartTime: startTime,
+		EndTime: startTime.Add(time.Hour)}
+	assert.Nil(t, usecaseTest.TimeEntryUsecase.AddTimeEntry(&timeEntry))
+	otherTimeEntry := model.TimeEntry{UserId: otherUserId, ProjectId: teamProject.ID, StartTime: startTime,
+		EndTime: startTime.Add(time.Hour)}
+	assert.Nil(t, usecaseTest.TimeEntryUsecase.AddTimeEntry(&otherTimeEntry))
+
+	now := time.Now().UTC()
+	request, err := usecaseTest.ErasureUsecase.RequestErasure(userId, userId, now)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusRequested, request.Status)
+	assert.Equal(t, now.Add(14*24*time.Hour), request.DueAt)
+	// Only one open request per user:
+	var entityExistsError *EntityExistsError
+	_, err = usecaseTest.ErasureUsecase.RequestErasure(userId, userId, now)
+	assert.True(t, errors.As(err, &entityExistsError))
+
+	// Requests are only executed after the confirmation:
+	executed, err := usecaseTest.ErasureUsecase.ExecuteDueErasures(request.DueAt.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, executed)
+	request, err = usecaseTest.ErasureUsecase.ConfirmErasure(request.ID, adminId, now)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusConfirmed, request.Status)
+	notifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, model.NotificationErasureScheduled, notifications[0].Type)
+	// ... and after the grace period:
+	executed, err = usecaseTest.ErasureUsecase.ExecuteDueErasures(now.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, executed)
+	erasureTime := request.DueAt.Add(time.Second)
+	executed, err = usecaseTest.ErasureUsecase.ExecuteDueErasures(erasureTime)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, executed)
+
+	_, err = usecaseTest.TimeEntryUsecase.GetTimeEntryById(timeEntry.ID)
+	assert.NotNil(t, err)
+	_, err = usecaseTest.ProjectUsecase.GetProjectById(personalProject.ID)
+	assert.NotNil(t, err)
+	teamAssignments, err := usecaseTest.TeamUsecase.GetTeamsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, teamAssignments)
+	notifications, err = usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Empty(t, notifications)
+	// The team project and the entries of the other members are kept:
+	keptProject, err := usecaseTest.ProjectUsecase.GetProjectById(teamProject.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.Nil, keptProject.UserId)
+	_, err = usecaseTest.TimeEntryUsecase.GetTimeEntryById(otherTimeEntry.ID)
+	assert.Nil(t, err)
+
+	request, err = usecaseTest.ErasureUsecase.GetErasureRequestById(request.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusCompleted, request.Status)
+	var receipt model.ErasureReceipt
+	assert.Nil(t, json.Unmarshal([]byte(request.Receipt), &receipt))
+	assert.Equal(t, userId, receipt.UserID)
+	assert.Equal(t, adminId, receipt.ConfirmedBy)
+	assert.Equal(t, int64(1), receipt.Deleted["time_entries"])
+	assert.Equal(t, int64(1), receipt.Deleted["projects"])
+	assert.Equal(t, int64(1), receipt.Deleted["user_team_assignments"])
+	assert.Equal(t, int64(1), receipt.Anonymised["projects"])
+
+	erasedAt, err := usecaseTest.ErasureUsecase.GetErasureTime(userId)
+	assert.Nil(t, err)
+	assert.NotNil(t, erasedAt)
+	assert.WithinDuration(t, erasureTime, *erasedAt, time.Millisecond)
+	erasedAt, err = usecaseTest.ErasureUsecase.GetErasureTime(otherUserId)
+	assert.Nil(t, err)
+	assert.Nil(t, erasedAt)
+}
+
+func Test_erasureUsecase_CancelErasure(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	adminId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "Project", userId)
+	now := time.Now().UTC()
+	request, err := usecaseTest.ErasureUsecase.RequestErasure(userId, userId, now)
+	assert.Nil(t, err)
+	_, err = usecaseTest.ErasureUsecase.ConfirmErasure(request.ID, adminId, now)
+	assert.Nil(t, err)
+	request, err = usecaseTest.ErasureUsecase.CancelErasure(request.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusCancelled, request.Status)
+
+	executed, err := usecaseTest.ErasureUsecase.ExecuteDueErasures(request.DueAt.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, executed)
+	_, err = usecaseTest.ProjectUsecase.GetProjectById(project.ID)
+	assert.Nil(t, err)
+
+	// Cancelled requests cannot be confirmed or cancelled again:
+	var invalidValueError *InvalidValueError
+	_, err = usecaseTest.ErasureUsecase.ConfirmErasure(request.ID, adminId, now)
+	assert.True(t, errors.As(err, &invalidValueError))
+	_, err = usecaseTest.ErasureUsecase.CancelErasure(request.ID)
+	assert.True(t, errors.As(err, &invalidValueError))
+	openRequests, err := usecaseTest.ErasureUsecase.GetOpenErasureRequests()
+	assert.Nil(t, err)
+	assert.Empty(t, openRequests)
+	// A new erasure can be requested:
+	_, err = usecaseTest.ErasureUsecase.RequestErasure(userId, userId, now)
+	assert.Nil(t, err)
+}
+
+func Test_erasureUsecase_ConfirmErasureRequiresAnotherAdmin(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	adminId := GetTestUserId(t)
+	otherAdminId := GetTestUserId(t)
+	now := time.Now().UTC()
+	request, err := usecaseTest.ErasureUsecase.RequestErasure(userId, adminId, now)
+	assert.Nil(t, err)
+
+	var invalidValueError *InvalidValueError
+	_, err = usecaseTest.ErasureUsecase.ConfirmErasure(request.ID, adminId, now)
+	assert.True(t, errors.As(err, &invalidValueError))
+	request, err = usecaseTest.ErasureUsecase.GetErasureRequestById(request.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusRequested, request.Status)
+
+	request, err = usecaseTest.ErasureUsecase.ConfirmErasure(request.ID, otherAdminId, now)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ErasureStatusConfirmed, request.Status)
+}
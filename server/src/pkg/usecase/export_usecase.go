@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/export"
+
+	"github.com/gofrs/uuid"
+)
+
+// ExportFilter selects the time entries of an export. The range [From, To) is open on a side with a zero time,
+// empty lists do not filter.
+type ExportFilter struct {
+	From       time.Time
+	To         time.Time
+	ProjectIds []uuid.UUID
+	UserIds    []uuid.UUID
+	// Clients are the clients of the projects
+	Clients []string
+}
+
+type ExportUsecase interface {
+	GetExportRowsOfUser(userId uuid.UUID, filter ExportFilter) ([]export.Row, error)
+	GetExportRowsOfTeam(teamId uuid.UUID, filter ExportFilter) ([]export.Row, error)
+}
+
+type exportUsecase struct {
+	timeEntryUsecase TimeEntryUsecase
+	projectUsecase   ProjectUsecase
+	teamUsecase      TeamUsecase
+}
+
+func NewExportUsecase(timeEntryUsecase TimeEntryUsecase, projectUsecase ProjectUsecase, teamUsecase TeamUsecase) ExportUsecase {
+	return &exportUsecase{
+		timeEntryUsecase: timeEntryUsecase,
+		projectUsecase:   projectUsecase,
+		teamUsecase:      teamUsecase,
+	}
+}
+
+// GetExportRowsOfUser returns the time entries of the user sorted by start time. The net durations are calculated
+// from all entries of the user before the project filter is applied, because the breaks depend on the whole day.
+func (usecase *exportUsecase) GetExportRowsOfUser(userId uuid.UUID, filter ExportFilter) ([]export.Row, error) {
+	timeEntries, err := usecase.timeEntryUsecase.GetTimeEntriesOfUserInRange(userId, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[uuid.UUID]model.Project)
+	for i := range timeEntries {
+		project, ok := projects[timeEntries[i].ProjectId]
+		if !ok {
+			loadedProject, err := usecase.projectUsecase.GetProjectById(timeEntries[i].ProjectId)
+			if err != nil {
+				return nil, err
+			}
+			project = *loadedProject
+			projects[project.ID] = project
+		}
+		timeEntries[i].Project = project
+	}
+	return filter.apply(export.NewRows(timeEntries)), nil
+}
+
+// GetExportRowsOfTeam returns the time entries of the projects of the team sorted by start time. The net durations
+// are calculated from the team entries of each user only, private entries of the members are not taken into account.
+func (usecase *exportUsecase) GetExportRowsOfTeam(teamId uuid.UUID, filter ExportFilter) ([]export.Row, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	timeEntries, err := usecase.timeEntryUsecase.GetAllTimeEntriesOfTeam(teamId, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	return filter.apply(export.NewRows(timeEntries)), nil
+}
+
+func (filter ExportFilter) apply(rows []export.Row) []export.Row {
+	var filteredRows []export.Row
+	for _, row := range rows {
+		if containsId(filter.ProjectIds, row.TimeEntry.ProjectId) && containsId(filter.UserIds, row.TimeEntry.UserId) &&
+			containsClient(filter.Clients, row.TimeEntry.Project.Client) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return filteredRows
+}
+
+// containsId returns true if the id is in the list or the list is empty.
+func containsId(ids []uuid.UUID, id uuid.UUID) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// containsClient returns true if the client is in the list or the list is empty.
+func containsClient(clients []string, client string) bool {
+	if len(clients) == 0 {
+		return true
+	}
+	for _, candidate := range clients {
+		if candidate == client {
+			return true
+		}
+	}
+	return false
+}
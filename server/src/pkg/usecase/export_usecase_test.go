@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/compliance"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_exportUsecase_GetExportRowsOfUser(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	otherProject := addProject(t, usecaseTest.ProjectUsecase, "other project", userId)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 4*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, otherProject, time.Date(2023, 9, 4, 12, 0, 0, 0, time.UTC), 3*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 10, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	rows, err := usecaseTest.ExportUsecase.GetExportRowsOfUser(userId, ExportFilter{
+		From: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "project", rows[0].TimeEntry.Project.Name)
+	assert.Equal(t, 4*time.Hour, rows[0].NetDuration)
+	assert.Equal(t, "other project", rows[1].TimeEntry.Project.Name)
+	assert.Equal(t, 3*time.Hour-compliance.ShortBreak, rows[1].NetDuration)
+
+	// The missing break is calculated from all entries of the day, even if a project is filtered:
+	rows, err = usecaseTest.ExportUsecase.GetExportRowsOfUser(userId, ExportFilter{
+		From:       time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		ProjectIds: []uuid.UUID{otherProject.ID},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, 3*time.Hour-compliance.ShortBreak, rows[0].NetDuration)
+}
+
+func Test_exportUsecase_GetExportRowsOfUserFilteredByClient(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	website := model.Project{Name: "Website", UserId: userId, Client: "ACME"}
+	assert.Nil(t, usecaseTest.ProjectUsecase.AddProject(&website))
+	other := model.Project{Name: "Other", UserId: userId, Client: "Other client"}
+	assert.Nil(t, usecaseTest.ProjectUsecase.AddProject(&other))
+	addReportTimeEntry(t, usecaseTest, userId, website, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, other, time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC), 2*time.Hour)
+
+	rows, err := usecaseTest.ExportUsecase.GetExportRowsOfUser(userId, ExportFilter{
+		From:    time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		Clients: []string{"ACME"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Website", rows[0].TimeEntry.Project.Name)
+
+	rows, err = usecaseTest.ExportUsecase.GetExportRowsOfUser(userId, ExportFilter{
+		From:    time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		Clients: []string{"ACME", "Other client"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+}
+
+func Test_exportUsecase_GetExportRowsOfTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	memberId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	teamProject := addProject(t, usecaseTest.ProjectUsecase, "team project", adminId)
+	err = usecaseTest.ProjectUsecase.AssignProjectToTeam(&teamProject, &team)
+	assert.Nil(t, err)
+	privateProject := addProject(t, usecaseTest.ProjectUsecase, "private project", memberId)
+
+	addReportTimeEntry(t, usecaseTest, memberId, teamProject, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, adminId, teamProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 3*time.Hour)
+	addReportTimeEntry(t, usecaseTest, memberId, privateProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	rows, err := usecaseTest.ExportUsecase.GetExportRowsOfTeam(team.ID, ExportFilter{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, memberId, rows[0].TimeEntry.UserId)
+	assert.Equal(t, "team project", rows[0].TimeEntry.Project.Name)
+	assert.Equal(t, adminId, rows[1].TimeEntry.UserId)
+
+	rows, err = usecaseTest.ExportUsecase.GetExportRowsOfTeam(team.ID, ExportFilter{UserIds: []uuid.UUID{adminId}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, adminId, rows[0].TimeEntry.UserId)
+}
+
+func Test_exportUsecase_GetExportRowsOfUnknownTeamFails(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	_, err := usecaseTest.ExportUsecase.GetExportRowsOfTeam(GetTestUserId(t), ExportFilter{})
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
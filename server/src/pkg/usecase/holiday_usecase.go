@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/holiday"
+
+	"github.com/gofrs/uuid"
+)
+
+type HolidayUsecase interface {
+	GetHolidayCalendarById(id uuid.UUID) (*model.HolidayCalendar, error)
+	GetHolidayCalendarsOfTeam(teamId uuid.UUID) ([]model.HolidayCalendar, error)
+	AddHolidayCalendar(calendar *model.HolidayCalendar) error
+	UpdateHolidayCalendar(calendar *model.HolidayCalendar) error
+	DeleteHolidayCalendar(id uuid.UUID) error
+	GetCustomHolidayById(id uuid.UUID) (*model.CustomHoliday, error)
+	AddCustomHoliday(customHoliday *model.CustomHoliday) error
+	DeleteCustomHoliday(id uuid.UUID) error
+	AssignHolidayCalendarToTeam(teamId uuid.UUID, calendarId *uuid.UUID) error
+	AssignHolidayCalendarToUser(userId uuid.UUID, teamId uuid.UUID, calendarId *uuid.UUID) error
+	GetHolidays(calendarId uuid.UUID, from time.Time, to time.Time) ([]model.Holiday, error)
+	GetHolidaysOfUserInTeam(userId uuid.UUID, teamId uuid.UUID, from time.Time, to time.Time) ([]model.Holiday, error)
+}
+
+type holidayUsecase struct {
+	repo        repository.HolidayCalendarRepository
+	teamUsecase TeamUsecase
+}
+
+func NewHolidayUsecase(repo repository.HolidayCalendarRepository, teamUsecase TeamUsecase) HolidayUsecase {
+	return &holidayUsecase{
+		repo:        repo,
+		teamUsecase: teamUsecase,
+	}
+}
+
+func (usecase *holidayUsecase) GetHolidayCalendarById(id uuid.UUID) (*model.HolidayCalendar, error) {
+	calendar, err := usecase.repo.GetHolidayCalendarById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("holiday calendar with id %v not found", id))
+	}
+	return calendar, nil
+}
+
+func (usecase *holidayUsecase) GetHolidayCalendarsOfTeam(teamId uuid.UUID) ([]model.HolidayCalendar, error) {
+	return usecase.repo.GetHolidayCalendarsOfTeam(teamId)
+}
+
+func (usecase *holidayUsecase) AddHolidayCalendar(calendar *model.HolidayCalendar) error {
+	err := usecase.checkHolidayCalendar(calendar)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.AddHolidayCalendar(calendar)
+}
+
+func (usecase *holidayUsecase) UpdateHolidayCalendar(calendar *model.HolidayCalendar) error {
+	_, err := usecase.GetHolidayCalendarById(calendar.ID)
+	if err != nil {
+		return err
+	}
+	err = usecase.checkHolidayCalendar(calendar)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.UpdateHolidayCalendar(calendar)
+}
+
+// DeleteHolidayCalendar deletes the calendar with its custom holidays. Teams and members the calendar was assigned to
+// have no holidays afterwards.
+func (usecase *holidayUsecase) DeleteHolidayCalendar(id uuid.UUID) error {
+	calendar, err := usecase.GetHolidayCalendarById(id)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.DeleteHolidayCalendar(calendar)
+}
+
+func (usecase *holidayUsecase) GetCustomHolidayById(id uuid.UUID) (*model.CustomHoliday, error) {
+	customHoliday, err := usecase.repo.GetCustomHolidayById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("custom holiday with id %v not found", id))
+	}
+	return customHoliday, nil
+}
+
+func (usecase *holidayUsecase) AddCustomHoliday(customHoliday *model.CustomHoliday) error {
+	if customHoliday.Date.IsZero() {
+		return NewEntityIncompleteError("the date of the holiday must not be empty")
+	}
+	if customHoliday.Name == "" {
+		return NewEntityIncompleteError("the name of the holiday must not be empty")
+	}
+	_, err := usecase.GetHolidayCalendarById(customHoliday.HolidayCalendarID)
+	if err != nil {
+		return err
+	}
+	customHoliday.Date = startOfDay(customHoliday.Date)
+	return usecase.repo.AddCustomHoliday(customHoliday)
+}
+
+func (usecase *holidayUsecase) DeleteCustomHoliday(id uuid.UUID) error {
+	customHoliday, err := usecase.GetCustomHolidayById(id)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.DeleteCustomHoliday(customHoliday)
+}
+
+// AssignHolidayCalendarToTeam sets the calendar that applies to all members of the team. A nil calendar id removes the
+// assignment.
+func (usecase *holidayUsecase) AssignHolidayCalendarToTeam(teamId uuid.UUID, calendarId *uuid.UUID) error {
+	team, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return err
+	}
+	err = usecase.checkCalendarOfTeam(teamId, calendarId)
+	if err != nil {
+		return err
+	}
+	team.HolidayCalendarID = calendarId
+	return usecase.teamUsecase.UpdateTeam(team)
+}
+
+// AssignHolidayCalendarToUser sets a calendar for a single member of the team that overrides the calendar of the team.
+// A nil calendar id removes the assignment, so the calendar of the team applies again.
+func (usecase *holidayUsecase) AssignHolidayCalendarToUser(userId uuid.UUID, teamId uuid.UUID, calendarId *uuid.UUID) error {
+	team, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return err
+	}
+	err = usecase.checkCalendarOfTeam(teamId, calendarId)
+	if err != nil {
+		return err
+	}
+	return usecase.teamUsecase.UpdateHolidayCalendarOfUser(userId, team, calendarId)
+}
+
+// GetHolidays returns the public holidays of the calendar's region and its custom holidays in the interval [from, to)
+// sorted by date.
+func (usecase *holidayUsecase) GetHolidays(calendarId uuid.UUID, from time.Time, to time.Time) ([]model.Holiday, error) {
+	from = startOfDay(from)
+	to = startOfDay(to)
+	if !from.Before(to) {
+		return nil, NewInvalidValueError("the start of the date range must be before its end")
+	}
+	calendar, err := usecase.GetHolidayCalendarById(calendarId)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []model.Holiday
+	for year := from.Year(); year <= to.AddDate(0, 0, -1).Year(); year++ {
+		publicHolidays, err := holiday.GermanHolidays(year, calendar.Region)
+		if err != nil {
+			return nil, err
+		}
+		for _, publicHoliday := range publicHolidays {
+			if !publicHoliday.Date.Before(from) && publicHoliday.Date.Before(to) {
+				holidays = append(holidays, publicHoliday)
+			}
+		}
+	}
+	customHolidays, err := usecase.repo.GetCustomHolidaysOfCalendar(calendarId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, customHoliday := range customHolidays {
+		holidays = append(holidays, model.Holiday{
+			Date: startOfDay(customHoliday.Date),
+			Name: customHoliday.Name,
+		})
+	}
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+	return holidays, nil
+}
+
+// GetHolidaysOfUserInTeam returns the holidays that apply to the user as a member of the team. The calendar assigned
+// to the user takes precedence over the calendar of the team. Without any calendar there are no holidays.
+func (usecase *holidayUsecase) GetHolidaysOfUserInTeam(userId uuid.UUID, teamId uuid.UUID, from time.Time, to time.Time) ([]model.Holiday, error) {
+	teamAssignments, err := usecase.teamUsecase.GetTeamsOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, teamAssignment := range teamAssignments {
+		if teamAssignment.TeamID != teamId {
+			continue
+		}
+		calendarId := teamAssignment.HolidayCalendarID
+		if calendarId == nil {
+			calendarId = teamAssignment.Team.HolidayCalendarID
+		}
+		if calendarId == nil {
+			return nil, nil
+		}
+		return usecase.GetHolidays(*calendarId, from, to)
+	}
+	return nil, nil
+}
+
+func (usecase *holidayUsecase) checkCalendarOfTeam(teamId uuid.UUID, calendarId *uuid.UUID) error {
+	if calendarId == nil {
+		return nil
+	}
+	calendar, err := usecase.GetHolidayCalendarById(*calendarId)
+	if err != nil {
+		return err
+	}
+	if calendar.TeamID != teamId {
+		return NewInvalidValueError(fmt.Sprintf("holiday calendar %v does not belong to team %v", calendar.ID, teamId))
+	}
+	return nil
+}
+
+func (usecase *holidayUsecase) checkHolidayCalendar(calendar *model.HolidayCalendar) error {
+	if calendar.TeamID == uuid.Nil {
+		return NewEntityIncompleteError("the team id must not be empty")
+	}
+	if calendar.Name == "" {
+		return NewEntityIncompleteError("the name of the holiday calendar must not be empty")
+	}
+	if !holiday.IsValidRegion(calendar.Region) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid region", calendar.Region))
+	}
+	_, err := usecase.teamUsecase.GetTeamById(calendar.TeamID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
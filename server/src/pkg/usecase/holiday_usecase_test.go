@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_holidayUsecase_AddHolidayCalendar(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	calendar := model.HolidayCalendar{TeamID: team.ID, Name: "Stuttgart", Region: "BW"}
+	err := usecaseTest.HolidayUsecase.AddHolidayCalendar(&calendar)
+	assert.Nil(t, err)
+
+	calendarsFromDb, err := usecaseTest.HolidayUsecase.GetHolidayCalendarsOfTeam(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(calendarsFromDb))
+	assert.Equal(t, "Stuttgart", calendarsFromDb[0].Name)
+	assert.Equal(t, "BW", calendarsFromDb[0].Region)
+}
+
+func Test_holidayUsecase_AddHolidayCalendarFailsWithInvalidRegion(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	calendar := model.HolidayCalendar{TeamID: team.ID, Name: "Somewhere", Region: "XX"}
+	err := usecaseTest.HolidayUsecase.AddHolidayCalendar(&calendar)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_holidayUsecase_GetHolidaysContainsCustomHolidays(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	calendar := addHolidayCalendar(t, usecaseTest, team.ID, "DE")
+	customHoliday := model.CustomHoliday{
+		HolidayCalendarID: calendar.ID,
+		Date:              time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC),
+		Name:              "Brückentag",
+	}
+	err := usecaseTest.HolidayUsecase.AddCustomHoliday(&customHoliday)
+	assert.Nil(t, err)
+
+	holidays, err := usecaseTest.HolidayUsecase.GetHolidays(calendar.ID, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(holidays))
+	assert.Equal(t, "Brückentag", holidays[0].Name)
+	assert.Equal(t, "Tag der Deutschen Einheit", holidays[1].Name)
+}
+
+func Test_holidayUsecase_CalendarOfUserOverridesCalendarOfTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	nationwide := addHolidayCalendar(t, usecaseTest, team.ID, "DE")
+	bavaria := addHolidayCalendar(t, usecaseTest, team.ID, "BY")
+	err := usecaseTest.HolidayUsecase.AssignHolidayCalendarToTeam(team.ID, &nationwide.ID)
+	assert.Nil(t, err)
+
+	from := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC)
+	holidays, err := usecaseTest.HolidayUsecase.GetHolidaysOfUserInTeam(userId, team.ID, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(holidays))
+
+	err = usecaseTest.HolidayUsecase.AssignHolidayCalendarToUser(userId, team.ID, &bavaria.ID)
+	assert.Nil(t, err)
+	holidays, err = usecaseTest.HolidayUsecase.GetHolidaysOfUserInTeam(userId, team.ID, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(holidays))
+	assert.Equal(t, "Allerheiligen", holidays[0].Name)
+}
+
+func Test_holidayUsecase_AssignHolidayCalendarFailsIfItBelongsToAnotherTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	otherTeam := addTeam(t, usecaseTest.TeamUsecase, "other team", userId)
+	calendar := addHolidayCalendar(t, usecaseTest, otherTeam.ID, "DE")
+
+	err := usecaseTest.HolidayUsecase.AssignHolidayCalendarToTeam(team.ID, &calendar.ID)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_holidayUsecase_DeleteHolidayCalendarRemovesAssignments(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	calendar := addHolidayCalendar(t, usecaseTest, team.ID, "DE")
+	err := usecaseTest.HolidayUsecase.AssignHolidayCalendarToTeam(team.ID, &calendar.ID)
+	assert.Nil(t, err)
+
+	err = usecaseTest.HolidayUsecase.DeleteHolidayCalendar(calendar.ID)
+	assert.Nil(t, err)
+
+	teamFromDb, err := usecaseTest.TeamUsecase.GetTeamById(team.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, teamFromDb.HolidayCalendarID)
+}
+
+func Test_holidayUsecase_HolidaysAreNonWorkingDays(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+	calendar := addHolidayCalendar(t, usecaseTest, team.ID, "DE")
+	err = usecaseTest.HolidayUsecase.AssignHolidayCalendarToTeam(team.ID, &calendar.ID)
+	assert.Nil(t, err)
+
+	// Monday, 2023-10-02 until Sunday, 2023-10-08 with the Tag der Deutschen Einheit on tuesday:
+	from := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 9, 0, 0, 0, 0, time.UTC)
+	targetTimes, err := usecaseTest.WorkingTimeUsecase.GetDailyTargetTimes(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 8*time.Hour, targetTimes[0].Target)
+	assert.Equal(t, time.Duration(0), targetTimes[1].Target)
+	assert.Equal(t, 8*time.Hour, targetTimes[1].Holiday)
+
+	targetTime, err := usecaseTest.WorkingTimeUsecase.GetTargetTime(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 4*8*time.Hour, targetTime)
+}
+
+func addHolidayCalendar(t *testing.T, usecaseTest *UsecaseTest, teamId uuid.UUID, region string) model.HolidayCalendar {
+	calendar := model.HolidayCalendar{
+		TeamID: teamId,
+		Name:   "calendar " + region,
+		Region: region,
+	}
+	err := usecaseTest.HolidayUsecase.AddHolidayCalendar(&calendar)
+	assert.Nil(t, err)
+	return calendar
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/importer"
+
+	"github.com/gofrs/uuid"
+)
+
+// ImportOptions control how time entries are imported.
+type ImportOptions struct {
+	// DryRun only validates the entries without writing anything
+	DryRun bool
+	// TeamID is the team new projects are assigned to, they only belong to the importing user if it is not set
+	TeamID *uuid.UUID
+}
+
+// ImportResult describes what an import did or, in a dry run, would do.
+type ImportResult struct {
+	DryRun bool
+	// Imported is the number of imported time entries
+	Imported int
+	// Skipped is the number of entries which already exist, e.g. because the file was imported before
+	Skipped int
+	// NewProjects are the names of the projects which are created for the import
+	NewProjects []string
+	// Errors are the lines which cannot be imported. If there are errors, nothing is imported.
+	Errors []importer.RecordError
+}
+
+type ImportUsecase interface {
+	ImportTimeEntries(userId uuid.UUID, source string, records []importer.Record, recordErrors []importer.RecordError,
+		options ImportOptions) (*ImportResult, error)
+	ImportAppDatabase(userId uuid.UUID, appDatabase *importer.AppDatabase, recordErrors []importer.RecordError,
+		dryRun bool) (*ImportResult, error)
+}
+
+type importUsecase struct {
+	repo             repository.ImportedTimeEntryRepository
+	timeEntryUsecase TimeEntryUsecase
+	projectUsecase   ProjectUsecase
+	teamUsecase      TeamUsecase
+	syncUsecase      SyncUsecase
+}
+
+func NewImportUsecase(repo repository.ImportedTimeEntryRepository, timeEntryUsecase TimeEntryUsecase,
+	projectUsecase ProjectUsecase, teamUsecase TeamUsecase, syncUsecase SyncUsecase) ImportUsecase {
+	return &importUsecase{
+		repo:             repo,
+		timeEntryUsecase: timeEntryUsecase,
+		projectUsecase:   projectUsecase,
+		teamUsecase:      teamUsecase,
+		syncUsecase:      syncUsecase,
+	}
+}
+
+// ImportTimeEntries imports the records of the source as time entries of the user. The projects are looked up by
+// client and name among the projects of the team, or the personal projects of the user without team. Missing
+// projects are created. Records which were imported before
+// (same id in the source) or which match an existing entry of the project exactly are skipped.
+// The recordErrors of reading the file are part of the result, so either all records are imported or none.
+// The new projects, the entries and their attributes of the source are written in one transaction. A dry run validates the entries without writing
+// anything.
+func (usecase *importUsecase) ImportTimeEntries(userId uuid.UUID, source string, records []importer.Record,
+	recordErrors []importer.RecordError, options ImportOptions) (*ImportResult, error) {
+	if options.TeamID != nil {
+		if _, err := usecase.teamUsecase.GetTeamById(*options.TeamID); err != nil {
+			return nil, NewEntityNotFoundError(fmt.Sprintf("team with id %v does not exist", *options.TeamID))
+		}
+	}
+	result := ImportResult{DryRun: options.DryRun, Errors: recordErrors}
+	projectIds, err := usecase.getProjectIdsOfImport(userId, options.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	newRecords, err := usecase.skipExistingRecords(userId, source, records, projectIds)
+	if err != nil {
+		return nil, err
+	}
+	result.Skipped = len(records) - len(newRecords)
+	records = newRecords
+	// New projects are billable if one of their entries is billable
+	var newProjects []model.Project
+	newProjectIndexes := make(map[string]int)
+	for _, record := range records {
+		key := projectKey(record.Client, record.Project)
+		if _, ok := projectIds[key]; !ok {
+			// The id is set in advance to link the entries of the project
+			projectId, err := uuid.NewV4()
+			if err != nil {
+				return nil, err
+			}
+			projectIds[key] = projectId
+			newProjectIndexes[key] = len(newProjects)
+			newProjects = append(newProjects, model.Project{ID: projectId, Name: record.Project, Client: record.Client,
+				UserId: userId, TeamID: options.TeamID})
+		}
+		if index, ok := newProjectIndexes[key]; ok && record.Billable {
+			newProjects[index].Billable = true
+		}
+	}
+	for _, project := range newProjects {
+		result.NewProjects = append(result.NewProjects, project.Name)
+	}
+
+	var timeEntries []model.TimeEntry
+	var importedTimeEntries []model.ImportedTimeEntry
+	for _, record := range records {
+		key := projectKey(record.Client, record.Project)
+		timeEntry := newImportedTimeEntry(userId, record, projectIds[key])
+		var checkErr error
+		if index, ok := newProjectIndexes[key]; ok {
+			checkErr = usecase.timeEntryUsecase.CheckTimeEntryOfNewProject(&timeEntry, &newProjects[index])
+		} else {
+			checkErr = usecase.timeEntryUsecase.CheckTimeEntry(&timeEntry)
+		}
+		if checkErr != nil {
+			result.Errors = append(result.Errors, importer.RecordError{Line: record.Line, Message: checkErr.Error()})
+		}
+		// The id is set in advance to link the attributes of the source
+		timeEntry.ID, err = uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		timeEntries = append(timeEntries, timeEntry)
+		importedTimeEntry := model.ImportedTimeEntry{
+			TimeEntryID: timeEntry.ID,
+			UserID:      userId,
+			Source:      source,
+			ExternalID:  record.ExternalID,
+			Task:        record.Task,
+			Tags:        record.Tags,
+		}
+		// The flag of each entry is kept, because the project is billable if only one of its entries is
+		if model.ImportSourceHasBillableFlag(source) {
+			billable := record.Billable
+			importedTimeEntry.Billable = &billable
+		}
+		if importedTimeEntry.Billable != nil || record.ExternalID != "" || record.Task != "" || len(record.Tags) > 0 {
+			importedTimeEntries = append(importedTimeEntries, importedTimeEntry)
+		}
+	}
+	if options.DryRun || len(result.Errors) > 0 {
+		if len(result.Errors) == 0 {
+			result.Imported = len(timeEntries)
+		}
+		return &result, nil
+	}
+
+	// The new projects, the time entries and their attributes of the source are added in one transaction
+	if err := usecase.repo.ImportTimeEntries(newProjects, timeEntries, importedTimeEntries); err != nil {
+		return nil, err
+	}
+	result.Imported = len(timeEntries)
+	return &result, nil
+}
+
+// ImportAppDatabase imports the projects and time entries of the database of the tracking app for the user. The ids
+// of the app are kept, so a later sync of the app does not duplicate anything. Projects and entries which already
+// exist on the server (e.g. because they were synced or imported before) are skipped, the server version is kept.
+// Projects deleted in the app are imported as deleted projects, because their entries still reference them.
+// Everything is written in one transaction like the changes of a sync, either all entries are imported or none.
+func (usecase *importUsecase) ImportAppDatabase(userId uuid.UUID, appDatabase *importer.AppDatabase,
+	recordErrors []importer.RecordError, dryRun bool) (*ImportResult, error) {
+	result := ImportResult{DryRun: dryRun, Errors: recordErrors}
+	var data model.SyncData
+	projectIds := make(map[uuid.UUID]bool)
+	for _, appProject := range appDatabase.Projects {
+		projectIds[appProject.ID] = true
+		if project, err := usecase.projectUsecase.GetProjectById(appProject.ID); err == nil {
+			if project.UserId != userId {
+				result.Errors = append(result.Errors, importer.RecordError{Line: appProject.Line,
+					Message: fmt.Sprintf("project %v belongs to another user", appProject.ID)})
+			}
+			continue
+		}
+		project := model.Project{ID: appProject.ID, Name: appProject.Name, UserId: userId}
+		data.ProjectsToBeUpdated = append(data.ProjectsToBeUpdated, project)
+		if appProject.Deleted {
+			data.ProjectsToBeDeleted = append(data.ProjectsToBeDeleted, project)
+		} else {
+			result.NewProjects = append(result.NewProjects, project.Name)
+		}
+	}
+	for _, appTimeEntry := range appDatabase.TimeEntries {
+		if timeEntry, err := usecase.timeEntryUsecase.GetTimeEntryById(appTimeEntry.ID); err == nil {
+			if timeEntry.UserId != userId {
+				result.Errors = append(result.Errors, importer.RecordError{Line: appTimeEntry.Line,
+					Message: fmt.Sprintf("time entry %v belongs to another user", appTimeEntry.ID)})
+			} else {
+				result.Skipped++
+			}
+			continue
+		}
+		if !projectIds[appTimeEntry.ProjectID] {
+			project, err := usecase.projectUsecase.GetProjectById(appTimeEntry.ProjectID)
+			if err != nil || project.UserId != userId {
+				result.Errors = append(result.Errors, importer.RecordError{Line: appTimeEntry.Line,
+					Message: fmt.Sprintf("project %v of time entry %v does not exist", appTimeEntry.ProjectID,
+						appTimeEntry.ID)})
+				continue
+			}
+		}
+		timeEntry := model.TimeEntry{
+			ID:          appTimeEntry.ID,
+			UserId:      userId,
+			ProjectId:   appTimeEntry.ProjectID,
+			StartTime:   appTimeEntry.StartTime,
+			EndTime:     appTimeEntry.EndTime,
+			Description: appTimeEntry.Description,
+		}
+		if err := usecase.timeEntryUsecase.CheckTimeEntryIsEditable(&timeEntry); err != nil {
+			result.Errors = append(result.Errors, importer.RecordError{Line: appTimeEntry.Line, Message: err.Error()})
+			continue
+		}
+		data.TimeEntriesToBeUpdated = append(data.TimeEntriesToBeUpdated, timeEntry)
+	}
+
+	if len(result.Errors) > 0 {
+		return &result, nil
+	}
+	if !dryRun {
+		if err := usecase.syncUsecase.UpdateAndDeleteData(data); err != nil {
+			return nil, err
+		}
+	}
+	result.Imported = len(data.TimeEntriesToBeUpdated)
+	return &result, nil
+}
+
+// getProjectIdsOfImport returns the ids of the projects the records of an import are assigned to by their key, i.e.
+// the projects of the team or, without team, the personal projects of the user. Projects with the same key in other
+// teams are ignored. If a key is used twice, the first project in the order of the names is used.
+func (usecase *importUsecase) getProjectIdsOfImport(userId uuid.UUID, teamId *uuid.UUID) (map[string]uuid.UUID, error) {
+	projects, err := usecase.projectUsecase.GetAllProjectsOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	projectIds := make(map[string]uuid.UUID)
+	for _, project := range projects {
+		if teamId != nil && (project.TeamID == nil || *project.TeamID != *teamId) {
+			continue
+		}
+		if teamId == nil && (project.TeamID != nil || project.UserId != userId) {
+			continue
+		}
+		key := projectKey(project.Client, project.Name)
+		if _, ok := projectIds[key]; !ok {
+			projectIds[key] = project.ID
+		}
+	}
+	return projectIds, nil
+}
+
+// skipExistingRecords removes the records which were imported before from the list.
+func (usecase *importUsecase) skipExistingRecords(userId uuid.UUID, source string, records []importer.Record,
+	projectIds map[string]uuid.UUID) ([]importer.Record, error) {
+	if len(records) == 0 {
+		return records, nil
+	}
+	importedTimeEntries, err := usecase.repo.GetImportedTimeEntriesOfUser(userId, source)
+	if err != nil {
+		return nil, err
+	}
+	externalIds := make(map[string]bool)
+	for _, importedTimeEntry := range importedTimeEntries {
+		if importedTimeEntry.ExternalID != "" {
+			externalIds[importedTimeEntry.ExternalID] = true
+		}
+	}
+	from, to := records[0].StartTime, records[0].StartTime
+	for _, record := range records {
+		if record.StartTime.Before(from) {
+			from = record.StartTime
+		}
+		if record.StartTime.After(to) {
+			to = record.StartTime
+		}
+	}
+	timeEntries, err := usecase.timeEntryUsecase.GetTimeEntriesOfUserInRange(userId, from, to.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	existingEntries := make(map[string]bool)
+	for _, timeEntry := range timeEntries {
+		existingEntries[timeEntryKey(timeEntry.ProjectId, timeEntry.StartTime, timeEntry.EndTime)] = true
+	}
+
+	var newRecords []importer.Record
+	for _, record := range records {
+		if record.ExternalID != "" && externalIds[record.ExternalID] {
+			continue
+		}
+		projectId, ok := projectIds[projectKey(record.Client, record.Project)]
+		if ok && existingEntries[timeEntryKey(projectId, record.StartTime, record.EndTime)] {
+			continue
+		}
+		if record.ExternalID != "" {
+			// the same entry may be contained twice in a file
+			externalIds[record.ExternalID] = true
+		}
+		newRecords = append(newRecords, record)
+	}
+	return newRecords, nil
+}
+
+func newImportedTimeEntry(userId uuid.UUID, record importer.Record, projectId uuid.UUID) model.TimeEntry {
+	return model.TimeEntry{
+		UserId:      userId,
+		ProjectId:   projectId,
+		StartTime:   record.StartTime,
+		EndTime:     record.EndTime,
+		Description: record.Description,
+	}
+}
+
+// projectKey is used to look up projects by client and name ignoring case and surrounding spaces.
+func projectKey(client string, name string) string {
+	return strings.ToLower(strings.TrimSpace(client)) + "/" + strings.ToLower(strings.TrimSpace(name))
+}
+
+// timeEntryKey identifies time entries with the same project and times.
+func timeEntryKey(projectId uuid.UUID, startTime time.Time, endTime time.Time) string {
+	return fmt.Sprintf("%v/%d/%d", projectId, startTime.Unix(), endTime.Unix())
+}
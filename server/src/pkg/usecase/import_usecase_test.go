@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/importer"
+	"timeasy-server/pkg/test"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_importUsecase_ImportTimeEntries(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "Project", userId)
+	records := []importer.Record{
+		{Line: 2, StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC),
+			Project: "project", Description: "existing project"},
+		{Line: 3, StartTime: time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 5, 9, 0, 0, 0, time.UTC),
+			Project: "New Project"},
+		{Line: 4, StartTime: time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 6, 9, 0, 0, 0, time.UTC),
+			Project: "new project "},
+	}
+
+	// A dry run does not write anything:
+	result, err := usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil, ImportOptions{DryRun: true})
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, []string{"New Project"}, result.NewProjects)
+	assert.Empty(t, result.Errors)
+	projects, err := usecaseTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(projects))
+	timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, timeEntries)
+
+	result, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil, ImportOptions{DryRun: false})
+	assert.Nil(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, 3, result.Imported)
+	projects, err = usecaseTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(projects))
+	timeEntries, err = usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUserAndProject(userId, project.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timeEntries))
+	assert.Equal(t, "existing project", timeEntries[0].Description)
+	timeEntries, err = usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(timeEntries))
+}
+
+func Test_importUsecase_ImportTimeEntriesWithErrors(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	project := addProject(t, usecaseTest.ProjectUsecase, "team project", userId)
+	err := usecaseTest.ProjectUsecase.AssignProjectToTeam(&project, &team)
+	assert.Nil(t, err)
+	lockDate := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	_, err = usecaseTest.PeriodLockUsecase.SetLockDate(team.ID, &lockDate, userId, "closed")
+	assert.Nil(t, err)
+	records := []importer.Record{
+		{Line: 2, StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC),
+			Project: "team project"},
+		{Line: 4, StartTime: time.Date(2023, 10, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 10, 4, 10, 0, 0, 0, time.UTC),
+			Project: "new project"},
+	}
+	recordErrors := []importer.RecordError{{Line: 3, Message: "the project is missing"}}
+
+	for _, dryRun := range []bool{true, false} {
+		result, err := usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, recordErrors, ImportOptions{DryRun: dryRun})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, result.Imported)
+		assert.Equal(t, 2, len(result.Errors))
+		assert.Equal(t, 3, result.Errors[0].Line)
+		assert.Equal(t, 2, result.Errors[1].Line)
+	}
+	// Without the parse error the lock date still prevents the import, the new project is not created:
+	result, err := usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil, ImportOptions{DryRun: false})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 1, len(result.Errors))
+	projects, err := usecaseTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(projects))
+	timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, timeEntries)
+	var projectCount int64
+	assert.Nil(t, test.DB.Unscoped().Model(&model.Project{}).Where("user_id=?", userId).Count(&projectCount).Error)
+	assert.Equal(t, int64(1), projectCount)
+
+	// The entries of new projects of the team are checked against the lock date of the team, too:
+	records = []importer.Record{{Line: 2, StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC),
+		EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC), Project: "new project"}}
+	for _, dryRun := range []bool{true, false} {
+		result, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil,
+			ImportOptions{DryRun: dryRun, TeamID: &team.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, result.Imported)
+		assert.Equal(t, 1, len(result.Errors))
+	}
+}
+
+func Test_importUsecase_ImportTimeEntriesSkipsExistingEntries(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	records := []importer.Record{
+		{Line: 1, StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC),
+			Client: "ACME", Project: "Website", Task: "Design", Tags: []string{"meeting"}, Billable: true, ExternalID: "1001"},
+		{Line: 2, StartTime: time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 5, 9, 0, 0, 0, time.UTC),
+			Client: "Other", Project: "Website", ExternalID: "1002"},
+	}
+	result, err := usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceToggl, records, nil, ImportOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Imported)
+	// Projects of different clients are different projects:
+	assert.Equal(t, []string{"Website", "Website"}, result.NewProjects)
+	projects, err := usecaseTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(projects))
+	for _, project := range projects {
+		assert.Equal(t, project.Client == "ACME", project.Billable)
+	}
+
+	// A re-import skips the entries, also if their times were changed in timeasy:
+	timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	for _, timeEntry := range timeEntries {
+		timeEntry.EndTime = timeEntry.EndTime.Add(time.Hour)
+		assert.Nil(t, usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&timeEntry))
+	}
+	records = append(records, importer.Record{Line: 3, StartTime: time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC),
+		EndTime: time.Date(2023, 9, 6, 9, 0, 0, 0, time.UTC), Client: "ACME", Project: "Website", ExternalID: "1003"})
+	result, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceToggl, records, nil, ImportOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Empty(t, result.NewProjects)
+
+	// Files without ids are recognized by project and times:
+	records = []importer.Record{{Line: 1, StartTime: time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC),
+		EndTime: time.Date(2023, 9, 6, 9, 0, 0, 0, time.UTC), Client: "acme", Project: "website"}}
+	result, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil, ImportOptions{DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 1, result.Skipped)
+
+	importedTimeEntries, err := database.NewGormImportedTimeEntryRepository(test.DB).GetImportedTimeEntriesOfUser(userId, model.ImportSourceToggl)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(importedTimeEntries))
+	for _, importedTimeEntry := range importedTimeEntries {
+		// The flag of each entry is kept, the entries of a billable project may be not billable
+		assert.Equal(t, importedTimeEntry.ExternalID == "1001", *importedTimeEntry.Billable)
+		if importedTimeEntry.ExternalID == "1001" {
+			assert.Equal(t, "Design", importedTimeEntry.Task)
+			assert.Equal(t, model.StringList{"meeting"}, importedTimeEntry.Tags)
+		}
+	}
+}
+
+func Test_importUsecase_ImportTimeEntriesIntoTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	records := []importer.Record{{Line: 1, StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC),
+		EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC), Project: "Website"}}
+	result, err := usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceClockify, records, nil,
+		ImportOptions{TeamID: &team.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Imported)
+	projects, err := usecaseTest.ProjectUsecase.GetAllProjectsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(projects))
+	assert.Equal(t, team.ID, *projects[0].TeamID)
+
+	var entityNotFoundError *EntityNotFoundError
+	unknownTeamId := GetTestUserId(t)
+	_, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceClockify, records, nil,
+		ImportOptions{TeamID: &unknownTeamId})
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_importUsecase_ImportTimeEntriesOnlyUsesProjectsOfTheTarget(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	otherTeam := addTeam(t, usecaseTest.TeamUsecase, "other team", userId)
+	personalProject := addProject(t, usecaseTest.ProjectUsecase, "Website", userId)
+	otherTeamProject := addProject(t, usecaseTest.ProjectUsecase, "Website", userId)
+	err := usecaseTest.ProjectUsecase.AssignProjectToTeam(&otherTeamProject, &otherTeam)
+	assert.Nil(t, err)
+	startTime := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	records := []importer.Record{{Line: 1, StartTime: startTime, EndTime: startTime.Add(time.Hour), Project: "Website"}}
+
+	result, err := usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil,
+		ImportOptions{TeamID: &team.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, []string{"Website"}, result.NewProjects)
+	for _, project := range []model.Project{personalProject, otherTeamProject} {
+		timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUserAndProject(userId, project.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, timeEntries)
+	}
+
+	// The entry of the team project does not make the entry of the personal project a duplicate:
+	result, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil, ImportOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 0, result.Skipped)
+	assert.Empty(t, result.NewProjects)
+	timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUserAndProject(userId, personalProject.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timeEntries))
+
+	// Importing the file into the team again skips the entry:
+	result, err = usecaseTest.ImportUsecase.ImportTimeEntries(userId, model.ImportSourceCsv, records, nil,
+		ImportOptions{TeamID: &team.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 1, result.Skipped)
+}
+
+func Test_importUsecase_ImportAppDatabase(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	existingProject := addProject(t, usecaseTest.ProjectUsecase, "Existing", userId)
+	projectId := uuid.Must(uuid.NewV1())
+	deletedProjectId := uuid.Must(uuid.NewV1())
+	start := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	appDatabase := importer.AppDatabase{
+		Projects: []importer.AppProject{
+			{Line: 1, ID: existingProject.ID, Name: "Renamed in the app"},
+			{Line: 2, ID: projectId, Name: "Website"},
+			{Line: 3, ID: deletedProjectId, Name: "Old", Deleted: true},
+		},
+		TimeEntries: []importer.AppTimeEntry{
+			{Line: 1, ID: uuid.Must(uuid.NewV1()), ProjectID: existingProject.ID, StartTime: start, EndTime: start.Add(time.Hour)},
+			{Line: 2, ID: uuid.Must(uuid.NewV1()), ProjectID: projectId, StartTime: start.Add(2 * time.Hour),
+				EndTime: start.Add(3 * time.Hour), Description: "Mockups"},
+			{Line: 3, ID: uuid.Must(uuid.NewV1()), ProjectID: deletedProjectId, StartTime: start.Add(24 * time.Hour),
+				EndTime: start.Add(25 * time.Hour)},
+		},
+	}
+
+	// A dry run does not write anything:
+	result, err := usecaseTest.ImportUsecase.ImportAppDatabase(userId, &appDatabase, nil, true)
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, []string{"Website"}, result.NewProjects)
+	assert.Empty(t, result.Errors)
+	timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, timeEntries)
+
+	result, err = usecaseTest.ImportUsecase.ImportAppDatabase(userId, &appDatabase, nil, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, result.Imported)
+	// The ids of the app are kept:
+	for _, appTimeEntry := range appDatabase.TimeEntries {
+		timeEntry, err := usecaseTest.TimeEntryUsecase.GetTimeEntryById(appTimeEntry.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, userId, timeEntry.UserId)
+		assert.Equal(t, appTimeEntry.ProjectID, timeEntry.ProjectId)
+	}
+	project, err := usecaseTest.ProjectUsecase.GetProjectById(projectId)
+	assert.Nil(t, err)
+	assert.Equal(t, "Website", project.Name)
+	project, err = usecaseTest.ProjectUsecase.GetProjectById(existingProject.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Existing", project.Name)
+	_, err = usecaseTest.ProjectUsecase.GetProjectById(deletedProjectId)
+	assert.NotNil(t, err)
+
+	// A re-import skips everything:
+	result, err = usecaseTest.ImportUsecase.ImportAppDatabase(userId, &appDatabase, nil, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 3, result.Skipped)
+	timeEntries, err = usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(timeEntries))
+}
+
+func Test_importUsecase_ImportAppDatabaseWithErrors(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	otherUserId := GetTestUserId(t)
+	otherProject := addProject(t, usecaseTest.ProjectUsecase, "Other", otherUserId)
+	projectId := uuid.Must(uuid.NewV1())
+	start := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	appDatabase := importer.AppDatabase{
+		Projects: []importer.AppProject{
+			{Line: 1, ID: otherProject.ID, Name: "Other"},
+			{Line: 2, ID: projectId, Name: "Website"},
+		},
+		TimeEntries: []importer.AppTimeEntry{
+			{Line: 1, ID: uuid.Must(uuid.NewV1()), ProjectID: projectId, StartTime: start, EndTime: start.Add(time.Hour)},
+			{Line: 2, ID: uuid.Must(uuid.NewV1()), ProjectID: uuid.Must(uuid.NewV1()), StartTime: start},
+		},
+	}
+	result, err := usecaseTest.ImportUsecase.ImportAppDatabase(userId, &appDatabase,
+		[]importer.RecordError{{Line: 3, Message: "time entry has an invalid id"}}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 3, len(result.Errors))
+	assert.Equal(t, 1, result.Errors[1].Line)
+	assert.Equal(t, 2, result.Errors[2].Line)
+	_, err = usecaseTest.ProjectUsecase.GetProjectById(projectId)
+	assert.NotNil(t, err)
+	timeEntries, err := usecaseTest.TimeEntryUsecase.GetAllTimeEntriesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Empty(t, timeEntries)
+}
+
+func Test_importUsecase_ImportTimeEntriesWritesNothingOnFailure(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	project := model.Project{ID: uuid.Must(uuid.NewV4()), Name: "New project", UserId: userId}
+	timeEntry := model.TimeEntry{ID: uuid.Must(uuid.NewV4()), UserId: userId, ProjectId: project.ID,
+		StartTime: time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 9, 4, 10, 0, 0, 0, time.UTC)}
+	// The attributes of an entry can only be stored once:
+	importedTimeEntries := []model.ImportedTimeEntry{
+		{TimeEntryID: timeEntry.ID, UserID: userId, Source: model.ImportSourceToggl, ExternalID: "1001"},
+		{TimeEntryID: timeEntry.ID, UserID: userId, Source: model.ImportSourceToggl, ExternalID: "1002"},
+	}
+	err := database.NewGormImportedTimeEntryRepository(test.DB).ImportTimeEntries([]model.Project{project},
+		[]model.TimeEntry{timeEntry}, importedTimeEntries)
+	assert.NotNil(t, err)
+
+	var projectCount, timeEntryCount, outboxEventCount int64
+	assert.Nil(t, test.DB.Unscoped().Model(&model.Project{}).Where("user_id=?", userId).Count(&projectCount).Error)
+	assert.Equal(t, int64(0), projectCount)
+	assert.Nil(t, test.DB.Unscoped().Model(&model.TimeEntry{}).Where("user_id=?", userId).Count(&timeEntryCount).Error)
+	assert.Equal(t, int64(0), timeEntryCount)
+	assert.Nil(t, test.DB.Model(&model.OutboxEvent{}).Where("aggregate_id IN ?", []uuid.UUID{project.ID, timeEntry.ID}).
+		Count(&outboxEventCount).Error)
+	assert.Equal(t, int64(0), outboxEventCount)
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/invoice"
+
+	"github.com/gofrs/uuid"
+)
+
+// defaultCurrency is the currency of invoices without currency
+const defaultCurrency = "EUR"
+
+// InvoiceRequest selects the time entries of a new invoice and defines how they are billed.
+type InvoiceRequest struct {
+	Filter    model.InvoiceFilter
+	CreatedBy uuid.UUID
+	Currency  string
+	Rates     invoice.Rates
+	Rounding  invoice.Rounding
+}
+
+type InvoiceUsecase interface {
+	CreateInvoice(request InvoiceRequest, now time.Time) (*model.Invoice, error)
+	GetInvoiceById(id uuid.UUID) (*model.Invoice, error)
+	GetInvoicesOfUser(userId uuid.UUID) ([]model.Invoice, error)
+	GetInvoicesOfTeam(teamId uuid.UUID) ([]model.Invoice, error)
+	GetTimeEntryIdsOfInvoice(id uuid.UUID) ([]uuid.UUID, error)
+	CancelInvoice(id uuid.UUID) (*model.Invoice, error)
+}
+
+type invoiceUsecase struct {
+	repo           repository.InvoiceRepository
+	projectUsecase ProjectUsecase
+	teamUsecase    TeamUsecase
+}
+
+func NewInvoiceUsecase(repo repository.InvoiceRepository, projectUsecase ProjectUsecase, teamUsecase TeamUsecase) InvoiceUsecase {
+	return &invoiceUsecase{
+		repo:           repo,
+		projectUsecase: projectUsecase,
+		teamUsecase:    teamUsecase,
+	}
+}
+
+// CreateInvoice creates a draft invoice of the billable, not yet invoiced time entries of a client or project in the
+// period. The entries are grouped by project, task and day, and marked as invoiced.
+func (usecase *invoiceUsecase) CreateInvoice(request InvoiceRequest, now time.Time) (*model.Invoice, error) {
+	if err := usecase.checkInvoiceRequest(request); err != nil {
+		return nil, err
+	}
+	entries, err := usecase.repo.GetBillableTimeEntries(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, NewInvalidValueError("there are no billable time entries to invoice in the period")
+	}
+	timeEntryIds := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		timeEntryIds[i] = entry.TimeEntry.ID
+	}
+	currency := request.Currency
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	roundingMode := request.Rounding.Mode
+	if roundingMode == "" {
+		roundingMode = model.RoundingModeNearest
+	}
+	newInvoice := model.Invoice{
+		IssuerID:        request.Filter.UserID,
+		TeamID:          request.Filter.TeamID,
+		CreatedBy:       request.CreatedBy,
+		Status:          model.InvoiceStatusDraft,
+		Client:          request.Filter.Client,
+		ProjectID:       request.Filter.ProjectID,
+		From:            request.Filter.From,
+		To:              request.Filter.To,
+		Currency:        currency,
+		HourlyRate:      request.Rates.Default,
+		RoundingMinutes: request.Rounding.Minutes,
+		RoundingMode:    roundingMode,
+		Lines:           invoice.NewLines(entries, request.Rates, request.Rounding),
+	}
+	if request.Filter.TeamID != nil {
+		newInvoice.IssuerID = *request.Filter.TeamID
+	}
+	newInvoice.TotalMinutes, newInvoice.TotalAmount = invoice.Totals(newInvoice.Lines)
+	newInvoice.CreatedAt = now
+	if err := usecase.repo.AddInvoice(&newInvoice, timeEntryIds); err != nil {
+		return nil, err
+	}
+	return &newInvoice, nil
+}
+
+func (usecase *invoiceUsecase) GetInvoiceById(id uuid.UUID) (*model.Invoice, error) {
+	invoice, err := usecase.repo.GetInvoiceById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("invoice with id %v does not exist", id))
+	}
+	return invoice, nil
+}
+
+// GetInvoicesOfUser returns the personal invoices of the user without their lines, the latest first.
+func (usecase *invoiceUsecase) GetInvoicesOfUser(userId uuid.UUID) ([]model.Invoice, error) {
+	return usecase.repo.GetInvoicesOfIssuer(userId)
+}
+
+// GetInvoicesOfTeam returns the invoices of the team without their lines, the latest first.
+func (usecase *invoiceUsecase) GetInvoicesOfTeam(teamId uuid.UUID) ([]model.Invoice, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.repo.GetInvoicesOfIssuer(teamId)
+}
+
+func (usecase *invoiceUsecase) GetTimeEntryIdsOfInvoice(id uuid.UUID) ([]uuid.UUID, error) {
+	return usecase.repo.GetTimeEntryIdsOfInvoice(id)
+}
+
+// CancelInvoice cancels a draft invoice. Its number is not reused, its time entries can be changed and invoiced again.
+func (usecase *invoiceUsecase) CancelInvoice(id uuid.UUID) (*model.Invoice, error) {
+	invoice, err := usecase.GetInvoiceById(id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != model.InvoiceStatusDraft {
+		return nil, NewInvalidValueError(fmt.Sprintf("invoice %v cannot be cancelled (%v)", invoice.Number, invoice.Status))
+	}
+	if err := usecase.repo.CancelInvoice(invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (usecase *invoiceUsecase) checkInvoiceRequest(request InvoiceRequest) error {
+	filter := request.Filter
+	if filter.Client == "" && filter.ProjectID == nil {
+		return NewEntityIncompleteError("please select the client or the project of the invoice")
+	}
+	if filter.From.IsZero() || filter.To.IsZero() || !filter.From.Before(filter.To) {
+		return NewEntityIncompleteError("please select the period of the invoice")
+	}
+	if request.Rates.Default < 0 {
+		return NewInvalidValueError("the hourly rate must not be negative")
+	}
+	for projectId, rate := range request.Rates.Projects {
+		if rate < 0 {
+			return NewInvalidValueError(fmt.Sprintf("the hourly rate of project %v must not be negative", projectId))
+		}
+	}
+	if request.Rounding.Minutes < 0 || !invoice.IsValidRoundingMode(request.Rounding.Mode) {
+		return NewInvalidValueError(fmt.Sprintf("the rounding must be a positive number of minutes and one of %v, %v or %v",
+			model.RoundingModeNearest, model.RoundingModeUp, model.RoundingModeDown))
+	}
+	if filter.TeamID != nil {
+		if _, err := usecase.teamUsecase.GetTeamById(*filter.TeamID); err != nil {
+			return err
+		}
+	}
+	if filter.ProjectID != nil {
+		project, err := usecase.projectUsecase.GetProjectById(*filter.ProjectID)
+		if err != nil {
+			return NewEntityNotFoundError(fmt.Sprintf("project with id %v does not exist", *filter.ProjectID))
+		}
+		if filter.TeamID != nil && (project.TeamID == nil || *project.TeamID != *filter.TeamID) {
+			return NewInvalidValueError(fmt.Sprintf("project %v does not belong to team %v", project.ID, *filter.TeamID))
+		}
+		if filter.TeamID == nil && (project.TeamID != nil || project.UserId != filter.UserID) {
+			return NewInvalidValueError(fmt.Sprintf("project %v is not a personal project of the user", project.ID))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/invoice"
+	"timeasy-server/pkg/test"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_invoiceUsecase_CreateInvoice(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	website := model.Project{Name: "Website", UserId: userId, Client: "ACME", Billable: true}
+	assert.Nil(t, usecaseTest.ProjectUsecase.AddProject(&website))
+	internal := model.Project{Name: "Internal", UserId: userId, Client: "ACME"}
+	assert.Nil(t, usecaseTest.ProjectUsecase.AddProject(&internal))
+	other := model.Project{Name: "Other", UserId: userId, Client: "Other client", Billable: true}
+	assert.Nil(t, usecaseTest.ProjectUsecase.AddProject(&other))
+
+	day := time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC)
+	addEntry := func(project model.Project, description string, startTime time.Time, duration time.Duration) model.TimeEntry {
+		timeEntry := model.TimeEntry{UserId: userId, ProjectId: project.ID, Description: description,
+			StartTime: startTime, EndTime: startTime.Add(duration)}
+		assert.Nil(t, usecaseTest.TimeEntryUsecase.AddTimeEntry(&timeEntry))
+		return timeEntry
+	}
+	design := addEntry(website, "Design", day, 50*time.Minute)
+	addEntry(website, "Design", day.Add(2*time.Hour), 20*time.Minute)
+	support := addEntry(internal, "Hotline", day.Add(4*time.Hour), 30*time.Minute)
+	// Not billable, of another client and outside of the period:
+	addEntry(internal, "Meeting", day.Add(5*time.Hour), time.Hour)
+	breakEntry := addEntry(website, "Break", day.Add(6*time.Hour), time.Hour)
+	addEntry(other, "Design", day, time.Hour)
+	addEntry(website, "Design", day.AddDate(0, 1, 0), time.Hour)
+	// The flags of imported entries override the flags of their projects:
+	billable, notBillable := true, false
+	assert.Nil(t, test.DB.Create(&model.ImportedTimeEntry{TimeEntryID: support.ID, UserID: userId,
+		Source: model.ImportSourceToggl, Task: "Support", Billable: &billable}).Error)
+	assert.Nil(t, test.DB.Create(&model.ImportedTimeEntry{TimeEntryID: breakEntry.ID, UserID: userId,
+		Source: model.ImportSourceToggl, Billable: &notBillable}).Error)
+
+	request := InvoiceRequest{
+		Filter: model.InvoiceFilter{UserID: userId, Client: "ACME",
+			From: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)},
+		CreatedBy: userId,
+		Rates:     invoice.Rates{Default: 8000, Projects: map[uuid.UUID]int64{internal.ID: 6000}},
+		Rounding:  invoice.Rounding{Minutes: 15, Mode: model.RoundingModeUp},
+	}
+	now := time.Date(2023, 10, 2, 9, 0, 0, 0, time.UTC)
+	newInvoice, err := usecaseTest.InvoiceUsecase.CreateInvoice(request, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-0001", newInvoice.Number)
+	assert.Equal(t, model.InvoiceStatusDraft, newInvoice.Status)
+	assert.Equal(t, "EUR", newInvoice.Currency)
+	assert.Equal(t, 2, len(newInvoice.Lines))
+	assert.Equal(t, "Internal", newInvoice.Lines[0].ProjectName)
+	assert.Equal(t, "Support", newInvoice.Lines[0].Task)
+	assert.Equal(t, int64(3000), newInvoice.Lines[0].Amount)
+	assert.Equal(t, "Website", newInvoice.Lines[1].ProjectName)
+	assert.Equal(t, int64(75), newInvoice.Lines[1].BilledMinutes)
+	assert.Equal(t, int64(10000), newInvoice.Lines[1].Amount)
+	assert.Equal(t, int64(105), newInvoice.TotalMinutes)
+	assert.Equal(t, int64(13000), newInvoice.TotalAmount)
+
+	storedInvoice, err := usecaseTest.InvoiceUsecase.GetInvoiceById(newInvoice.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(storedInvoice.Lines))
+	timeEntryIds, err := usecaseTest.InvoiceUsecase.GetTimeEntryIdsOfInvoice(newInvoice.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(timeEntryIds))
+	// Clients and webhooks learn that the entries are locked:
+	assert.Equal(t, 1, countTimeEntryUpdatedEvents(t, design.ID, &newInvoice.ID))
+	invoicedEntry, err := usecaseTest.TimeEntryUsecase.GetTimeEntryById(design.ID)
+	assert.Nil(t, err)
+	assert.True(t, invoicedEntry.UpdatedAt.After(design.UpdatedAt))
+
+	// Invoiced entries cannot be changed or invoiced again:
+	var entityLockedError *EntityLockedError
+	design.Description = "Changed"
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&design)
+	assert.True(t, errors.As(err, &entityLockedError))
+	err = usecaseTest.TimeEntryUsecase.DeleteTimeEntry(design.ID)
+	assert.True(t, errors.As(err, &entityLockedError))
+	// A change that was checked before the entry was invoiced does not release the entry:
+	timeEntryRepo := database.NewGormTimeEntryRepository(test.DB)
+	var invoicedError *model.TimeEntryInvoicedError
+	err = timeEntryRepo.UpdateTimeEntry(&design)
+	assert.True(t, errors.As(err, &invoicedError))
+	err = timeEntryRepo.DeleteTimeEntry(&design)
+	assert.True(t, errors.As(err, &invoicedError))
+	storedEntry, err := usecaseTest.TimeEntryUsecase.GetTimeEntryById(design.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, newInvoice.ID, *storedEntry.InvoiceID)
+	assert.Equal(t, "Design", storedEntry.Description)
+	var invalidValueError *InvalidValueError
+	_, err = usecaseTest.InvoiceUsecase.CreateInvoice(request, now)
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	// After the cancellation the entries are released and get a new number:
+	cancelledInvoice, err := usecaseTest.InvoiceUsecase.CancelInvoice(newInvoice.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.InvoiceStatusCancelled, cancelledInvoice.Status)
+	assert.Equal(t, 1, countTimeEntryUpdatedEvents(t, design.ID, nil))
+	assert.Nil(t, usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&design))
+	_, err = usecaseTest.InvoiceUsecase.CancelInvoice(newInvoice.ID)
+	assert.True(t, errors.As(err, &invalidValueError))
+	secondInvoice, err := usecaseTest.InvoiceUsecase.CreateInvoice(request, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-0002", secondInvoice.Number)
+
+	invoices, err := usecaseTest.InvoiceUsecase.GetInvoicesOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(invoices))
+
+	// Personal invoices are personal data of the user:
+	personalData, err := database.NewGormDataExportRepository(test.DB).GetPersonalData(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(personalData.Invoices))
+	assert.Equal(t, 2, len(personalData.Invoices[0].Lines))
+}
+
+func Test_invoiceUsecase_CreateInvoiceWithInvalidRequest(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "Team", userId)
+	teamProject := addProject(t, usecaseTest.ProjectUsecase, "Team project", userId)
+	assert.Nil(t, usecaseTest.ProjectUsecase.AssignProjectToTeam(&teamProject, &team))
+	from := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 10, 2, 9, 0, 0, 0, time.UTC)
+
+	var entityIncompleteError *EntityIncompleteError
+	_, err := usecaseTest.InvoiceUsecase.CreateInvoice(InvoiceRequest{
+		Filter: model.InvoiceFilter{UserID: userId, From: from, To: to}}, now)
+	assert.True(t, errors.As(err, &entityIncompleteError))
+	_, err = usecaseTest.InvoiceUsecase.CreateInvoice(InvoiceRequest{
+		Filter: model.InvoiceFilter{UserID: userId, Client: "ACME"}}, now)
+	assert.True(t, errors.As(err, &entityIncompleteError))
+
+	var invalidValueError *InvalidValueError
+	_, err = usecaseTest.InvoiceUsecase.CreateInvoice(InvoiceRequest{
+		Filter:   model.InvoiceFilter{UserID: userId, Client: "ACME", From: from, To: to},
+		Rounding: invoice.Rounding{Minutes: 15, Mode: "SOMETIMES"}}, now)
+	assert.True(t, errors.As(err, &invalidValueError))
+	// Team projects are only invoiced by the team:
+	_, err = usecaseTest.InvoiceUsecase.CreateInvoice(InvoiceRequest{
+		Filter: model.InvoiceFilter{UserID: userId, ProjectID: &teamProject.ID, From: from, To: to}}, now)
+	assert.True(t, errors.As(err, &invalidValueError))
+	otherTeam := addTeam(t, usecaseTest.TeamUsecase, "Other team", userId)
+	_, err = usecaseTest.InvoiceUsecase.CreateInvoice(InvoiceRequest{
+		Filter: model.InvoiceFilter{UserID: userId, TeamID: &otherTeam.ID, ProjectID: &teamProject.ID, From: from, To: to}},
+		now)
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+// countTimeEntryUpdatedEvents counts the TimeEntryUpdated events of the entry in the outbox with the given invoice id.
+func countTimeEntryUpdatedEvents(t *testing.T, timeEntryId uuid.UUID, invoiceId *uuid.UUID) int {
+	var outboxEvents []model.OutboxEvent
+	assert.Nil(t, test.DB.Find(&outboxEvents, "type=? AND aggregate_id=?", model.EventTimeEntryUpdated, timeEntryId).Error)
+	count := 0
+	for _, outboxEvent := range outboxEvents {
+		event, err := model.DecodeDomainEvent(&outboxEvent)
+		assert.Nil(t, err)
+		if assert.ObjectsAreEqual(invoiceId, event.(*model.TimeEntryUpdated).InvoiceID) {
+			count++
+		}
+	}
+	return count
+}
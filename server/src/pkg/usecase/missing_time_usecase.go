@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type MissingTimeUsecase interface {
+	GetMissingTimesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.MissingTime, error)
+	GetMissingTimesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.MissingTime, error)
+}
+
+type missingTimeUsecase struct {
+	timeEntryUsecase   TimeEntryUsecase
+	workingTimeUsecase WorkingTimeUsecase
+	teamUsecase        TeamUsecase
+}
+
+func NewMissingTimeUsecase(timeEntryUsecase TimeEntryUsecase, workingTimeUsecase WorkingTimeUsecase, teamUsecase TeamUsecase) MissingTimeUsecase {
+	return &missingTimeUsecase{
+		timeEntryUsecase:   timeEntryUsecase,
+		workingTimeUsecase: workingTimeUsecase,
+		teamUsecase:        teamUsecase,
+	}
+}
+
+// GetMissingTimesOfUser returns the days in the interval [from, to) where the recorded time of the user is below the
+// target or where an entry is still running. Only days before today are checked because the current day is not over
+// yet. A zero "to" checks all days up to yesterday.
+func (usecase *missingTimeUsecase) GetMissingTimesOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.MissingTime, error) {
+	if from.IsZero() {
+		return nil, NewInvalidValueError("the start of the date range must not be empty")
+	}
+	from = startOfDay(from)
+	today := startOfDay(time.Now())
+	if to.IsZero() || to.After(today) {
+		to = today
+	}
+	to = startOfDay(to)
+	if !from.Before(to) {
+		return nil, nil
+	}
+
+	targetTimes, err := usecase.workingTimeUsecase.GetDailyTargetTimes(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	timeEntries, err := usecase.timeEntryUsecase.GetTimeEntriesOfUserInRange(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	recordedPerDay := make(map[time.Time]time.Duration)
+	runningEntriesPerDay := make(map[time.Time][]model.TimeEntry)
+	for _, timeEntry := range timeEntries {
+		day := startOfDay(timeEntry.StartTime)
+		if timeEntry.EndTime.IsZero() {
+			runningEntriesPerDay[day] = append(runningEntriesPerDay[day], timeEntry)
+			continue
+		}
+		recordedPerDay[day] += timeEntry.Duration()
+	}
+
+	var missingTimes []model.MissingTime
+	for _, targetTime := range targetTimes {
+		day := targetTime.Day
+		for _, runningEntry := range runningEntriesPerDay[day] {
+			timeEntryId := runningEntry.ID
+			missingTimes = append(missingTimes, model.MissingTime{
+				UserID:      userId,
+				Day:         day,
+				Reason:      model.MissingTimeRunningEntry,
+				Target:      targetTime.Target,
+				Recorded:    recordedPerDay[day],
+				TimeEntryID: &timeEntryId,
+			})
+		}
+		if targetTime.Target <= 0 || recordedPerDay[day] >= targetTime.Target {
+			continue
+		}
+		reason := model.MissingTimeBelowTarget
+		if recordedPerDay[day] == 0 {
+			reason = model.MissingTimeNoTimeRecorded
+		}
+		missingTimes = append(missingTimes, model.MissingTime{
+			UserID:   userId,
+			Day:      day,
+			Reason:   reason,
+			Target:   targetTime.Target,
+			Recorded: recordedPerDay[day],
+		})
+	}
+	return missingTimes, nil
+}
+
+// GetMissingTimesOfTeam returns the missing times of all members of the team.
+func (usecase *missingTimeUsecase) GetMissingTimesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.MissingTime, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	members, err := usecase.teamUsecase.GetUsersOfTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+	var missingTimes []model.MissingTime
+	for _, member := range members {
+		missingTimesOfUser, err := usecase.GetMissingTimesOfUser(member.UserID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		missingTimes = append(missingTimes, missingTimesOfUser...)
+	}
+	return missingTimes, nil
+}
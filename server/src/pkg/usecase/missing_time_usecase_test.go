@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_missingTimeUsecase_GetMissingTimesOfUser(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 6, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 6, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+	_, err = usecaseTest.AbsenceUsecase.ApproveAbsence(absence.ID, reviewerId)
+	assert.Nil(t, err)
+
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 8*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 5*time.Hour)
+	runningEntry := model.TimeEntry{
+		Description: "running",
+		UserId:      userId,
+		ProjectId:   project.ID,
+		StartTime:   time.Date(2023, 9, 7, 8, 0, 0, 0, time.UTC),
+	}
+	err = usecaseTest.TimeEntryUsecase.AddTimeEntry(&runningEntry)
+	assert.Nil(t, err)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 8, 8, 0, 0, 0, time.UTC), 9*time.Hour)
+
+	missingTimes, err := usecaseTest.MissingTimeUsecase.GetMissingTimesOfUser(userId,
+		time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(missingTimes))
+	assert.Equal(t, model.MissingTimeBelowTarget, missingTimes[0].Reason)
+	assert.True(t, time.Date(2023, 9, 5, 0, 0, 0, 0, time.UTC).Equal(missingTimes[0].Day))
+	assert.Equal(t, 5*time.Hour, missingTimes[0].Recorded)
+	assert.Equal(t, 8*time.Hour, missingTimes[0].Target)
+	assert.Equal(t, model.MissingTimeRunningEntry, missingTimes[1].Reason)
+	assert.Equal(t, runningEntry.ID, *missingTimes[1].TimeEntryID)
+	assert.Equal(t, model.MissingTimeNoTimeRecorded, missingTimes[2].Reason)
+	assert.True(t, time.Date(2023, 9, 7, 0, 0, 0, 0, time.UTC).Equal(missingTimes[2].Day))
+}
+
+func Test_missingTimeUsecase_GetMissingTimesOfTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	memberId := GetTestUserId(t)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	workingTimeModel := newFullTimeWorkingTimeModel(memberId, team.ID, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+
+	// Monday to Friday without any entries:
+	missingTimes, err := usecaseTest.MissingTimeUsecase.GetMissingTimesOfTeam(team.ID,
+		time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(missingTimes))
+	for _, missingTime := range missingTimes {
+		assert.Equal(t, memberId, missingTime.UserID)
+		assert.Equal(t, model.MissingTimeNoTimeRecorded, missingTime.Reason)
+	}
+}
+
+func Test_missingTimeUsecase_GetMissingTimesFailsWithoutStartDate(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	_, err := usecaseTest.MissingTimeUsecase.GetMissingTimesOfUser(GetTestUserId(t), time.Time{}, time.Time{})
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/notification"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/glog"
+)
+
+// The dates and times in notifications are formatted in UTC:
+const notificationDateFormat = "2006-01-02"
+const notificationTimeFormat = "2006-01-02 15:04 MST"
+
+type NotificationUsecase interface {
+	Notify(userId uuid.UUID, notificationType string, data map[string]string) error
+	GetNotificationById(id uuid.UUID) (*model.Notification, error)
+	GetNotificationsOfUser(userId uuid.UUID, onlyUnread bool) ([]model.Notification, error)
+	MarkNotificationAsRead(id uuid.UUID) (*model.Notification, error)
+	GetNotificationSettings(userId uuid.UUID) (*model.NotificationSettings, error)
+	SetNotificationSettings(settings *model.NotificationSettings) error
+	GetNotificationTemplates() ([]model.NotificationTemplate, error)
+	SetNotificationTemplate(template *model.NotificationTemplate) error
+	ResetNotificationTemplate(notificationType string) error
+}
+
+type notificationUsecase struct {
+	repo     repository.NotificationRepository
+	channels map[string]notification.Channel
+}
+
+// NewNotificationUsecase creates the usecase with the available channels. Users can only enable channels that are
+// available, e.g. email is missing if no SMTP server is configured.
+func NewNotificationUsecase(repo repository.NotificationRepository, channels map[string]notification.Channel) NotificationUsecase {
+	return &notificationUsecase{
+		repo:     repo,
+		channels: channels,
+	}
+}
+
+// Notify renders the template of the notification type and sends it through all channels the user has enabled.
+// Slow channels like email and webhooks are wrapped in a notification.AsyncChannel by the server, so they only queue
+// the message. Failing channels do not stop the others, their errors are logged and the first one is returned.
+// Callers usually ignore the error as a notification must not make the triggering action fail.
+func (usecase *notificationUsecase) Notify(userId uuid.UUID, notificationType string, data map[string]string) error {
+	settings, err := usecase.GetNotificationSettings(userId)
+	if err != nil {
+		glog.Errorf("loading the notification settings of user %v failed: %v", userId, err)
+		return err
+	}
+	if settings.MutedTypes.Contains(notificationType) {
+		return nil
+	}
+	template, err := usecase.getNotificationTemplate(notificationType)
+	if err != nil {
+		glog.Errorf("notification %v for user %v failed: %v", notificationType, userId, err)
+		return err
+	}
+	subject, body, err := notification.Render(template, data)
+	if err != nil {
+		glog.Errorf("rendering notification %v for user %v failed: %v", notificationType, userId, err)
+		return err
+	}
+	message := notification.Message{
+		UserID:    userId,
+		Type:      notificationType,
+		Subject:   subject,
+		Body:      body,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+	var firstError error
+	for _, channelName := range settings.Channels {
+		channel, ok := usecase.channels[channelName]
+		if !ok {
+			continue
+		}
+		err = channel.Send(settings, &message)
+		if err != nil {
+			glog.Errorf("sending notification %v to user %v via %v failed: %v", notificationType, userId, channelName, err)
+			if firstError == nil {
+				firstError = err
+			}
+		}
+	}
+	return firstError
+}
+
+func (usecase *notificationUsecase) GetNotificationById(id uuid.UUID) (*model.Notification, error) {
+	notification, err := usecase.repo.GetNotificationById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("notification with id %v not found", id))
+	}
+	return notification, nil
+}
+
+func (usecase *notificationUsecase) GetNotificationsOfUser(userId uuid.UUID, onlyUnread bool) ([]model.Notification, error) {
+	return usecase.repo.GetNotificationsOfUser(userId, onlyUnread)
+}
+
+func (usecase *notificationUsecase) MarkNotificationAsRead(id uuid.UUID) (*model.Notification, error) {
+	notification, err := usecase.GetNotificationById(id)
+	if err != nil {
+		return nil, err
+	}
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+	now := time.Now().UTC()
+	notification.ReadAt = &now
+	err = usecase.repo.UpdateNotification(notification)
+	if err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// GetNotificationSettings returns the stored settings of the user. Without stored settings only the inbox is enabled.
+func (usecase *notificationUsecase) GetNotificationSettings(userId uuid.UUID) (*model.NotificationSettings, error) {
+	settings, err := usecase.repo.GetNotificationSettingsOfUser(userId)
+	if err != nil {
+		return &model.NotificationSettings{
+			UserID:     userId,
+			Channels:   model.StringList{model.NotificationChannelInbox},
+			MutedTypes: model.StringList{},
+		}, nil
+	}
+	return settings, nil
+}
+
+func (usecase *notificationUsecase) SetNotificationSettings(settings *model.NotificationSettings) error {
+	if settings.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	for _, channel := range settings.Channels {
+		if !model.IsValidNotificationChannel(channel) {
+			return NewInvalidValueError(fmt.Sprintf("%v is not a valid notification channel", channel))
+		}
+		if _, ok := usecase.channels[channel]; !ok {
+			return NewInvalidValueError(fmt.Sprintf("the notification channel %v is not available on this server", channel))
+		}
+	}
+	for _, mutedType := range settings.MutedTypes {
+		if !notification.IsKnownNotificationType(mutedType) {
+			return NewInvalidValueError(fmt.Sprintf("%v is not a valid notification type", mutedType))
+		}
+	}
+	if settings.Channels.Contains(model.NotificationChannelEmail) && settings.Email == "" {
+		return NewEntityIncompleteError("please specify an email address to receive notifications by email")
+	}
+	if settings.Channels.Contains(model.NotificationChannelWebhook) && settings.WebhookURL == "" {
+		return NewEntityIncompleteError("please specify a webhook url to receive notifications by webhook")
+	}
+	if settings.WebhookURL != "" {
+		if err := checkHttpUrl(settings.WebhookURL); err != nil {
+			return err
+		}
+	}
+	storedSettings, err := usecase.repo.GetNotificationSettingsOfUser(settings.UserID)
+	if err == nil {
+		settings.Model = storedSettings.Model
+		settings.ID = storedSettings.ID
+	}
+	return usecase.repo.SaveNotificationSettings(settings)
+}
+
+// GetNotificationTemplates returns the effective templates of all notification types, i.e. the stored ones and the
+// built-in defaults for all others.
+func (usecase *notificationUsecase) GetNotificationTemplates() ([]model.NotificationTemplate, error) {
+	storedTemplates, err := usecase.repo.GetAllNotificationTemplates()
+	if err != nil {
+		return nil, err
+	}
+	templatesByType := make(map[string]model.NotificationTemplate)
+	for notificationType, template := range notification.DefaultTemplates {
+		templatesByType[notificationType] = template
+	}
+	for _, template := range storedTemplates {
+		templatesByType[template.Type] = template
+	}
+	var templates []model.NotificationTemplate
+	for _, template := range templatesByType {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Type < templates[j].Type
+	})
+	return templates, nil
+}
+
+func (usecase *notificationUsecase) SetNotificationTemplate(template *model.NotificationTemplate) error {
+	if !notification.IsKnownNotificationType(template.Type) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid notification type", template.Type))
+	}
+	if template.Subject == "" || template.Body == "" {
+		return NewEntityIncompleteError("subject and body of the template must not be empty")
+	}
+	err := notification.CheckTemplate(template)
+	if err != nil {
+		return NewInvalidValueError(fmt.Sprintf("the template is invalid: %v", err))
+	}
+	storedTemplate, err := usecase.repo.GetNotificationTemplate(template.Type)
+	if err == nil {
+		template.Model = storedTemplate.Model
+		template.ID = storedTemplate.ID
+	}
+	return usecase.repo.SaveNotificationTemplate(template)
+}
+
+// ResetNotificationTemplate deletes the stored template of the type, so that the built-in default is used again.
+func (usecase *notificationUsecase) ResetNotificationTemplate(notificationType string) error {
+	if !notification.IsKnownNotificationType(notificationType) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid notification type", notificationType))
+	}
+	storedTemplate, err := usecase.repo.GetNotificationTemplate(notificationType)
+	if err != nil {
+		return nil
+	}
+	return usecase.repo.DeleteNotificationTemplate(storedTemplate)
+}
+
+func (usecase *notificationUsecase) getNotificationTemplate(notificationType string) (*model.NotificationTemplate, error) {
+	template, err := usecase.repo.GetNotificationTemplate(notificationType)
+	if err == nil {
+		return template, nil
+	}
+	defaultTemplate, ok := notification.DefaultTemplates[notificationType]
+	if !ok {
+		return nil, NewInvalidValueError(fmt.Sprintf("%v is not a valid notification type", notificationType))
+	}
+	return &defaultTemplate, nil
+}
+
+// notifyManagersOfTeam sends the notification to all admins and managers of the team except the given user.
+func notifyManagersOfTeam(notificationUsecase NotificationUsecase, teamUsecase TeamUsecase, teamId uuid.UUID, exceptUserId uuid.UUID,
+	notificationType string, data map[string]string) {
+	assignments, err := teamUsecase.GetUsersOfTeam(teamId)
+	if err != nil {
+		glog.Errorf("loading the members of team %v failed: %v", teamId, err)
+		return
+	}
+	for _, assignment := range assignments {
+		if assignment.UserID == exceptUserId {
+			continue
+		}
+		if teamUsecase.IsUserManagerInTeam(assignment.UserID, teamId) {
+			notificationUsecase.Notify(assignment.UserID, notificationType, data)
+		}
+	}
+}
+
+// checkHttpUrl returns an InvalidValueError unless the value is an absolute http or https url, e.g. of a webhook.
+func checkHttpUrl(value string) error {
+	parsedUrl, err := url.Parse(value)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid http or https url", value))
+	}
+	return nil
+}
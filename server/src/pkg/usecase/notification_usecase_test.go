@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_notificationUsecase_NotifyStoresNotificationInInbox(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	err := usecaseTest.NotificationUsecase.Notify(userId, model.NotificationTimesheetApproved, map[string]string{
+		"StartDate": "2023-09-04",
+		"EndDate":   "2023-09-10",
+	})
+	assert.Nil(t, err)
+
+	notifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, model.NotificationTimesheetApproved, notifications[0].Type)
+	assert.Equal(t, "Timesheet 2023-09-04 - 2023-09-10 approved", notifications[0].Subject)
+	assert.Nil(t, notifications[0].ReadAt)
+
+	readNotification, err := usecaseTest.NotificationUsecase.MarkNotificationAsRead(notifications[0].ID)
+	assert.Nil(t, err)
+	assert.NotNil(t, readNotification.ReadAt)
+	notifications, err = usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(notifications))
+	notifications, err = usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notifications))
+}
+
+func Test_notificationUsecase_NotifyUsesChannelsOfUser(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	var receivedTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		assert.Nil(t, err)
+		receivedTypes = append(receivedTypes, payload["type"].(string))
+	}))
+	defer server.Close()
+
+	userId := GetTestUserId(t)
+	settings := model.NotificationSettings{
+		UserID:     userId,
+		Channels:   model.StringList{model.NotificationChannelWebhook},
+		MutedTypes: model.StringList{model.NotificationAddedToTeam},
+		WebhookURL: server.URL,
+	}
+	err := usecaseTest.NotificationUsecase.SetNotificationSettings(&settings)
+	assert.Nil(t, err)
+
+	err = usecaseTest.NotificationUsecase.Notify(userId, model.NotificationRunningTimerFlagged, map[string]string{})
+	assert.Nil(t, err)
+	err = usecaseTest.NotificationUsecase.Notify(userId, model.NotificationAddedToTeam, map[string]string{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{model.NotificationRunningTimerFlagged}, receivedTypes)
+
+	notifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(notifications))
+}
+
+func Test_notificationUsecase_SetNotificationSettingsFailsWithUnavailableChannel(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	// No SMTP server is configured in the tests:
+	settings := model.NotificationSettings{
+		UserID:   userId,
+		Channels: model.StringList{model.NotificationChannelEmail},
+		Email:    "user@example.com",
+	}
+	err := usecaseTest.NotificationUsecase.SetNotificationSettings(&settings)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	settings = model.NotificationSettings{
+		UserID:   userId,
+		Channels: model.StringList{model.NotificationChannelWebhook},
+	}
+	err = usecaseTest.NotificationUsecase.SetNotificationSettings(&settings)
+	assert.NotNil(t, err)
+	var entityIncompleteError *EntityIncompleteError
+	assert.True(t, errors.As(err, &entityIncompleteError))
+}
+
+func Test_notificationUsecase_SetNotificationSettingsFailsWithInvalidWebhookUrl(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	for _, webhookUrl := range []string{"file:///etc/passwd", "example.com/hook", "https://"} {
+		settings := model.NotificationSettings{
+			UserID:     userId,
+			Channels:   model.StringList{model.NotificationChannelWebhook},
+			WebhookURL: webhookUrl,
+		}
+		err := usecaseTest.NotificationUsecase.SetNotificationSettings(&settings)
+		assert.NotNil(t, err, webhookUrl)
+		var invalidValueError *InvalidValueError
+		assert.True(t, errors.As(err, &invalidValueError), webhookUrl)
+	}
+}
+
+func Test_notificationUsecase_SetNotificationTemplate(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	template := model.NotificationTemplate{
+		Type:    model.NotificationTimesheetApproved,
+		Subject: "Approved: {{.StartDate}}",
+		Body:    "Well done!",
+	}
+	err := usecaseTest.NotificationUsecase.SetNotificationTemplate(&template)
+	assert.Nil(t, err)
+
+	userId := GetTestUserId(t)
+	err = usecaseTest.NotificationUsecase.Notify(userId, model.NotificationTimesheetApproved, map[string]string{"StartDate": "2023-09-04"})
+	assert.Nil(t, err)
+	notifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "Approved: 2023-09-04", notifications[0].Subject)
+	assert.Equal(t, "Well done!", notifications[0].Body)
+
+	templates, err := usecaseTest.NotificationUsecase.GetNotificationTemplates()
+	assert.Nil(t, err)
+	for _, effectiveTemplate := range templates {
+		if effectiveTemplate.Type == model.NotificationTimesheetApproved {
+			assert.Equal(t, "Well done!", effectiveTemplate.Body)
+		}
+	}
+
+	err = usecaseTest.NotificationUsecase.ResetNotificationTemplate(model.NotificationTimesheetApproved)
+	assert.Nil(t, err)
+	err = usecaseTest.NotificationUsecase.Notify(userId, model.NotificationTimesheetApproved, map[string]string{"StartDate": "2023-09-04"})
+	assert.Nil(t, err)
+	notifications, err = usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(notifications))
+	assert.Equal(t, "Timesheet 2023-09-04 -  approved", notifications[0].Subject)
+}
+
+func Test_notificationUsecase_SetNotificationTemplateFailsWithInvalidTemplate(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	template := model.NotificationTemplate{
+		Type:    model.NotificationTimesheetApproved,
+		Subject: "Approved: {{.StartDate",
+		Body:    "Well done!",
+	}
+	err := usecaseTest.NotificationUsecase.SetNotificationTemplate(&template)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	template = model.NotificationTemplate{
+		Type:    "BUDGET_EXPLODED",
+		Subject: "subject",
+		Body:    "body",
+	}
+	err = usecaseTest.NotificationUsecase.SetNotificationTemplate(&template)
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_notificationUsecase_ReviewsAndInvitationsTriggerNotifications(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(userId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	_, err = usecaseTest.TimesheetUsecase.RejectTimesheet(timesheet.ID, adminId, "friday is missing")
+	assert.Nil(t, err)
+
+	// The owner of a new team is not notified:
+	adminNotifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(adminId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(adminNotifications))
+	assert.Equal(t, model.NotificationTimesheetSubmitted, adminNotifications[0].Type)
+
+	userNotifications, err := usecaseTest.NotificationUsecase.GetNotificationsOfUser(userId, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(userNotifications))
+	assert.Equal(t, model.NotificationTimesheetRejected, userNotifications[0].Type)
+	assert.Contains(t, userNotifications[0].Body, "friday is missing")
+	assert.Equal(t, model.NotificationAddedToTeam, userNotifications[1].Type)
+	assert.Equal(t, "Welcome to team team.1", userNotifications[1].Subject)
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+type OvertimeUsecase interface {
+	GetOvertimeAccount(userId uuid.UUID) (*model.OvertimeAccount, error)
+	SetOvertimeAccount(account *model.OvertimeAccount) error
+	GetOvertimeCorrectionById(id uuid.UUID) (*model.OvertimeCorrection, error)
+	GetOvertimeCorrectionsOfUser(userId uuid.UUID) ([]model.OvertimeCorrection, error)
+	AddOvertimeCorrection(correction *model.OvertimeCorrection) error
+	DeleteOvertimeCorrection(id uuid.UUID) error
+	GetOvertimeBalance(userId uuid.UUID, from time.Time, to time.Time) (*model.OvertimeBalance, error)
+}
+
+type overtimeUsecase struct {
+	repo               repository.OvertimeRepository
+	timeEntryUsecase   TimeEntryUsecase
+	workingTimeUsecase WorkingTimeUsecase
+}
+
+func NewOvertimeUsecase(repo repository.OvertimeRepository, timeEntryUsecase TimeEntryUsecase, workingTimeUsecase WorkingTimeUsecase) OvertimeUsecase {
+	return &overtimeUsecase{
+		repo:               repo,
+		timeEntryUsecase:   timeEntryUsecase,
+		workingTimeUsecase: workingTimeUsecase,
+	}
+}
+
+func (usecase *overtimeUsecase) GetOvertimeAccount(userId uuid.UUID) (*model.OvertimeAccount, error) {
+	account, err := usecase.repo.GetOvertimeAccountOfUser(userId)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("overtime account of user %v not found", userId))
+	}
+	return account, nil
+}
+
+// SetOvertimeAccount creates the overtime account of the user or changes the start date and opening balance of the
+// existing one.
+func (usecase *overtimeUsecase) SetOvertimeAccount(account *model.OvertimeAccount) error {
+	if account.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	if account.StartDate.IsZero() {
+		return NewEntityIncompleteError("the start date of the overtime account must not be empty")
+	}
+	account.StartDate = startOfDay(account.StartDate)
+	existingAccount, err := usecase.repo.GetOvertimeAccountOfUser(account.UserID)
+	if err != nil {
+		return usecase.repo.AddOvertimeAccount(account)
+	}
+	existingAccount.StartDate = account.StartDate
+	existingAccount.OpeningBalanceMinutes = account.OpeningBalanceMinutes
+	err = usecase.repo.UpdateOvertimeAccount(existingAccount)
+	if err != nil {
+		return err
+	}
+	*account = *existingAccount
+	return nil
+}
+
+func (usecase *overtimeUsecase) GetOvertimeCorrectionById(id uuid.UUID) (*model.OvertimeCorrection, error) {
+	correction, err := usecase.repo.GetOvertimeCorrectionById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("overtime correction with id %v not found", id))
+	}
+	return correction, nil
+}
+
+func (usecase *overtimeUsecase) GetOvertimeCorrectionsOfUser(userId uuid.UUID) ([]model.OvertimeCorrection, error) {
+	return usecase.repo.GetOvertimeCorrectionsOfUser(userId, time.Time{}, time.Time{})
+}
+
+func (usecase *overtimeUsecase) AddOvertimeCorrection(correction *model.OvertimeCorrection) error {
+	if correction.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	if correction.CreatedBy == uuid.Nil {
+		return NewEntityIncompleteError("the user creating the correction must not be empty")
+	}
+	if correction.Date.IsZero() {
+		return NewEntityIncompleteError("the date of the correction must not be empty")
+	}
+	switch correction.Type {
+	case model.OvertimeCorrectionPayout, model.OvertimeCorrectionCompensation, model.OvertimeCorrectionOther:
+	default:
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid correction type", correction.Type))
+	}
+	if correction.Minutes == 0 {
+		return NewInvalidValueError("the correction must not be zero")
+	}
+	if correction.Minutes > 0 && correction.Type != model.OvertimeCorrectionOther {
+		// payouts and compensatory time off always reduce the balance
+		return NewInvalidValueError(fmt.Sprintf("the minutes of a %v correction must be negative", correction.Type))
+	}
+	account, err := usecase.GetOvertimeAccount(correction.UserID)
+	if err != nil {
+		return err
+	}
+	correction.Date = startOfDay(correction.Date)
+	if correction.Date.Before(account.StartDate) {
+		return NewInvalidValueError("the correction must not be before the start date of the overtime account")
+	}
+	return usecase.repo.AddOvertimeCorrection(correction)
+}
+
+func (usecase *overtimeUsecase) DeleteOvertimeCorrection(id uuid.UUID) error {
+	correction, err := usecase.GetOvertimeCorrectionById(id)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.DeleteOvertimeCorrection(correction)
+}
+
+// GetOvertimeBalance calculates the overtime of the user from the start date of the account up to the day before "to"
+// (today if "to" is zero). The target times already take absences and holidays into account. The running balance is
+// returned for the days in the interval [from, to), a zero "from" returns all days since the start date.
+func (usecase *overtimeUsecase) GetOvertimeBalance(userId uuid.UUID, from time.Time, to time.Time) (*model.OvertimeBalance, error) {
+	account, err := usecase.GetOvertimeAccount(userId)
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = time.Now().AddDate(0, 0, 1)
+	}
+	to = startOfDay(to)
+	from = startOfDayOrZero(from)
+	balance := &model.OvertimeBalance{
+		UserID:         userId,
+		StartDate:      account.StartDate,
+		EndDate:        to,
+		OpeningBalance: time.Duration(account.OpeningBalanceMinutes) * time.Minute,
+	}
+	balance.Balance = balance.OpeningBalance
+	if !account.StartDate.Before(to) {
+		return balance, nil
+	}
+
+	targetTimes, err := usecase.workingTimeUsecase.GetDailyTargetTimes(userId, account.StartDate, to)
+	if err != nil {
+		return nil, err
+	}
+	timeEntries, err := usecase.timeEntryUsecase.GetTimeEntriesOfUserInRange(userId, account.StartDate, to)
+	if err != nil {
+		return nil, err
+	}
+	workedPerDay := make(map[time.Time]time.Duration)
+	for _, timeEntry := range timeEntries {
+		workedPerDay[startOfDay(timeEntry.StartTime)] += timeEntry.Duration()
+	}
+	corrections, err := usecase.repo.GetOvertimeCorrectionsOfUser(userId, account.StartDate, to)
+	if err != nil {
+		return nil, err
+	}
+	correctionsPerDay := make(map[time.Time]time.Duration)
+	for _, correction := range corrections {
+		correctionsPerDay[startOfDay(correction.Date)] += time.Duration(correction.Minutes) * time.Minute
+	}
+
+	for _, targetTime := range targetTimes {
+		day := model.DailyOvertime{
+			Day:        targetTime.Day,
+			Worked:     workedPerDay[targetTime.Day],
+			Target:     targetTime.Target,
+			Correction: correctionsPerDay[targetTime.Day],
+		}
+		balance.Worked += day.Worked
+		balance.Target += day.Target
+		balance.Corrections += day.Correction
+		balance.Balance += day.Worked - day.Target + day.Correction
+		day.Balance = balance.Balance
+		if !day.Day.Before(from) {
+			balance.Days = append(balance.Days, day)
+		}
+	}
+	return balance, nil
+}
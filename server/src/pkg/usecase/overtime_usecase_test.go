@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_overtimeUsecase_GetOvertimeBalance(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+	account := model.OvertimeAccount{
+		UserID:                userId,
+		StartDate:             time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC),
+		OpeningBalanceMinutes: 120,
+	}
+	err = usecaseTest.OvertimeUsecase.SetOvertimeAccount(&account)
+	assert.Nil(t, err)
+
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	// Entries before the start date are not counted:
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 1, 8, 0, 0, 0, time.UTC), 10*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 9*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 8*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC), 6*time.Hour)
+	addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 8, 8, 0, 0, 0, time.UTC), 8*time.Hour)
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 7, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 7, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.AbsenceUsecase.RequestAbsence(&absence)
+	assert.Nil(t, err)
+	_, err = usecaseTest.AbsenceUsecase.ApproveAbsence(absence.ID, reviewerId)
+	assert.Nil(t, err)
+	correction := model.OvertimeCorrection{
+		UserID:    userId,
+		Date:      time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC),
+		Minutes:   -60,
+		Type:      model.OvertimeCorrectionPayout,
+		CreatedBy: userId,
+	}
+	err = usecaseTest.OvertimeUsecase.AddOvertimeCorrection(&correction)
+	assert.Nil(t, err)
+
+	balance, err := usecaseTest.OvertimeUsecase.GetOvertimeBalance(userId,
+		time.Date(2023, 9, 6, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 9, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 2*time.Hour, balance.OpeningBalance)
+	assert.Equal(t, 31*time.Hour, balance.Worked)
+	assert.Equal(t, 32*time.Hour, balance.Target)
+	assert.Equal(t, -time.Hour, balance.Corrections)
+	assert.Equal(t, time.Duration(0), balance.Balance)
+	assert.Equal(t, 3, len(balance.Days))
+	assert.True(t, time.Date(2023, 9, 6, 0, 0, 0, 0, time.UTC).Equal(balance.Days[0].Day))
+	assert.Equal(t, time.Hour, balance.Days[0].Balance)
+	assert.Equal(t, time.Duration(0), balance.Days[1].Target)
+	assert.Equal(t, time.Duration(0), balance.Days[2].Balance)
+}
+
+func Test_overtimeUsecase_SetOvertimeAccountUpdatesExistingAccount(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	account := model.OvertimeAccount{
+		UserID:    userId,
+		StartDate: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	err := usecaseTest.OvertimeUsecase.SetOvertimeAccount(&account)
+	assert.Nil(t, err)
+	changedAccount := model.OvertimeAccount{
+		UserID:                userId,
+		StartDate:             time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalanceMinutes: -30,
+	}
+	err = usecaseTest.OvertimeUsecase.SetOvertimeAccount(&changedAccount)
+	assert.Nil(t, err)
+	assert.Equal(t, account.ID, changedAccount.ID)
+
+	accountFromDb, err := usecaseTest.OvertimeUsecase.GetOvertimeAccount(userId)
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC).Equal(accountFromDb.StartDate))
+	assert.Equal(t, -30, accountFromDb.OpeningBalanceMinutes)
+}
+
+func Test_overtimeUsecase_GetOvertimeBalanceFailsWithoutAccount(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	_, err := usecaseTest.OvertimeUsecase.GetOvertimeBalance(GetTestUserId(t), time.Time{}, time.Time{})
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_overtimeUsecase_AddOvertimeCorrectionFailsBeforeStartDate(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	account := model.OvertimeAccount{
+		UserID:    userId,
+		StartDate: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	err := usecaseTest.OvertimeUsecase.SetOvertimeAccount(&account)
+	assert.Nil(t, err)
+
+	correction := model.OvertimeCorrection{
+		UserID:    userId,
+		Date:      time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC),
+		Minutes:   -60,
+		Type:      model.OvertimeCorrectionCompensation,
+		CreatedBy: userId,
+	}
+	err = usecaseTest.OvertimeUsecase.AddOvertimeCorrection(&correction)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_overtimeUsecase_AddOvertimeCorrectionFailsWithPositivePayout(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	account := model.OvertimeAccount{
+		UserID:    userId,
+		StartDate: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	err := usecaseTest.OvertimeUsecase.SetOvertimeAccount(&account)
+	assert.Nil(t, err)
+
+	for _, correctionType := range []string{model.OvertimeCorrectionPayout, model.OvertimeCorrectionCompensation} {
+		correction := model.OvertimeCorrection{
+			UserID:    userId,
+			Date:      time.Date(2023, 9, 29, 0, 0, 0, 0, time.UTC),
+			Minutes:   60,
+			Type:      correctionType,
+			CreatedBy: userId,
+		}
+		err = usecaseTest.OvertimeUsecase.AddOvertimeCorrection(&correction)
+		assert.NotNil(t, err)
+		var invalidValueError *InvalidValueError
+		assert.True(t, errors.As(err, &invalidValueError))
+	}
+
+	// Other corrections may increase the balance:
+	correction := model.OvertimeCorrection{
+		UserID:    userId,
+		Date:      time.Date(2023, 9, 29, 0, 0, 0, 0, time.UTC),
+		Minutes:   60,
+		Type:      model.OvertimeCorrectionOther,
+		CreatedBy: userId,
+	}
+	err = usecaseTest.OvertimeUsecase.AddOvertimeCorrection(&correction)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/export"
+
+	"github.com/gofrs/uuid"
+)
+
+type PdfReportUsecase interface {
+	GetReportTemplate(teamId uuid.UUID) (*model.ReportTemplate, error)
+	SetReportTemplate(template *model.ReportTemplate) error
+	ResetReportTemplate(teamId uuid.UUID) error
+	GetMonthlyTimesheet(teamId uuid.UUID, month time.Time, userId *uuid.UUID, projectId *uuid.UUID) (*export.Timesheet, error)
+}
+
+type pdfReportUsecase struct {
+	repo           repository.ReportTemplateRepository
+	exportUsecase  ExportUsecase
+	projectUsecase ProjectUsecase
+	teamUsecase    TeamUsecase
+}
+
+func NewPdfReportUsecase(repo repository.ReportTemplateRepository, exportUsecase ExportUsecase, projectUsecase ProjectUsecase,
+	teamUsecase TeamUsecase) PdfReportUsecase {
+	return &pdfReportUsecase{
+		repo:           repo,
+		exportUsecase:  exportUsecase,
+		projectUsecase: projectUsecase,
+		teamUsecase:    teamUsecase,
+	}
+}
+
+// GetReportTemplate returns the stored template of the team or the default template.
+func (usecase *pdfReportUsecase) GetReportTemplate(teamId uuid.UUID) (*model.ReportTemplate, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	template, err := usecase.repo.GetReportTemplateOfTeam(teamId)
+	if err != nil {
+		return model.NewDefaultReportTemplate(teamId), nil
+	}
+	return template, nil
+}
+
+func (usecase *pdfReportUsecase) SetReportTemplate(template *model.ReportTemplate) error {
+	_, err := usecase.teamUsecase.GetTeamById(template.TeamID)
+	if err != nil {
+		return err
+	}
+	if template.Layout == "" {
+		template.Layout = model.ReportLayoutEntries
+	}
+	if !model.IsValidReportLayout(template.Layout) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid layout, allowed are %v and %v", template.Layout,
+			model.ReportLayoutEntries, model.ReportLayoutDailyTotals))
+	}
+	if template.Language == "" {
+		template.Language = "en"
+	}
+	if !export.IsKnownLanguage(template.Language) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a supported language", template.Language))
+	}
+	storedTemplate, err := usecase.repo.GetReportTemplateOfTeam(template.TeamID)
+	if err == nil {
+		template.Model = storedTemplate.Model
+		template.ID = storedTemplate.ID
+	}
+	return usecase.repo.SaveReportTemplate(template)
+}
+
+// ResetReportTemplate deletes the stored template of the team, so that the default is used again.
+func (usecase *pdfReportUsecase) ResetReportTemplate(teamId uuid.UUID) error {
+	storedTemplate, err := usecase.repo.GetReportTemplateOfTeam(teamId)
+	if err != nil {
+		return nil
+	}
+	return usecase.repo.DeleteReportTemplate(storedTemplate)
+}
+
+// GetMonthlyTimesheet collects the time entries of the month for the timesheet of either a user or a project of the
+// team. Only the entries of the projects of the team are included.
+func (usecase *pdfReportUsecase) GetMonthlyTimesheet(teamId uuid.UUID, month time.Time, userId *uuid.UUID, projectId *uuid.UUID) (*export.Timesheet, error) {
+	if (userId == nil) == (projectId == nil) {
+		return nil, NewInvalidValueError("a timesheet is created either for a user or for a project")
+	}
+	team, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	template, err := usecase.GetReportTemplate(teamId)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	timesheet := export.Timesheet{
+		Template: *template,
+		TeamName: team.Name1,
+		UserID:   userId,
+		From:     from,
+		To:       from.AddDate(0, 1, 0),
+	}
+	filter := ExportFilter{From: timesheet.From, To: timesheet.To}
+	if userId != nil {
+		filter.UserIds = []uuid.UUID{*userId}
+	} else {
+		project, err := usecase.projectUsecase.GetProjectById(*projectId)
+		if err != nil {
+			return nil, NewEntityNotFoundError(fmt.Sprintf("project with id %v does not exist", *projectId))
+		}
+		if project.TeamID == nil || *project.TeamID != teamId {
+			return nil, NewInvalidValueError(fmt.Sprintf("the project %v does not belong to the team", project.Name))
+		}
+		timesheet.ProjectName = project.Name
+		filter.ProjectIds = []uuid.UUID{*projectId}
+	}
+	timesheet.Rows, err = usecase.exportUsecase.GetExportRowsOfTeam(teamId, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &timesheet, nil
+}
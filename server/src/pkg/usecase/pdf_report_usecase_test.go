@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pdfReportUsecase_SetReportTemplate(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+
+	template, err := usecaseTest.PdfReportUsecase.GetReportTemplate(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ReportLayoutEntries, template.Layout)
+	assert.Equal(t, "en", template.Language)
+
+	err = usecaseTest.PdfReportUsecase.SetReportTemplate(&model.ReportTemplate{
+		TeamID:          team.ID,
+		Client:          "ACME",
+		Layout:          model.ReportLayoutDailyTotals,
+		Language:        "de",
+		SignatureLabels: model.StringList{"Mitarbeiter", "Kunde"},
+	})
+	assert.Nil(t, err)
+	// Setting the template again replaces the stored one:
+	err = usecaseTest.PdfReportUsecase.SetReportTemplate(&model.ReportTemplate{
+		TeamID:          team.ID,
+		Client:          "ACME GmbH",
+		Layout:          model.ReportLayoutDailyTotals,
+		Language:        "de",
+		SignatureLabels: model.StringList{"Mitarbeiter", "Kunde"},
+	})
+	assert.Nil(t, err)
+	template, err = usecaseTest.PdfReportUsecase.GetReportTemplate(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "ACME GmbH", template.Client)
+	assert.Equal(t, model.ReportLayoutDailyTotals, template.Layout)
+	assert.Equal(t, model.StringList{"Mitarbeiter", "Kunde"}, template.SignatureLabels)
+
+	err = usecaseTest.PdfReportUsecase.ResetReportTemplate(team.ID)
+	assert.Nil(t, err)
+	template, err = usecaseTest.PdfReportUsecase.GetReportTemplate(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "", template.Client)
+}
+
+func Test_pdfReportUsecase_SetReportTemplateFailsWithInvalidValues(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", GetTestUserId(t))
+
+	var invalidValueError *InvalidValueError
+	err := usecaseTest.PdfReportUsecase.SetReportTemplate(&model.ReportTemplate{TeamID: team.ID, Layout: "pivot"})
+	assert.True(t, errors.As(err, &invalidValueError))
+	err = usecaseTest.PdfReportUsecase.SetReportTemplate(&model.ReportTemplate{TeamID: team.ID, Language: "fr"})
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	var entityNotFoundError *EntityNotFoundError
+	err = usecaseTest.PdfReportUsecase.SetReportTemplate(&model.ReportTemplate{TeamID: GetTestUserId(t)})
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_pdfReportUsecase_GetMonthlyTimesheet(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	memberId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	teamProject := addProject(t, usecaseTest.ProjectUsecase, "team project", adminId)
+	err = usecaseTest.ProjectUsecase.AssignProjectToTeam(&teamProject, &team)
+	assert.Nil(t, err)
+	privateProject := addProject(t, usecaseTest.ProjectUsecase, "private project", memberId)
+
+	addReportTimeEntry(t, usecaseTest, memberId, teamProject, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, memberId, teamProject, time.Date(2023, 10, 2, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, adminId, teamProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 3*time.Hour)
+	addReportTimeEntry(t, usecaseTest, memberId, privateProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	month := time.Date(2023, 9, 15, 0, 0, 0, 0, time.UTC)
+	timesheet, err := usecaseTest.PdfReportUsecase.GetMonthlyTimesheet(team.ID, month, &memberId, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "team", timesheet.TeamName)
+	assert.Equal(t, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), timesheet.From)
+	assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), timesheet.To)
+	assert.Equal(t, 1, len(timesheet.Rows))
+	assert.Equal(t, teamProject.ID, timesheet.Rows[0].TimeEntry.ProjectId)
+
+	timesheet, err = usecaseTest.PdfReportUsecase.GetMonthlyTimesheet(team.ID, month, nil, &teamProject.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "team project", timesheet.ProjectName)
+	assert.Equal(t, 2, len(timesheet.Rows))
+
+	var invalidValueError *InvalidValueError
+	_, err = usecaseTest.PdfReportUsecase.GetMonthlyTimesheet(team.ID, month, nil, &privateProject.ID)
+	assert.True(t, errors.As(err, &invalidValueError))
+	_, err = usecaseTest.PdfReportUsecase.GetMonthlyTimesheet(team.ID, month, nil, nil)
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	var entityNotFoundError *EntityNotFoundError
+	unknownId := uuid.Must(uuid.NewV4())
+	_, err = usecaseTest.PdfReportUsecase.GetMonthlyTimesheet(team.ID, month, nil, &unknownId)
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+type PeriodLockUsecase interface {
+	GetLockDate(teamId uuid.UUID) (*time.Time, error)
+	SetLockDate(teamId uuid.UUID, lockDate *time.Time, changedBy uuid.UUID, comment string) (*model.PeriodLockChange, error)
+	GetLockDateChanges(teamId uuid.UUID) ([]model.PeriodLockChange, error)
+	IsTimeLocked(teamId uuid.UUID, t time.Time) (bool, error)
+}
+
+type periodLockUsecase struct {
+	repo        repository.PeriodLockRepository
+	teamUsecase TeamUsecase
+}
+
+func NewPeriodLockUsecase(repo repository.PeriodLockRepository, teamUsecase TeamUsecase) PeriodLockUsecase {
+	return &periodLockUsecase{
+		repo:        repo,
+		teamUsecase: teamUsecase,
+	}
+}
+
+func (usecase *periodLockUsecase) GetLockDate(teamId uuid.UUID) (*time.Time, error) {
+	team, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return team.LockDate, nil
+}
+
+// SetLockDate moves the lock date of the team and records the change. A nil lock date unlocks all periods.
+func (usecase *periodLockUsecase) SetLockDate(teamId uuid.UUID, lockDate *time.Time, changedBy uuid.UUID, comment string) (*model.PeriodLockChange, error) {
+	team, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	if changedBy == uuid.Nil {
+		return nil, NewEntityIncompleteError("the user changing the lock date must not be empty")
+	}
+	if lockDate != nil {
+		day := startOfDay(*lockDate)
+		lockDate = &day
+	}
+	change := model.PeriodLockChange{
+		TeamID:      teamId,
+		ChangedBy:   changedBy,
+		OldLockDate: team.LockDate,
+		NewLockDate: lockDate,
+		Comment:     comment,
+	}
+	team.LockDate = lockDate
+	err = usecase.repo.SetLockDate(team, &change)
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (usecase *periodLockUsecase) GetLockDateChanges(teamId uuid.UUID) ([]model.PeriodLockChange, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.repo.GetPeriodLockChangesOfTeam(teamId)
+}
+
+// IsTimeLocked checks if the given point in time is on or before the lock date of the team.
+func (usecase *periodLockUsecase) IsTimeLocked(teamId uuid.UUID, t time.Time) (bool, error) {
+	lockDate, err := usecase.GetLockDate(teamId)
+	if err != nil {
+		return false, err
+	}
+	if lockDate == nil {
+		return false, nil
+	}
+	return t.Before(startOfDay(*lockDate).AddDate(0, 0, 1)), nil
+}
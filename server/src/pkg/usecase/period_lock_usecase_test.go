@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_periodLockUsecase_SetLockDateRecordsChanges(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	firstLockDate := time.Date(2023, 8, 31, 15, 0, 0, 0, time.UTC)
+	_, err := usecaseTest.PeriodLockUsecase.SetLockDate(team.ID, &firstLockDate, userId, "august is closed")
+	assert.Nil(t, err)
+	secondLockDate := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	change, err := usecaseTest.PeriodLockUsecase.SetLockDate(team.ID, &secondLockDate, userId, "")
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC).Equal(*change.OldLockDate))
+	assert.True(t, secondLockDate.Equal(*change.NewLockDate))
+
+	lockDate, err := usecaseTest.PeriodLockUsecase.GetLockDate(team.ID)
+	assert.Nil(t, err)
+	assert.True(t, secondLockDate.Equal(*lockDate))
+
+	changes, err := usecaseTest.PeriodLockUsecase.GetLockDateChanges(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, userId, changes[1].ChangedBy)
+	assert.Nil(t, changes[1].OldLockDate)
+	assert.Equal(t, "august is closed", changes[1].Comment)
+
+	_, err = usecaseTest.PeriodLockUsecase.SetLockDate(team.ID, nil, userId, "reopened")
+	assert.Nil(t, err)
+	lockDate, err = usecaseTest.PeriodLockUsecase.GetLockDate(team.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, lockDate)
+}
+
+func Test_periodLockUsecase_SetLockDateFailsForUnknownTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	lockDate := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	_, err := usecaseTest.PeriodLockUsecase.SetLockDate(uuid.Must(uuid.NewV4()), &lockDate, GetTestUserId(t), "")
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_periodLockUsecase_LockDateLocksTimeEntriesOfTeamProjects(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	teamProject := model.Project{
+		Name:   "team project",
+		UserId: userId,
+		TeamID: &team.ID,
+	}
+	err := usecaseTest.ProjectUsecase.AddProject(&teamProject)
+	assert.Nil(t, err)
+	privateProject := addProject(t, usecaseTest.ProjectUsecase, "private project", userId)
+	lockedEntry := addReportTimeEntry(t, usecaseTest, userId, teamProject, time.Date(2023, 9, 30, 20, 0, 0, 0, time.UTC), time.Hour)
+	openEntry := addReportTimeEntry(t, usecaseTest, userId, teamProject, time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC), time.Hour)
+	privateEntry := addReportTimeEntry(t, usecaseTest, userId, privateProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	lockDate := time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	_, err = usecaseTest.PeriodLockUsecase.SetLockDate(team.ID, &lockDate, userId, "")
+	assert.Nil(t, err)
+
+	var entityLockedError *EntityLockedError
+	lockedEntry.Description = "changed"
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&lockedEntry)
+	assert.True(t, errors.As(err, &entityLockedError))
+	err = usecaseTest.TimeEntryUsecase.DeleteTimeEntry(lockedEntry.ID)
+	assert.True(t, errors.As(err, &entityLockedError))
+	newEntry := model.TimeEntry{
+		UserId:    userId,
+		ProjectId: teamProject.ID,
+		StartTime: time.Date(2023, 9, 12, 8, 0, 0, 0, time.UTC),
+	}
+	err = usecaseTest.TimeEntryUsecase.AddTimeEntry(&newEntry)
+	assert.True(t, errors.As(err, &entityLockedError))
+	// Moving an entry into the locked period is not allowed either:
+	openEntry.StartTime = time.Date(2023, 9, 29, 8, 0, 0, 0, time.UTC)
+	openEntry.EndTime = openEntry.StartTime.Add(time.Hour)
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&openEntry)
+	assert.True(t, errors.As(err, &entityLockedError))
+	err = usecaseTest.SyncUsecase.UpdateAndDeleteData(model.SyncData{TimeEntriesToBeDeleted: []model.TimeEntry{lockedEntry}})
+	assert.True(t, errors.As(err, &entityLockedError))
+
+	// Entries after the lock date and entries of projects without team can still be changed:
+	openEntry.StartTime = time.Date(2023, 10, 2, 8, 0, 0, 0, time.UTC)
+	openEntry.EndTime = openEntry.StartTime.Add(time.Hour)
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&openEntry)
+	assert.Nil(t, err)
+	privateEntry.Description = "changed"
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&privateEntry)
+	assert.Nil(t, err)
+
+	// After the lock date was removed the entries can be changed again:
+	_, err = usecaseTest.PeriodLockUsecase.SetLockDate(team.ID, nil, userId, "")
+	assert.Nil(t, err)
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&lockedEntry)
+	assert.Nil(t, err)
+}
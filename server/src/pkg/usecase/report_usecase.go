@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+)
+
+type ReportUsecase interface {
+	GetTimeEntriesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error)
+	GetTeamReport(teamId uuid.UUID, from time.Time, to time.Time) (*model.TeamReport, error)
+}
+
+type reportUsecase struct {
+	timeEntryUsecase TimeEntryUsecase
+	teamUsecase      TeamUsecase
+	absenceUsecase   AbsenceUsecase
+}
+
+func NewReportUsecase(timeEntryUsecase TimeEntryUsecase, teamUsecase TeamUsecase, absenceUsecase AbsenceUsecase) ReportUsecase {
+	return &reportUsecase{
+		timeEntryUsecase: timeEntryUsecase,
+		teamUsecase:      teamUsecase,
+		absenceUsecase:   absenceUsecase,
+	}
+}
+
+func (usecase *reportUsecase) GetTimeEntriesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error) {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.timeEntryUsecase.GetAllTimeEntriesOfTeam(teamId, from, to)
+}
+
+func (usecase *reportUsecase) GetTeamReport(teamId uuid.UUID, from time.Time, to time.Time) (*model.TeamReport, error) {
+	timeEntries, err := usecase.GetTimeEntriesOfTeam(teamId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report := model.NewTeamReport(teamId, from, to)
+	for _, timeEntry := range timeEntries {
+		duration := timeEntry.Duration()
+		day := startOfDay(timeEntry.StartTime)
+		report.Total += duration
+		report.ByUser[timeEntry.UserId] += duration
+		report.ByProject[timeEntry.ProjectId] += duration
+		report.ByDay[day] += duration
+		report.ProjectNames[timeEntry.ProjectId] = timeEntry.Project.Name
+	}
+	absences, err := usecase.absenceUsecase.GetAbsencesOfTeam(teamId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, absence := range absences {
+		if absence.Status == model.AbsenceStatusApproved {
+			report.Absences = append(report.Absences, absence)
+		}
+	}
+	return report, nil
+}
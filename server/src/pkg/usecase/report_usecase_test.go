@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reportUsecase_GetTimeEntriesOfTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	memberId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	teamProject := addProject(t, usecaseTest.ProjectUsecase, "team project", adminId)
+	err = usecaseTest.ProjectUsecase.AssignProjectToTeam(&teamProject, &team)
+	assert.Nil(t, err)
+	privateProject := addProject(t, usecaseTest.ProjectUsecase, "private project", memberId)
+
+	addReportTimeEntry(t, usecaseTest, memberId, teamProject, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, adminId, teamProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 3*time.Hour)
+	addReportTimeEntry(t, usecaseTest, memberId, privateProject, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	entries, err := usecaseTest.ReportUsecase.GetTimeEntriesOfTeam(team.ID, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	for _, entry := range entries {
+		assert.Equal(t, teamProject.ID, entry.ProjectId)
+	}
+}
+
+func Test_reportUsecase_GetTimeEntriesOfTeamFiltersByDate(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	teamProject := addProject(t, usecaseTest.ProjectUsecase, "team project", adminId)
+	err := usecaseTest.ProjectUsecase.AssignProjectToTeam(&teamProject, &team)
+	assert.Nil(t, err)
+
+	addReportTimeEntry(t, usecaseTest, adminId, teamProject, time.Date(2023, 8, 31, 8, 0, 0, 0, time.UTC), time.Hour)
+	addReportTimeEntry(t, usecaseTest, adminId, teamProject, time.Date(2023, 9, 1, 8, 0, 0, 0, time.UTC), time.Hour)
+	addReportTimeEntry(t, usecaseTest, adminId, teamProject, time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	entries, err := usecaseTest.ReportUsecase.GetTimeEntriesOfTeam(team.ID, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, time.Date(2023, 9, 1, 8, 0, 0, 0, time.UTC), entries[0].StartTime)
+}
+
+func Test_reportUsecase_GetTimeEntriesOfTeamFailsIfTeamDoesNotExist(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	missingId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	_, err = usecaseTest.ReportUsecase.GetTimeEntriesOfTeam(missingId, time.Time{}, time.Time{})
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_reportUsecase_GetTeamReport(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	memberId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	project1 := addProject(t, usecaseTest.ProjectUsecase, "project 1", adminId)
+	err = usecaseTest.ProjectUsecase.AssignProjectToTeam(&project1, &team)
+	assert.Nil(t, err)
+	project2 := addProject(t, usecaseTest.ProjectUsecase, "project 2", adminId)
+	err = usecaseTest.ProjectUsecase.AssignProjectToTeam(&project2, &team)
+	assert.Nil(t, err)
+
+	addReportTimeEntry(t, usecaseTest, memberId, project1, time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC), 2*time.Hour)
+	addReportTimeEntry(t, usecaseTest, memberId, project2, time.Date(2023, 9, 4, 11, 0, 0, 0, time.UTC), time.Hour)
+	addReportTimeEntry(t, usecaseTest, adminId, project1, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), 3*time.Hour)
+
+	report, err := usecaseTest.ReportUsecase.GetTeamReport(team.ID, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 6*time.Hour, report.Total)
+	assert.Equal(t, 3*time.Hour, report.ByUser[memberId])
+	assert.Equal(t, 3*time.Hour, report.ByUser[adminId])
+	assert.Equal(t, 5*time.Hour, report.ByProject[project1.ID])
+	assert.Equal(t, time.Hour, report.ByProject[project2.ID])
+	assert.Equal(t, "project 1", report.ProjectNames[project1.ID])
+	assert.Equal(t, 3*time.Hour, report.ByDay[time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)])
+	assert.Equal(t, 3*time.Hour, report.ByDay[time.Date(2023, 9, 5, 0, 0, 0, 0, time.UTC)])
+}
+
+func addReportTimeEntry(t *testing.T, usecaseTest *UsecaseTest, userId uuid.UUID, project model.Project, startTime time.Time, duration time.Duration) model.TimeEntry {
+	timeEntry := model.TimeEntry{
+		Description: "entry",
+		UserId:      userId,
+		ProjectId:   project.ID,
+		StartTime:   startTime,
+		EndTime:     startTime.Add(duration),
+	}
+	err := usecaseTest.TimeEntryUsecase.AddTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	return timeEntry
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+type RunningTimerUsecase interface {
+	CheckRunningTimers(now time.Time) ([]model.RunningTimerNotice, error)
+	GetRunningTimerPolicyOfUser(userId uuid.UUID) string
+	SetRunningTimerPolicyOfTeam(teamId uuid.UUID, policy string) error
+	SetRunningTimerPolicyOfUser(userId uuid.UUID, teamId uuid.UUID, policy string) error
+}
+
+type runningTimerUsecase struct {
+	repo                repository.RunningTimerRepository
+	timeEntryUsecase    TimeEntryUsecase
+	teamUsecase         TeamUsecase
+	workingTimeUsecase  WorkingTimeUsecase
+	notificationUsecase NotificationUsecase
+	threshold           time.Duration
+}
+
+func NewRunningTimerUsecase(repo repository.RunningTimerRepository, timeEntryUsecase TimeEntryUsecase, teamUsecase TeamUsecase,
+	workingTimeUsecase WorkingTimeUsecase, notificationUsecase NotificationUsecase, threshold time.Duration) RunningTimerUsecase {
+	return &runningTimerUsecase{
+		repo:                repo,
+		timeEntryUsecase:    timeEntryUsecase,
+		teamUsecase:         teamUsecase,
+		workingTimeUsecase:  workingTimeUsecase,
+		notificationUsecase: notificationUsecase,
+		threshold:           threshold,
+	}
+}
+
+// CheckRunningTimers looks for time entries that are running longer than the threshold. Depending on the policy of
+// the user they are stopped or flagged. Every entry is only handled once and the returned notices are stored so that
+// the user is informed on the next sync. Entries in locked periods are only flagged.
+func (usecase *runningTimerUsecase) CheckRunningTimers(now time.Time) ([]model.RunningTimerNotice, error) {
+	timeEntries, err := usecase.repo.GetRunningTimeEntriesStartedBefore(now.Add(-usecase.threshold))
+	if err != nil {
+		return nil, err
+	}
+	var notices []model.RunningTimerNotice
+	for _, timeEntry := range timeEntries {
+		existingNotices, err := usecase.repo.GetRunningTimerNoticesOfTimeEntry(timeEntry.ID)
+		if err != nil {
+			return notices, err
+		}
+		if len(existingNotices) > 0 {
+			continue
+		}
+		notice := model.RunningTimerNotice{
+			TimeEntryID: timeEntry.ID,
+			UserID:      timeEntry.UserId,
+			Action:      model.RunningTimerActionFlagged,
+		}
+		endTime, stop := usecase.getStopTime(&timeEntry)
+		if stop && !endTime.After(now) {
+			stoppedEntry := timeEntry
+			stoppedEntry.EndTime = endTime
+			if usecase.timeEntryUsecase.CheckTimeEntryIsEditable(&stoppedEntry) == nil {
+				notice.Action = model.RunningTimerActionStopped
+				notice.EndTime = &endTime
+				err = usecase.repo.StopTimeEntry(&stoppedEntry, &notice)
+				if err != nil {
+					return notices, err
+				}
+				usecase.notificationUsecase.Notify(timeEntry.UserId, model.NotificationRunningTimerStopped, map[string]string{
+					"StartTime": timeEntry.StartTime.UTC().Format(notificationTimeFormat),
+					"EndTime":   endTime.UTC().Format(notificationTimeFormat),
+				})
+				notices = append(notices, notice)
+				continue
+			}
+		}
+		err = usecase.repo.AddRunningTimerNotice(&notice)
+		if err != nil {
+			return notices, err
+		}
+		usecase.notificationUsecase.Notify(timeEntry.UserId, model.NotificationRunningTimerFlagged, map[string]string{
+			"StartTime": timeEntry.StartTime.UTC().Format(notificationTimeFormat),
+		})
+		notices = append(notices, notice)
+	}
+	return notices, nil
+}
+
+// GetRunningTimerPolicyOfUser returns the policy for forgotten running entries of the user. The policy of the member
+// overrides the one of the team. If the user belongs to several teams the first policy that is found wins, without
+// any policy the entries are only flagged.
+func (usecase *runningTimerUsecase) GetRunningTimerPolicyOfUser(userId uuid.UUID) string {
+	assignments, err := usecase.teamUsecase.GetTeamsOfUser(userId)
+	if err != nil {
+		return model.RunningTimerPolicyFlag
+	}
+	for _, assignment := range assignments {
+		if assignment.RunningTimerPolicy != "" {
+			return assignment.RunningTimerPolicy
+		}
+	}
+	for _, assignment := range assignments {
+		if assignment.Team.RunningTimerPolicy != "" {
+			return assignment.Team.RunningTimerPolicy
+		}
+	}
+	return model.RunningTimerPolicyFlag
+}
+
+func (usecase *runningTimerUsecase) SetRunningTimerPolicyOfTeam(teamId uuid.UUID, policy string) error {
+	_, err := usecase.teamUsecase.GetTeamById(teamId)
+	if err != nil {
+		return err
+	}
+	if policy != "" && !model.IsValidRunningTimerPolicy(policy) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid running timer policy", policy))
+	}
+	return usecase.repo.UpdateRunningTimerPolicyOfTeam(teamId, policy)
+}
+
+// SetRunningTimerPolicyOfUser overrides the policy of the team for one of its members. An empty policy removes the
+// override.
+func (usecase *runningTimerUsecase) SetRunningTimerPolicyOfUser(userId uuid.UUID, teamId uuid.UUID, policy string) error {
+	if !usecase.teamUsecase.DoesUserBelongToTeam(userId, teamId) {
+		return NewEntityNotFoundError(fmt.Sprintf("user %v is not a member of team %v", userId, teamId))
+	}
+	if policy != "" && !model.IsValidRunningTimerPolicy(policy) {
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid running timer policy", policy))
+	}
+	return usecase.repo.UpdateRunningTimerPolicyOfUser(userId, teamId, policy)
+}
+
+// getStopTime returns the end time a forgotten entry gets according to the policy of its user. The second result is
+// false if the entry must not be stopped. Stopping at the target time assumes that the whole target time of the day
+// was worked in this entry; without target time (e.g. on weekends) the threshold is used.
+func (usecase *runningTimerUsecase) getStopTime(timeEntry *model.TimeEntry) (time.Time, bool) {
+	switch usecase.GetRunningTimerPolicyOfUser(timeEntry.UserId) {
+	case model.RunningTimerPolicyStopAtThreshold:
+		return timeEntry.StartTime.Add(usecase.threshold), true
+	case model.RunningTimerPolicyStopAtTargetTime:
+		day := startOfDay(timeEntry.StartTime)
+		targetTimes, err := usecase.workingTimeUsecase.GetDailyTargetTimes(timeEntry.UserId, day, day.AddDate(0, 0, 1))
+		if err != nil || len(targetTimes) == 0 || targetTimes[0].Target <= 0 {
+			return timeEntry.StartTime.Add(usecase.threshold), true
+		}
+		return timeEntry.StartTime.Add(targetTimes[0].Target), true
+	default:
+		return time.Time{}, false
+	}
+}
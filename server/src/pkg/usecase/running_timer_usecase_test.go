@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_runningTimerUsecase_CheckRunningTimersFlagsEntriesByDefault(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	timeEntry := addRunningTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC))
+	// Not running long enough yet:
+	addRunningTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 6, 7, 0, 0, 0, time.UTC))
+
+	now := time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC)
+	notices, err := usecaseTest.RunningTimerUsecase.CheckRunningTimers(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notices))
+	assert.Equal(t, timeEntry.ID, notices[0].TimeEntryID)
+	assert.Equal(t, model.RunningTimerActionFlagged, notices[0].Action)
+	assert.Nil(t, notices[0].EndTime)
+
+	entryFromDb, err := usecaseTest.TimeEntryUsecase.GetTimeEntryById(timeEntry.ID)
+	assert.Nil(t, err)
+	assert.True(t, entryFromDb.EndTime.IsZero())
+
+	// Every entry is handled only once:
+	notices, err = usecaseTest.RunningTimerUsecase.CheckRunningTimers(now.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(notices))
+}
+
+func Test_runningTimerUsecase_CheckRunningTimersStopsEntriesAtTargetTime(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+	err = usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfTeam(team.ID, model.RunningTimerPolicyStopAtTargetTime)
+	assert.Nil(t, err)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	// Tuesday:
+	timeEntry := addRunningTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC))
+
+	notices, err := usecaseTest.RunningTimerUsecase.CheckRunningTimers(time.Date(2023, 9, 6, 8, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notices))
+	assert.Equal(t, model.RunningTimerActionStopped, notices[0].Action)
+	expectedEndTime := time.Date(2023, 9, 5, 16, 0, 0, 0, time.UTC)
+	assert.True(t, expectedEndTime.Equal(*notices[0].EndTime))
+
+	entryFromDb, err := usecaseTest.TimeEntryUsecase.GetTimeEntryById(timeEntry.ID)
+	assert.Nil(t, err)
+	assert.True(t, expectedEndTime.Equal(entryFromDb.EndTime))
+
+	// The user is informed on the next sync:
+	syncNotices, err := usecaseTest.SyncUsecase.GetRunningTimerNotices(userId, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(syncNotices))
+	assert.Equal(t, timeEntry.ID, syncNotices[0].TimeEntryID)
+}
+
+func Test_runningTimerUsecase_PolicyOfUserOverridesPolicyOfTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	err := usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfTeam(team.ID, model.RunningTimerPolicyFlag)
+	assert.Nil(t, err)
+	err = usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfUser(userId, team.ID, model.RunningTimerPolicyStopAtThreshold)
+	assert.Nil(t, err)
+	assert.Equal(t, model.RunningTimerPolicyStopAtThreshold, usecaseTest.RunningTimerUsecase.GetRunningTimerPolicyOfUser(userId))
+
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	addRunningTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 9, 10, 0, 0, 0, time.UTC))
+	notices, err := usecaseTest.RunningTimerUsecase.CheckRunningTimers(time.Date(2023, 9, 11, 8, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notices))
+	assert.Equal(t, model.RunningTimerActionStopped, notices[0].Action)
+	assert.True(t, time.Date(2023, 9, 9, 22, 0, 0, 0, time.UTC).Equal(*notices[0].EndTime))
+
+	// Removing the override falls back to the policy of the team:
+	err = usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfUser(userId, team.ID, "")
+	assert.Nil(t, err)
+	assert.Equal(t, model.RunningTimerPolicyFlag, usecaseTest.RunningTimerUsecase.GetRunningTimerPolicyOfUser(userId))
+}
+
+func Test_runningTimerUsecase_LockedEntriesAreOnlyFlagged(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	err := usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfTeam(team.ID, model.RunningTimerPolicyStopAtThreshold)
+	assert.Nil(t, err)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	addRunningTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC))
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	_, err = usecaseTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	assert.Nil(t, err)
+
+	notices, err := usecaseTest.RunningTimerUsecase.CheckRunningTimers(time.Date(2023, 9, 12, 8, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(notices))
+	assert.Equal(t, model.RunningTimerActionFlagged, notices[0].Action)
+}
+
+func Test_runningTimerUsecase_SetRunningTimerPolicyFailsWithInvalidPolicy(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	err := usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfTeam(team.ID, "DELETE")
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	err = usecaseTest.RunningTimerUsecase.SetRunningTimerPolicyOfUser(GetTestUserId(t), team.ID, model.RunningTimerPolicyFlag)
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func addRunningTimeEntry(t *testing.T, usecaseTest *UsecaseTest, userId uuid.UUID, project model.Project, startTime time.Time) model.TimeEntry {
+	timeEntry := model.TimeEntry{
+		Description: "running",
+		UserId:      userId,
+		ProjectId:   project.ID,
+		StartTime:   startTime,
+	}
+	err := usecaseTest.TimeEntryUsecase.AddTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+	return timeEntry
+}
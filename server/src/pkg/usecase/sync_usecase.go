@@ -12,20 +12,31 @@ type SyncUsecase interface {
 	UpdateAndDeleteData(data model.SyncData) error
 	GetChangedTimeEntries(userId uuid.UUID, sinceWhen time.Time) ([]model.TimeEntry, error)
 	GetChangedProjects(userId uuid.UUID, sinceWhen time.Time) ([]model.Project, error)
+	GetRunningTimerNotices(userId uuid.UUID, sinceWhen time.Time) ([]model.RunningTimerNotice, error)
 }
 
 type syncUsecase struct {
-	repo repository.SyncRepository
+	repo             repository.SyncRepository
+	timeEntryUsecase TimeEntryUsecase
 }
 
-func NewSyncUsecase(repo repository.SyncRepository) SyncUsecase {
+func NewSyncUsecase(repo repository.SyncRepository, timeEntryUsecase TimeEntryUsecase) SyncUsecase {
 	return &syncUsecase{
-		repo: repo,
+		repo:             repo,
+		timeEntryUsecase: timeEntryUsecase,
 	}
 }
 
+// UpdateAndDeleteData stores the changes of a client. Nothing is stored if one of the time entries is locked.
 func (usecase *syncUsecase) UpdateAndDeleteData(data model.SyncData) error {
-	return usecase.repo.UpdateAndDeleteData(data)
+	timeEntries := append(append([]model.TimeEntry{}, data.TimeEntriesToBeUpdated...), data.TimeEntriesToBeDeleted...)
+	for _, timeEntry := range timeEntries {
+		err := usecase.timeEntryUsecase.CheckTimeEntryIsEditable(&timeEntry)
+		if err != nil {
+			return err
+		}
+	}
+	return lockedIfInvoiced(usecase.repo.UpdateAndDeleteData(data))
 }
 
 func (tu *syncUsecase) GetChangedTimeEntries(userId uuid.UUID, sinceWhen time.Time) ([]model.TimeEntry, error) {
@@ -35,3 +46,8 @@ func (tu *syncUsecase) GetChangedTimeEntries(userId uuid.UUID, sinceWhen time.Ti
 func (tu *syncUsecase) GetChangedProjects(userId uuid.UUID, sinceWhen time.Time) ([]model.Project, error) {
 	return tu.repo.GetUpdatedProjectsOfUser(userId, sinceWhen)
 }
+
+// GetRunningTimerNotices returns the running time entries of the user that were stopped or flagged by the server.
+func (tu *syncUsecase) GetRunningTimerNotices(userId uuid.UUID, sinceWhen time.Time) ([]model.RunningTimerNotice, error) {
+	return tu.repo.GetRunningTimerNoticesOfUser(userId, sinceWhen)
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"fmt"
+	"strings"
 	"timeasy-server/pkg/domain/model"
 	"timeasy-server/pkg/domain/repository"
 
@@ -15,20 +16,25 @@ type TeamUsecase interface {
 	UpdateTeam(team *model.Team) error
 	DeleteTeam(id uuid.UUID) error
 	GetTeamsOfUser(userId uuid.UUID) ([]model.UserTeamAssignment, error)
+	GetUsersOfTeam(teamId uuid.UUID) ([]model.UserTeamAssignment, error)
 	DoesUserBelongToTeam(userId uuid.UUID, teamId uuid.UUID) bool
 	AddUserToTeam(userId uuid.UUID, team *model.Team, roles model.RoleList) (*model.UserTeamAssignment, error)
 	DeleteUserFromTeam(userId uuid.UUID, team *model.Team) error
 	UpdateUserRolesInTeam(userId uuid.UUID, team *model.Team, roles model.RoleList) error
+	UpdateHolidayCalendarOfUser(userId uuid.UUID, team *model.Team, calendarId *uuid.UUID) error
 	IsUserAdminInTeam(userId uuid.UUID, teamId uuid.UUID) bool
+	IsUserManagerInTeam(userId uuid.UUID, teamId uuid.UUID) bool
 }
 
 type teamUsecase struct {
-	repo repository.TeamRepository
+	repo                repository.TeamRepository
+	notificationUsecase NotificationUsecase
 }
 
-func NewTeamUsecase(repo repository.TeamRepository) TeamUsecase {
+func NewTeamUsecase(repo repository.TeamRepository, notificationUsecase NotificationUsecase) TeamUsecase {
 	return &teamUsecase{
-		repo: repo,
+		repo:                repo,
+		notificationUsecase: notificationUsecase,
 	}
 }
 
@@ -37,7 +43,7 @@ func (usecase *teamUsecase) AddTeam(team *model.Team, ownerId uuid.UUID) error {
 	if err != nil {
 		return err
 	}
-	_, err = usecase.AddUserToTeam(ownerId, team, model.RoleList{model.RoleUser, model.RoleAdmin})
+	_, err = usecase.addUserToTeam(ownerId, team, model.RoleList{model.RoleUser, model.RoleAdmin})
 	if err != nil {
 		return err
 	}
@@ -60,6 +66,8 @@ func (usecase *teamUsecase) UpdateTeam(team *model.Team) error {
 	return usecase.repo.UpdateTeam(team)
 }
 
+// DeleteTeam deletes the team and all assignments of its members. Projects of the team are not deleted but
+// handed back to the users who created them.
 func (usecase *teamUsecase) DeleteTeam(id uuid.UUID) error {
 	team, err := usecase.GetTeamById(id)
 	if err != nil {
@@ -76,6 +84,10 @@ func (usecase *teamUsecase) GetTeamsOfUser(userId uuid.UUID) ([]model.UserTeamAs
 	return usecase.repo.GetTeamsOfUser(userId)
 }
 
+func (usecase *teamUsecase) GetUsersOfTeam(teamId uuid.UUID) ([]model.UserTeamAssignment, error) {
+	return usecase.repo.GetUsersOfTeam(teamId)
+}
+
 func (usecase *teamUsecase) DoesUserBelongToTeam(userId uuid.UUID, teamId uuid.UUID) bool {
 	teamAssignments, err := usecase.GetTeamsOfUser(userId)
 	if err != nil {
@@ -89,7 +101,20 @@ func (usecase *teamUsecase) DoesUserBelongToTeam(userId uuid.UUID, teamId uuid.U
 	return false
 }
 
+// AddUserToTeam adds the user to the team and informs them about it.
 func (usecase *teamUsecase) AddUserToTeam(userId uuid.UUID, team *model.Team, roles model.RoleList) (*model.UserTeamAssignment, error) {
+	assignment, err := usecase.addUserToTeam(userId, team, roles)
+	if err != nil {
+		return nil, err
+	}
+	usecase.notificationUsecase.Notify(userId, model.NotificationAddedToTeam, map[string]string{
+		"TeamName": team.Name1,
+		"Roles":    strings.Join(assignment.Roles, ", "),
+	})
+	return assignment, nil
+}
+
+func (usecase *teamUsecase) addUserToTeam(userId uuid.UUID, team *model.Team, roles model.RoleList) (*model.UserTeamAssignment, error) {
 	_, err := usecase.repo.GetUserTeamAssignment(userId, team.ID)
 	// if this throws no error the assignment already exists:
 	if err == nil {
@@ -140,6 +165,19 @@ func (usecase *teamUsecase) UpdateUserRolesInTeam(userId uuid.UUID, team *model.
 	return nil
 }
 
+func (usecase *teamUsecase) UpdateHolidayCalendarOfUser(userId uuid.UUID, team *model.Team, calendarId *uuid.UUID) error {
+	teamAssignment, err := usecase.repo.GetUserTeamAssignment(userId, team.ID)
+	if err != nil {
+		return NewEntityNotFoundError(fmt.Sprintf("assignment between user %v and team %v not found", userId, team.ID))
+	}
+	teamAssignment.HolidayCalendarID = calendarId
+	err = usecase.repo.UpdateUserTeamAssignment(teamAssignment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (usecase *teamUsecase) IsUserAdminInTeam(userId uuid.UUID, teamId uuid.UUID) bool {
 	teamAssignment, err := usecase.repo.GetUserTeamAssignment(userId, teamId)
 	if err != nil {
@@ -148,9 +186,18 @@ func (usecase *teamUsecase) IsUserAdminInTeam(userId uuid.UUID, teamId uuid.UUID
 	return usecase.hasRole(teamAssignment.Roles, model.RoleAdmin)
 }
 
+// IsUserManagerInTeam checks if the user may manage the team's data. This is true for team managers and team admins.
+func (usecase *teamUsecase) IsUserManagerInTeam(userId uuid.UUID, teamId uuid.UUID) bool {
+	teamAssignment, err := usecase.repo.GetUserTeamAssignment(userId, teamId)
+	if err != nil {
+		return false
+	}
+	return usecase.hasRole(teamAssignment.Roles, model.RoleAdmin) || usecase.hasRole(teamAssignment.Roles, model.RoleManager)
+}
+
 func (usecase *teamUsecase) hasRole(roles model.RoleList, role string) bool {
-	for _, role := range roles {
-		if role == model.RoleAdmin {
+	for _, assignedRole := range roles {
+		if assignedRole == role {
 			return true
 		}
 	}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/test"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
@@ -97,6 +99,83 @@ func Test_teamUsecase_DeleteTeam(t *testing.T) {
 	assert.Equal(t, 0, len(teamsFromDb))
 }
 
+func Test_teamUsecase_DeleteTeamRemovesAssignmentsAndDetachesProjects(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	memberId := GetTestUserId(t)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	err = usecaseTest.ProjectUsecase.AssignProjectToTeam(&project, &team)
+	assert.Nil(t, err)
+
+	err = usecaseTest.TeamUsecase.DeleteTeam(team.ID)
+	assert.Nil(t, err)
+
+	assignments, err := usecaseTest.TeamUsecase.GetTeamsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(assignments))
+	assignments, err = usecaseTest.TeamUsecase.GetTeamsOfUser(memberId)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(assignments))
+
+	projectFromDb, err := usecaseTest.ProjectUsecase.GetProjectById(project.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, projectFromDb.TeamID)
+	assert.Equal(t, userId, projectFromDb.UserId)
+
+	projectsOfMember, err := usecaseTest.ProjectUsecase.GetAllProjectsOfUser(memberId)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(projectsOfMember))
+
+	// No domain event is lost:
+	var removedCount, projectUpdatedCount int64
+	assert.Nil(t, test.DB.Model(&model.OutboxEvent{}).Where("type=? AND aggregate_id=?", model.EventUserRemovedFromTeam,
+		team.ID).Count(&removedCount).Error)
+	assert.Equal(t, int64(2), removedCount)
+	assert.Nil(t, test.DB.Model(&model.OutboxEvent{}).Where("type=? AND aggregate_id=?", model.EventProjectUpdated,
+		project.ID).Count(&projectUpdatedCount).Error)
+	// The first update assigned the project to the team
+	assert.Equal(t, int64(2), projectUpdatedCount)
+}
+
+func Test_teamUsecase_DeleteTeamRemovesAbsencesReportTemplateAndWebhooks(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	absence := newAbsence(userId, team.ID, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, usecaseTest.AbsenceUsecase.RequestAbsence(&absence))
+	assert.Nil(t, usecaseTest.PdfReportUsecase.SetReportTemplate(&model.ReportTemplate{TeamID: team.ID,
+		Layout: model.ReportLayoutDailyTotals, Language: "en"}))
+	subscription := model.WebhookSubscription{UserID: userId, TeamID: &team.ID, URL: "https://example.com/hook", Active: true}
+	assert.Nil(t, usecaseTest.WebhookUsecase.AddWebhookSubscription(&subscription))
+	assert.Nil(t, test.DB.Create(&model.WebhookDelivery{SubscriptionID: subscription.ID, EventID: userId,
+		Status: model.WebhookDeliveryStatusPending}).Error)
+
+	err := usecaseTest.TeamUsecase.DeleteTeam(team.ID)
+	assert.Nil(t, err)
+
+	absences, err := usecaseTest.AbsenceUsecase.GetAbsencesOfUser(userId, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(absences))
+	var templateCount, deliveryCount int64
+	assert.Nil(t, test.DB.Unscoped().Model(&model.ReportTemplate{}).Where("team_id=?", team.ID).Count(&templateCount).Error)
+	assert.Equal(t, int64(0), templateCount)
+	subscriptions, err := usecaseTest.WebhookUsecase.GetWebhookSubscriptionsOfTeam(team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(subscriptions))
+	assert.Nil(t, test.DB.Unscoped().Model(&model.WebhookDelivery{}).Where("subscription_id=?", subscription.ID).
+		Count(&deliveryCount).Error)
+	assert.Equal(t, int64(0), deliveryCount)
+}
+
 func Test_teamUsecase_DeleteTeamFailsIfItDoesNotExist(t *testing.T) {
 	usecaseTest := NewUsecaseTest()
 	teardownTest := usecaseTest.SetupTest(t)
@@ -340,6 +419,26 @@ func Test_teamUsecase_DoesUserBelongToTeam(t *testing.T) {
 	assert.False(t, usecaseTest.TeamUsecase.DoesUserBelongToTeam(userId, otherTeam.ID))
 }
 
+func Test_teamUsecase_IsUserManagerInTeam(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	adminId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", adminId)
+	managerId := GetTestUserId(t)
+	_, err := usecaseTest.TeamUsecase.AddUserToTeam(managerId, &team, model.RoleList{model.RoleUser, model.RoleManager})
+	assert.Nil(t, err)
+	memberId := GetTestUserId(t)
+	_, err = usecaseTest.TeamUsecase.AddUserToTeam(memberId, &team, model.RoleList{model.RoleUser})
+	assert.Nil(t, err)
+
+	assert.True(t, usecaseTest.TeamUsecase.IsUserManagerInTeam(adminId, team.ID))
+	assert.True(t, usecaseTest.TeamUsecase.IsUserManagerInTeam(managerId, team.ID))
+	assert.False(t, usecaseTest.TeamUsecase.IsUserManagerInTeam(memberId, team.ID))
+	assert.False(t, usecaseTest.TeamUsecase.IsUserAdminInTeam(managerId, team.ID))
+}
+
 func addTeams(t *testing.T, teamUsecase TeamUsecase, count int, ownerId uuid.UUID) []model.Team {
 	var teams []model.Team
 	for i := 0; i < count; i++ {
@@ -1,7 +1,9 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
+	"time"
 	"timeasy-server/pkg/domain/model"
 	"timeasy-server/pkg/domain/repository"
 
@@ -11,23 +13,33 @@ import (
 type TimeEntryUsecase interface {
 	GetTimeEntryById(id uuid.UUID) (*model.TimeEntry, error)
 	GetAllTimeEntriesOfUser(userId uuid.UUID) ([]model.TimeEntry, error)
+	GetTimeEntriesOfUserInRange(userId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error)
 	GetAllTimeEntriesOfUserAndProject(userId uuid.UUID, projectId uuid.UUID) ([]model.TimeEntry, error)
+	GetAllTimeEntriesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error)
 	AddTimeEntry(timeEntry *model.TimeEntry) error
 	AddTimeEntryList(timeEntryList []model.TimeEntry) error
 	UpdateTimeEntry(timeEntry *model.TimeEntry) error
 	UpdateTimeEntryList(timeEntry []model.TimeEntry) error
 	DeleteTimeEntry(id uuid.UUID) error
+	CheckTimeEntryIsEditable(timeEntry *model.TimeEntry) error
+	CheckTimeEntry(timeEntry *model.TimeEntry) error
+	CheckTimeEntryOfNewProject(timeEntry *model.TimeEntry, project *model.Project) error
 }
 
 type timeEntryUsecase struct {
-	repo           repository.TimeEntryRepository
-	projectUsecase ProjectUsecase
+	repo              repository.TimeEntryRepository
+	projectUsecase    ProjectUsecase
+	timesheetUsecase  TimesheetUsecase
+	periodLockUsecase PeriodLockUsecase
 }
 
-func NewTimeEntryUsecase(repo repository.TimeEntryRepository, projectUsecase ProjectUsecase) TimeEntryUsecase {
+func NewTimeEntryUsecase(repo repository.TimeEntryRepository, projectUsecase ProjectUsecase, timesheetUsecase TimesheetUsecase,
+	periodLockUsecase PeriodLockUsecase) TimeEntryUsecase {
 	return &timeEntryUsecase{
-		repo:           repo,
-		projectUsecase: projectUsecase,
+		repo:              repo,
+		projectUsecase:    projectUsecase,
+		timesheetUsecase:  timesheetUsecase,
+		periodLockUsecase: periodLockUsecase,
 	}
 }
 
@@ -43,15 +55,27 @@ func (tu *timeEntryUsecase) GetAllTimeEntriesOfUser(userId uuid.UUID) ([]model.T
 	return tu.repo.GetAllTimeEntriesOfUser(userId)
 }
 
+func (tu *timeEntryUsecase) GetTimeEntriesOfUserInRange(userId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error) {
+	return tu.repo.GetTimeEntriesOfUserInRange(userId, from, to)
+}
+
 func (tu *timeEntryUsecase) GetAllTimeEntriesOfUserAndProject(userId uuid.UUID, projectId uuid.UUID) ([]model.TimeEntry, error) {
 	return tu.repo.GetAllTimeEntriesOfUserAndProject(userId, projectId)
 }
 
+func (tu *timeEntryUsecase) GetAllTimeEntriesOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.TimeEntry, error) {
+	return tu.repo.GetAllTimeEntriesOfTeam(teamId, from, to)
+}
+
 func (tu *timeEntryUsecase) AddTimeEntry(timeEntry *model.TimeEntry) error {
 	err := tu.checkEntry(timeEntry)
 	if err != nil {
 		return err
 	}
+	err = tu.CheckTimeEntryIsEditable(timeEntry)
+	if err != nil {
+		return err
+	}
 	return tu.repo.AddTimeEntry(timeEntry)
 }
 
@@ -61,6 +85,10 @@ func (tu *timeEntryUsecase) AddTimeEntryList(timeEntryList []model.TimeEntry) er
 		if err != nil {
 			return err
 		}
+		err = tu.CheckTimeEntryIsEditable(&timeEntry)
+		if err != nil {
+			return err
+		}
 	}
 	return tu.repo.AddTimeEntryList(timeEntryList)
 }
@@ -74,7 +102,11 @@ func (tu *timeEntryUsecase) UpdateTimeEntry(timeEntry *model.TimeEntry) error {
 	if err != nil {
 		return err
 	}
-	return tu.repo.UpdateTimeEntry(timeEntry)
+	err = tu.CheckTimeEntryIsEditable(timeEntry)
+	if err != nil {
+		return err
+	}
+	return lockedIfInvoiced(tu.repo.UpdateTimeEntry(timeEntry))
 }
 
 func (tu *timeEntryUsecase) UpdateTimeEntryList(timeEntryList []model.TimeEntry) error {
@@ -83,8 +115,12 @@ func (tu *timeEntryUsecase) UpdateTimeEntryList(timeEntryList []model.TimeEntry)
 		if err != nil {
 			return err
 		}
+		err = tu.CheckTimeEntryIsEditable(&timeEntry)
+		if err != nil {
+			return err
+		}
 	}
-	return tu.repo.UpdateTimeEntryList(timeEntryList)
+	return lockedIfInvoiced(tu.repo.UpdateTimeEntryList(timeEntryList))
 }
 
 func (tu *timeEntryUsecase) DeleteTimeEntry(id uuid.UUID) error {
@@ -92,7 +128,93 @@ func (tu *timeEntryUsecase) DeleteTimeEntry(id uuid.UUID) error {
 	if err != nil {
 		return NewEntityNotFoundError(fmt.Sprintf("timeEntry with id %v does not exist", id))
 	}
-	return tu.repo.DeleteTimeEntry(timeEntry)
+	err = tu.CheckTimeEntryIsEditable(timeEntry)
+	if err != nil {
+		return err
+	}
+	return lockedIfInvoiced(tu.repo.DeleteTimeEntry(timeEntry))
+}
+
+// CheckTimeEntryIsEditable returns an EntityLockedError if the entry or its currently stored version is invoiced or
+// starts within a locked period, i.e. an approved timesheet or on or before the lock date of the project's team.
+func (tu *timeEntryUsecase) CheckTimeEntryIsEditable(timeEntry *model.TimeEntry) error {
+	err := tu.checkNotLocked(timeEntry)
+	if err != nil {
+		return err
+	}
+	if timeEntry.ID == uuid.Nil {
+		return nil
+	}
+	storedEntry, err := tu.repo.GetTimeEntryById(timeEntry.ID)
+	if err != nil {
+		// the entry is new
+		return nil
+	}
+	return tu.checkNotLocked(storedEntry)
+}
+
+// CheckTimeEntry runs the checks of AddTimeEntry without storing the entry, e.g. for the dry run of an import.
+func (tu *timeEntryUsecase) CheckTimeEntry(timeEntry *model.TimeEntry) error {
+	err := tu.checkEntry(timeEntry)
+	if err != nil {
+		return err
+	}
+	return tu.CheckTimeEntryIsEditable(timeEntry)
+}
+
+// CheckTimeEntryOfNewProject runs the checks of CheckTimeEntry for a new entry of a project which does not exist yet,
+// because it is created together with the entry, e.g. by an import.
+func (tu *timeEntryUsecase) CheckTimeEntryOfNewProject(timeEntry *model.TimeEntry, project *model.Project) error {
+	err := tu.checkUser(timeEntry)
+	if err != nil {
+		return err
+	}
+	return tu.checkNotLockedInProject(timeEntry, project)
+}
+
+// lockedIfInvoiced turns the error of an entry that was invoiced after the check of CheckTimeEntryIsEditable into an
+// EntityLockedError.
+func lockedIfInvoiced(err error) error {
+	var invoicedError *model.TimeEntryInvoicedError
+	if errors.As(err, &invoicedError) {
+		return NewEntityLockedError(invoicedError.Error())
+	}
+	return err
+}
+
+func (tu *timeEntryUsecase) checkNotLocked(timeEntry *model.TimeEntry) error {
+	project, err := tu.projectUsecase.GetProjectById(timeEntry.ProjectId)
+	if err != nil {
+		// without a project there is no lock date
+		project = nil
+	}
+	return tu.checkNotLockedInProject(timeEntry, project)
+}
+
+// checkNotLockedInProject checks the locks of checkNotLocked with the given project, which may be nil.
+func (tu *timeEntryUsecase) checkNotLockedInProject(timeEntry *model.TimeEntry, project *model.Project) error {
+	if timeEntry.InvoiceID != nil {
+		return NewEntityLockedError(fmt.Sprintf("time entry %v is invoiced (invoice %v) and cannot be changed", timeEntry.ID, *timeEntry.InvoiceID))
+	}
+	locked, err := tu.timesheetUsecase.IsTimeLocked(timeEntry.UserId, timeEntry.StartTime)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return NewEntityLockedError(fmt.Sprintf("time entry %v belongs to an approved timesheet and cannot be changed", timeEntry.ID))
+	}
+	if project == nil || project.TeamID == nil {
+		// without a team there is no lock date
+		return nil
+	}
+	locked, err = tu.periodLockUsecase.IsTimeLocked(*project.TeamID, timeEntry.StartTime)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return NewEntityLockedError(fmt.Sprintf("time entry %v is on or before the lock date of team %v and cannot be changed", timeEntry.ID, *project.TeamID))
+	}
+	return nil
 }
 
 func (tu *timeEntryUsecase) checkEntry(timeEntry *model.TimeEntry) error {
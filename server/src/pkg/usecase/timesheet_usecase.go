@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+type TimesheetUsecase interface {
+	GetTimesheetById(id uuid.UUID) (*model.Timesheet, error)
+	GetTimesheetsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error)
+	GetTimesheetsOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error)
+	SubmitTimesheet(timesheet *model.Timesheet) error
+	WithdrawTimesheet(id uuid.UUID) error
+	ApproveTimesheet(id uuid.UUID, reviewerId uuid.UUID) (*model.Timesheet, error)
+	RejectTimesheet(id uuid.UUID, reviewerId uuid.UUID, comment string) (*model.Timesheet, error)
+	IsTimeLocked(userId uuid.UUID, t time.Time) (bool, error)
+}
+
+type timesheetUsecase struct {
+	repo                repository.TimesheetRepository
+	teamUsecase         TeamUsecase
+	notificationUsecase NotificationUsecase
+}
+
+func NewTimesheetUsecase(repo repository.TimesheetRepository, teamUsecase TeamUsecase, notificationUsecase NotificationUsecase) TimesheetUsecase {
+	return &timesheetUsecase{
+		repo:                repo,
+		teamUsecase:         teamUsecase,
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+func (usecase *timesheetUsecase) GetTimesheetById(id uuid.UUID) (*model.Timesheet, error) {
+	timesheet, err := usecase.repo.GetTimesheetById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("timesheet with id %v not found", id))
+	}
+	return timesheet, nil
+}
+
+func (usecase *timesheetUsecase) GetTimesheetsOfUser(userId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error) {
+	return usecase.repo.GetTimesheetsOfUser(userId, from, to)
+}
+
+func (usecase *timesheetUsecase) GetTimesheetsOfTeam(teamId uuid.UUID, from time.Time, to time.Time) ([]model.Timesheet, error) {
+	return usecase.repo.GetTimesheetsOfTeam(teamId, from, to)
+}
+
+// SubmitTimesheet submits the week or month containing the start date of the timesheet for approval. A rejected
+// timesheet of the same period is submitted again.
+func (usecase *timesheetUsecase) SubmitTimesheet(timesheet *model.Timesheet) error {
+	err := usecase.checkTimesheet(timesheet)
+	if err != nil {
+		return err
+	}
+	overlappingTimesheets, err := usecase.repo.GetTimesheetsOfUser(timesheet.UserID, timesheet.StartDate, timesheet.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+	var rejectedTimesheet *model.Timesheet
+	for i, overlappingTimesheet := range overlappingTimesheets {
+		if overlappingTimesheet.TeamID != timesheet.TeamID {
+			continue
+		}
+		if overlappingTimesheet.Status == model.TimesheetStatusRejected {
+			if overlappingTimesheet.PeriodType == timesheet.PeriodType && overlappingTimesheet.StartDate.Equal(timesheet.StartDate) {
+				rejectedTimesheet = &overlappingTimesheets[i]
+			}
+			continue
+		}
+		return NewEntityExistsError(fmt.Sprintf("the period overlaps with timesheet %v", overlappingTimesheet.ID))
+	}
+
+	timesheet.Status = model.TimesheetStatusSubmitted
+	timesheet.SubmittedAt = time.Now().UTC()
+	timesheet.ReviewedBy = nil
+	timesheet.ReviewedAt = nil
+	timesheet.Comment = ""
+	if rejectedTimesheet != nil {
+		timesheet.Model = rejectedTimesheet.Model
+		timesheet.ID = rejectedTimesheet.ID
+		err = usecase.repo.UpdateTimesheet(timesheet)
+	} else {
+		err = usecase.repo.AddTimesheet(timesheet)
+	}
+	if err != nil {
+		return err
+	}
+	notifyManagersOfTeam(usecase.notificationUsecase, usecase.teamUsecase, timesheet.TeamID, timesheet.UserID,
+		model.NotificationTimesheetSubmitted, usecase.getNotificationData(timesheet))
+	return nil
+}
+
+// WithdrawTimesheet deletes a timesheet that has not been approved yet.
+func (usecase *timesheetUsecase) WithdrawTimesheet(id uuid.UUID) error {
+	timesheet, err := usecase.GetTimesheetById(id)
+	if err != nil {
+		return err
+	}
+	if timesheet.Status == model.TimesheetStatusApproved {
+		return NewEntityLockedError(fmt.Sprintf("timesheet %v has already been approved", id))
+	}
+	return usecase.repo.DeleteTimesheet(timesheet)
+}
+
+func (usecase *timesheetUsecase) ApproveTimesheet(id uuid.UUID, reviewerId uuid.UUID) (*model.Timesheet, error) {
+	timesheet, err := usecase.GetTimesheetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet.Status != model.TimesheetStatusSubmitted {
+		return nil, NewInvalidValueError(fmt.Sprintf("only submitted timesheets can be approved, timesheet %v is %v", id, timesheet.Status))
+	}
+	return usecase.reviewTimesheet(timesheet, reviewerId, model.TimesheetStatusApproved, "")
+}
+
+// RejectTimesheet sends a submitted timesheet back to the user. An approved timesheet can be rejected as well to
+// unlock its time entries for corrections.
+func (usecase *timesheetUsecase) RejectTimesheet(id uuid.UUID, reviewerId uuid.UUID, comment string) (*model.Timesheet, error) {
+	timesheet, err := usecase.GetTimesheetById(id)
+	if err != nil {
+		return nil, err
+	}
+	if timesheet.Status == model.TimesheetStatusRejected {
+		return nil, NewInvalidValueError(fmt.Sprintf("timesheet %v has already been rejected", id))
+	}
+	return usecase.reviewTimesheet(timesheet, reviewerId, model.TimesheetStatusRejected, comment)
+}
+
+// IsTimeLocked checks if the given point in time belongs to an approved timesheet of the user.
+func (usecase *timesheetUsecase) IsTimeLocked(userId uuid.UUID, t time.Time) (bool, error) {
+	day := startOfDay(t)
+	timesheets, err := usecase.repo.GetTimesheetsOfUser(userId, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return false, err
+	}
+	for _, timesheet := range timesheets {
+		if timesheet.Status == model.TimesheetStatusApproved && timesheet.Contains(t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (usecase *timesheetUsecase) reviewTimesheet(timesheet *model.Timesheet, reviewerId uuid.UUID, status string, comment string) (*model.Timesheet, error) {
+	if reviewerId == timesheet.UserID {
+		return nil, NewInvalidValueError(fmt.Sprintf("timesheet %v must be reviewed by another team manager", timesheet.ID))
+	}
+	now := time.Now().UTC()
+	timesheet.Status = status
+	timesheet.ReviewedBy = &reviewerId
+	timesheet.ReviewedAt = &now
+	timesheet.Comment = comment
+	err := usecase.repo.UpdateTimesheet(timesheet)
+	if err != nil {
+		return nil, err
+	}
+	notificationType := model.NotificationTimesheetApproved
+	if status == model.TimesheetStatusRejected {
+		notificationType = model.NotificationTimesheetRejected
+	}
+	usecase.notificationUsecase.Notify(timesheet.UserID, notificationType, usecase.getNotificationData(timesheet))
+	return timesheet, nil
+}
+
+func (usecase *timesheetUsecase) getNotificationData(timesheet *model.Timesheet) map[string]string {
+	data := map[string]string{
+		"StartDate": timesheet.StartDate.Format(notificationDateFormat),
+		"EndDate":   timesheet.EndDate.Format(notificationDateFormat),
+		"Comment":   timesheet.Comment,
+	}
+	team, err := usecase.teamUsecase.GetTeamById(timesheet.TeamID)
+	if err == nil {
+		data["TeamName"] = team.Name1
+	}
+	return data
+}
+
+// checkTimesheet validates the timesheet and sets its start and end date to the boundaries of the period.
+func (usecase *timesheetUsecase) checkTimesheet(timesheet *model.Timesheet) error {
+	if timesheet.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	if timesheet.TeamID == uuid.Nil {
+		return NewEntityIncompleteError("the team id must not be empty")
+	}
+	if timesheet.StartDate.IsZero() {
+		return NewEntityIncompleteError("the start date of the timesheet must not be empty")
+	}
+	day := startOfDay(timesheet.StartDate)
+	switch timesheet.PeriodType {
+	case model.TimesheetPeriodWeek:
+		// time.Weekday starts with sunday:
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		timesheet.StartDate = day.AddDate(0, 0, -daysSinceMonday)
+		timesheet.EndDate = timesheet.StartDate.AddDate(0, 0, 6)
+	case model.TimesheetPeriodMonth:
+		timesheet.StartDate = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		timesheet.EndDate = timesheet.StartDate.AddDate(0, 1, -1)
+	default:
+		return NewInvalidValueError(fmt.Sprintf("%v is not a valid timesheet period", timesheet.PeriodType))
+	}
+	if !usecase.teamUsecase.DoesUserBelongToTeam(timesheet.UserID, timesheet.TeamID) {
+		return NewEntityNotFoundError(fmt.Sprintf("user %v is not a member of team %v", timesheet.UserID, timesheet.TeamID))
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_timesheetUsecase_SubmitWeeklyTimesheet(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	// Thursday, 2023-09-07:
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 7, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+
+	timesheetFromDb, err := usecaseTest.TimesheetUsecase.GetTimesheetById(timesheet.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.TimesheetStatusSubmitted, timesheetFromDb.Status)
+	assert.True(t, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC).Equal(timesheetFromDb.StartDate))
+	assert.True(t, time.Date(2023, 9, 10, 0, 0, 0, 0, time.UTC).Equal(timesheetFromDb.EndDate))
+}
+
+func Test_timesheetUsecase_SubmitMonthlyTimesheet(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodMonth, time.Date(2024, 2, 12, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Equal(timesheet.StartDate))
+	assert.True(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC).Equal(timesheet.EndDate))
+}
+
+func Test_timesheetUsecase_SubmitTimesheetFailsIfItOverlaps(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	monthlyTimesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodMonth, time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&monthlyTimesheet)
+	assert.Nil(t, err)
+
+	weeklyTimesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 7, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.TimesheetUsecase.SubmitTimesheet(&weeklyTimesheet)
+	assert.NotNil(t, err)
+	var entityExistsError *EntityExistsError
+	assert.True(t, errors.As(err, &entityExistsError))
+}
+
+func Test_timesheetUsecase_SubmitTimesheetFailsWithInvalidPeriod(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	timesheet := newTimesheet(userId, team.ID, "YEAR", time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_timesheetUsecase_RejectedTimesheetCanBeSubmittedAgain(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	_, err = usecaseTest.TimesheetUsecase.RejectTimesheet(timesheet.ID, reviewerId, "friday is missing")
+	assert.Nil(t, err)
+
+	resubmittedTimesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 8, 0, 0, 0, 0, time.UTC))
+	err = usecaseTest.TimesheetUsecase.SubmitTimesheet(&resubmittedTimesheet)
+	assert.Nil(t, err)
+	assert.Equal(t, timesheet.ID, resubmittedTimesheet.ID)
+
+	timesheets, err := usecaseTest.TimesheetUsecase.GetTimesheetsOfUser(userId, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timesheets))
+	assert.Equal(t, model.TimesheetStatusSubmitted, timesheets[0].Status)
+	assert.Equal(t, "", timesheets[0].Comment)
+}
+
+func Test_timesheetUsecase_ApprovedTimesheetLocksTimeEntries(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	timeEntry := addReportTimeEntry(t, usecaseTest, userId, project, time.Date(2023, 9, 5, 8, 0, 0, 0, time.UTC), time.Hour)
+
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	approvedTimesheet, err := usecaseTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	assert.Nil(t, err)
+	assert.Equal(t, model.TimesheetStatusApproved, approvedTimesheet.Status)
+
+	var entityLockedError *EntityLockedError
+	timeEntry.Description = "changed"
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&timeEntry)
+	assert.True(t, errors.As(err, &entityLockedError))
+	err = usecaseTest.TimeEntryUsecase.DeleteTimeEntry(timeEntry.ID)
+	assert.True(t, errors.As(err, &entityLockedError))
+	newEntry := model.TimeEntry{
+		UserId:    userId,
+		ProjectId: project.ID,
+		StartTime: time.Date(2023, 9, 10, 23, 0, 0, 0, time.UTC),
+	}
+	err = usecaseTest.TimeEntryUsecase.AddTimeEntry(&newEntry)
+	assert.True(t, errors.As(err, &entityLockedError))
+	// Moving an entry out of the locked period is not allowed either:
+	timeEntry.StartTime = time.Date(2023, 9, 11, 8, 0, 0, 0, time.UTC)
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&timeEntry)
+	assert.True(t, errors.As(err, &entityLockedError))
+	err = usecaseTest.SyncUsecase.UpdateAndDeleteData(model.SyncData{TimeEntriesToBeDeleted: []model.TimeEntry{timeEntry}})
+	assert.True(t, errors.As(err, &entityLockedError))
+
+	// After the timesheet was rejected the entries can be changed again:
+	_, err = usecaseTest.TimesheetUsecase.RejectTimesheet(timesheet.ID, reviewerId, "please correct tuesday")
+	assert.Nil(t, err)
+	err = usecaseTest.TimeEntryUsecase.UpdateTimeEntry(&timeEntry)
+	assert.Nil(t, err)
+}
+
+func Test_timesheetUsecase_ApproveTimesheetFailsIfItIsNotSubmitted(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	_, err = usecaseTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	assert.Nil(t, err)
+
+	_, err = usecaseTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_timesheetUsecase_ApproveTimesheetFailsForOwnTimesheet(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+
+	_, err = usecaseTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, userId)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+	_, err = usecaseTest.TimesheetUsecase.RejectTimesheet(timesheet.ID, userId, "")
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	timesheetFromDb, err := usecaseTest.TimesheetUsecase.GetTimesheetById(timesheet.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.TimesheetStatusSubmitted, timesheetFromDb.Status)
+}
+
+func Test_timesheetUsecase_WithdrawTimesheetFailsIfItIsApproved(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	reviewerId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	timesheet := newTimesheet(userId, team.ID, model.TimesheetPeriodWeek, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.TimesheetUsecase.SubmitTimesheet(&timesheet)
+	assert.Nil(t, err)
+	_, err = usecaseTest.TimesheetUsecase.ApproveTimesheet(timesheet.ID, reviewerId)
+	assert.Nil(t, err)
+
+	err = usecaseTest.TimesheetUsecase.WithdrawTimesheet(timesheet.ID)
+	assert.NotNil(t, err)
+	var entityLockedError *EntityLockedError
+	assert.True(t, errors.As(err, &entityLockedError))
+}
+
+func newTimesheet(userId uuid.UUID, teamId uuid.UUID, periodType string, day time.Time) model.Timesheet {
+	return model.Timesheet{
+		UserID:     userId,
+		TeamID:     teamId,
+		PeriodType: periodType,
+		StartDate:  day,
+	}
+}
@@ -75,3 +75,31 @@ func NewEntityIncompleteError(msg string) *EntityIncompleteError {
 		Msg: msg,
 	}
 }
+
+type InvalidValueError struct {
+	Msg string
+}
+
+func (e *InvalidValueError) Error() string {
+	return e.Msg
+}
+
+func NewInvalidValueError(msg string) *InvalidValueError {
+	return &InvalidValueError{
+		Msg: msg,
+	}
+}
+
+type EntityLockedError struct {
+	Msg string
+}
+
+func (e *EntityLockedError) Error() string {
+	return e.Msg
+}
+
+func NewEntityLockedError(msg string) *EntityLockedError {
+	return &EntityLockedError{
+		Msg: msg,
+	}
+}
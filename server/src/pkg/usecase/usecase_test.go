@@ -4,8 +4,12 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 	"timeasy-server/pkg/database"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/notification"
 	"timeasy-server/pkg/test"
+	"timeasy-server/pkg/webhook"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
@@ -22,10 +26,29 @@ func TestMain(m *testing.M) {
 }
 
 type UsecaseTest struct {
-	ProjectUsecase   ProjectUsecase
-	TimeEntryUsecase TimeEntryUsecase
-	TeamUsecase      TeamUsecase
-	SyncUsecase      SyncUsecase
+	ProjectUsecase      ProjectUsecase
+	TimeEntryUsecase    TimeEntryUsecase
+	TeamUsecase         TeamUsecase
+	SyncUsecase         SyncUsecase
+	ReportUsecase       ReportUsecase
+	WorkingTimeUsecase  WorkingTimeUsecase
+	AbsenceUsecase      AbsenceUsecase
+	HolidayUsecase      HolidayUsecase
+	TimesheetUsecase    TimesheetUsecase
+	PeriodLockUsecase   PeriodLockUsecase
+	ComplianceUsecase   ComplianceUsecase
+	OvertimeUsecase     OvertimeUsecase
+	MissingTimeUsecase  MissingTimeUsecase
+	RunningTimerUsecase RunningTimerUsecase
+	NotificationUsecase NotificationUsecase
+	WebhookUsecase      WebhookUsecase
+	ExportUsecase       ExportUsecase
+	PdfReportUsecase    PdfReportUsecase
+	CalendarFeedUsecase CalendarFeedUsecase
+	ImportUsecase       ImportUsecase
+	DataExportUsecase   DataExportUsecase
+	ErasureUsecase      ErasureUsecase
+	InvoiceUsecase      InvoiceUsecase
 }
 
 func NewUsecaseTest() *UsecaseTest {
@@ -39,17 +62,64 @@ func (u *UsecaseTest) SetupTest(tb testing.TB) func(tb testing.TB) {
 }
 
 func (u *UsecaseTest) initUsecases() {
+	notificationRepo := database.NewGormNotificationRepository(test.DB)
+	u.NotificationUsecase = NewNotificationUsecase(notificationRepo, map[string]notification.Channel{
+		model.NotificationChannelInbox:   notification.NewInboxChannel(notificationRepo),
+		model.NotificationChannelWebhook: notification.NewWebhookChannel(time.Second, true),
+	})
+
 	teamRepo := database.NewGormTeamRepository(test.DB)
-	u.TeamUsecase = NewTeamUsecase(teamRepo)
+	u.TeamUsecase = NewTeamUsecase(teamRepo, u.NotificationUsecase)
 
 	projectRepo := database.NewGormProjectRepository(test.DB, teamRepo)
 	u.ProjectUsecase = NewProjectUsecase(projectRepo, u.TeamUsecase)
 
+	timesheetRepo := database.NewGormTimesheetRepository(test.DB)
+	u.TimesheetUsecase = NewTimesheetUsecase(timesheetRepo, u.TeamUsecase, u.NotificationUsecase)
+
+	periodLockRepo := database.NewGormPeriodLockRepository(test.DB)
+	u.PeriodLockUsecase = NewPeriodLockUsecase(periodLockRepo, u.TeamUsecase)
+
 	timeEntryRepo := database.NewGormTimeEntryRepository(test.DB)
-	u.TimeEntryUsecase = NewTimeEntryUsecase(timeEntryRepo, u.ProjectUsecase)
+	u.TimeEntryUsecase = NewTimeEntryUsecase(timeEntryRepo, u.ProjectUsecase, u.TimesheetUsecase, u.PeriodLockUsecase)
 
 	syncRepo := database.NewGormSyncRepository(test.DB)
-	u.SyncUsecase = NewSyncUsecase(syncRepo)
+	u.SyncUsecase = NewSyncUsecase(syncRepo, u.TimeEntryUsecase)
+
+	absenceRepo := database.NewGormAbsenceRepository(test.DB)
+	u.AbsenceUsecase = NewAbsenceUsecase(absenceRepo, u.TeamUsecase, u.NotificationUsecase)
+
+	u.ComplianceUsecase = NewComplianceUsecase(u.TimeEntryUsecase, u.TeamUsecase)
+	u.ReportUsecase = NewReportUsecase(u.TimeEntryUsecase, u.TeamUsecase, u.AbsenceUsecase)
+
+	holidayCalendarRepo := database.NewGormHolidayCalendarRepository(test.DB)
+	u.HolidayUsecase = NewHolidayUsecase(holidayCalendarRepo, u.TeamUsecase)
+
+	workingTimeRepo := database.NewGormWorkingTimeModelRepository(test.DB)
+	u.WorkingTimeUsecase = NewWorkingTimeUsecase(workingTimeRepo, u.TeamUsecase, u.AbsenceUsecase, u.HolidayUsecase)
+
+	overtimeRepo := database.NewGormOvertimeRepository(test.DB)
+	u.OvertimeUsecase = NewOvertimeUsecase(overtimeRepo, u.TimeEntryUsecase, u.WorkingTimeUsecase)
+	u.MissingTimeUsecase = NewMissingTimeUsecase(u.TimeEntryUsecase, u.WorkingTimeUsecase, u.TeamUsecase)
+
+	runningTimerRepo := database.NewGormRunningTimerRepository(test.DB)
+	u.RunningTimerUsecase = NewRunningTimerUsecase(runningTimerRepo, u.TimeEntryUsecase, u.TeamUsecase, u.WorkingTimeUsecase,
+		u.NotificationUsecase, 12*time.Hour)
+
+	u.WebhookUsecase = NewWebhookUsecase(database.NewGormWebhookRepository(test.DB), u.ProjectUsecase,
+		webhook.NewHttpSender(time.Second, true))
+
+	u.ExportUsecase = NewExportUsecase(u.TimeEntryUsecase, u.ProjectUsecase, u.TeamUsecase)
+	u.PdfReportUsecase = NewPdfReportUsecase(database.NewGormReportTemplateRepository(test.DB), u.ExportUsecase,
+		u.ProjectUsecase, u.TeamUsecase)
+	u.CalendarFeedUsecase = NewCalendarFeedUsecase(database.NewGormCalendarFeedRepository(test.DB), u.ExportUsecase)
+	u.ImportUsecase = NewImportUsecase(database.NewGormImportedTimeEntryRepository(test.DB), u.TimeEntryUsecase,
+		u.ProjectUsecase, u.TeamUsecase, u.SyncUsecase)
+	u.DataExportUsecase = NewDataExportUsecase(database.NewGormDataExportRepository(test.DB), u.NotificationUsecase,
+		24*time.Hour)
+	u.ErasureUsecase = NewErasureUsecase(database.NewGormErasureRepository(test.DB), u.NotificationUsecase,
+		14*24*time.Hour)
+	u.InvoiceUsecase = NewInvoiceUsecase(database.NewGormInvoiceRepository(test.DB), u.ProjectUsecase, u.TeamUsecase)
 }
 
 func GetTestUserId(t *testing.T) uuid.UUID {
@@ -0,0 +1,308 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+	"timeasy-server/pkg/webhook"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/glog"
+)
+
+// A failing webhook is retried with doubling delays, starting with webhookFirstRetryDelay. After webhookMaxAttempts
+// the delivery is marked as failed and can only be redelivered manually.
+const webhookMaxAttempts = 10
+const webhookFirstRetryDelay = 30 * time.Second
+const webhookMaxRetryDelay = 2 * time.Hour
+
+const webhookDeliveryBatchSize = 100
+
+type WebhookUsecase interface {
+	AddWebhookSubscription(subscription *model.WebhookSubscription) error
+	UpdateWebhookSubscription(subscription *model.WebhookSubscription) error
+	DeleteWebhookSubscription(id uuid.UUID) error
+	GetWebhookSubscriptionById(id uuid.UUID) (*model.WebhookSubscription, error)
+	GetWebhookSubscriptionsOfUser(userId uuid.UUID) ([]model.WebhookSubscription, error)
+	GetWebhookSubscriptionsOfTeam(teamId uuid.UUID) ([]model.WebhookSubscription, error)
+	// EnqueueEvent creates a pending delivery for every subscription that wants the event, DeliverDueWebhooks sends
+	// it. The event bus is not blocked by slow webhooks this way. Enqueueing the same event again does not create
+	// further deliveries.
+	EnqueueEvent(eventId uuid.UUID, occurredAt time.Time, event model.DomainEvent) error
+	// DeliverDueWebhooks sends the new and retries the failed deliveries that are due and returns how many succeeded.
+	DeliverDueWebhooks(now time.Time) (int, error)
+	// RedeliverWebhook sends the delivery again, regardless of its status. A delivery that has used up its attempts
+	// is not retried automatically if it fails again.
+	RedeliverWebhook(deliveryId uuid.UUID) (*model.WebhookDelivery, error)
+	GetWebhookDeliveryById(id uuid.UUID) (*model.WebhookDelivery, error)
+	GetWebhookDeliveries(subscriptionId uuid.UUID, limit int) ([]model.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	repo           repository.WebhookRepository
+	projectUsecase ProjectUsecase
+	sender         webhook.Sender
+}
+
+func NewWebhookUsecase(repo repository.WebhookRepository, projectUsecase ProjectUsecase, sender webhook.Sender) WebhookUsecase {
+	return &webhookUsecase{
+		repo:           repo,
+		projectUsecase: projectUsecase,
+		sender:         sender,
+	}
+}
+
+// webhookPayload is the body that is posted to the subscribers.
+type webhookPayload struct {
+	Id         uuid.UUID         `json:"id"`
+	Type       string            `json:"type"`
+	OccurredAt time.Time         `json:"occurredAt"`
+	Data       model.DomainEvent `json:"data"`
+}
+
+// AddWebhookSubscription validates the subscription and creates a secret if none is given.
+func (usecase *webhookUsecase) AddWebhookSubscription(subscription *model.WebhookSubscription) error {
+	err := usecase.checkWebhookSubscription(subscription)
+	if err != nil {
+		return err
+	}
+	if subscription.Secret == "" {
+		subscription.Secret, err = webhook.GenerateSecret()
+		if err != nil {
+			return err
+		}
+	}
+	return usecase.repo.AddWebhookSubscription(subscription)
+}
+
+func (usecase *webhookUsecase) UpdateWebhookSubscription(subscription *model.WebhookSubscription) error {
+	err := usecase.checkWebhookSubscription(subscription)
+	if err != nil {
+		return err
+	}
+	if subscription.Secret == "" {
+		return NewEntityIncompleteError("the secret must not be empty")
+	}
+	return usecase.repo.UpdateWebhookSubscription(subscription)
+}
+
+func (usecase *webhookUsecase) checkWebhookSubscription(subscription *model.WebhookSubscription) error {
+	if subscription.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	if subscription.URL == "" {
+		return NewEntityIncompleteError("the url must not be empty")
+	}
+	if err := checkHttpUrl(subscription.URL); err != nil {
+		return err
+	}
+	for _, eventType := range subscription.EventTypes {
+		if !model.IsKnownDomainEventType(eventType) {
+			return NewInvalidValueError(fmt.Sprintf("%v is not a valid event type", eventType))
+		}
+	}
+	return nil
+}
+
+func (usecase *webhookUsecase) DeleteWebhookSubscription(id uuid.UUID) error {
+	subscription, err := usecase.GetWebhookSubscriptionById(id)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.DeleteWebhookSubscription(subscription)
+}
+
+func (usecase *webhookUsecase) GetWebhookSubscriptionById(id uuid.UUID) (*model.WebhookSubscription, error) {
+	subscription, err := usecase.repo.GetWebhookSubscriptionById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("webhook subscription with id %v not found", id))
+	}
+	return subscription, nil
+}
+
+func (usecase *webhookUsecase) GetWebhookSubscriptionsOfUser(userId uuid.UUID) ([]model.WebhookSubscription, error) {
+	return usecase.repo.GetWebhookSubscriptionsOfUser(userId)
+}
+
+func (usecase *webhookUsecase) GetWebhookSubscriptionsOfTeam(teamId uuid.UUID) ([]model.WebhookSubscription, error) {
+	return usecase.repo.GetWebhookSubscriptionsOfTeam(teamId)
+}
+
+func (usecase *webhookUsecase) EnqueueEvent(eventId uuid.UUID, occurredAt time.Time, event model.DomainEvent) error {
+	userId, teamId := usecase.getOwnersOfEvent(event)
+	subscriptions, err := usecase.repo.GetActiveWebhookSubscriptions(userId, teamId)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(webhookPayload{
+		Id:         eventId,
+		Type:       event.EventType(),
+		OccurredAt: occurredAt.UTC(),
+		Data:       event,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if !subscription.IsSubscribedTo(event.EventType()) {
+			continue
+		}
+		if _, err := usecase.repo.GetWebhookDeliveryOfEvent(subscription.ID, eventId); err == nil {
+			continue
+		}
+		delivery := model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventId,
+			EventType:      event.EventType(),
+			Payload:        string(payload),
+			Status:         model.WebhookDeliveryStatusPending,
+			NextAttemptAt:  time.Now().UTC(),
+		}
+		err = usecase.repo.AddWebhookDelivery(&delivery)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getOwnersOfEvent returns the user whose personal subscriptions and the team whose subscriptions receive the event.
+func (usecase *webhookUsecase) getOwnersOfEvent(event model.DomainEvent) (*uuid.UUID, *uuid.UUID) {
+	switch typedEvent := event.(type) {
+	case *model.TimeEntryCreated:
+		return usecase.getOwnersOfTimeEntryEvent(&typedEvent.TimeEntryEvent)
+	case *model.TimeEntryUpdated:
+		return usecase.getOwnersOfTimeEntryEvent(&typedEvent.TimeEntryEvent)
+	case *model.TimeEntryDeleted:
+		return usecase.getOwnersOfTimeEntryEvent(&typedEvent.TimeEntryEvent)
+	case *model.ProjectCreated:
+		return &typedEvent.UserID, typedEvent.TeamID
+	case *model.ProjectUpdated:
+		return &typedEvent.UserID, typedEvent.TeamID
+	case *model.ProjectDeleted:
+		return &typedEvent.UserID, typedEvent.TeamID
+	case *model.TeamCreated:
+		return nil, &typedEvent.TeamID
+	case *model.TeamDeleted:
+		return nil, &typedEvent.TeamID
+	case *model.UserAddedToTeam:
+		return &typedEvent.UserID, &typedEvent.TeamID
+	case *model.UserRemovedFromTeam:
+		return &typedEvent.UserID, &typedEvent.TeamID
+	}
+	return nil, nil
+}
+
+func (usecase *webhookUsecase) getOwnersOfTimeEntryEvent(event *model.TimeEntryEvent) (*uuid.UUID, *uuid.UUID) {
+	userId := event.UserID
+	project, err := usecase.projectUsecase.GetProjectById(event.ProjectID)
+	if err != nil {
+		// The project was deleted in the meantime, only the user is informed
+		return &userId, nil
+	}
+	return &userId, project.TeamID
+}
+
+// deliver posts the payload and records the result. Only errors of the delivery log are returned, a failing webhook
+// is retried later.
+func (usecase *webhookUsecase) deliver(subscription *model.WebhookSubscription, delivery *model.WebhookDelivery, now time.Time) error {
+	body := []byte(delivery.Payload)
+	status, err := usecase.sender.Send(subscription.URL, map[string]string{
+		webhook.EventHeader:     delivery.EventType,
+		webhook.DeliveryHeader:  delivery.ID.String(),
+		webhook.SignatureHeader: webhook.Sign(subscription.Secret, body),
+	}, body)
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = model.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		glog.Warningf("webhook delivery %v to %v failed: %v", delivery.ID, subscription.URL, err)
+		usecase.recordFailedAttempt(delivery, err.Error(), now)
+	}
+	return usecase.repo.UpdateWebhookDelivery(delivery)
+}
+
+func (usecase *webhookUsecase) recordFailedAttempt(delivery *model.WebhookDelivery, message string, now time.Time) {
+	delivery.LastError = message
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = model.WebhookDeliveryStatusFailed
+		return
+	}
+	delivery.Status = model.WebhookDeliveryStatusPending
+	delivery.NextAttemptAt = now.Add(getWebhookRetryDelay(delivery.Attempts))
+}
+
+// getWebhookRetryDelay doubles the delay with every failed attempt.
+func getWebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookFirstRetryDelay
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetryDelay {
+		return webhookMaxRetryDelay
+	}
+	return delay
+}
+
+func (usecase *webhookUsecase) DeliverDueWebhooks(now time.Time) (int, error) {
+	deliveries, err := usecase.repo.GetDueWebhookDeliveries(now, webhookDeliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	succeeded := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, err := usecase.repo.GetWebhookSubscriptionById(delivery.SubscriptionID)
+		if err != nil || !subscription.Active {
+			delivery.Status = model.WebhookDeliveryStatusFailed
+			delivery.LastError = "the subscription is disabled"
+			if err := usecase.repo.UpdateWebhookDelivery(delivery); err != nil {
+				return succeeded, err
+			}
+			continue
+		}
+		err = usecase.deliver(subscription, delivery, now)
+		if err != nil {
+			return succeeded, err
+		}
+		if delivery.Status == model.WebhookDeliveryStatusSucceeded {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+func (usecase *webhookUsecase) RedeliverWebhook(deliveryId uuid.UUID) (*model.WebhookDelivery, error) {
+	delivery, err := usecase.GetWebhookDeliveryById(deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := usecase.GetWebhookSubscriptionById(delivery.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	err = usecase.deliver(subscription, delivery, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (usecase *webhookUsecase) GetWebhookDeliveryById(id uuid.UUID) (*model.WebhookDelivery, error) {
+	delivery, err := usecase.repo.GetWebhookDeliveryById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("webhook delivery with id %v not found", id))
+	}
+	return delivery, nil
+}
+
+func (usecase *webhookUsecase) GetWebhookDeliveries(subscriptionId uuid.UUID, limit int) ([]model.WebhookDelivery, error) {
+	return usecase.repo.GetWebhookDeliveries(subscriptionId, limit)
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/webhook"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type receivedWebhook struct {
+	headers http.Header
+	body    []byte
+}
+
+// newWebhookServer records the received requests and responds with the status of the function.
+func newWebhookServer(t *testing.T, getStatus func() int) (*httptest.Server, *[]receivedWebhook) {
+	var received []receivedWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.Nil(t, err)
+		received = append(received, receivedWebhook{headers: r.Header, body: body})
+		w.WriteHeader(getStatus())
+	}))
+	return server, &received
+}
+
+func newTimeEntryCreated(userId uuid.UUID, projectId uuid.UUID) *model.TimeEntryCreated {
+	return &model.TimeEntryCreated{TimeEntryEvent: model.TimeEntryEvent{
+		TimeEntryID: uuid.Must(uuid.NewV4()),
+		UserID:      userId,
+		ProjectID:   projectId,
+		StartTime:   time.Date(2023, 9, 4, 8, 0, 0, 0, time.UTC),
+	}}
+}
+
+func Test_webhookUsecase_AddWebhookSubscription(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	subscription := model.WebhookSubscription{
+		UserID:     userId,
+		URL:        "https://example.com/hook",
+		EventTypes: model.StringList{model.EventTimeEntryCreated},
+		Active:     true,
+	}
+	err := usecaseTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+	assert.Equal(t, 64, len(subscription.Secret))
+
+	subscriptions, err := usecaseTest.WebhookUsecase.GetWebhookSubscriptionsOfUser(userId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(subscriptions))
+	assert.Equal(t, model.StringList{model.EventTimeEntryCreated}, subscriptions[0].EventTypes)
+}
+
+func Test_webhookUsecase_AddWebhookSubscriptionFailsForInvalidValues(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	var invalidValueError *InvalidValueError
+	err := usecaseTest.WebhookUsecase.AddWebhookSubscription(&model.WebhookSubscription{
+		UserID: userId,
+		URL:    "ftp://example.com/hook",
+	})
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&model.WebhookSubscription{
+		UserID:     userId,
+		URL:        "https://example.com/hook",
+		EventTypes: model.StringList{"SomethingHappened"},
+	})
+	assert.True(t, errors.As(err, &invalidValueError))
+
+	var entityIncompleteError *EntityIncompleteError
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&model.WebhookSubscription{UserID: userId})
+	assert.True(t, errors.As(err, &entityIncompleteError))
+}
+
+func Test_webhookUsecase_EnqueueEventDeliversSignedPayload(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	server, received := newWebhookServer(t, func() int { return http.StatusOK })
+	defer server.Close()
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	err := usecaseTest.ProjectUsecase.AssignProjectToTeam(&project, &team)
+	assert.Nil(t, err)
+
+	personalSubscription := model.WebhookSubscription{UserID: userId, URL: server.URL, Active: true}
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&personalSubscription)
+	assert.Nil(t, err)
+	teamSubscription := model.WebhookSubscription{UserID: userId, TeamID: &team.ID, URL: server.URL, Secret: "secret",
+		Active: true}
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&teamSubscription)
+	assert.Nil(t, err)
+	otherTypeSubscription := model.WebhookSubscription{UserID: userId, URL: server.URL, Active: true,
+		EventTypes: model.StringList{model.EventProjectDeleted}}
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&otherTypeSubscription)
+	assert.Nil(t, err)
+	inactiveSubscription := model.WebhookSubscription{UserID: userId, URL: server.URL, Active: false}
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&inactiveSubscription)
+	assert.Nil(t, err)
+
+	eventId := uuid.Must(uuid.NewV4())
+	event := newTimeEntryCreated(userId, project.ID)
+	err = usecaseTest.WebhookUsecase.EnqueueEvent(eventId, time.Now(), event)
+	assert.Nil(t, err)
+	// Enqueueing does not send the webhooks yet
+	assert.Equal(t, 0, len(*received))
+	delivered, err := usecaseTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, delivered)
+	assert.Equal(t, 2, len(*received))
+
+	// The event bus may hand over an event again
+	err = usecaseTest.WebhookUsecase.EnqueueEvent(eventId, time.Now(), event)
+	assert.Nil(t, err)
+	_, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(*received))
+
+	deliveries, err := usecaseTest.WebhookUsecase.GetWebhookDeliveries(teamSubscription.ID, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(deliveries))
+	assert.Equal(t, model.WebhookDeliveryStatusSucceeded, deliveries[0].Status)
+	assert.Equal(t, eventId, deliveries[0].EventID)
+	assert.Equal(t, http.StatusOK, deliveries[0].ResponseStatus)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+
+	var teamRequest *receivedWebhook
+	for i := range *received {
+		if (*received)[i].headers.Get(webhook.DeliveryHeader) == deliveries[0].ID.String() {
+			teamRequest = &(*received)[i]
+		}
+	}
+	assert.NotNil(t, teamRequest)
+	assert.Equal(t, model.EventTimeEntryCreated, teamRequest.headers.Get(webhook.EventHeader))
+	assert.True(t, webhook.VerifySignature("secret", teamRequest.body, teamRequest.headers.Get(webhook.SignatureHeader)))
+
+	var payload struct {
+		Id   uuid.UUID
+		Type string
+		Data model.TimeEntryEvent
+	}
+	err = json.Unmarshal(teamRequest.body, &payload)
+	assert.Nil(t, err)
+	assert.Equal(t, eventId, payload.Id)
+	assert.Equal(t, model.EventTimeEntryCreated, payload.Type)
+	assert.Equal(t, event.TimeEntryID, payload.Data.TimeEntryID)
+	assert.Equal(t, project.ID, payload.Data.ProjectID)
+}
+
+func Test_webhookUsecase_EnqueueEventIgnoresSubscriptionsOfOthers(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	server, received := newWebhookServer(t, func() int { return http.StatusOK })
+	defer server.Close()
+
+	userId := GetTestUserId(t)
+	otherUserId := GetTestUserId(t)
+	project := addProject(t, usecaseTest.ProjectUsecase, "project", userId)
+	otherTeam := addTeam(t, usecaseTest.TeamUsecase, "other team", otherUserId)
+	err := usecaseTest.WebhookUsecase.AddWebhookSubscription(&model.WebhookSubscription{UserID: otherUserId,
+		URL: server.URL, Active: true})
+	assert.Nil(t, err)
+	err = usecaseTest.WebhookUsecase.AddWebhookSubscription(&model.WebhookSubscription{UserID: otherUserId,
+		TeamID: &otherTeam.ID, URL: server.URL, Active: true})
+	assert.Nil(t, err)
+
+	err = usecaseTest.WebhookUsecase.EnqueueEvent(uuid.Must(uuid.NewV4()), time.Now(), newTimeEntryCreated(userId, project.ID))
+	assert.Nil(t, err)
+	_, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(*received))
+}
+
+func Test_webhookUsecase_FailedDeliveriesAreRetriedWithBackoff(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	status := http.StatusServiceUnavailable
+	server, received := newWebhookServer(t, func() int { return status })
+	defer server.Close()
+
+	userId := GetTestUserId(t)
+	subscription := model.WebhookSubscription{UserID: userId, URL: server.URL, Active: true}
+	err := usecaseTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+
+	err = usecaseTest.WebhookUsecase.EnqueueEvent(uuid.Must(uuid.NewV4()), time.Now(), newTimeEntryCreated(userId, uuid.Must(uuid.NewV4())))
+	assert.Nil(t, err)
+	_, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+	deliveries, err := usecaseTest.WebhookUsecase.GetWebhookDeliveries(subscription.ID, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(deliveries))
+	assert.Equal(t, model.WebhookDeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[0].ResponseStatus)
+	firstRetry := deliveries[0].NextAttemptAt
+	assert.True(t, firstRetry.After(time.Now().Add(webhookFirstRetryDelay/2)))
+
+	// Not due yet
+	delivered, err := usecaseTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 1, len(*received))
+
+	delivered, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(firstRetry)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, delivered)
+	delivery, err := usecaseTest.WebhookUsecase.GetWebhookDeliveryById(deliveries[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, delivery.Attempts)
+	assertTimesAreEqual(t, firstRetry.Add(2*webhookFirstRetryDelay), delivery.NextAttemptAt)
+
+	status = http.StatusNoContent
+	delivered, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(delivery.NextAttemptAt)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, delivered)
+	delivery, err = usecaseTest.WebhookUsecase.GetWebhookDeliveryById(deliveries[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.WebhookDeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Equal(t, "", delivery.LastError)
+	assert.Equal(t, 3, len(*received))
+}
+
+func Test_webhookUsecase_DeliveryFailsAfterMaxAttemptsAndCanBeRedelivered(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	status := http.StatusInternalServerError
+	server, received := newWebhookServer(t, func() int { return status })
+	defer server.Close()
+
+	userId := GetTestUserId(t)
+	subscription := model.WebhookSubscription{UserID: userId, URL: server.URL, Active: true}
+	err := usecaseTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+	err = usecaseTest.WebhookUsecase.EnqueueEvent(uuid.Must(uuid.NewV4()), time.Now(), newTimeEntryCreated(userId, uuid.Must(uuid.NewV4())))
+	assert.Nil(t, err)
+	_, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(time.Now().UTC())
+	assert.Nil(t, err)
+
+	now := time.Now().UTC()
+	for i := 1; i < webhookMaxAttempts; i++ {
+		now = now.Add(webhookMaxRetryDelay)
+		_, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(now)
+		assert.Nil(t, err)
+	}
+	deliveries, err := usecaseTest.WebhookUsecase.GetWebhookDeliveries(subscription.ID, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, model.WebhookDeliveryStatusFailed, deliveries[0].Status)
+	assert.Equal(t, webhookMaxAttempts, deliveries[0].Attempts)
+	assert.Equal(t, webhookMaxAttempts, len(*received))
+
+	// Failed deliveries are not retried automatically
+	_, err = usecaseTest.WebhookUsecase.DeliverDueWebhooks(now.Add(webhookMaxRetryDelay))
+	assert.Nil(t, err)
+	assert.Equal(t, webhookMaxAttempts, len(*received))
+
+	status = http.StatusOK
+	delivery, err := usecaseTest.WebhookUsecase.RedeliverWebhook(deliveries[0].ID)
+	assert.Nil(t, err)
+	assert.Equal(t, model.WebhookDeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, webhookMaxAttempts+1, len(*received))
+}
+
+func Test_webhookUsecase_DeleteWebhookSubscription(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	subscription := model.WebhookSubscription{UserID: userId, URL: "https://example.com/hook", Active: true}
+	err := usecaseTest.WebhookUsecase.AddWebhookSubscription(&subscription)
+	assert.Nil(t, err)
+
+	err = usecaseTest.WebhookUsecase.DeleteWebhookSubscription(subscription.ID)
+	assert.Nil(t, err)
+	_, err = usecaseTest.WebhookUsecase.GetWebhookSubscriptionById(subscription.ID)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
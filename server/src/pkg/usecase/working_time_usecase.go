@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"timeasy-server/pkg/domain/model"
+	"timeasy-server/pkg/domain/repository"
+
+	"github.com/gofrs/uuid"
+)
+
+const maxMinutesPerDay = 24 * 60
+
+type WorkingTimeUsecase interface {
+	GetWorkingTimeModelById(id uuid.UUID) (*model.WorkingTimeModel, error)
+	GetWorkingTimeModelsOfUserInTeam(userId uuid.UUID, teamId uuid.UUID) ([]model.WorkingTimeModel, error)
+	AddWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error
+	UpdateWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error
+	DeleteWorkingTimeModel(id uuid.UUID) error
+	GetDailyTargetTimes(userId uuid.UUID, from time.Time, to time.Time) ([]model.DailyTargetTime, error)
+	GetTargetTime(userId uuid.UUID, from time.Time, to time.Time) (time.Duration, error)
+}
+
+type workingTimeUsecase struct {
+	repo           repository.WorkingTimeModelRepository
+	teamUsecase    TeamUsecase
+	absenceUsecase AbsenceUsecase
+	holidayUsecase HolidayUsecase
+}
+
+func NewWorkingTimeUsecase(repo repository.WorkingTimeModelRepository, teamUsecase TeamUsecase, absenceUsecase AbsenceUsecase,
+	holidayUsecase HolidayUsecase) WorkingTimeUsecase {
+	return &workingTimeUsecase{
+		repo:           repo,
+		teamUsecase:    teamUsecase,
+		absenceUsecase: absenceUsecase,
+		holidayUsecase: holidayUsecase,
+	}
+}
+
+func (usecase *workingTimeUsecase) GetWorkingTimeModelById(id uuid.UUID) (*model.WorkingTimeModel, error) {
+	workingTimeModel, err := usecase.repo.GetWorkingTimeModelById(id)
+	if err != nil {
+		return nil, NewEntityNotFoundError(fmt.Sprintf("working time model with id %v not found", id))
+	}
+	return workingTimeModel, nil
+}
+
+func (usecase *workingTimeUsecase) GetWorkingTimeModelsOfUserInTeam(userId uuid.UUID, teamId uuid.UUID) ([]model.WorkingTimeModel, error) {
+	return usecase.repo.GetWorkingTimeModelsOfUserInTeam(userId, teamId)
+}
+
+func (usecase *workingTimeUsecase) AddWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error {
+	err := usecase.checkWorkingTimeModel(workingTimeModel)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.AddWorkingTimeModel(workingTimeModel)
+}
+
+func (usecase *workingTimeUsecase) UpdateWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error {
+	_, err := usecase.GetWorkingTimeModelById(workingTimeModel.ID)
+	if err != nil {
+		return err
+	}
+	err = usecase.checkWorkingTimeModel(workingTimeModel)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.UpdateWorkingTimeModel(workingTimeModel)
+}
+
+func (usecase *workingTimeUsecase) DeleteWorkingTimeModel(id uuid.UUID) error {
+	workingTimeModel, err := usecase.GetWorkingTimeModelById(id)
+	if err != nil {
+		return err
+	}
+	return usecase.repo.DeleteWorkingTimeModel(workingTimeModel)
+}
+
+// GetDailyTargetTimes returns the target time of every day in the interval [from, to). If the user belongs to several
+// teams the target times of all teams are added up. Holidays of the team are non-working days and approved absences
+// reduce the target time.
+func (usecase *workingTimeUsecase) GetDailyTargetTimes(userId uuid.UUID, from time.Time, to time.Time) ([]model.DailyTargetTime, error) {
+	from = startOfDay(from)
+	to = startOfDay(to)
+	if !from.Before(to) {
+		return nil, NewInvalidValueError("the start of the date range must be before its end")
+	}
+	workingTimeModels, err := usecase.repo.GetWorkingTimeModelsOfUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := usecase.absenceUsecase.GetApprovedAbsencesOfUser(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	modelsOfTeams := make(map[uuid.UUID][]model.WorkingTimeModel)
+	for _, workingTimeModel := range workingTimeModels {
+		modelsOfTeams[workingTimeModel.TeamID] = append(modelsOfTeams[workingTimeModel.TeamID], workingTimeModel)
+	}
+	holidaysOfTeams := make(map[uuid.UUID]map[time.Time]bool)
+	for teamId := range modelsOfTeams {
+		holidays, err := usecase.holidayUsecase.GetHolidaysOfUserInTeam(userId, teamId, from, to)
+		if err != nil {
+			return nil, err
+		}
+		holidaysOfTeams[teamId] = make(map[time.Time]bool)
+		for _, holiday := range holidays {
+			holidaysOfTeams[teamId][holiday.Date] = true
+		}
+	}
+
+	var targetTimes []model.DailyTargetTime
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		targetTime := model.DailyTargetTime{
+			Day: day,
+		}
+		for teamId, modelsOfTeam := range modelsOfTeams {
+			validModel := usecase.getValidWorkingTimeModel(modelsOfTeam, day)
+			if validModel == nil {
+				continue
+			}
+			if holidaysOfTeams[teamId][day] {
+				targetTime.Holiday += validModel.TargetTimeOfWeekday(day.Weekday())
+			} else {
+				targetTime.Target += validModel.TargetTimeOfWeekday(day.Weekday())
+			}
+		}
+		usecase.subtractAbsences(&targetTime, absences)
+		targetTimes = append(targetTimes, targetTime)
+	}
+	return targetTimes, nil
+}
+
+func (usecase *workingTimeUsecase) GetTargetTime(userId uuid.UUID, from time.Time, to time.Time) (time.Duration, error) {
+	targetTimes, err := usecase.GetDailyTargetTimes(userId, from, to)
+	if err != nil {
+		return 0, err
+	}
+	var total time.Duration
+	for _, targetTime := range targetTimes {
+		total += targetTime.Target
+	}
+	return total, nil
+}
+
+func (usecase *workingTimeUsecase) subtractAbsences(targetTime *model.DailyTargetTime, absences []model.Absence) {
+	fraction := 0.0
+	for _, absence := range absences {
+		fraction += absence.FractionOfDay(targetTime.Day)
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	targetTime.Absence = time.Duration(float64(targetTime.Target) * fraction)
+	targetTime.Target -= targetTime.Absence
+}
+
+// getValidWorkingTimeModel returns the model that is valid on the given day. The models must be sorted by the day they
+// become valid.
+func (usecase *workingTimeUsecase) getValidWorkingTimeModel(workingTimeModels []model.WorkingTimeModel, day time.Time) *model.WorkingTimeModel {
+	var validModel *model.WorkingTimeModel
+	for i := range workingTimeModels {
+		if workingTimeModels[i].ValidFrom.After(day) {
+			break
+		}
+		validModel = &workingTimeModels[i]
+	}
+	return validModel
+}
+
+func (usecase *workingTimeUsecase) checkWorkingTimeModel(workingTimeModel *model.WorkingTimeModel) error {
+	if workingTimeModel.UserID == uuid.Nil {
+		return NewEntityIncompleteError("the user id must not be empty")
+	}
+	if workingTimeModel.TeamID == uuid.Nil {
+		return NewEntityIncompleteError("the team id must not be empty")
+	}
+	if workingTimeModel.ValidFrom.IsZero() {
+		return NewEntityIncompleteError("the date the working time model becomes valid must not be empty")
+	}
+	if !usecase.teamUsecase.DoesUserBelongToTeam(workingTimeModel.UserID, workingTimeModel.TeamID) {
+		return NewEntityNotFoundError(fmt.Sprintf("user %v is not a member of team %v", workingTimeModel.UserID, workingTimeModel.TeamID))
+	}
+	if workingTimeModel.PartTimePercentage <= 0 || workingTimeModel.PartTimePercentage > 100 {
+		return NewInvalidValueError("the part time percentage must be between 1 and 100")
+	}
+	minutesPerWeekday := []int{
+		workingTimeModel.MondayMinutes,
+		workingTimeModel.TuesdayMinutes,
+		workingTimeModel.WednesdayMinutes,
+		workingTimeModel.ThursdayMinutes,
+		workingTimeModel.FridayMinutes,
+		workingTimeModel.SaturdayMinutes,
+		workingTimeModel.SundayMinutes,
+	}
+	for _, minutes := range minutesPerWeekday {
+		if minutes < 0 || minutes > maxMinutesPerDay {
+			return NewInvalidValueError(fmt.Sprintf("the working time of a day must be between 0 and %v minutes", maxMinutesPerDay))
+		}
+	}
+	workingTimeModel.ValidFrom = startOfDay(workingTimeModel.ValidFrom)
+	return nil
+}
+
+// startOfDay returns midnight (UTC) of the day the given time belongs to.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
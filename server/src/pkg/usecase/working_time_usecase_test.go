@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"timeasy-server/pkg/domain/model"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_workingTimeUsecase_AddWorkingTimeModel(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+
+	modelsFromDb, err := usecaseTest.WorkingTimeUsecase.GetWorkingTimeModelsOfUserInTeam(userId, team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(modelsFromDb))
+	assert.Equal(t, 100, modelsFromDb[0].PartTimePercentage)
+	assert.Equal(t, 8*60, modelsFromDb[0].MondayMinutes)
+	assert.Equal(t, 0, modelsFromDb[0].SundayMinutes)
+	assert.True(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Equal(modelsFromDb[0].ValidFrom))
+}
+
+func Test_workingTimeUsecase_AddWorkingTimeModelFailsIfUserIsNoTeamMember(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	otherUserId := GetTestUserId(t)
+
+	workingTimeModel := newFullTimeWorkingTimeModel(otherUserId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_workingTimeUsecase_AddWorkingTimeModelFailsWithInvalidPercentage(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	workingTimeModel.PartTimePercentage = 120
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.NotNil(t, err)
+	var invalidValueError *InvalidValueError
+	assert.True(t, errors.As(err, &invalidValueError))
+}
+
+func Test_workingTimeUsecase_UpdateWorkingTimeModel(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+
+	workingTimeModel.PartTimePercentage = 50
+	err = usecaseTest.WorkingTimeUsecase.UpdateWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+
+	modelFromDb, err := usecaseTest.WorkingTimeUsecase.GetWorkingTimeModelById(workingTimeModel.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, modelFromDb.PartTimePercentage)
+}
+
+func Test_workingTimeUsecase_DeleteWorkingTimeModel(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	workingTimeModel := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&workingTimeModel)
+	assert.Nil(t, err)
+
+	err = usecaseTest.WorkingTimeUsecase.DeleteWorkingTimeModel(workingTimeModel.ID)
+	assert.Nil(t, err)
+
+	modelsFromDb, err := usecaseTest.WorkingTimeUsecase.GetWorkingTimeModelsOfUserInTeam(userId, team.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(modelsFromDb))
+}
+
+func Test_workingTimeUsecase_DeleteWorkingTimeModelFailsIfItDoesNotExist(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	missingId, err := uuid.NewV4()
+	assert.Nil(t, err)
+	err = usecaseTest.WorkingTimeUsecase.DeleteWorkingTimeModel(missingId)
+	assert.NotNil(t, err)
+	var entityNotFoundError *EntityNotFoundError
+	assert.True(t, errors.As(err, &entityNotFoundError))
+}
+
+func Test_workingTimeUsecase_GetTargetTime(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	team := addTeam(t, usecaseTest.TeamUsecase, "team", userId)
+	fullTime := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&fullTime)
+	assert.Nil(t, err)
+	// The user works half time from wednesday, 2023-09-06 on:
+	halfTime := newFullTimeWorkingTimeModel(userId, team.ID, time.Date(2023, 9, 6, 0, 0, 0, 0, time.UTC))
+	halfTime.PartTimePercentage = 50
+	err = usecaseTest.WorkingTimeUsecase.AddWorkingTimeModel(&halfTime)
+	assert.Nil(t, err)
+
+	// Monday, 2023-09-04 until Sunday, 2023-09-10:
+	from := time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC)
+	targetTimes, err := usecaseTest.WorkingTimeUsecase.GetDailyTargetTimes(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(targetTimes))
+	assert.Equal(t, 8*time.Hour, targetTimes[0].Target)
+	assert.Equal(t, 8*time.Hour, targetTimes[1].Target)
+	assert.Equal(t, 4*time.Hour, targetTimes[2].Target)
+	assert.Equal(t, time.Duration(0), targetTimes[6].Target)
+
+	targetTime, err := usecaseTest.WorkingTimeUsecase.GetTargetTime(userId, from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*8*time.Hour+3*4*time.Hour, targetTime)
+}
+
+func Test_workingTimeUsecase_GetTargetTimeWithoutWorkingTimeModel(t *testing.T) {
+	usecaseTest := NewUsecaseTest()
+	teardownTest := usecaseTest.SetupTest(t)
+	defer teardownTest(t)
+
+	userId := GetTestUserId(t)
+	targetTime, err := usecaseTest.WorkingTimeUsecase.GetTargetTime(userId, time.Date(2023, 9, 4, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), targetTime)
+}
+
+func newFullTimeWorkingTimeModel(userId uuid.UUID, teamId uuid.UUID, validFrom time.Time) model.WorkingTimeModel {
+	return model.WorkingTimeModel{
+		UserID:             userId,
+		TeamID:             teamId,
+		ValidFrom:          validFrom,
+		PartTimePercentage: 100,
+		MondayMinutes:      8 * 60,
+		TuesdayMinutes:     8 * 60,
+		WednesdayMinutes:   8 * 60,
+		ThursdayMinutes:    8 * 60,
+		FridayMinutes:      8 * 60,
+	}
+}
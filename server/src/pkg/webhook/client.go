@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const maxRedirects = 10
+
+// NewHttpClient creates the client that posts to the urls of the users, i.e. the webhook subscriptions and the
+// notification webhooks. Unless private networks are allowed, it refuses to connect to loopback, link-local, private
+// and unspecified addresses, so users cannot make the server call internal services. The address is checked when the
+// connection is made, after the name was resolved, and again for every redirect.
+func NewHttpClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	if allowPrivateNetworks {
+		return &http.Client{Timeout: timeout}
+	}
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialedAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the webhook, so the check would not see the address of the webhook
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// checkDialedAddress is the control function of the dialer. The address is an ip address with port.
+func checkDialedAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%v is not an ip address", host)
+	}
+	return checkPublicIp(ip)
+}
+
+// checkRedirect follows redirects to http and https urls only. Their addresses are checked by the dialer, ip
+// addresses in the url are refused right away.
+func checkRedirect(request *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %v redirects", maxRedirects)
+	}
+	if request.URL.Scheme != "http" && request.URL.Scheme != "https" {
+		return fmt.Errorf("the redirect to %v is not an http or https url", request.URL)
+	}
+	if ip := net.ParseIP(request.URL.Hostname()); ip != nil {
+		return checkPublicIp(ip)
+	}
+	return nil
+}
+
+var errPrivateAddress = errors.New("webhooks must not be sent to loopback, link-local, private or unspecified addresses")
+
+func checkPublicIp(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate() || ip.IsUnspecified() {
+		return fmt.Errorf("%v: %w", ip, errPrivateAddress)
+	}
+	return nil
+}
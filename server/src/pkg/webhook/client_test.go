@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewHttpClientRefusesPrivateNetworks(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	status, err := NewHttpSender(time.Second, false).Send(server.URL, nil, []byte("{}"))
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, errPrivateAddress))
+	assert.Equal(t, 0, status)
+	// The name is resolved before the check:
+	localhostUrl, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+	localhostUrl.Host = net.JoinHostPort("localhost", localhostUrl.Port())
+	_, err = NewHttpSender(time.Second, false).Send(localhostUrl.String(), nil, []byte("{}"))
+	assert.True(t, errors.Is(err, errPrivateAddress))
+	assert.Equal(t, 0, requests)
+
+	status, err = NewHttpSender(time.Second, true).Send(server.URL, nil, []byte("{}"))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, requests)
+}
+
+func Test_checkPublicIp(t *testing.T) {
+	for _, address := range []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254",
+		"fe80::1", "fd00::1", "0.0.0.0", "::", "::ffff:127.0.0.1"} {
+		assert.NotNil(t, checkPublicIp(net.ParseIP(address)), address)
+	}
+	for _, address := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.Nil(t, checkPublicIp(net.ParseIP(address)), address)
+	}
+}
+
+func Test_checkRedirect(t *testing.T) {
+	newRequest := func(rawUrl string) *http.Request {
+		request, err := http.NewRequest(http.MethodPost, rawUrl, nil)
+		assert.Nil(t, err)
+		return request
+	}
+	via := []*http.Request{newRequest("https://example.com/hook")}
+
+	assert.Nil(t, checkRedirect(newRequest("https://example.org/hook"), via))
+	assert.NotNil(t, checkRedirect(newRequest("http://169.254.169.254/latest/meta-data"), via))
+	assert.NotNil(t, checkRedirect(newRequest("http://[::1]:8080/"), via))
+	assert.NotNil(t, checkRedirect(newRequest("file:///etc/passwd"), via))
+
+	for len(via) < maxRedirects {
+		via = append(via, newRequest("https://example.com/hook"))
+	}
+	assert.NotNil(t, checkRedirect(newRequest("https://example.org/hook"), via))
+}
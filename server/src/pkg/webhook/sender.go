@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Sender posts webhook payloads.
+type Sender interface {
+	// Send posts the JSON body to the url and returns the status code of the response. Responses other than 2xx are
+	// returned as error too.
+	Send(url string, headers map[string]string, body []byte) (int, error)
+}
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHttpSender creates a sender with the client of NewHttpClient.
+func NewHttpSender(timeout time.Duration, allowPrivateNetworks bool) Sender {
+	return &httpSender{
+		client: NewHttpClient(timeout, allowPrivateNetworks),
+	}
+}
+
+func (sender *httpSender) Send(url string, headers map[string]string, body []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "timeasy-webhooks")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Read the response, so that the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("the webhook responded with status %v", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
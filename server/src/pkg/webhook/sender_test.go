@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_httpSender_Send(t *testing.T) {
+	var receivedBody []byte
+	var receivedHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		receivedHeaders = r.Header
+		body, err := io.ReadAll(r.Body)
+		assert.Nil(t, err)
+		receivedBody = body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	body := []byte("{\"type\":\"TimeEntryCreated\"}")
+	status, err := NewHttpSender(time.Second, true).Send(server.URL, map[string]string{
+		EventHeader:     "TimeEntryCreated",
+		SignatureHeader: Sign("secret", body),
+	}, body)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, body, receivedBody)
+	assert.Equal(t, "application/json", receivedHeaders.Get("Content-Type"))
+	assert.Equal(t, "TimeEntryCreated", receivedHeaders.Get(EventHeader))
+	assert.True(t, VerifySignature("secret", receivedBody, receivedHeaders.Get(SignatureHeader)))
+}
+
+func Test_httpSender_SendFailsIfWebhookRespondsWithError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	status, err := NewHttpSender(time.Second, true).Send(server.URL, nil, []byte("{}"))
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusGone, status)
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const EventHeader = "X-Timeasy-Event"
+const DeliveryHeader = "X-Timeasy-Delivery"
+
+// SignatureHeader contains the HMAC-SHA256 of the request body with the secret of the subscription, e.g.
+// "sha256=4f2b...". Receivers should compute the signature themselves and compare it in constant time.
+const SignatureHeader = "X-Timeasy-Signature-256"
+
+const signaturePrefix = "sha256="
+
+// Sign returns the value of the signature header for the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature header of a request body.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// GenerateSecret creates a random secret for a subscription.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Sign(t *testing.T) {
+	// Reference value: echo -n '{"a":1}' | openssl dgst -sha256 -hmac secret
+	signature := Sign("secret", []byte("{\"a\":1}"))
+	assert.Equal(t, "sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494", signature)
+}
+
+func Test_VerifySignature(t *testing.T) {
+	body := []byte("{\"a\":1}")
+	assert.True(t, VerifySignature("secret", body, Sign("secret", body)))
+	assert.False(t, VerifySignature("other", body, Sign("secret", body)))
+	assert.False(t, VerifySignature("secret", []byte("{\"a\":2}"), Sign("secret", body)))
+}
+
+func Test_GenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+	assert.Equal(t, 64, len(secret))
+	other, err := GenerateSecret()
+	assert.Nil(t, err)
+	assert.NotEqual(t, secret, other)
+}